@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// Errors for clean command.
+var (
+	errCleanDryRunAndForce = errors.New("cannot use --dry-run and --force together")
+	errCleanFailed         = errors.New("some merged worktrees could not be removed")
+)
+
+// CleanCmd returns the clean command.
+func CleanCmd(cfg Config, fsys fs.FS, git *Git, env map[string]string) *Command {
+	flags := flag.NewFlagSet("clean", flag.ContinueOnError)
+	flags.BoolP("help", "h", false, "Show help")
+	flags.Bool("dry-run", false, "Only list the worktrees that would be removed")
+	flags.BoolP("force", "f", false, "Also remove merged worktrees that have uncommitted changes")
+	flags.BoolP("with-branch", "b", false, "Also delete the merged branches")
+
+	return &Command{
+		Flags: flags,
+		Usage: "clean [flags]",
+		Short: "Remove all merged worktrees",
+		Long: `Remove every wt-managed worktree whose branch is fully merged, e.g. ones
+kept with wt merge --keep.
+
+A worktree counts as merged if its branch has commits of its own (its tip
+differs from start_commit) and is contained in its default target: the
+branch wt merge would merge it into (merge_into, else the merge_into
+config key, else its base_branch). Fresh worktrees without commits,
+detached or locked worktrees, and the current worktree are skipped.
+
+Each worktree is removed like wt remove: the pre-delete hook runs first
+and can veto it. Worktrees with uncommitted changes are skipped unless
+--force is given. Branches are kept unless --with-branch is given; since
+they are merged, a branch named after its worktree is then deleted even if
+git branch -d would refuse.
+
+A worktree that fails to be removed is reported and the others are still
+processed; the exit code is then 1. Ends with a count, e.g. "Cleaned 2
+merged worktrees."`,
+		Exec: func(ctx context.Context, _ io.Reader, stdout, stderr io.Writer, _ []string) error {
+			dryRun, _ := flags.GetBool("dry-run")
+			force, _ := flags.GetBool("force")
+			withBranch, _ := flags.GetBool("with-branch")
+
+			if dryRun && force {
+				return errCleanDryRunAndForce
+			}
+
+			if !dryRun {
+				stdout = informational(cfg, stdout)
+			}
+
+			return execClean(ctx, stdout, stderr, cfg, fsys, git, env, dryRun, force, withBranch)
+		},
+	}
+}
+
+func execClean(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	env map[string]string,
+	dryRun, force, withBranch bool,
+) error {
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("scanning worktrees: %w", err)
+	}
+
+	sortWorktrees(worktrees, listSortID, false)
+
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	gitIndex := newGitWorktreeIndex(entries)
+
+	currentPath, _ := findWorktreeRoot(fsys, cfg.EffectiveCwd)
+
+	hooksDir, err := resolveHooksDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	hookRunner := NewHookRunner(fsys, mainRepoRoot, hooksDir, env, stdout, stderr)
+
+	var failures []error
+
+	cleaned, skipped := 0, 0
+
+	for _, wt := range worktrees {
+		entry, gitKnown := gitIndex.lookup(wt.Path)
+
+		reason := ""
+
+		switch {
+		case !gitKnown:
+			reason = "not a git worktree (see wt prune)"
+		case entry.Branch == "":
+			reason = "no branch checked out"
+		case entry.Locked:
+			reason = "locked"
+		case currentPath != "" && isSamePath(wt.Path, currentPath):
+			reason = "current worktree"
+		}
+
+		if reason != "" {
+			fprintf(stdout, "Skipped %s: %s\n", wt.Name, reason)
+
+			skipped++
+
+			continue
+		}
+
+		target, _ := resolveMergeTarget(cfg, &wt.WorktreeInfo)
+
+		merged, mergedErr := worktreeMerged(ctx, git, mainRepoRoot, &wt.WorktreeInfo, entry.Branch, target)
+		if mergedErr != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", wt.Name, mergedErr))
+			fprintf(stderr, "error: %s: %v\n", wt.Name, mergedErr)
+
+			continue
+		}
+
+		if !merged {
+			continue
+		}
+
+		if !force {
+			dirty, dirtyErr := git.IsDirty(ctx, wt.Path)
+			if dirtyErr != nil {
+				failures = append(failures, fmt.Errorf("%s: %w: %w", wt.Name, errCheckingWorktreeStatus, dirtyErr))
+				fprintf(stderr, "error: %s: %v\n", wt.Name, dirtyErr)
+
+				continue
+			}
+
+			if dirty {
+				fprintf(stdout, "Skipped %s: uncommitted changes (use --force)\n", wt.Name)
+
+				skipped++
+
+				continue
+			}
+		}
+
+		if dryRun {
+			fprintf(stdout, "Would remove %s (%s): merged into %s\n", wt.Name, wt.Path, target)
+
+			cleaned++
+
+			continue
+		}
+
+		// Like wt remove, only a branch named after the worktree is deleted
+		deleteBranch := withBranch && entry.Branch == wt.BranchName()
+
+		cleanupErr := CleanupWorktree(ctx, stdout, git, hookRunner, &wt.WorktreeInfo, wt.Path, mainRepoRoot, deleteBranch, force, true)
+		if cleanupErr != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", wt.Name, cleanupErr))
+			fprintf(stderr, "error: %s: %v\n", wt.Name, cleanupErr)
+
+			continue
+		}
+
+		cleaned++
+	}
+
+	summary := fmt.Sprintf("Cleaned %s", pluralizeMerged(cleaned))
+	if dryRun {
+		summary = fmt.Sprintf("Would clean %s", pluralizeMerged(cleaned))
+	}
+
+	if skipped > 0 || len(failures) > 0 {
+		summary += fmt.Sprintf(" (%d skipped, %d failed)", skipped, len(failures))
+	}
+
+	fprintln(stdout, summary+".")
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%w: %w", errCleanFailed, errors.Join(failures...))
+	}
+
+	return nil
+}
+
+// worktreeMerged reports whether branch, checked out in the worktree
+// described by info, is fully merged into target. A branch still at the
+// worktree's start_commit has no work of its own and does not count as
+// merged. A target branch that does not exist is not merged into.
+func worktreeMerged(ctx context.Context, git *Git, dir string, info *WorktreeInfo, branch, target string) (bool, error) {
+	tip, err := git.RevParse(ctx, dir, "refs/heads/"+branch)
+	if err != nil {
+		return false, err
+	}
+
+	if tip == info.StartCommit {
+		return false, nil
+	}
+
+	if target == "" {
+		return false, nil
+	}
+
+	exists, err := git.BranchExists(ctx, dir, target)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	return git.IsAncestor(ctx, dir, tip, "refs/heads/"+target)
+}
+
+func pluralizeMerged(n int) string {
+	if n == 1 {
+		return "1 merged worktree"
+	}
+
+	return fmt.Sprintf("%d merged worktrees", n)
+}
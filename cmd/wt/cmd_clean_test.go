@@ -0,0 +1,138 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// createMergedWorktree creates a worktree with one commit and merges its
+// branch into the main repo's checked-out branch.
+func createMergedWorktree(t *testing.T, c *CLI, name string) string {
+	t.Helper()
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", name))
+	gitCommitInDir(t, wtPath, name+".txt", name, "add "+name)
+
+	out, err := testGitCmd("-C", c.Dir, "merge", "--ff-only", name).CombinedOutput()
+	if err != nil {
+		t.Fatalf("merging %s: %v\n%s", name, err, out)
+	}
+
+	return wtPath
+}
+
+func Test_Clean_Removes_Merged_Worktrees_And_Keeps_Others(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	mergedPath := createMergedWorktree(t, c, "done")
+
+	unmergedPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "wip"))
+	gitCommitInDir(t, unmergedPath, "wip.txt", "wip", "add wip")
+
+	c.MustRun("--config", "config.json", "create", "--name", "fresh")
+
+	stdout := c.MustRun("--config", "config.json", "clean", "--with-branch")
+
+	AssertContains(t, stdout, "Removed worktree: "+mergedPath)
+	AssertContains(t, stdout, "Deleted branch: done")
+	AssertContains(t, stdout, "Cleaned 1 merged worktree.")
+
+	if c.FileExists(filepath.Join("worktrees", "done")) {
+		t.Error("merged worktree should be removed")
+	}
+
+	for _, name := range []string{"wip", "fresh"} {
+		if !c.FileExists(filepath.Join("worktrees", name, ".wt", "worktree.json")) {
+			t.Errorf("%s should be kept", name)
+		}
+	}
+
+	branches := listBranches(t, c.Dir)
+	if slices.Contains(branches, "done") || !slices.Contains(branches, "wip") {
+		t.Errorf("expected only branch done deleted, got %v", branches)
+	}
+}
+
+func Test_Clean_Dry_Run_Changes_Nothing(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	mergedPath := createMergedWorktree(t, c, "done")
+
+	stdout := c.MustRun("--config", "config.json", "clean", "--dry-run")
+
+	AssertContains(t, stdout, "Would remove done ("+mergedPath+")")
+	AssertContains(t, stdout, "Would clean 1 merged worktree.")
+
+	if !c.FileExists(filepath.Join("worktrees", "done", ".wt", "worktree.json")) {
+		t.Error("dry run should keep the worktree")
+	}
+}
+
+func Test_Clean_Skips_Dirty_Worktree_Unless_Forced(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	mergedPath := createMergedWorktree(t, c, "done")
+	writeTestFile(t, filepath.Join(mergedPath, "scratch.txt"), "not committed")
+
+	stdout := c.MustRun("--config", "config.json", "clean")
+
+	AssertContains(t, stdout, "Skipped done: uncommitted changes (use --force)")
+	AssertContains(t, stdout, "Cleaned 0 merged worktrees (1 skipped, 0 failed).")
+
+	if !c.FileExists(filepath.Join("worktrees", "done", ".wt", "worktree.json")) {
+		t.Fatal("dirty worktree should be kept without --force")
+	}
+
+	stdout = c.MustRun("--config", "config.json", "clean", "--force")
+
+	AssertContains(t, stdout, "Cleaned 1 merged worktree.")
+
+	if c.FileExists(filepath.Join("worktrees", "done")) {
+		t.Error("dirty worktree should be removed with --force")
+	}
+}
+
+func Test_Clean_Reports_Hook_Veto_And_Continues(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.WriteExecutable(".wt/hooks/pre-delete", "#!/bin/sh\n[ \"$WT_NAME\" != vetoed ]\n")
+
+	createMergedWorktree(t, c, "vetoed")
+	createMergedWorktree(t, c, "done")
+
+	stdout, stderr, code := c.Run("--config", "config.json", "clean")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+
+	AssertContains(t, stderr, "error: vetoed:")
+	AssertContains(t, stdout, "Cleaned 1 merged worktree (0 skipped, 1 failed).")
+
+	if !c.FileExists(filepath.Join("worktrees", "vetoed", ".wt", "worktree.json")) {
+		t.Error("vetoed worktree should be kept")
+	}
+
+	if c.FileExists(filepath.Join("worktrees", "done")) {
+		t.Error("other merged worktree should still be removed")
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// Errors for config command.
+var (
+	errConfigUsage         = errors.New("usage: wt config set <key> <value>")
+	errUnknownConfigAction = errors.New("unknown config action (supported: set)")
+	errUnknownConfigKey    = errors.New("unknown config key (valid: base, readme_file, sparse_checkout, copy_ignored, template_dir, hooks_dir, branch_prefix, post_create_cmd, name_slug.lowercase, name_slug.separator, default_merge_target, merge_into, merge_message_trailer, naming)")
+	errInvalidConfigValue  = errors.New("invalid config value")
+	errWritingConfig       = errors.New("writing config")
+)
+
+// ConfigCmd returns the config command.
+func ConfigCmd(cfg Config, fsys fs.FS, git *Git) *Command {
+	flags := flag.NewFlagSet("config", flag.ContinueOnError)
+	flags.BoolP("help", "h", false, "Show help")
+
+	return &Command{
+		Flags: flags,
+		Usage: "config set <key> <value>",
+		Short: "Change the project config",
+		Long: `Set a key in the project config (.wt/config.json in the repository root).
+
+The file is created if it does not exist. Other fields are preserved, and
+the file is replaced atomically.
+
+Keys:
+  base                  Base directory for worktrees (non-empty)
+  readme_file           File name written by create --readme (no directories)
+  sparse_checkout       Comma-separated directories ("" for a full checkout)
+  copy_ignored          Comma-separated globs of ignored files create --with-changes copies ("" for none)
+  template_dir          Directory copied into every new worktree ("" for none)
+  hooks_dir             Directory hooks run from instead of .wt/hooks ("" for .wt/hooks)
+  branch_prefix         Prefix of the branches create makes, e.g. agent/ ("" for none)
+  post_create_cmd       Shell command create runs in new worktrees ("" for none)
+  name_slug.lowercase   true or false
+  name_slug.separator   -, _ or .
+  default_merge_target  Branch wt merge targets by default ("" for base_branch)
+  merge_into            Older name of default_merge_target
+  merge_message_trailer true or false: SHA trailers in squash and merge commits
+  naming                adjective-animal, uuid, numeric or a template like agent-<n>`,
+		Exec: func(ctx context.Context, _ io.Reader, stdout, _ io.Writer, args []string) error {
+			return execConfig(ctx, stdout, cfg, fsys, git, args)
+		},
+	}
+}
+
+func execConfig(ctx context.Context, stdout io.Writer, cfg Config, fsys fs.FS, git *Git, args []string) error {
+	if len(args) == 0 {
+		return errConfigUsage
+	}
+
+	if args[0] != "set" {
+		return fmt.Errorf("%w: %s", errUnknownConfigAction, args[0])
+	}
+
+	if len(args) != 3 {
+		return errConfigUsage
+	}
+
+	key, rawValue := args[1], args[2]
+
+	// merge_into is the older name; write the key that wins on load
+	if key == "merge_into" {
+		key = "default_merge_target"
+	}
+
+	value, err := parseConfigValue(key, rawValue)
+	if err != nil {
+		return err
+	}
+
+	// Same location LoadConfig reads the project config from
+	repoRoot, err := git.RepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	configPath := filepath.Join(repoRoot, ".wt", "config.json")
+
+	err = setConfigKey(fsys, configPath, key, value)
+	if err != nil {
+		return err
+	}
+
+	fprintf(informational(cfg, stdout), "Set %s = %s in %s\n", key, rawValue, configPath)
+
+	return nil
+}
+
+// parseConfigValue validates rawValue for key and converts it to the JSON
+// value stored in the config file.
+func parseConfigValue(key, rawValue string) (any, error) {
+	switch key {
+	case "base":
+		if rawValue == "" {
+			return nil, fmt.Errorf("%w: base must not be empty", errInvalidConfigValue)
+		}
+
+		return rawValue, nil
+	case "readme_file":
+		err := validateReadmeFile(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidConfigValue, err)
+		}
+
+		return rawValue, nil
+	case "sparse_checkout":
+		dirs := []string{}
+
+		if rawValue == "" {
+			return dirs, nil
+		}
+
+		for dir := range strings.SplitSeq(rawValue, ",") {
+			dir = strings.TrimSpace(dir)
+			if dir == "" || filepath.IsAbs(dir) || slices.Contains(strings.Split(dir, "/"), "..") {
+				return nil, fmt.Errorf("%w: sparse_checkout entries must be relative directories, got %q", errInvalidConfigValue, dir)
+			}
+
+			dirs = append(dirs, dir)
+		}
+
+		return dirs, nil
+	case "copy_ignored":
+		patterns := []string{}
+
+		if rawValue == "" {
+			return patterns, nil
+		}
+
+		for pattern := range strings.SplitSeq(rawValue, ",") {
+			pattern = strings.TrimSpace(pattern)
+
+			err := validateCopyPattern(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%w: copy_ignored: %w", errInvalidConfigValue, err)
+			}
+
+			patterns = append(patterns, pattern)
+		}
+
+		return patterns, nil
+	case "template_dir", "hooks_dir", "branch_prefix", "post_create_cmd":
+		return rawValue, nil
+	case "name_slug.lowercase":
+		lowercase, err := strconv.ParseBool(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: name_slug.lowercase must be true or false, got %q", errInvalidConfigValue, rawValue)
+		}
+
+		return lowercase, nil
+	case "name_slug.separator":
+		_, err := slugifyName("x", NameSlugConfig{Separator: rawValue})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidConfigValue, err)
+		}
+
+		return rawValue, nil
+	case "default_merge_target", "merge_into":
+		return rawValue, nil
+	case "merge_message_trailer":
+		trailer, err := strconv.ParseBool(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: merge_message_trailer must be true or false, got %q", errInvalidConfigValue, rawValue)
+		}
+
+		return trailer, nil
+	case "naming":
+		err := validateNamingScheme(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidConfigValue, err)
+		}
+
+		return rawValue, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownConfigKey, key)
+	}
+}
+
+// setConfigKey sets key (optionally "object.field") in the JSON file at
+// configPath, keeping all other fields as they are.
+func setConfigKey(fsys fs.FS, configPath, key string, value any) error {
+	fields := map[string]json.RawMessage{}
+
+	data, err := fsys.ReadFile(configPath)
+
+	switch {
+	case err == nil:
+		err = json.Unmarshal(data, &fields)
+		if err != nil {
+			return fmt.Errorf("parsing config %s: %w", configPath, err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+		// Created below
+	default:
+		return fmt.Errorf("reading config %s: %w", configPath, err)
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+
+	if parent, child, nested := strings.Cut(key, "."); nested {
+		object := map[string]json.RawMessage{}
+
+		if raw, ok := fields[parent]; ok && string(raw) != "null" {
+			err = json.Unmarshal(raw, &object)
+			if err != nil {
+				return fmt.Errorf("parsing config %s: %s: %w", configPath, parent, err)
+			}
+		}
+
+		object[child] = encoded
+
+		encoded, err = json.Marshal(object)
+		if err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+
+		key = parent
+	}
+
+	fields[key] = encoded
+
+	out, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+
+	out = append(out, '\n')
+
+	// Make sure the result still loads as a config before replacing the file
+	var check Config
+
+	err = json.Unmarshal(out, &check)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errWritingConfig, configPath, err)
+	}
+
+	err = fsys.MkdirAll(filepath.Dir(configPath), 0o750)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errWritingConfig, configPath, err)
+	}
+
+	// Write to a temp file and rename, so readers never see a partial file
+	tmpPath := configPath + ".tmp"
+
+	err = fsys.WriteFile(tmpPath, out, 0o644)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errWritingConfig, configPath, err)
+	}
+
+	err = fsys.Rename(tmpPath, configPath)
+	if err != nil {
+		_ = fsys.Remove(tmpPath)
+
+		return fmt.Errorf("%w %s: %w", errWritingConfig, configPath, err)
+	}
+
+	return nil
+}
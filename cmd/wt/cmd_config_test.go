@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_Config_Set_Creates_Project_Config(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	stdout := c.MustRun("config", "set", "base", "worktrees")
+	AssertContains(t, stdout, "Set base = worktrees")
+
+	var cfg Config
+
+	err := json.Unmarshal([]byte(c.ReadFile(".wt/config.json")), &cfg)
+	if err != nil {
+		t.Fatalf("invalid config written: %v", err)
+	}
+
+	if cfg.Base != "worktrees" {
+		t.Errorf("expected base worktrees, got %q", cfg.Base)
+	}
+
+	if c.FileExists(".wt/config.json.tmp") {
+		t.Error("temp file should be renamed into place")
+	}
+
+	// The new value is picked up by the next command
+	stdout = c.MustRun("create", "--name", "from-config")
+	AssertContains(t, stdout, "worktrees/from-config")
+}
+
+func Test_Config_Set_Preserves_Other_Fields(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile(".wt/config.json", `{"base": "worktrees", "readme_file": "NOTES.md", "name_slug": {"lowercase": true}}`)
+
+	c.MustRun("config", "set", "sparse_checkout", "docs, src/app")
+	c.MustRun("config", "set", "name_slug.separator", "_")
+	c.MustRun("config", "set", "copy_ignored", ".env,**/*.local.json")
+	c.MustRun("config", "set", "merge_into", "develop")
+
+	var raw map[string]any
+
+	err := json.Unmarshal([]byte(c.ReadFile(".wt/config.json")), &raw)
+	if err != nil {
+		t.Fatalf("invalid config written: %v", err)
+	}
+
+	if raw["base"] != "worktrees" || raw["readme_file"] != "NOTES.md" {
+		t.Errorf("existing fields should be preserved: %v", raw)
+	}
+
+	sparse, ok := raw["sparse_checkout"].([]any)
+	if !ok || len(sparse) != 2 || sparse[0] != "docs" || sparse[1] != "src/app" {
+		t.Errorf("unexpected sparse_checkout: %v", raw["sparse_checkout"])
+	}
+
+	copyIgnored, ok := raw["copy_ignored"].([]any)
+	if !ok || len(copyIgnored) != 2 || copyIgnored[0] != ".env" || copyIgnored[1] != "**/*.local.json" {
+		t.Errorf("unexpected copy_ignored: %v", raw["copy_ignored"])
+	}
+
+	slug, ok := raw["name_slug"].(map[string]any)
+	if !ok || slug["lowercase"] != true || slug["separator"] != "_" {
+		t.Errorf("unexpected name_slug: %v", raw["name_slug"])
+	}
+
+	// merge_into is stored under its newer name
+	if _, ok := raw["merge_into"]; ok || raw["default_merge_target"] != "develop" {
+		t.Errorf("merge_into should be written as default_merge_target: %v", raw)
+	}
+}
+
+func Test_Config_Set_Rejects_Unknown_Key(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	_, stderr, code := c.Run("config", "set", "colour", "blue")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "unknown config key")
+
+	if c.FileExists(".wt/config.json") {
+		t.Error("config should not be written for an unknown key")
+	}
+}
+
+func Test_Config_Set_Rejects_Invalid_Values(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	tests := [][]string{
+		{"base", ""},
+		{"readme_file", "docs/TASK.md"},
+		{"sparse_checkout", "../outside"},
+		{"copy_ignored", ".env, /etc/passwd"},
+		{"name_slug.lowercase", "maybe"},
+		{"name_slug.separator", "/"},
+		{"merge_message_trailer", "sometimes"},
+		{"naming", "random"},
+	}
+
+	for _, tt := range tests {
+		_, stderr, code := c.Run("config", "set", tt[0], tt[1])
+		if code != 1 {
+			t.Errorf("config set %s %q: expected exit code 1, got %d", tt[0], tt[1], code)
+		}
+
+		AssertContains(t, stderr, "invalid config value")
+	}
+
+	if c.FileExists(".wt/config.json") {
+		t.Error("config should not be written for invalid values")
+	}
+}
+
+func Test_Config_Requires_Set_Action(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	_, stderr, code := c.Run("config", "get", "base")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "unknown config action")
+
+	_, stderr, code = c.Run("config", "set", "base")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "usage: wt config set")
+}
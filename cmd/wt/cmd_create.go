@@ -6,8 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,18 +22,96 @@ import (
 // ErrNameAlreadyInUse is returned when the requested worktree name is already in use.
 var ErrNameAlreadyInUse = errors.New("name already in use (use wt list to see worktrees)")
 
-// errSwitchAndJSONMutuallyExclusive is returned when both --switch and --json are specified.
-var errSwitchAndJSONMutuallyExclusive = errors.New("cannot use --switch and --json together")
+// errCreateInterrupted is returned when create is cancelled before it completes.
+var errCreateInterrupted = errors.New("create interrupted, worktree rolled back")
+
+// errRemoteNotFound is returned when --set-upstream names a remote that is not configured.
+var errRemoteNotFound = errors.New("remote not found (see git remote)")
+
+// errSwitchAndDryRunMutuallyExclusive is returned when both --switch and --dry-run are specified.
+var errSwitchAndDryRunMutuallyExclusive = errors.New("cannot use --switch and --dry-run together")
+
+// Errors for create --eval.
+var (
+	errInvalidEvalShell = errors.New("invalid --eval shell (valid: sh, bash, zsh, fish)")
+	errEvalConflict     = errors.New("cannot use --eval with")
+)
+
+// errAgentHintConflict is returned when --agent-hint is combined with a
+// flag that creates no single worktree.
+var errAgentHintConflict = errors.New("cannot use --agent-hint with")
+
+// errInvalidEnvAssignment is returned when --env is not KEY=VALUE with a valid variable name.
+var errInvalidEnvAssignment = errors.New("invalid --env (expected KEY=VALUE, KEY of letters, digits and _)")
+
+// Errors for create --also-copy and copy_ignored.
+var (
+	errAlsoCopyRequiresWithChanges = errors.New("--also-copy requires --with-changes")
+	errInvalidCopyPattern          = errors.New("copy patterns must be relative globs inside the repository")
+)
+
+// errTemplateNotDir is returned when template_dir is not a directory.
+var errTemplateNotDir = errors.New("template_dir is not a directory")
+
+// errEmptyBaseOverride is returned when --base is given an empty value.
+var errEmptyBaseOverride = errors.New("--base must not be empty")
+
+// errBaseBranchNotExist is returned when --from-branch names a branch or revision that does not exist.
+var errBaseBranchNotExist = errors.New("does not exist")
+
+// errFromConflict is returned when both --from and --from-branch are given.
+var errFromConflict = errors.New("cannot use --from and --from-branch together")
+
+// Errors for create --pool and --count.
+var (
+	errInvalidPoolCount  = errors.New("--count must be at least 1")
+	errPoolFlagConflict  = errors.New("cannot use --pool with")
+	errCountFlagConflict = errors.New("cannot use --count with")
+)
+
+// errPreCreateHookAborted is returned when the pre-create hook fails.
+var errPreCreateHookAborted = errors.New("pre-create hook aborted creation")
+
+// errStartCleanConflict is returned when --start-clean is combined with a flag that adds files.
+var errStartCleanConflict = errors.New("cannot use --start-clean with")
+
+// errNoCheckoutConflict is returned when --no-checkout is combined with a flag that needs checked-out files.
+var errNoCheckoutConflict = errors.New("cannot use --no-checkout with")
 
 // CreateCmd returns the create command.
 func CreateCmd(cfg Config, fsys fs.FS, git *Git, env map[string]string) *Command {
 	flags := flag.NewFlagSet("create", flag.ContinueOnError)
 	flags.BoolP("help", "h", false, "Show help")
 	flags.StringP("name", "n", "", "Worktree and branch name (default: auto-generated)")
-	flags.StringP("from-branch", "b", "", "Branch to base off (default: current branch)")
+	flags.StringP("from-branch", "b", "", "Branch to base off (default: current branch); a tag or commit also works")
+	flags.String("from", "", "Same as --from-branch")
+	flags.String("base", "", "Base directory for this worktree, overriding the base config key")
 	flags.Bool("with-changes", false, "Copy staged, unstaged, and untracked files to new worktree")
+	flags.StringArray("also-copy", nil, "With --with-changes, also copy gitignored files matching this glob (repeatable)")
+	flags.Bool("start-clean", false, "Start from the committed tree only (refuses --with-changes and --readme)")
+	flags.Bool("no-checkout", false, "Check out no files (git worktree add --no-checkout), e.g. for a later sparse checkout")
 	flags.Bool("json", false, "Output as JSON")
-	flags.BoolP("switch", "s", false, "Output only the path (for use with cd)")
+	flags.BoolP("switch", "s", false, "Output only the path (for use with cd; with --json, add switch_path)")
+	addPorcelainFlag(flags)
+	flags.String("eval", "", "Output a shell line that cds into the worktree, for eval (sh, bash, zsh or fish; default sh)")
+	flags.Bool("agent-hint", false, "Also print the worktree's WT_* variables to stderr as WT_HINT: KEY=VALUE lines")
+	flags.Lookup("eval").NoOptDefVal = evalShellSh
+	flags.String("readme", "", "Write a task README into the worktree (file path or literal text)")
+	flags.Bool("checkout-base", false, "Fast-forward the base branch from its upstream before creating")
+	flags.String("post-create-cmd", "", "Shell command to run in the new worktree after the post-create hook")
+	flags.StringArray("env", nil, "Set KEY=VALUE in the create hooks' environment (repeatable)")
+	flags.Bool("dry-run", false, "Show the worktree that would be created without creating it")
+	flags.Bool("no-hooks", false, "Do not run the pre-create and post-create hooks or post_create_cmd (--post-create-cmd still runs)")
+	flags.String("set-upstream", "", "Configure the new branch to push to this remote (does not push)")
+	flags.String("branch-description", "", "Set the new branch's git description (branch.<name>.description)")
+	flags.StringArray("label", nil, "Add a key=value label to the new worktree's metadata (repeatable, see wt label)")
+	flags.String("merge-into", "", "Record a default merge target for the new worktree (used by wt merge)")
+	flags.String("lock", "", "Lock the new worktree with git worktree lock (optional reason: --lock=<reason>)")
+	flags.Lookup("lock").NoOptDefVal = lockWithoutReason
+	flags.String("pool", "", "Create --count worktrees named <pool>-1 to <pool>-N in one batch")
+	flags.Int("count", 1, "Number of worktrees to create in one batch (named <name>-1 to <name>-N with --name)")
+	flags.String("overwrite-metadata", "", "Write fresh .wt/worktree.json into the existing git worktree at this path instead of creating one")
+	flags.Bool("force", false, "With --overwrite-metadata: also replace valid metadata")
 
 	return &Command{
 		Flags:   flags,
@@ -38,33 +120,272 @@ func CreateCmd(cfg Config, fsys fs.FS, git *Git, env map[string]string) *Command
 		Aliases: []string{"new"},
 		Long: `Create a new worktree with auto-generated name and unique ID.
 
-A git branch is created with the same name as the worktree. The worktree
-directory is created at <base>/<repo>/<name>, where base is configured
-in .wt/config.json or ~/.config/wt/config.json.
+A git branch is created with the same name as the worktree (with the
+branch_prefix config in front, if set). The worktree directory is created
+at <base>/<repo>/<name>, where base is configured in .wt/config.json or
+~/.config/wt/config.json; --base overrides it for one create. --from-branch
+accepts any commit-ish, recorded in the metadata as its branch or tag name,
+or the short SHA if it has none.
+
+Metadata is written to .wt/worktree.json inside the new worktree, which is
+added to .git/info/exclude. When create runs from inside another wt-managed
+worktree, that worktree's id is recorded as parent_id (see wt remove
+--recursive).
+
+If .wt/hooks/pre-create exists and is executable, it runs in the
+repository root before anything is created, and a non-zero exit aborts the
+create. If .wt/hooks/post-create exists and is executable, it runs in the
+new worktree afterwards, followed by the post_create_cmd config key (or
+--post-create-cmd). They get WT_* variables and any --env KEY=VALUE. The
+hooks_dir config key replaces .wt/hooks; --no-hooks skips the hooks and
+post_create_cmd.
+
+Before the post-create hook, the contents of template_dir are copied in,
+--with-changes copies the uncommitted changes (gitignored files only if
+they match copy_ignored or --also-copy), and --readme writes a task file
+(TASK.md unless readme_file is configured) that is excluded from git.
+--start-clean skips all of these, so the worktree holds exactly the
+committed tree. --no-checkout adds the worktree without any files, for a
+hook to check out what it needs.
 
-Metadata is written to .wt/worktree.json inside the new worktree.
-If .wt/hooks/post-create exists and is executable, it runs after creation.`,
+If a step fails or create is interrupted, the worktree and branch are
+removed again.
+
+--json and --porcelain (key<TAB>value lines) are for scripts. --switch
+prints only the path, for cd "$(wt create --switch)" or shell integration
+(wt init), and --eval a line for eval "$(wt create --eval)" that sets
+WT_PATH and changes into it. --agent-hint also prints the WT_* variables
+to stderr as WT_HINT: KEY=VALUE lines. --dry-run shows what would be
+created without creating anything.
+
+With --pool <name> --count N, or --count N alone, N worktrees are created
+in one batch under the create lock, named <name>-1 to <name>-N (or with
+generated names). The batch is all or nothing: if one fails, the ones
+created before it are removed again.
+
+With --overwrite-metadata <path>, nothing is created: the existing git
+worktree at <path> gets a fresh .wt/worktree.json with a new id and
+agent_id. Valid metadata is only replaced with --force.`,
 		Exec: func(ctx context.Context, _ io.Reader, stdout, stderr io.Writer, _ []string) error {
-			customName, _ := flags.GetString("name")
-			fromBranch, _ := flags.GetString("from-branch")
-			withChanges, _ := flags.GetBool("with-changes")
-			jsonOutput, _ := flags.GetBool("json")
-			switchOutput, _ := flags.GetBool("switch")
-
-			if jsonOutput && switchOutput {
-				return errSwitchAndJSONMutuallyExclusive
+			var opts createOptions
+
+			// --base wins over the config for this invocation only
+			cfg := cfg
+
+			if flags.Changed("base") {
+				cfg.Base, _ = flags.GetString("base")
+				if strings.TrimSpace(cfg.Base) == "" {
+					return errEmptyBaseOverride
+				}
+			}
+
+			opts.customName, _ = flags.GetString("name")
+			opts.fromBranch, _ = flags.GetString("from-branch")
+
+			if flags.Changed("from") {
+				if flags.Changed("from-branch") {
+					return errFromConflict
+				}
+
+				opts.fromBranch, _ = flags.GetString("from")
+			}
+
+			opts.withChanges, _ = flags.GetBool("with-changes")
+			opts.noCheckout, _ = flags.GetBool("no-checkout")
+			opts.alsoCopy, _ = flags.GetStringArray("also-copy")
+			opts.jsonOutput, _ = flags.GetBool("json")
+			opts.switchOutput, _ = flags.GetBool("switch")
+			opts.porcelain, _ = flags.GetBool("porcelain")
+			opts.readme, _ = flags.GetString("readme")
+			opts.checkoutBase, _ = flags.GetBool("checkout-base")
+			opts.postCreateCmd, _ = flags.GetString("post-create-cmd")
+			opts.dryRun, _ = flags.GetBool("dry-run")
+			opts.setUpstream, _ = flags.GetString("set-upstream")
+			opts.branchDesc, _ = flags.GetString("branch-description")
+			opts.mergeInto, _ = flags.GetString("merge-into")
+			opts.noHooks, _ = flags.GetBool("no-hooks")
+			opts.noHooks = opts.noHooks || cfg.NoHooks
+			opts.lock = flags.Changed("lock")
+
+			if len(opts.alsoCopy) > 0 && !opts.withChanges {
+				return errAlsoCopyRequiresWithChanges
+			}
+
+			for _, pattern := range opts.alsoCopy {
+				patternErr := validateCopyPattern(pattern)
+				if patternErr != nil {
+					return fmt.Errorf("--also-copy: %w", patternErr)
+				}
+			}
+
+			envAssignments, _ := flags.GetStringArray("env")
+
+			hookEnvVars, err := parseEnvAssignments(envAssignments)
+			if err != nil {
+				return err
+			}
+
+			opts.hookEnv = hookEnvVars
+
+			labelAssignments, _ := flags.GetStringArray("label")
+
+			opts.labels, err = parseLabels(labelAssignments)
+			if err != nil {
+				return err
+			}
+
+			if opts.lock {
+				opts.lockReason, _ = flags.GetString("lock")
+				if opts.lockReason == lockWithoutReason {
+					opts.lockReason = ""
+				}
+			}
+
+			if opts.dryRun && opts.switchOutput {
+				return errSwitchAndDryRunMutuallyExclusive
+			}
+
+			if opts.porcelain {
+				for _, conflict := range []string{"json", "switch", "eval", "dry-run"} {
+					if flags.Changed(conflict) {
+						return fmt.Errorf("%w --%s", errPorcelainConflict, conflict)
+					}
+				}
+			}
+
+			if repairPath, _ := flags.GetString("overwrite-metadata"); flags.Changed("overwrite-metadata") {
+				flagErr := checkRepairFlags(flags)
+				if flagErr != nil {
+					return flagErr
+				}
+
+				force, _ := flags.GetBool("force")
+
+				return execRepairMetadata(ctx, stdout, stderr, cfg, fsys, git, opts, repairPath, force)
+			}
+
+			if flags.Changed("force") {
+				return errForceRequiresRepair
+			}
+
+			if flags.Changed("eval") {
+				opts.evalShell, _ = flags.GetString("eval")
+
+				switch opts.evalShell {
+				case evalShellSh, "bash", "zsh", evalShellFish:
+				default:
+					return fmt.Errorf("%w: %q", errInvalidEvalShell, opts.evalShell)
+				}
+
+				for _, conflict := range []string{"json", "switch", "dry-run", "pool", "count"} {
+					if flags.Changed(conflict) {
+						return fmt.Errorf("%w --%s", errEvalConflict, conflict)
+					}
+				}
+			}
+
+			if opts.agentHint, _ = flags.GetBool("agent-hint"); opts.agentHint {
+				for _, conflict := range []string{"dry-run", "pool", "count"} {
+					if flags.Changed(conflict) {
+						return fmt.Errorf("%w --%s", errAgentHintConflict, conflict)
+					}
+				}
+			}
+
+			if opts.noCheckout && opts.withChanges {
+				return fmt.Errorf("%w --with-changes", errNoCheckoutConflict)
+			}
+
+			if opts.startClean, _ = flags.GetBool("start-clean"); opts.startClean {
+				for _, conflict := range []string{"with-changes", "readme"} {
+					if flags.Changed(conflict) {
+						return fmt.Errorf("%w --%s", errStartCleanConflict, conflict)
+					}
+				}
+			}
+
+			pool, _ := flags.GetString("pool")
+			count, _ := flags.GetInt("count")
+
+			if pool == "" && !flags.Changed("count") {
+				return execCreate(ctx, stdout, stderr, cfg, fsys, git, env, opts)
+			}
+
+			if pool != "" {
+				for _, conflict := range []string{"name", "switch", "dry-run", "lock"} {
+					if flags.Changed(conflict) {
+						return fmt.Errorf("%w --%s", errPoolFlagConflict, conflict)
+					}
+				}
+			} else {
+				for _, conflict := range []string{"switch", "dry-run", "lock"} {
+					if flags.Changed(conflict) {
+						return fmt.Errorf("%w --%s", errCountFlagConflict, conflict)
+					}
+				}
+
+				// --name <name> --count N is a pool named <name>
+				pool = opts.customName
+			}
+
+			if count < 1 {
+				return errInvalidPoolCount
 			}
 
-			return execCreate(ctx, stdout, stderr, cfg, fsys, git, env, customName, fromBranch, withChanges, jsonOutput, switchOutput)
+			return execCreatePool(ctx, stdout, stderr, cfg, fsys, git, env, opts, pool, count)
 		},
 	}
 }
 
-// createLockTimeout is the maximum time to wait for the create lock.
-// This is short because we only hold the lock during ID/name generation
-// and metadata write, not during slow operations like hooks.
+// createOptions holds the flag values for a single create invocation.
+type createOptions struct {
+	customName    string
+	fromBranch    string
+	readme        string
+	postCreateCmd string
+	setUpstream   string
+	branchDesc    string
+	mergeInto     string
+	lockReason    string
+	lock          bool
+	noHooks       bool
+	withChanges   bool
+	noCheckout    bool
+	startClean    bool // Skip template_dir; --with-changes and --readme are refused
+	jsonOutput    bool
+	switchOutput  bool
+	porcelain     bool
+	evalShell     string            // Shell to quote the --eval line for ("" without --eval)
+	hookEnv       map[string]string // --env variables for the create hooks
+	labels        map[string]string // --label key=value pairs
+	alsoCopy      []string          // --also-copy globs, added to copy_ignored
+	checkoutBase  bool
+	dryRun        bool
+	agentHint     bool
+
+	// The caller already holds the create lock (set by create --pool, never
+	// a flag), so it is neither taken nor released here.
+	lockHeld bool
+
+	// Check out this existing branch instead of creating one named after
+	// the worktree (set by merge --create-worktree, never a flag). It is
+	// not deleted on rollback.
+	existingBranch string
+}
+
+// lockWithoutReason is the value of a bare --lock, which pflag requires to be
+// non-empty. It cannot be typed as a reason.
+const lockWithoutReason = "\x00"
+
+// createLockTimeout is the default maximum time to wait for the create lock
+// (see the global --lock-timeout). This is short because we only hold the
+// lock during ID/name generation and metadata write, not during slow
+// operations like hooks.
 const createLockTimeout = 5 * time.Second
 
+// errCreateLockTimeout is returned when the create lock cannot be taken in time.
+var errCreateLockTimeout = errors.New("could not acquire the create lock")
+
 // worktreeLockPath returns the path to the lock file for worktree operations.
 // We use a dedicated lock file inside the git common directory to:
 // - Avoid orphan files in the workspace (it's inside .git/)
@@ -75,27 +396,51 @@ func worktreeLockPath(gitCommonDir string) string {
 	return filepath.Join(gitCommonDir, "wt.lock")
 }
 
-// worktreeExcludePattern is the pattern added to .git/info/exclude
-// to prevent .wt/worktree.json from being tracked.
-const worktreeExcludePattern = ".wt/worktree.json"
+// Patterns added to .git/info/exclude to prevent wt's files in a worktree
+// from being tracked: its metadata, and the state wt merge keeps while it runs.
+const (
+	worktreeExcludePattern   = ".wt/worktree.json"
+	mergeStateExcludePattern = ".wt/merge-state.json"
+)
+
+// ensureWorktreeExcluded adds .wt/worktree.json and .wt/merge-state.json to
+// .git/info/exclude if not present. Returns the patterns added by this call,
+// and a warning message if the operation fails (empty string on success).
+func ensureWorktreeExcluded(fsys fs.FS, gitCommonDir string) ([]string, string) {
+	var added []string
+
+	for _, pattern := range []string{worktreeExcludePattern, mergeStateExcludePattern} {
+		patternAdded, warning := ensureExcluded(fsys, gitCommonDir, pattern)
+		if warning != "" {
+			return added, warning
+		}
+
+		if patternAdded {
+			added = append(added, pattern)
+		}
+	}
 
-// ensureWorktreeExcluded adds .wt/worktree.json to .git/info/exclude if not present.
-// Returns a warning message if the operation fails, or empty string on success.
-func ensureWorktreeExcluded(fsys fs.FS, gitCommonDir string) string {
+	return added, ""
+}
+
+// ensureExcluded adds pattern to .git/info/exclude if not present.
+// Returns whether the pattern was added by this call, and a warning message
+// if the operation fails (empty string on success).
+func ensureExcluded(fsys fs.FS, gitCommonDir, pattern string) (bool, string) {
 	excludePath := filepath.Join(gitCommonDir, "info", "exclude")
 
 	// Read existing content
 	content, err := fsys.ReadFile(excludePath)
 	if err != nil {
-		return fmt.Sprintf("warning: could not read %s: %v\nPlease add '%s' to your .gitignore manually.",
-			excludePath, err, worktreeExcludePattern)
+		return false, fmt.Sprintf("warning: could not read %s: %v\nPlease add '%s' to your .gitignore manually.",
+			excludePath, err, pattern)
 	}
 
 	// Check if pattern already exists
 	lines := strings.SplitSeq(string(content), "\n")
 	for line := range lines {
-		if strings.TrimSpace(line) == worktreeExcludePattern {
-			return "" // Already present
+		if strings.TrimSpace(line) == pattern {
+			return false, "" // Already present
 		}
 	}
 
@@ -105,16 +450,65 @@ func ensureWorktreeExcluded(fsys fs.FS, gitCommonDir string) string {
 		newContent += "\n"
 	}
 
-	newContent += worktreeExcludePattern + "\n"
+	newContent += pattern + "\n"
 
 	// Write back
 	err = fsys.WriteFile(excludePath, []byte(newContent), 0o644)
 	if err != nil {
-		return fmt.Sprintf("warning: could not update %s: %v\nPlease add '%s' to your .gitignore manually.",
-			excludePath, err, worktreeExcludePattern)
+		return false, fmt.Sprintf("warning: could not update %s: %v\nPlease add '%s' to your .gitignore manually.",
+			excludePath, err, pattern)
 	}
 
-	return ""
+	return true, ""
+}
+
+// removeExcluded removes the lines equal to pattern from .git/info/exclude,
+// undoing ensureExcluded.
+func removeExcluded(fsys fs.FS, gitCommonDir, pattern string) error {
+	excludePath := filepath.Join(gitCommonDir, "info", "exclude")
+
+	content, err := fsys.ReadFile(excludePath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", excludePath, err)
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	kept := slices.DeleteFunc(lines, func(line string) bool {
+		return strings.TrimSpace(line) == pattern
+	})
+
+	err = fsys.WriteFile(excludePath, []byte(strings.Join(kept, "")), 0o644)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", excludePath, err)
+	}
+
+	return nil
+}
+
+// revertWorktreeExclude removes the exclude lines (patterns) a create that
+// failed added, but only if no managed worktree exists (checked under the
+// create lock, which the caller may already hold), so another create's
+// metadata or task file never becomes trackable. Best effort: on any error
+// the lines are kept.
+func revertWorktreeExclude(ctx context.Context, fsys fs.FS, gitCommonDir, baseDir string, patterns []string, lockHeld bool) {
+	if !lockHeld {
+		// Cleanup, so it waits the default time even under --lock-timeout
+		lock, err := acquireCreateLock(ctx, fsys, gitCommonDir, createLockTimeout)
+		if err != nil {
+			return
+		}
+
+		defer func() { _ = lock.Close() }()
+	}
+
+	existing, err := findWorktrees(fsys, baseDir)
+	if err != nil || len(existing) > 0 {
+		return
+	}
+
+	for _, pattern := range patterns {
+		_ = removeExcluded(fsys, gitCommonDir, pattern)
+	}
 }
 
 func execCreate(
@@ -124,248 +518,1330 @@ func execCreate(
 	fsys fs.FS,
 	git *Git,
 	env map[string]string,
-	customName, fromBranch string,
-	withChanges, jsonOutput, switchOutput bool,
+	opts createOptions,
 ) error {
-	// 1. Verify git repository and get main repo root
-	// MainRepoRoot returns the main repo's root even when inside a worktree,
-	// ensuring all worktrees share the same base directory and lock file.
-	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
-	if err != nil {
+	created, err := createWorktree(ctx, progressWriter(stdout, stderr, opts), stderr, cfg, fsys, git, env, opts)
+	if err != nil || created == nil {
 		return err
 	}
 
-	// 2. Get git common directory (shared across all worktrees) for locking
-	gitCommonDir, err := git.GitCommonDir(ctx, cfg.EffectiveCwd)
-	if err != nil {
-		return fmt.Errorf("cannot determine git directory: %w", err)
+	if opts.agentHint {
+		printAgentHint(stderr, created)
 	}
 
-	// 2a. Ensure .wt/worktree.json is excluded from git tracking
-	if warning := ensureWorktreeExcluded(fsys, gitCommonDir); warning != "" {
-		fprintln(stderr, warning)
-	}
+	// 14. Print success output
+	switch {
+	case opts.switchOutput && !opts.jsonOutput:
+		fprintln(stdout, created.path)
 
-	// 3. Resolve base branch
-	baseBranch := fromBranch
-	if baseBranch == "" {
-		baseBranch, err = git.CurrentBranch(ctx, cfg.EffectiveCwd)
-		if err != nil {
-			return fmt.Errorf("getting current branch (use --from-branch if in detached HEAD): %w", err)
+		return nil
+	case opts.evalShell != "":
+		fprintln(stdout, createEvalLine(opts.evalShell, created.path))
+
+		return nil
+	case opts.porcelain:
+		printWorktreePorcelain(stdout, "created", created)
+
+		return nil
+	case opts.jsonOutput:
+		out := created.jsonOutput()
+		if opts.switchOutput {
+			out.SwitchPath = created.path
 		}
-	}
 
-	// 4. Create base directory if needed (must exist before locking)
-	baseDir := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
 
-	err = fsys.MkdirAll(baseDir, 0o750)
-	if err != nil {
-		return fmt.Errorf("cannot create base directory: %w", err)
+		encodeErr := enc.Encode(out)
+		if encodeErr != nil {
+			return fmt.Errorf("encoding JSON: %w", encodeErr)
+		}
+
+		return nil
+	default:
+		printCreated(informational(cfg, stdout), created)
+
+		return nil
 	}
+}
 
-	// 5. Acquire exclusive lock for ID generation
-	// This prevents race conditions when multiple processes create worktrees
-	locker := fs.NewLocker(fsys)
-	lockPath := worktreeLockPath(gitCommonDir)
+// agentHintKeys are the hookEnv variables create --agent-hint prints, in
+// output order.
+var agentHintKeys = []string{"WT_ID", "WT_AGENT_ID", "WT_NAME", "WT_PATH", "WT_BASE_BRANCH", "WT_REPO_ROOT"}
 
-	lockCtx, lockCancel := context.WithTimeout(ctx, createLockTimeout)
-	defer lockCancel()
+// printAgentHint prints the WT_HINT lines of create --agent-hint.
+func printAgentHint(stderr io.Writer, created *createdWorktree) {
+	vars := hookEnv(created.info, created.path, created.repoRoot)
 
-	lock, err := locker.LockWithTimeout(lockCtx, lockPath)
-	if err != nil {
-		return fmt.Errorf("acquiring create lock (another wt process may be running): %w", err)
+	for _, key := range agentHintKeys {
+		fprintf(stderr, "WT_HINT: %s=%s\n", key, vars[key])
 	}
+}
 
-	// Safety net - Close is idempotent; we release early after metadata write
-	// but this handles cleanup on early returns
-	defer func() { _ = lock.Close() }()
+// Shells create --eval quotes for; bash and zsh are treated as sh.
+const (
+	evalShellSh   = "sh"
+	evalShellFish = "fish"
+)
 
-	// 6. Find existing worktrees (safe now, we hold the lock)
-	existing, err := findWorktrees(fsys, baseDir)
-	if err != nil {
-		return fmt.Errorf("scanning existing worktrees: %w", err)
-	}
+// createEvalLine returns the shell line printed by create --eval: export
+// WT_PATH and cd into path.
+func createEvalLine(shell, path string) string {
+	if shell == evalShellFish {
+		quoted := fishQuote(path)
 
-	// Calculate next ID
-	nextID := 1
-	for _, wt := range existing {
-		if wt.ID >= nextID {
-			nextID = wt.ID + 1
-		}
+		return "set -gx WT_PATH " + quoted + "; cd " + quoted
 	}
 
-	// 7. Generate agent_id
-	existingNames := getExistingNames(existing)
+	quoted := shQuote(path)
+
+	return "export WT_PATH=" + quoted + "; cd " + quoted
+}
+
+// shQuote single-quotes s for POSIX shells. A quote in s closes the quoting,
+// is backslash-escaped and reopens it.
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote single-quotes s for fish, where \ and ' are backslash-escaped
+// inside single quotes.
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
 
-	agentID, err := generateAgentID(existingNames)
+// execCreatePool creates count worktrees named <pool>-1 to <pool>-count,
+// or with generated names if pool is empty, while holding the create lock,
+// so no other create can take IDs in between. If one fails, the ones
+// created before it are removed again.
+func execCreatePool(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	env map[string]string,
+	opts createOptions,
+	pool string,
+	count int,
+) error {
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
 	if err != nil {
 		return err
 	}
 
-	// 8. Set name
-	name := customName
-	if name == "" {
-		name = agentID
-	}
-
-	// Check name collision (in case --name was provided)
-	if slices.Contains(existingNames, name) {
-		return fmt.Errorf("%w: %s", ErrNameAlreadyInUse, name)
+	gitCommonDir, err := git.GitCommonDir(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return fmt.Errorf("cannot determine git directory: %w", err)
 	}
 
-	// 9. Resolve worktree path
-	wtPath := resolveWorktreePath(cfg, mainRepoRoot, name)
-
-	// 10. git worktree add -b <name> <path> <base-branch>
-	err = git.WorktreeAdd(ctx, mainRepoRoot, wtPath, name, baseBranch)
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
 	if err != nil {
 		return err
 	}
 
-	// 11. Write .wt/worktree.json metadata
-	info := &WorktreeInfo{
-		Name:       name,
-		AgentID:    agentID,
-		ID:         nextID,
-		BaseBranch: baseBranch,
-		Created:    time.Now().UTC(),
+	err = fsys.MkdirAll(baseDir, 0o750)
+	if err != nil {
+		return fmt.Errorf("cannot create base directory: %w", err)
 	}
 
-	err = writeWorktreeInfo(fsys, wtPath, info)
+	lock, err := acquireCreateLock(ctx, fsys, gitCommonDir, cfg.LockTimeout)
 	if err != nil {
-		// Rollback: remove worktree
-		rmErr := git.WorktreeRemove(ctx, mainRepoRoot, wtPath, true)
-		brErr := git.BranchDelete(ctx, mainRepoRoot, name, true)
-
-		return errors.Join(
-			fmt.Errorf("writing worktree metadata: %w", err),
-			rmErr,
-			brErr,
-		)
+		return err
 	}
 
-	// Release lock early - only needed for ID/name generation.
-	// Close is idempotent; defer above handles cleanup on early returns.
-	_ = lock.Close()
+	defer func() { _ = lock.Close() }()
 
-	// 12. If --with-changes: copy uncommitted changes
-	if withChanges {
-		err = copyUncommittedChanges(ctx, fsys, git, cfg.EffectiveCwd, wtPath)
-		if err != nil {
-			// Rollback: remove worktree and delete branch
-			rmErr := git.WorktreeRemove(ctx, mainRepoRoot, wtPath, true)
-			brErr := git.BranchDelete(ctx, mainRepoRoot, name, true)
+	// An empty name makes createWorktree generate one
+	names := make([]string, count)
 
-			return errors.Join(
-				fmt.Errorf("copying uncommitted changes: %w", err),
-				rmErr,
-				brErr,
-			)
+	if pool != "" {
+		if cfg.NameSlug != nil {
+			pool, err = slugifyName(pool, *cfg.NameSlug)
+			if err != nil {
+				return err
+			}
 		}
-	}
 
-	// 13. Run post-create hook
-	hookRunner := NewHookRunner(fsys, mainRepoRoot, env, stdout, stderr)
+		// Check every name up front, so a taken name fails before anything exists
+		existing, scanErr := findWorktrees(fsys, baseDir)
+		if scanErr != nil {
+			return fmt.Errorf("scanning existing worktrees: %w", scanErr)
+		}
 
-	err = hookRunner.RunPostCreate(ctx, info, wtPath)
-	if err != nil {
-		// Rollback: remove worktree and delete branch
-		rmErr := git.WorktreeRemove(ctx, mainRepoRoot, wtPath, true)
-		brErr := git.BranchDelete(ctx, mainRepoRoot, name, true)
+		existingNames := getExistingNames(existing)
 
-		return errors.Join(
-			fmt.Errorf("post-create hook failed (check hook output above): %w", err),
-			rmErr,
-			brErr,
-		)
+		for i := range names {
+			names[i] = fmt.Sprintf("%s-%d", pool, i+1)
+			if slices.Contains(existingNames, names[i]) {
+				return fmt.Errorf("%w: %s", ErrNameAlreadyInUse, names[i])
+			}
+		}
 	}
 
-	// 14. Print success output
-	if switchOutput {
-		fprintln(stdout, wtPath)
-
-		return nil
-	}
+	created := make([]*createdWorktree, 0, count)
 
-	if jsonOutput {
-		return outputCreateJSON(stdout, name, agentID, nextID, wtPath, baseBranch)
-	}
+	for i, name := range names {
+		wtOpts := opts
+		wtOpts.customName = name
+		wtOpts.lockHeld = true
 
-	fprintln(stdout, "Created worktree:")
-	fprintf(stdout, "  name:        %s\n", name)
-	fprintf(stdout, "  agent_id:    %s\n", agentID)
-	fprintf(stdout, "  id:          %d\n", nextID)
-	fprintf(stdout, "  path:        %s\n", wtPath)
-	fprintf(stdout, "  branch:      %s\n", name)
-	fprintf(stdout, "  from:        %s\n", baseBranch)
+		wt, createErr := createWorktree(ctx, progressWriter(stdout, stderr, opts), stderr, cfg, fsys, git, env, wtOpts)
+		if createErr != nil {
+			failed := fmt.Errorf("creating pool worktree %s (pool rolled back): %w", name, createErr)
+			if name == "" {
+				failed = fmt.Errorf("creating worktree %d of %d (batch rolled back): %w", i+1, count, createErr)
+			}
 
-	return nil
-}
+			// createWorktree rolled back its own worktree; remove the rest
+			return errors.Join(failed, rollbackPool(context.WithoutCancel(ctx), fsys, git, mainRepoRoot, gitCommonDir, baseDir, created))
+		}
 
-// copyUncommittedChanges copies staged, unstaged, and untracked files from srcDir to dstDir.
-// It respects .gitignore for untracked files.
-func copyUncommittedChanges(ctx context.Context, fsys fs.FS, git *Git, srcDir, dstDir string) error {
-	// Get all uncommitted files (staged, unstaged, and untracked)
-	files, err := git.ChangedFiles(ctx, srcDir)
-	if err != nil {
-		return fmt.Errorf("getting changed files: %w", err)
+		created = append(created, wt)
 	}
 
-	// Copy each file
-	for _, relPath := range files {
-		srcPath := filepath.Join(srcDir, relPath)
-		dstPath := filepath.Join(dstDir, relPath)
+	_ = lock.Close()
 
-		// Read source file
-		data, readErr := fsys.ReadFile(srcPath)
-		if readErr != nil {
-			// File might have been deleted (shown in diff but gone), skip silently
-			continue
+	if opts.jsonOutput {
+		results := make([]jsonCreateOutput, 0, len(created))
+		for _, wt := range created {
+			results = append(results, wt.jsonOutput())
 		}
 
-		// Create parent directories
-		mkdirErr := fsys.MkdirAll(filepath.Dir(dstPath), 0o755)
-		if mkdirErr != nil {
-			return fmt.Errorf("creating directory for %s: %w", relPath, mkdirErr)
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		encodeErr := enc.Encode(results)
+		if encodeErr != nil {
+			return fmt.Errorf("encoding JSON: %w", encodeErr)
 		}
 
-		// Write to destination
-		writeErr := fsys.WriteFile(dstPath, data, 0o644)
-		if writeErr != nil {
-			return fmt.Errorf("writing %s: %w", relPath, writeErr)
+		return nil
+	}
+
+	for _, wt := range created {
+		if opts.porcelain {
+			printWorktreePorcelain(stdout, "created", wt)
+		} else {
+			printCreated(informational(cfg, stdout), wt)
 		}
 	}
 
 	return nil
 }
 
-// jsonCreateOutput is the JSON output format for the create command.
-type jsonCreateOutput struct {
-	Name    string `json:"name"`
-	AgentID string `json:"agent_id"`
-	ID      int    `json:"id"`
-	Path    string `json:"path"`
-	Branch  string `json:"branch"`
-	From    string `json:"from"`
+// progressWriter returns where createWorktree writes hook output: stdout,
+// or stderr with --porcelain, --switch or --eval so stdout has only the
+// lines a script parses (or a shell wrapper evaluates).
+func progressWriter(stdout, stderr io.Writer, opts createOptions) io.Writer {
+	if opts.porcelain || opts.switchOutput || opts.evalShell != "" {
+		return stderr
+	}
+
+	return stdout
 }
 
-func outputCreateJSON(output io.Writer, name, agentID string, id int, path, from string) error {
-	result := jsonCreateOutput{
-		Name:    name,
-		AgentID: agentID,
-		ID:      id,
-		Path:    path,
-		Branch:  name,
-		From:    from,
+// rollbackPool removes the worktrees and branches of a partially created
+// pool, newest first, and the exclude lines they added. The caller holds the
+// create lock.
+func rollbackPool(ctx context.Context, fsys fs.FS, git *Git, mainRepoRoot, gitCommonDir, baseDir string, created []*createdWorktree) error {
+	var errs []error
+
+	for _, wt := range slices.Backward(created) {
+		errs = append(errs,
+			git.WorktreeRemove(ctx, mainRepoRoot, wt.path, true),
+			git.BranchDelete(ctx, mainRepoRoot, wt.branch, true),
+		)
 	}
 
-	enc := json.NewEncoder(output)
-	enc.SetIndent("", "  ")
+	var excludesAdded []string
+	for _, wt := range created {
+		excludesAdded = append(excludesAdded, wt.excludesAdded...)
+	}
 
-	encodeErr := enc.Encode(result)
-	if encodeErr != nil {
-		return fmt.Errorf("encoding JSON: %w", encodeErr)
+	if len(excludesAdded) > 0 {
+		revertWorktreeExclude(ctx, fsys, gitCommonDir, baseDir, excludesAdded, true)
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// createdWorktree is a worktree created by createWorktree.
+type createdWorktree struct {
+	info              *WorktreeInfo
+	path              string
+	repoRoot          string
+	branch            string
+	hookRan           bool
+	hookSkippedReason string
+	excludesAdded     []string // The .git/info/exclude lines this create added
+}
+
+// createWorktree runs steps 0-13 of create. It returns nil (and no error)
+// for --dry-run, after printing the plan.
+func createWorktree(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	env map[string]string,
+	opts createOptions,
+) (*createdWorktree, error) {
+	// 0. Slugify --name if name_slug is configured
+	if opts.customName != "" && cfg.NameSlug != nil {
+		slug, err := slugifyName(opts.customName, *cfg.NameSlug)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.customName = slug
+	}
+
+	if opts.customName != "" {
+		err := validateWorktreeName(opts.customName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 0a. The post_create_cmd config key, unless --post-create-cmd or --no-hooks
+	if opts.postCreateCmd == "" && !opts.noHooks {
+		opts.postCreateCmd = cfg.PostCreateCmd
+	}
+
+	// 1. Verify git repository and get main repo root
+	// MainRepoRoot returns the main repo's root even when inside a worktree,
+	// ensuring all worktrees share the same base directory and lock file.
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1a. Remember the worktree create runs from (if any) as the parent, so
+	// remove can find its children
+	parentID := 0
+
+	if parentRoot, findErr := findWorktreeRoot(fsys, cfg.EffectiveCwd); findErr == nil {
+		if parentInfo, readErr := readWorktreeInfo(fsys, parentRoot); readErr == nil {
+			parentID = parentInfo.ID
+		}
+	}
+
+	// 2. Get git common directory (shared across all worktrees) for locking
+	gitCommonDir, err := git.GitCommonDir(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine git directory: %w", err)
+	}
+
+	// 3. Resolve base branch
+	baseBranch := opts.fromBranch
+	if baseBranch == "" {
+		baseBranch, err = git.CurrentBranch(ctx, cfg.EffectiveCwd)
+		if err != nil {
+			return nil, fmt.Errorf("getting current branch (use --from-branch if in detached HEAD): %w", err)
+		}
+	} else if validateErr := validateBaseBranch(ctx, git, mainRepoRoot, baseBranch); validateErr != nil {
+		return nil, validateErr
+	}
+
+	// Record a branch or tag name for the base, or the short SHA when
+	// --from-branch is a bare commit like HEAD~2
+	recordedBase := baseBranch
+	if opts.fromBranch != "" {
+		recordedBase, err = recordedBaseName(ctx, git, mainRepoRoot, baseBranch)
+		if err != nil {
+			return nil, fmt.Errorf("resolving base branch: %w", err)
+		}
+	}
+
+	// 3a. If --checkout-base: bring the base branch up to date with its upstream
+	if opts.checkoutBase && !opts.dryRun {
+		if warning := updateBaseBranch(ctx, git, mainRepoRoot, baseBranch); warning != "" {
+			fprintln(stderr, warning)
+		}
+	}
+
+	// 3b. Resolve the commit the worktree will start from
+	startCommit, err := git.RevParse(ctx, mainRepoRoot, baseBranch)
+	if err != nil {
+		return nil, fmt.Errorf("resolving base branch: %w", err)
+	}
+
+	// 3c. If --set-upstream: the remote must exist before anything is created
+	if opts.setUpstream != "" {
+		exists, remoteErr := git.RemoteExists(ctx, mainRepoRoot, opts.setUpstream)
+		if remoteErr != nil {
+			return nil, remoteErr
+		}
+
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", errRemoteNotFound, opts.setUpstream)
+		}
+	}
+
+	// 4. Create base directory if needed (must exist before locking)
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	hooksDir, err := resolveHooksDir(cfg, mainRepoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generated names must not reuse a branch (e.g. one kept by wt remove);
+	// the branch checked is the one create would make, with branch_prefix
+	branchExists := func(name string) bool {
+		exists, _ := git.BranchExists(ctx, mainRepoRoot, branchForName(cfg, name))
+
+		return exists
+	}
+
+	// 4a. If --dry-run: report the plan from an unlocked scan and stop
+	if opts.dryRun {
+		name, agentID, nextID, allocErr := allocateWorktree(fsys, baseDir, opts.customName, cfg.Naming, branchExists)
+		if allocErr != nil {
+			return nil, allocErr
+		}
+
+		plan := jsonCreatePlan{
+			Name:         name,
+			AgentID:      agentID,
+			ID:           nextID,
+			Path:         filepath.Join(baseDir, name),
+			Branch:       branchForName(cfg, name),
+			From:         recordedBase,
+			StartCommit:  startCommit,
+			WouldRunHook: (!opts.noHooks && hookExists(fsys, hooksDir, "post-create")) || opts.postCreateCmd != "",
+		}
+
+		return nil, outputCreatePlan(stdout, &plan, opts.jsonOutput)
+	}
+
+	err = fsys.MkdirAll(baseDir, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create base directory: %w", err)
+	}
+
+	// If this create adds exclude lines (5a, 12a) but then fails, remove
+	// them again. Deferred before the lock, so it runs after the lock is
+	// released and can take it itself.
+	var excludesAdded []string
+
+	succeeded := false
+
+	defer func() {
+		if len(excludesAdded) > 0 && !succeeded {
+			revertWorktreeExclude(context.WithoutCancel(ctx), fsys, gitCommonDir, baseDir, excludesAdded, opts.lockHeld)
+		}
+	}()
+
+	hookRunner := NewHookRunner(fsys, mainRepoRoot, hooksDir, withEnvOverrides(env, opts.hookEnv), informational(cfg, stdout), stderr)
+
+	// 4b. Run pre-create hook (unless --no-hooks) before taking the lock, so
+	// a slow hook does not hold up other creates. It gets the name of an
+	// unlocked scan, which the allocation under the lock then keeps (failing
+	// if another create took it meanwhile). Nothing exists yet, so a failing
+	// hook needs no rollback
+	preCreate := !opts.noHooks && hooksDir != ""
+	if preCreate {
+		// Not executable still goes to RunPreCreate, which reports it
+		_, statErr := fsys.Stat(filepath.Join(hooksDir, "pre-create"))
+		preCreate = !errors.Is(statErr, os.ErrNotExist)
+	}
+	claimName := opts.customName
+
+	if preCreate {
+		claimName, _, _, err = allocateWorktree(fsys, baseDir, opts.customName, cfg.Naming, branchExists)
+		if err != nil {
+			return nil, err
+		}
+
+		err = hookRunner.RunPreCreate(ctx, claimName, recordedBase, cfg.EffectiveCwd)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errPreCreateHookAborted, err)
+		}
+	}
+
+	// 5. Acquire exclusive lock for ID generation (unless the caller holds it)
+	// This prevents race conditions when multiple processes create worktrees
+	releaseLock := func() {}
+
+	if !opts.lockHeld {
+		lock, lockErr := acquireCreateLock(ctx, fsys, gitCommonDir, cfg.LockTimeout)
+		if lockErr != nil {
+			return nil, lockErr
+		}
+
+		releaseLock = func() { _ = lock.Close() }
+
+		// Safety net - Close is idempotent; we release early after metadata
+		// write but this handles cleanup on early returns
+		defer releaseLock()
+	}
+
+	// 5a. Ensure .wt/worktree.json is excluded from git tracking. Done under
+	// the lock, so a concurrent failed create cannot revert it after this
+	var warning string
+
+	excludesAdded, warning = ensureWorktreeExcluded(fsys, gitCommonDir)
+	if warning != "" {
+		fprintln(stderr, warning)
+	}
+
+	// 6-8. Allocate ID, agent_id and name (safe now, we hold the lock)
+	name, agentID, nextID, err := allocateWorktree(fsys, baseDir, claimName, cfg.Naming, branchExists)
+	if err != nil {
+		return nil, err
+	}
+
+	// A generated name the pre-create hook saw is the agent_id too
+	if preCreate && opts.customName == "" {
+		agentID = name
+	}
+
+	// 9. Resolve worktree path
+	wtPath, err := resolveWorktreePath(cfg, mainRepoRoot, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// 10. git worktree add -b <branch_prefix><name> <path> <start-commit>:
+	// the commit recorded as start_commit, even if the base branch moved since
+	branch := branchForName(cfg, name)
+
+	// Only a branch this run creates may be deleted on rollback. '-b' fails
+	// for an existing one (e.g. --name of a branch without a worktree), and
+	// if the check itself fails the branch is left alone too
+	createsBranch := false
+
+	if opts.existingBranch != "" {
+		branch = opts.existingBranch
+		err = git.WorktreeAddExisting(ctx, mainRepoRoot, wtPath, branch)
+	} else {
+		existed, existsErr := git.BranchExists(ctx, mainRepoRoot, branch)
+		createsBranch = existsErr == nil && !existed
+
+		err = git.WorktreeAdd(ctx, mainRepoRoot, wtPath, branch, startCommit, opts.noCheckout)
+	}
+
+	// Rollbacks below must still run after ctx is cancelled by a signal,
+	// otherwise an interrupted create leaves a half-built worktree behind
+	rollbackCtx := context.WithoutCancel(ctx)
+
+	// rollback removes the worktree and the branch this run created, and
+	// returns err joined with whatever failed while doing so
+	rollback := func(err error) error {
+		rmErr := git.WorktreeRemove(rollbackCtx, mainRepoRoot, wtPath, true)
+
+		var brErr error
+		if createsBranch {
+			brErr = git.BranchDelete(rollbackCtx, mainRepoRoot, branch, true)
+		}
+
+		return errors.Join(err, rmErr, brErr)
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			// git may have been killed halfway; clean up whatever it created
+			_ = rollback(nil)
+			_ = git.WorktreePrune(rollbackCtx, mainRepoRoot)
+		}
+
+		return nil, err
+	}
+
+	// 10a. Restrict the checkout if sparse_checkout is configured
+	if len(cfg.SparseCheckout) > 0 {
+		err = git.SparseCheckoutSet(ctx, wtPath, cfg.SparseCheckout)
+		if err != nil {
+			return nil, rollback(err)
+		}
+	}
+
+	// 10b. If --set-upstream: point the new branch at <remote>/<branch>
+	upstream := ""
+
+	if opts.setUpstream != "" {
+		err = git.SetBranchUpstream(ctx, mainRepoRoot, branch, opts.setUpstream)
+		if err != nil {
+			return nil, rollback(err)
+		}
+
+		upstream = opts.setUpstream + "/" + branch
+	}
+
+	// 10c. If --branch-description: describe the branch in git config
+	if opts.branchDesc != "" {
+		err = git.SetBranchDescription(ctx, mainRepoRoot, branch, opts.branchDesc)
+		if err != nil {
+			return nil, rollback(err)
+		}
+	}
+
+	// 11. Write .wt/worktree.json metadata
+	info := &WorktreeInfo{
+		Name:        name,
+		AgentID:     agentID,
+		ID:          nextID,
+		BaseBranch:  recordedBase,
+		StartCommit: startCommit,
+		Upstream:    upstream,
+		Locked:      opts.lock,
+		ParentID:    parentID,
+		Source:      cfg.EffectiveCwd,
+		MergeInto:   opts.mergeInto,
+		Created:     time.Now().UTC(),
+	}
+
+	if opts.existingBranch == "" && branch != name {
+		info.Branch = branch
+	}
+
+	if len(opts.labels) > 0 {
+		info.Labels = opts.labels
+	}
+
+	err = writeWorktreeInfo(fsys, wtPath, info)
+	if err != nil {
+		return nil, rollback(fmt.Errorf("writing worktree metadata: %w", err))
+	}
+
+	// Release lock early - only needed for ID/name generation.
+	// Close is idempotent; defer above handles cleanup on early returns.
+	releaseLock()
+
+	// 11a. If template_dir is configured (and not --start-clean): copy its
+	// contents into the worktree
+	if cfg.TemplateDir != "" && !opts.startClean {
+		err = copyTemplateDir(fsys, cfg.TemplateDir, mainRepoRoot, wtPath)
+		if err != nil {
+			return nil, rollback(fmt.Errorf("copying template_dir: %w", err))
+		}
+	}
+
+	// 12. If --with-changes: copy uncommitted changes
+	if opts.withChanges {
+		err = copyUncommittedChanges(ctx, fsys, git, cfg.EffectiveCwd, wtPath)
+		if err != nil {
+			return nil, rollback(fmt.Errorf("copying uncommitted changes: %w", err))
+		}
+
+		err = copyIgnoredFiles(ctx, stderr, fsys, git, cfg.EffectiveCwd, wtPath, slices.Concat(cfg.CopyIgnored, opts.alsoCopy))
+		if err != nil {
+			return nil, rollback(fmt.Errorf("copying ignored files: %w", err))
+		}
+	}
+
+	// 12a. If --readme: write the task file before the hook sees the worktree
+	if opts.readme != "" {
+		var readmeExclude string
+
+		readmeExclude, err = writeTaskReadme(fsys, gitCommonDir, cfg, wtPath, opts.readme, stderr)
+		if readmeExclude != "" {
+			excludesAdded = append(excludesAdded, readmeExclude)
+		}
+
+		if err != nil {
+			return nil, rollback(fmt.Errorf("writing task readme: %w", err))
+		}
+	}
+
+	// 13. Run post-create hook (unless --no-hooks)
+	hookRan := false
+
+	if !opts.noHooks {
+		hookRan, err = hookRunner.RunPostCreate(ctx, info, wtPath)
+	}
+
+	if err != nil {
+		return nil, rollback(fmt.Errorf("post-create hook failed (check hook output above): %w", err))
+	}
+
+	var hookSkippedReason string
+
+	switch {
+	case opts.noHooks:
+		hookSkippedReason = hookSkippedNoHooksFlag
+	case !hookRan:
+		hookSkippedReason = hookSkippedNoHook
+	}
+
+	// 13a. Run --post-create-cmd as an inline post-create hook
+	if opts.postCreateCmd != "" {
+		err = hookRunner.RunPostCreateCmd(ctx, info, wtPath, opts.postCreateCmd)
+		if err != nil {
+			return nil, rollback(fmt.Errorf("post-create command failed (check output above): %w", err))
+		}
+	}
+
+	// 13b. Interrupted before completion (e.g. a hook exited cleanly on SIGTERM)
+	if ctx.Err() != nil {
+		return nil, rollback(fmt.Errorf("%w: %w", errCreateInterrupted, ctx.Err()))
+	}
+
+	// 13c. If --lock: lock last, since a locked worktree can't be rolled back
+	if opts.lock {
+		err = git.WorktreeLock(ctx, mainRepoRoot, wtPath, opts.lockReason)
+		if err != nil {
+			return nil, rollback(err)
+		}
+	}
+
+	succeeded = true
+
+	return &createdWorktree{
+		info:              info,
+		path:              wtPath,
+		repoRoot:          mainRepoRoot,
+		branch:            branch,
+		hookRan:           hookRan,
+		hookSkippedReason: hookSkippedReason,
+		excludesAdded:     excludesAdded,
+	}, nil
+}
+
+// envKeyPattern matches a valid environment variable name.
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseEnvAssignments parses --env KEY=VALUE values. The value may be empty
+// or contain "="; a later assignment to the same key wins.
+func parseEnvAssignments(assignments []string) (map[string]string, error) {
+	vars := make(map[string]string, len(assignments))
+
+	for _, assignment := range assignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok || !envKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("%w: %q", errInvalidEnvAssignment, assignment)
+		}
+
+		vars[key] = value
+	}
+
+	return vars, nil
+}
+
+// withEnvOverrides returns env with overrides applied, leaving env itself
+// unchanged.
+func withEnvOverrides(env, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return env
+	}
+
+	merged := make(map[string]string, len(env)+len(overrides))
+	maps.Copy(merged, env)
+	maps.Copy(merged, overrides)
+
+	return merged
+}
+
+// acquireCreateLock takes the exclusive create lock, waiting at most
+// timeout for another wt process to release it (0 tries only once). The
+// lock is an flock, so a holder that crashed no longer blocks it.
+func acquireCreateLock(ctx context.Context, fsys fs.FS, gitCommonDir string, timeout time.Duration) (*fs.Lock, error) {
+	locker := fs.NewLocker(fsys)
+
+	lockCtx, lockCancel := context.WithTimeout(ctx, timeout)
+	defer lockCancel()
+
+	lock, err := locker.LockWithTimeout(lockCtx, worktreeLockPath(gitCommonDir))
+	if err != nil {
+		// Our own deadline, not an interrupt of the whole command
+		if ctx.Err() == nil && errors.Is(lockCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w within %s (another wt process may be running; see --lock-timeout)", errCreateLockTimeout, timeout)
+		}
+
+		return nil, fmt.Errorf("acquiring create lock (another wt process may be running): %w", err)
+	}
+
+	return lock, nil
+}
+
+// printCreated prints the text output of a successful create.
+func printCreated(stdout io.Writer, created *createdWorktree) {
+	printWorktreeSummary(stdout, "Created worktree:", created)
+}
+
+// printWorktreeSummary prints header followed by the created worktree's
+// fields.
+func printWorktreeSummary(stdout io.Writer, header string, created *createdWorktree) {
+	info := created.info
+
+	fprintln(stdout, header)
+	fprintf(stdout, "  name:        %s\n", info.Name)
+	fprintf(stdout, "  agent_id:    %s\n", info.AgentID)
+	fprintf(stdout, "  id:          %d\n", info.ID)
+	fprintf(stdout, "  path:        %s\n", created.path)
+	fprintf(stdout, "  branch:      %s\n", created.branch)
+	fprintf(stdout, "  from:        %s\n", info.BaseBranch)
+
+	if info.Upstream != "" {
+		fprintf(stdout, "  upstream:    %s\n", info.Upstream)
+	}
+
+	if info.Locked {
+		fprintf(stdout, "  locked:      yes\n")
+	}
+}
+
+// printWorktreePorcelain prints the create --porcelain record.
+func printWorktreePorcelain(stdout io.Writer, status string, created *createdWorktree) {
+	info := created.info
+
+	printPorcelain(stdout,
+		"status", status,
+		"name", info.Name,
+		"agent_id", info.AgentID,
+		"id", strconv.Itoa(info.ID),
+		"path", created.path,
+		"branch", created.branch,
+		"from", info.BaseBranch,
+	)
+
+	if info.Upstream != "" {
+		printPorcelain(stdout, "upstream", info.Upstream)
+	}
+
+	printPorcelain(stdout, "locked", strconv.FormatBool(info.Locked))
+}
+
+// validateBaseBranch checks that --from-branch names a local branch or another
+// resolvable revision (tag, remote branch, commit) before anything is created,
+// so a typo fails with near-match suggestions instead of a git worktree error.
+func validateBaseBranch(ctx context.Context, git *Git, mainRepoRoot, baseBranch string) error {
+	exists, err := git.BranchExists(ctx, mainRepoRoot, baseBranch)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	if _, revErr := git.RevParse(ctx, mainRepoRoot, baseBranch); revErr == nil {
+		return nil
+	}
+
+	branches, _ := git.ListBranches(ctx, mainRepoRoot)
+	if suggestions := similarBranches(baseBranch, branches); len(suggestions) > 0 {
+		return fmt.Errorf("base branch '%s' %w (did you mean: %s?)", baseBranch, errBaseBranchNotExist, strings.Join(suggestions, ", "))
+	}
+
+	return fmt.Errorf("base branch '%s' %w (see git branch --list)", baseBranch, errBaseBranchNotExist)
+}
+
+// recordedBaseName returns what to record as base_branch for the --from-branch
+// revision rev: the branch or tag name it refers to, or else the short SHA
+// of the commit it resolves to.
+func recordedBaseName(ctx context.Context, git *Git, mainRepoRoot, rev string) (string, error) {
+	name, err := git.RevName(ctx, mainRepoRoot, rev)
+	if err != nil {
+		return "", err
+	}
+
+	if name != "" {
+		return name, nil
+	}
+
+	return git.ShortSHA(ctx, mainRepoRoot, rev)
+}
+
+// maxBranchSuggestions caps how many near-matches validateBaseBranch lists.
+const maxBranchSuggestions = 3
+
+// similarBranches returns the branches that look like a misspelling of name:
+// one contains the other (case-insensitively), or they are a few edits apart.
+// Closest matches come first.
+func similarBranches(name string, branches []string) []string {
+	type candidate struct {
+		branch   string
+		distance int
+	}
+
+	lower := strings.ToLower(name)
+	maxDistance := max(2, len(name)/3)
+
+	var matches []candidate
+
+	for _, branch := range branches {
+		lowerBranch := strings.ToLower(branch)
+		distance := editDistance(lower, lowerBranch)
+
+		if distance <= maxDistance || strings.Contains(lowerBranch, lower) || strings.Contains(lower, lowerBranch) {
+			matches = append(matches, candidate{branch, distance})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b candidate) int { return a.distance - b.distance })
+
+	names := make([]string, 0, min(len(matches), maxBranchSuggestions))
+	for i := 0; i < len(matches) && i < maxBranchSuggestions; i++ {
+		names = append(names, matches[i].branch)
+	}
+
+	return names
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// updateBaseBranch fetches the upstream of baseBranch and fast-forwards it.
+// If the branch is checked out, the fast-forward happens in that worktree
+// (only when it has no uncommitted tracked changes); otherwise the ref is
+// updated directly. Returns a warning message if the update was skipped,
+// or empty string on success.
+func updateBaseBranch(ctx context.Context, git *Git, mainRepoRoot, baseBranch string) string {
+	upstream, remote, err := git.Upstream(ctx, mainRepoRoot, baseBranch)
+	if err != nil {
+		return fmt.Sprintf("warning: not updating '%s': %v", baseBranch, err)
+	}
+
+	if remote != "." {
+		err = git.Fetch(ctx, mainRepoRoot, remote)
+		if err != nil {
+			return fmt.Sprintf("warning: not updating '%s': %v", baseBranch, err)
+		}
+	}
+
+	baseWtPath, err := git.FindWorktreeForBranch(ctx, mainRepoRoot, baseBranch)
+	if err != nil {
+		return fmt.Sprintf("warning: not updating '%s': %v", baseBranch, err)
+	}
+
+	if baseWtPath == "" {
+		err = git.PushLocal(ctx, mainRepoRoot, upstream, baseBranch)
+	} else {
+		dirty, dirtyErr := git.HasUncommittedTrackedChanges(ctx, baseWtPath)
+		if dirtyErr != nil {
+			return fmt.Sprintf("warning: not updating '%s': %v", baseBranch, dirtyErr)
+		}
+
+		if dirty {
+			return fmt.Sprintf("warning: not updating '%s': %s has uncommitted changes", baseBranch, baseWtPath)
+		}
+
+		err = git.Merge(ctx, baseWtPath, upstream, true)
+	}
+
+	if err != nil {
+		return fmt.Sprintf("warning: could not fast-forward '%s' to '%s': %v", baseBranch, upstream, err)
+	}
+
+	return ""
+}
+
+// copyUncommittedChanges copies staged, unstaged, and untracked files from srcDir to dstDir.
+// It respects .gitignore for untracked files.
+func copyUncommittedChanges(ctx context.Context, fsys fs.FS, git *Git, srcDir, dstDir string) error {
+	// Get all uncommitted files (staged, unstaged, and untracked)
+	files, err := git.ChangedFiles(ctx, srcDir)
+	if err != nil {
+		return fmt.Errorf("getting changed files: %w", err)
+	}
+
+	// Copy each file
+	for _, relPath := range files {
+		srcPath := filepath.Join(srcDir, relPath)
+		dstPath := filepath.Join(dstDir, relPath)
+
+		// Read source file
+		data, readErr := fsys.ReadFile(srcPath)
+		if readErr != nil {
+			// File might have been deleted (shown in diff but gone), skip silently
+			continue
+		}
+
+		// Create parent directories
+		mkdirErr := fsys.MkdirAll(filepath.Dir(dstPath), 0o755)
+		if mkdirErr != nil {
+			return fmt.Errorf("creating directory for %s: %w", relPath, mkdirErr)
+		}
+
+		// Write to destination
+		writeErr := fsys.WriteFile(dstPath, data, 0o644)
+		if writeErr != nil {
+			return fmt.Errorf("writing %s: %w", relPath, writeErr)
+		}
+	}
+
+	return nil
+}
+
+// copyTemplateDir copies the contents of templateDir (~ expanded, relative to
+// mainRepoRoot) into wtPath recursively, keeping file modes. Directories
+// named .git, symlinks to directories and the worktree's metadata file are
+// skipped.
+func copyTemplateDir(fsys fs.FS, templateDir, mainRepoRoot, wtPath string) error {
+	srcDir, err := ExpandPath(templateDir)
+	if err != nil {
+		return err
+	}
+
+	if !filepath.IsAbs(srcDir) {
+		srcDir = filepath.Join(mainRepoRoot, srcDir)
+	}
+
+	info, err := fsys.Stat(srcDir)
+	if err != nil {
+		return fmt.Errorf("reading template directory: %w", err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s", errTemplateNotDir, srcDir)
+	}
+
+	metadataPath := filepath.Join(".wt", "worktree.json")
+
+	var copyDir func(rel string) error
+
+	copyDir = func(rel string) error {
+		entries, readErr := fsys.ReadDir(filepath.Join(srcDir, rel))
+		if readErr != nil {
+			return fmt.Errorf("reading %s: %w", filepath.Join(srcDir, rel), readErr)
+		}
+
+		for _, entry := range entries {
+			entryRel := filepath.Join(rel, entry.Name())
+			if entry.Name() == ".git" || entryRel == metadataPath {
+				continue
+			}
+
+			// Stat follows symlinks, so linked files are copied as files
+			entryInfo, statErr := fsys.Stat(filepath.Join(srcDir, entryRel))
+			if statErr != nil {
+				return fmt.Errorf("reading %s: %w", entryRel, statErr)
+			}
+
+			if entryInfo.IsDir() {
+				if entry.Type()&os.ModeSymlink != 0 {
+					continue
+				}
+
+				dirErr := copyDir(entryRel)
+				if dirErr != nil {
+					return dirErr
+				}
+
+				continue
+			}
+
+			data, fileErr := fsys.ReadFile(filepath.Join(srcDir, entryRel))
+			if fileErr != nil {
+				return fmt.Errorf("reading %s: %w", entryRel, fileErr)
+			}
+
+			dstPath := filepath.Join(wtPath, entryRel)
+
+			mkdirErr := fsys.MkdirAll(filepath.Dir(dstPath), 0o755)
+			if mkdirErr != nil {
+				return fmt.Errorf("creating directory for %s: %w", entryRel, mkdirErr)
+			}
+
+			writeErr := fsys.WriteFile(dstPath, data, entryInfo.Mode().Perm())
+			if writeErr != nil {
+				return fmt.Errorf("writing %s: %w", entryRel, writeErr)
+			}
+		}
+
+		return nil
+	}
+
+	return copyDir("")
+}
+
+// validateCopyPattern checks a copy_ignored or --also-copy glob: non-empty,
+// relative and without .. components.
+func validateCopyPattern(pattern string) error {
+	if pattern == "" || filepath.IsAbs(pattern) || slices.Contains(strings.Split(pattern, "/"), "..") {
+		return fmt.Errorf("%w, got %q", errInvalidCopyPattern, pattern)
+	}
+
+	return nil
+}
+
+// copyIgnoredFiles copies the gitignored files of the worktree containing
+// srcDir that match patterns to the same relative paths in dstDir. Files
+// that are symlinks resolving outside that worktree are skipped with a
+// warning, as are symlinks to directories.
+func copyIgnoredFiles(ctx context.Context, stderr io.Writer, fsys fs.FS, git *Git, srcDir, dstDir string, patterns []string) error {
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	srcRoot, err := git.RepoRoot(ctx, srcDir)
+	if err != nil {
+		return err
+	}
+
+	resolvedRoot, err := filepath.EvalSymlinks(srcRoot)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", srcRoot, err)
+	}
+
+	files, err := git.IgnoredFiles(ctx, srcRoot, patterns)
+	if err != nil {
+		return err
+	}
+
+	for _, relPath := range files {
+		srcPath := filepath.Join(srcRoot, relPath)
+
+		resolved, resolveErr := filepath.EvalSymlinks(srcPath)
+		if resolveErr != nil {
+			// Broken symlink or deleted meanwhile
+			continue
+		}
+
+		rel, relErr := filepath.Rel(resolvedRoot, resolved)
+		if relErr != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			fprintf(stderr, "warning: not copying %s: it links outside the repository\n", relPath)
+
+			continue
+		}
+
+		info, statErr := fsys.Stat(resolved)
+		if statErr != nil || info.IsDir() {
+			continue
+		}
+
+		data, readErr := fsys.ReadFile(resolved)
+		if readErr != nil {
+			return fmt.Errorf("reading %s: %w", relPath, readErr)
+		}
+
+		dstPath := filepath.Join(dstDir, relPath)
+
+		mkdirErr := fsys.MkdirAll(filepath.Dir(dstPath), 0o755)
+		if mkdirErr != nil {
+			return fmt.Errorf("creating directory for %s: %w", relPath, mkdirErr)
+		}
+
+		writeErr := fsys.WriteFile(dstPath, data, info.Mode().Perm())
+		if writeErr != nil {
+			return fmt.Errorf("writing %s: %w", relPath, writeErr)
+		}
+	}
+
+	return nil
+}
+
+// writeTaskReadme writes the task file into the worktree root.
+// source is read as a file (relative to the working directory) if it names one,
+// otherwise it is used verbatim as the task description.
+// The file name comes from cfg.ReadmeFile and is added to .git/info/exclude;
+// the returned pattern is set (also on error) if this call added it.
+func writeTaskReadme(fsys fs.FS, gitCommonDir string, cfg Config, wtPath, source string, stderr io.Writer) (string, error) {
+	content := []byte(source)
+
+	srcPath := source
+	if !filepath.IsAbs(srcPath) {
+		srcPath = filepath.Join(cfg.EffectiveCwd, srcPath)
+	}
+
+	stat, statErr := fsys.Stat(srcPath)
+	if statErr == nil && !stat.IsDir() {
+		data, readErr := fsys.ReadFile(srcPath)
+		if readErr != nil {
+			return "", fmt.Errorf("reading %s: %w", srcPath, readErr)
+		}
+
+		content = data
+	}
+
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		content = append(content, '\n')
+	}
+
+	readmeFile := cfg.ReadmeFile
+	if readmeFile == "" {
+		readmeFile = DefaultConfig().ReadmeFile
+	}
+
+	pattern := "/" + filepath.ToSlash(readmeFile)
+
+	added, warning := ensureExcluded(fsys, gitCommonDir, pattern)
+	if warning != "" {
+		fprintln(stderr, warning)
+	}
+
+	if !added {
+		pattern = ""
+	}
+
+	dstPath := filepath.Join(wtPath, readmeFile)
+
+	err := fsys.MkdirAll(filepath.Dir(dstPath), 0o755)
+	if err != nil {
+		return pattern, fmt.Errorf("creating directory for %s: %w", readmeFile, err)
+	}
+
+	err = fsys.WriteFile(dstPath, content, 0o644)
+	if err != nil {
+		return pattern, fmt.Errorf("writing %s: %w", readmeFile, err)
+	}
+
+	return pattern, nil
+}
+
+// allocateWorktree scans baseDir and picks the next ID, a fresh agent_id in
+// the configured naming scheme and the worktree name (customName, or the
+// agent_id if empty). The agent_id avoids names of existing worktrees and
+// branches (branchExists). The result is only guaranteed unique while the
+// create lock is held.
+func allocateWorktree(fsys fs.FS, baseDir, customName, naming string, branchExists func(string) bool) (string, string, int, error) {
+	existing, err := findWorktrees(fsys, baseDir)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("scanning existing worktrees: %w", err)
+	}
+
+	return allocateAmong(existing, customName, naming, branchExists)
+}
+
+// allocateAmong allocates the ID, agent_id and name of a worktree next to
+// the existing ones, like allocateWorktree.
+func allocateAmong(existing []WorktreeInfo, customName, naming string, branchExists func(string) bool) (string, string, int, error) {
+	// Calculate next ID; ids still named as a parent_id are skipped too, so
+	// a new worktree never becomes the parent of existing ones
+	nextID := 1
+	for _, wt := range existing {
+		nextID = max(nextID, wt.ID+1, wt.ParentID+1)
+	}
+
+	// Generate agent_id
+	existingNames := getExistingNames(existing)
+
+	agentID, err := generateName(naming, nextID, func(candidate string) bool {
+		return slices.Contains(existingNames, candidate) || branchExists(candidate)
+	})
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	// Set name
+	name := customName
+	if name == "" {
+		name = agentID
+	}
+
+	// Check name collision (in case --name was provided)
+	if slices.Contains(existingNames, name) {
+		return "", "", 0, fmt.Errorf("%w: %s", ErrNameAlreadyInUse, name)
+	}
+
+	return name, agentID, nextID, nil
+}
+
+// jsonCreatePlan is the output format for create --dry-run.
+type jsonCreatePlan struct {
+	Name         string `json:"name"`
+	AgentID      string `json:"agent_id"`
+	ID           int    `json:"id"`
+	Path         string `json:"path"`
+	Branch       string `json:"branch"`
+	From         string `json:"from"`
+	StartCommit  string `json:"start_commit"`
+	WouldRunHook bool   `json:"would_run_hook"`
+}
+
+func outputCreatePlan(output io.Writer, plan *jsonCreatePlan, jsonOutput bool) error {
+	if jsonOutput {
+		enc := json.NewEncoder(output)
+		enc.SetIndent("", "  ")
+
+		encodeErr := enc.Encode(plan)
+		if encodeErr != nil {
+			return fmt.Errorf("encoding JSON: %w", encodeErr)
+		}
+
+		return nil
+	}
+
+	hook := "no"
+	if plan.WouldRunHook {
+		hook = "yes"
+	}
+
+	fprintln(output, "Dry run: wt create (nothing was created)")
+	fprintf(output, "  name:        %s\n", plan.Name)
+	fprintf(output, "  agent_id:    %s\n", plan.AgentID)
+	fprintf(output, "  id:          %d (advisory)\n", plan.ID)
+	fprintf(output, "  path:        %s\n", plan.Path)
+	fprintf(output, "  branch:      %s\n", plan.Branch)
+	fprintf(output, "  from:        %s (%s)\n", plan.From, plan.StartCommit)
+	fprintf(output, "  hook:        %s\n", hook)
+
+	return nil
+}
+
+// Reasons create --json reports in hook_skipped_reason.
+const (
+	hookSkippedNoHook      = "no_hook"       // no executable post-create hook
+	hookSkippedNoHooksFlag = "no_hooks_flag" // --no-hooks was given
+	hookSkippedRepair      = "repair"        // --overwrite-metadata creates no worktree
+)
+
+// jsonCreateOutput is the JSON output format for the create command.
+type jsonCreateOutput struct {
+	Name     string    `json:"name"`
+	AgentID  string    `json:"agent_id"`
+	ID       int       `json:"id"`
+	Path     string    `json:"path"`
+	Branch   string    `json:"branch"`
+	From     string    `json:"from"`
+	Upstream string    `json:"upstream,omitempty"`
+	Locked   bool      `json:"locked"`
+	Created  time.Time `json:"created"`
+
+	// Whether the post-create hook ran, and why not if it didn't
+	HookRan           bool   `json:"hook_ran"`
+	HookSkippedReason string `json:"hook_skipped_reason,omitempty"`
+
+	// Only set with --switch: the directory to cd into
+	SwitchPath string `json:"switch_path,omitempty"`
+}
+
+func (c *createdWorktree) jsonOutput() jsonCreateOutput {
+	return jsonCreateOutput{
+		Name:     c.info.Name,
+		AgentID:  c.info.AgentID,
+		ID:       c.info.ID,
+		Path:     c.path,
+		Branch:   c.branch,
+		From:     c.info.BaseBranch,
+		Upstream: c.info.Upstream,
+		Locked:   c.info.Locked,
+		Created:  c.info.Created,
+
+		HookRan:           c.hookRan,
+		HookSkippedReason: c.hookSkippedReason,
+	}
 }
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -59,6 +62,50 @@ func Test_Create_Creates_Worktree_With_Defaults(t *testing.T) {
 	AssertContains(t, stdout, "from:        master")
 }
 
+func Test_Create_Uses_Configured_Naming_Scheme(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees", "naming": "agent-<n>"}`)
+
+	// A branch left behind (e.g. by wt remove) takes the name of ID 1
+	createBranch(t, cli.Dir, "agent-1")
+
+	stdout := cli.MustRun("--config", "config.json", "create")
+
+	AssertContains(t, stdout, "name:        agent-2")
+	AssertContains(t, stdout, "agent_id:    agent-2")
+	AssertContains(t, stdout, "id:          1")
+
+	stdout = cli.MustRun("--config", "config.json", "create")
+
+	AssertContains(t, stdout, "name:        agent-3")
+	AssertContains(t, stdout, "id:          2")
+}
+
+func Test_Create_Fails_For_Unknown_Naming_Scheme(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees", "naming": "random"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "unknown naming scheme")
+	AssertContains(t, stderr, `"random"`)
+
+	if cli.FileExists("worktrees") {
+		t.Error("no worktree should be created")
+	}
+}
+
 func Test_Create_Creates_Worktree_Directory_And_Metadata(t *testing.T) {
 	t.Parallel()
 
@@ -205,6 +252,112 @@ func Test_Create_From_Specific_Branch(t *testing.T) {
 	AssertContains(t, stdout, "from:        develop")
 }
 
+func Test_Create_From_Missing_Branch_Fails_Before_Creating_Worktree(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "wt-missing", "--from-branch", "no-such-thing")
+
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stderr, "base branch 'no-such-thing' does not exist")
+	AssertNotContains(t, stderr, "did you mean")
+	AssertNotContains(t, stderr, "creating worktree")
+
+	if cli.FileExists("worktrees/wt-missing") {
+		t.Error("worktree directory should not be created")
+	}
+}
+
+func Test_Create_From_Misspelled_Branch_Suggests_Near_Matches(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	createBranch(t, cli.Dir, "develop")
+	createBranch(t, cli.Dir, "release")
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--from-branch", "devlop")
+
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stderr, "base branch 'devlop' does not exist (did you mean: develop?)")
+	AssertNotContains(t, stderr, "release")
+}
+
+func Test_Create_From_Tag_Is_Not_Rejected_As_Missing_Branch(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	out, err := testGitCmd("-C", cli.Dir, "tag", "v1.0").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git tag failed: %v\n%s", err, out)
+	}
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout, stderr, code := cli.Run("--config", "config.json", "create", "--name", "from-tag", "--from-branch", "v1.0")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "from:        v1.0")
+}
+
+func Test_Create_From_Commit_Records_Short_SHA(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+	gitCommitInDir(t, cli.Dir, "second.txt", "second", "Second commit")
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	parent := gitOutput(t, cli.Dir, "rev-parse", "HEAD~1")
+	shortParent := gitOutput(t, cli.Dir, "rev-parse", "--short", "HEAD~1")
+
+	for name, rev := range map[string]string{"from-expr": "HEAD~1", "from-sha": parent} {
+		stdout := cli.MustRun("--config", "config.json", "create", "--name", name, "--from", rev)
+
+		AssertContains(t, stdout, "from:        "+shortParent)
+
+		wtPath := extractPath(stdout)
+		if head := gitOutput(t, wtPath, "rev-parse", "HEAD"); head != parent {
+			t.Errorf("%s: expected worktree at %s, got %s", name, parent, head)
+		}
+
+		info, err := readWorktreeInfo(fs.NewReal(), wtPath)
+		if err != nil {
+			t.Fatalf("reading metadata: %v", err)
+		}
+
+		if info.BaseBranch != shortParent {
+			t.Errorf("%s: expected base_branch %s, got %q", name, shortParent, info.BaseBranch)
+		}
+	}
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--from", "master", "--from-branch", "master")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --from and --from-branch together")
+}
+
 func Test_Create_Increments_ID(t *testing.T) {
 	t.Parallel()
 
@@ -262,6 +415,28 @@ func Test_Create_Returns_Error_When_Name_Already_In_Use(t *testing.T) {
 	AssertContains(t, stderr, "already in use")
 }
 
+func Test_Create_Rejects_Name_That_Is_Not_One_Directory(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	for _, name := range []string{"..", "../escaped", "nested/name"} {
+		_, stderr, code := cli.Run("--config", "config.json", "create", "--name", name)
+		if code != 1 {
+			t.Errorf("create --name %q: expected exit code 1, got %d", name, code)
+		}
+
+		AssertContains(t, stderr, "invalid name")
+	}
+
+	if cli.FileExists("escaped") || cli.FileExists("worktrees/nested") {
+		t.Error("no worktree directory should be created")
+	}
+}
+
 func Test_Create_Returns_Error_When_Branch_Already_Exists(t *testing.T) {
 	t.Parallel()
 
@@ -875,6 +1050,148 @@ func Test_Create_With_Changes_Respects_Gitignore(t *testing.T) {
 	}
 }
 
+func Test_Create_With_Changes_Copies_Matching_Ignored_Files(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	gitCommitInDir(t, cli.Dir, ".gitignore", ".env\ncache/\n*.log\nconfig/*.local.json\n/worktrees/\n", "add gitignore")
+	cli.WriteFile("config.json", `{"base": "worktrees", "copy_ignored": [".env"]}`)
+
+	cli.WriteFile(".env", "API_KEY=1\n")
+	cli.WriteFile("cache/deep/nested/blob", "cached\n")
+	cli.WriteFile("config/dev.local.json", "{}\n")
+	cli.WriteFile("debug.log", "not wanted\n")
+
+	outside := filepath.Join(t.TempDir(), "secret")
+	writeTestFile(t, outside, "outside the repo\n")
+
+	err := os.Symlink(outside, filepath.Join(cli.Dir, "cache", "link"))
+	if err != nil {
+		t.Fatalf("creating symlink: %v", err)
+	}
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--with-changes", "--name", "wt-ignored",
+		"--also-copy", "cache", "--also-copy", "config/*.local.json")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	wtDir := filepath.Join("worktrees", "wt-ignored")
+
+	for file, want := range map[string]string{
+		".env":                   "API_KEY=1\n",
+		"cache/deep/nested/blob": "cached\n",
+		"config/dev.local.json":  "{}\n",
+	} {
+		if got := cli.ReadFile(filepath.Join(wtDir, file)); got != want {
+			t.Errorf("%s: expected %q, got %q", file, want, got)
+		}
+	}
+
+	if cli.FileExists(filepath.Join(wtDir, "debug.log")) {
+		t.Error("debug.log matches no copy pattern and should not have been copied")
+	}
+
+	if cli.FileExists(filepath.Join(wtDir, "cache", "link")) {
+		t.Error("a symlink to outside the repository should not have been copied")
+	}
+
+	AssertContains(t, stderr, "warning: not copying cache/link: it links outside the repository")
+}
+
+func Test_Create_Copies_Template_Dir_Into_Worktree(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees", "template_dir": "wt-template"}`)
+	cli.WriteFile("wt-template/.vscode/settings.json", "{}\n")
+	cli.WriteExecutable("wt-template/scripts/setup.sh", "#!/bin/sh\n")
+	cli.WriteFile("wt-template/TASK.md", "# Task\n")
+	cli.WriteFile("wt-template/.git/HEAD", "ref: refs/heads/template\n")
+
+	// The hook runs after the template is copied
+	cli.WriteExecutable(".wt/hooks/post-create", "#!/bin/sh\ntest -f .vscode/settings.json && echo template-present\n")
+
+	stdout, stderr, code := cli.Run("--config", "config.json", "create", "--name", "from-template")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "hook(post-create): template-present")
+
+	wtDir := filepath.Join("worktrees", "from-template")
+
+	if got := cli.ReadFile(filepath.Join(wtDir, "TASK.md")); got != "# Task\n" {
+		t.Errorf("TASK.md: expected template content, got %q", got)
+	}
+
+	info, err := os.Stat(filepath.Join(cli.Dir, wtDir, "scripts", "setup.sh"))
+	if err != nil || info.Mode()&0o111 == 0 {
+		t.Errorf("scripts/setup.sh should be copied executable: %v", err)
+	}
+
+	// The worktree's own .git file is untouched
+	gitFile := cli.ReadFile(filepath.Join(wtDir, ".git"))
+	if !strings.HasPrefix(gitFile, "gitdir: ") {
+		t.Errorf("template .git should be skipped, worktree .git is %q", gitFile)
+	}
+}
+
+func Test_Create_Rolls_Back_When_Template_Dir_Missing(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees", "template_dir": "missing-template"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "no-template")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "copying template_dir")
+
+	if cli.FileExists(filepath.Join("worktrees", "no-template")) {
+		t.Error("worktree should have been removed")
+	}
+
+	if slices.Contains(listBranches(t, cli.Dir), "no-template") {
+		t.Error("branch should have been deleted")
+	}
+}
+
+func Test_Create_Also_Copy_Requires_With_Changes_And_Relative_Glob(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--also-copy", ".env")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "--also-copy requires --with-changes")
+
+	_, stderr, code = cli.Run("--config", "config.json", "create", "--with-changes", "--also-copy", "../outside")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "copy patterns must be relative globs")
+
+	if cli.FileExists("worktrees") {
+		t.Error("no worktree should have been created")
+	}
+}
+
 func Test_Create_With_Changes_Copies_Nested_Directory_Structure(t *testing.T) {
 	t.Parallel()
 
@@ -1429,6 +1746,43 @@ func Test_Create_From_Worktree_Uses_Shared_Lock(t *testing.T) {
 	}
 }
 
+func Test_Create_From_Worktree_Uses_Main_Repo_Base_Dir(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "wt-first")
+	if code != 0 {
+		t.Fatalf("first create failed: %s", stderr)
+	}
+
+	wtPath := filepath.Join(cli.Dir, "worktrees", "wt-first")
+	cli.WriteFile(filepath.Join("worktrees", "wt-first", "config.json"), cli.ReadFile("config.json"))
+
+	stdout, stderr, code := cli.RunInDir(wtPath, "--config", "config.json", "create", "--name", "wt-second")
+	if code != 0 {
+		t.Fatalf("second create from worktree failed: %s", stderr)
+	}
+
+	want := filepath.Join(cli.Dir, "worktrees", "wt-second")
+	AssertContains(t, stdout, "path:        "+want)
+
+	if !cli.FileExists(filepath.Join("worktrees", "wt-second", ".wt", "worktree.json")) {
+		t.Error("wt-second should be created in the main repo's base dir")
+	}
+
+	if cli.FileExists(filepath.Join("worktrees", "wt-first", "worktrees", "wt-second")) {
+		t.Error("wt-second should not be nested inside wt-first")
+	}
+
+	listOut := cli.MustRun("--config", "config.json", "ls")
+	AssertContains(t, listOut, "wt-first")
+	AssertContains(t, listOut, "wt-second")
+}
+
 // Tests for early lock release
 
 func Test_Create_Lock_Released_After_Metadata_Written(t *testing.T) {
@@ -1468,6 +1822,41 @@ fi
 	}
 }
 
+func Test_Create_Pre_Create_Hook_Runs_Without_Lock_And_Keeps_Name(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	hookScript := `#!/bin/bash
+exec 200>"$WT_REPO_ROOT/.git/wt.lock"
+if flock -n 200; then
+    echo "LOCK_FREE $WT_NAME" > "$WT_REPO_ROOT/lock-status.txt"
+    flock -u 200
+else
+    echo "LOCK_HELD $WT_NAME" > "$WT_REPO_ROOT/lock-status.txt"
+fi
+`
+	cli.WriteExecutable(".wt/hooks/pre-create", hookScript)
+
+	// A generated name: the worktree gets the name the hook saw
+	stdout, stderr, code := cli.Run("--config", "config.json", "create", "--porcelain")
+	if code != 0 {
+		t.Fatalf("create failed: %s", stderr)
+	}
+
+	_, rest, _ := strings.Cut(stdout, "name\t")
+	name, _, _ := strings.Cut(rest, "\n")
+
+	if lockStatus := strings.TrimSpace(cli.ReadFile("lock-status.txt")); lockStatus != "LOCK_FREE "+name {
+		t.Errorf("pre-create should run without the lock for %s, but hook reported: %s", name, lockStatus)
+	}
+
+	AssertContains(t, stdout, "agent_id\t"+name+"\n")
+}
+
 func Test_Create_Concurrent_Create_During_Hook_Execution(t *testing.T) {
 	t.Parallel()
 
@@ -1555,9 +1944,18 @@ func Test_Create_Adds_Worktree_Exclusion_To_Git_Exclude(t *testing.T) {
 		t.Fatalf("create failed: %s", stderr)
 	}
 
-	// Verify .wt/worktree.json is in .git/info/exclude
+	// Verify .wt/worktree.json and .wt/merge-state.json are in .git/info/exclude
 	excludeContent := cli.ReadFile(".git/info/exclude")
-	AssertContains(t, excludeContent, ".wt/worktree.json")
+	AssertContains(t, excludeContent, ".wt/worktree.json\n")
+	AssertContains(t, excludeContent, ".wt/merge-state.json\n")
+
+	// A merge state file left behind does not show up in git status
+	wtPath := filepath.Join(cli.Dir, "worktrees", "exclude-test")
+	writeTestFile(t, mergeStatePath(wtPath), "{}")
+
+	if status := gitOutput(t, wtPath, "status", "--porcelain"); status != "" {
+		t.Errorf("expected a clean worktree, got status %q", status)
+	}
 }
 
 func Test_Create_Does_Not_Duplicate_Worktree_Exclusion(t *testing.T) {
@@ -1641,24 +2039,74 @@ func Test_Create_Handles_Exclude_Already_Present(t *testing.T) {
 	}
 }
 
-func Test_Create_Warns_When_Exclude_File_Not_Writable(t *testing.T) {
+func Test_Create_Rollback_Reverts_Exclude_Line_Added_By_First_Create(t *testing.T) {
 	t.Parallel()
 
 	cli := NewCLITester(t)
 	initRealGitRepo(t, cli.Dir)
 
-	// Make .git/info/exclude non-writable
-	excludePath := filepath.Join(cli.Dir, ".git", "info", "exclude")
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.WriteExecutable(".wt/hooks/post-create", "#!/bin/sh\nexit 1\n")
 
-	err := os.Chmod(excludePath, 0o444)
-	if err != nil {
-		t.Fatalf("failed to make exclude read-only: %v", err)
+	excludeBefore := cli.ReadFile(".git/info/exclude")
+
+	_, _, code := cli.Run("--config", "config.json", "create", "--name", "first")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 from failing hook, got %d", code)
 	}
 
-	// Restore permissions after test
-	t.Cleanup(func() {
-		_ = os.Chmod(excludePath, 0o644)
-	})
+	if got := cli.ReadFile(".git/info/exclude"); got != excludeBefore {
+		t.Errorf("exclude file not restored after rollback\nbefore:\n%s\nafter:\n%s", excludeBefore, got)
+	}
+
+	// The task file's line goes too
+	_, _, code = cli.Run("--config", "config.json", "create", "--name", "first", "--readme", "Fix the bug")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 from failing hook, got %d", code)
+	}
+
+	if got := cli.ReadFile(".git/info/exclude"); got != excludeBefore {
+		t.Errorf("exclude file not restored after rollback with --readme\nbefore:\n%s\nafter:\n%s", excludeBefore, got)
+	}
+}
+
+func Test_Create_Rollback_Keeps_Exclude_Line_That_Pre_Existed(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.MustRun("--config", "config.json", "create", "--name", "kept")
+
+	cli.WriteExecutable(".wt/hooks/post-create", "#!/bin/sh\nexit 1\n")
+
+	_, _, code := cli.Run("--config", "config.json", "create", "--name", "rolled-back")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 from failing hook, got %d", code)
+	}
+
+	AssertContains(t, cli.ReadFile(".git/info/exclude"), ".wt/worktree.json")
+}
+
+func Test_Create_Warns_When_Exclude_File_Not_Writable(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	// Make .git/info/exclude non-writable
+	excludePath := filepath.Join(cli.Dir, ".git", "info", "exclude")
+
+	err := os.Chmod(excludePath, 0o444)
+	if err != nil {
+		t.Fatalf("failed to make exclude read-only: %v", err)
+	}
+
+	// Restore permissions after test
+	t.Cleanup(func() {
+		_ = os.Chmod(excludePath, 0o644)
+	})
 
 	cli.WriteFile("config.json", `{"base": "worktrees"}`)
 
@@ -1799,6 +2247,50 @@ func Test_Create_JSON_Output_Contains_Correct_Values(t *testing.T) {
 	}
 }
 
+func Test_Create_JSON_Output_Contains_Created_Timestamp(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	before := time.Now().UTC().Add(-time.Second)
+
+	stdout := cli.MustRun("--config", "config.json", "create", "--json", "--name", "json-created")
+
+	var result map[string]any
+
+	err := json.Unmarshal([]byte(stdout), &result)
+	if err != nil {
+		t.Fatalf("stdout is not valid JSON: %v", err)
+	}
+
+	createdStr, ok := result["created"].(string)
+	if !ok {
+		t.Fatalf("expected created string, got %v", result["created"])
+	}
+
+	created, err := time.Parse(time.RFC3339, createdStr)
+	if err != nil {
+		t.Fatalf("created %q is not RFC3339: %v", createdStr, err)
+	}
+
+	if created.Location() != time.UTC || created.Before(before) || created.After(time.Now().UTC()) {
+		t.Errorf("created %q should be a current UTC timestamp", createdStr)
+	}
+
+	// Matches the metadata written to worktree.json
+	info, err := readWorktreeInfo(fs.NewReal(), filepath.Join(cli.Dir, "worktrees", "json-created"))
+	if err != nil {
+		t.Fatalf("failed to read worktree info: %v", err)
+	}
+
+	if !info.Created.Equal(created) {
+		t.Errorf("created %v does not match metadata %v", created, info.Created)
+	}
+}
+
 func Test_Create_JSON_Output_Has_Indentation(t *testing.T) {
 	t.Parallel()
 
@@ -2082,7 +2574,7 @@ func Test_Create_Switch_Flag_Works_With_Other_Flags(t *testing.T) {
 	}
 }
 
-func Test_Create_Switch_And_JSON_Flags_Are_Mutually_Exclusive(t *testing.T) {
+func Test_Create_Switch_With_JSON_Adds_Switch_Path(t *testing.T) {
 	t.Parallel()
 
 	cli := NewCLITester(t)
@@ -2090,13 +2582,21 @@ func Test_Create_Switch_And_JSON_Flags_Are_Mutually_Exclusive(t *testing.T) {
 
 	cli.WriteFile("config.json", `{"base": "worktrees"}`)
 
-	_, stderr, code := cli.Run("--config", "config.json", "create", "--switch", "--json")
+	stdout := cli.MustRun("--config", "config.json", "create", "--name", "both", "--switch", "--json")
 
-	if code != 1 {
-		t.Errorf("expected exit code 1, got %d", code)
+	var out jsonCreateOutput
+
+	err := json.Unmarshal([]byte(stdout), &out)
+	if err != nil {
+		t.Fatalf("expected only JSON on stdout: %v\n%s", err, stdout)
+	}
+
+	if out.SwitchPath == "" || out.SwitchPath != out.Path || out.Name != "both" {
+		t.Errorf("expected switch_path equal to path %q, got %+v", out.Path, out)
 	}
 
-	AssertContains(t, stderr, "cannot use --switch and --json together")
+	// Plain --json has no switch_path
+	AssertNotContains(t, cli.MustRun("--config", "config.json", "create", "--json"), "switch_path")
 }
 
 func Test_Create_Help_Shows_Switch_Flag(t *testing.T) {
@@ -2113,3 +2613,1771 @@ func Test_Create_Help_Shows_Switch_Flag(t *testing.T) {
 	AssertContains(t, stdout, "--switch")
 	AssertContains(t, stdout, "-s")
 }
+
+func Test_Create_Readme_Writes_Literal_Text_To_Task_File(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "readme-text", "--readme", "Fix the login bug")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	content := cli.ReadFile(filepath.Join("worktrees", "readme-text", "TASK.md"))
+
+	if content != "Fix the login bug\n" {
+		t.Errorf("TASK.md content = %q, want %q", content, "Fix the login bug\n")
+	}
+}
+
+func Test_Create_Readme_Reads_Content_From_File(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.WriteFile("prompt.md", "# Task\n\nImplement the feature.\n")
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "readme-file", "--readme", "prompt.md")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	content := cli.ReadFile(filepath.Join("worktrees", "readme-file", "TASK.md"))
+
+	if content != "# Task\n\nImplement the feature.\n" {
+		t.Errorf("TASK.md content = %q, want file content", content)
+	}
+}
+
+func Test_Create_Readme_Uses_Configured_File_Name(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees", "readme_file": "PROMPT.md"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "readme-cfg", "--readme", "do it")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	if !cli.FileExists(filepath.Join("worktrees", "readme-cfg", "PROMPT.md")) {
+		t.Error("expected PROMPT.md to be written")
+	}
+
+	if cli.FileExists(filepath.Join("worktrees", "readme-cfg", "TASK.md")) {
+		t.Error("TASK.md should not be written when readme_file is configured")
+	}
+}
+
+func Test_Create_Rejects_Readme_File_Outside_Worktree(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	escaped := filepath.Join(cli.Dir, "escaped.md")
+
+	for _, readmeFile := range []string{"../../escaped.md", escaped} {
+		cli.WriteFile("config.json", `{"base": "worktrees", "readme_file": "`+readmeFile+`"}`)
+
+		_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "readme-escape", "--readme", "do it")
+		if code != 1 {
+			t.Errorf("readme_file %q: expected exit code 1, got %d", readmeFile, code)
+		}
+
+		AssertContains(t, stderr, "readme_file must be a file name without directories")
+	}
+
+	if cli.FileExists("escaped.md") || cli.FileExists("worktrees/readme-escape") {
+		t.Error("nothing should be written")
+	}
+}
+
+func Test_Create_Readme_Is_Written_Before_Hook_And_Excluded_From_Git(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	hookScript := `#!/bin/bash
+cp "$WT_PATH/TASK.md" "$WT_PATH/.wt/hook-saw-task.txt"
+`
+	cli.WriteExecutable(".wt/hooks/post-create", hookScript)
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "readme-hook", "--readme", "hook input")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	wtPath := filepath.Join(cli.Dir, "worktrees", "readme-hook")
+
+	AssertContains(t, cli.ReadFileAt(wtPath, ".wt/hook-saw-task.txt"), "hook input")
+	AssertContains(t, cli.ReadFile(".git/info/exclude"), "/TASK.md")
+
+	out, err := testGitCmd("-C", wtPath, "status", "--porcelain").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git status failed: %v\n%s", err, out)
+	}
+
+	AssertNotContains(t, string(out), "TASK.md")
+}
+
+// cloneTestRepo clones an upstream repo created by initRealGitRepo into dst
+// and configures a commit identity, returning dst.
+func cloneTestRepo(t *testing.T, upstream, dst string) string {
+	t.Helper()
+
+	out, err := testGitCmd("clone", upstream, dst).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git clone failed: %v\n%s", err, out)
+	}
+
+	for _, kv := range [][2]string{{"user.email", "test@test.com"}, {"user.name", "Test User"}, {"commit.gpgsign", "false"}} {
+		out, err = testGitCmd("-C", dst, "config", kv[0], kv[1]).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git config failed: %v\n%s", err, out)
+		}
+	}
+
+	return dst
+}
+
+func Test_Create_Checkout_Base_Fast_Forwards_From_Upstream(t *testing.T) {
+	t.Parallel()
+
+	upstream := initRealGitRepo(t, t.TempDir())
+	local := cloneTestRepo(t, upstream, filepath.Join(t.TempDir(), "local"))
+
+	gitCommitInDir(t, upstream, "upstream.txt", "new upstream work\n", "Upstream commit")
+
+	cli := NewCLITesterAt(t, local)
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestFile(t, cfgPath, `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", cfgPath, "create", "--name", "fresh", "--checkout-base")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertNotContains(t, stderr, "warning:")
+
+	if !cli.FileExists(filepath.Join("worktrees", "fresh", "upstream.txt")) {
+		t.Error("new worktree should start from the updated upstream commit")
+	}
+
+	if !cli.FileExists("upstream.txt") {
+		t.Error("base worktree should have been fast-forwarded")
+	}
+
+	// The worktree starts exactly at the recorded start_commit
+	wtPath := filepath.Join(local, "worktrees", "fresh")
+
+	info, err := readWorktreeInfo(fs.NewReal(), wtPath)
+	if err != nil {
+		t.Fatalf("reading metadata: %v", err)
+	}
+
+	upstreamHead := gitOutput(t, upstream, "rev-parse", "HEAD")
+	if head := gitOutput(t, wtPath, "rev-parse", "HEAD"); head != upstreamHead || info.StartCommit != upstreamHead {
+		t.Errorf("expected HEAD and start_commit %s, got %s and %s", upstreamHead, head, info.StartCommit)
+	}
+}
+
+func Test_Create_Checkout_Base_Skips_With_Warning_When_Base_Worktree_Dirty(t *testing.T) {
+	t.Parallel()
+
+	upstream := initRealGitRepo(t, t.TempDir())
+	local := cloneTestRepo(t, upstream, filepath.Join(t.TempDir(), "local"))
+
+	gitCommitInDir(t, upstream, "upstream.txt", "new upstream work\n", "Upstream commit")
+	writeTestFile(t, filepath.Join(local, "README.md"), "local edit\n")
+
+	cli := NewCLITesterAt(t, local)
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestFile(t, cfgPath, `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", cfgPath, "create", "--name", "stale", "--checkout-base")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stderr, "warning: not updating 'master'")
+	AssertContains(t, stderr, "uncommitted changes")
+
+	if cli.FileExists(filepath.Join("worktrees", "stale", "upstream.txt")) {
+		t.Error("new worktree should start from the local base when the update is skipped")
+	}
+
+	if cli.ReadFile("README.md") != "local edit\n" {
+		t.Error("dirty base worktree must not be touched")
+	}
+}
+
+func Test_Create_Checkout_Base_Warns_When_No_Upstream(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "no-upstream", "--checkout-base")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stderr, "branch has no upstream")
+}
+
+func Test_Create_Records_Start_Commit_In_Metadata(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.MustRun("--config", "config.json", "create", "--name", "start-commit")
+
+	out, err := testGitCmd("-C", cli.Dir, "rev-parse", "master").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v\n%s", err, out)
+	}
+
+	info, err := readWorktreeInfo(fs.NewReal(), filepath.Join(cli.Dir, "worktrees", "start-commit"))
+	if err != nil {
+		t.Fatalf("reading metadata: %v", err)
+	}
+
+	if info.StartCommit != strings.TrimSpace(string(out)) {
+		t.Errorf("start_commit = %q, want %q", info.StartCommit, strings.TrimSpace(string(out)))
+	}
+}
+
+// commitSparseLayout commits files in src/, docs/ and the repo root so
+// sparse-checkout tests can tell which paths were materialized.
+func commitSparseLayout(t *testing.T, dir string) {
+	t.Helper()
+
+	gitCommitInDir(t, dir, "src/main.go", "package main\n", "Add src")
+	gitCommitInDir(t, dir, "docs/guide.md", "# Guide\n", "Add docs")
+	gitCommitInDir(t, dir, "Makefile", "all:\n", "Add Makefile")
+}
+
+func Test_Create_Sparse_Checkout_Only_Materializes_Configured_Paths(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+	commitSparseLayout(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees", "sparse_checkout": ["src"]}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "sparse-wt")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	wtDir := filepath.Join("worktrees", "sparse-wt")
+
+	if !cli.FileExists(filepath.Join(wtDir, "src", "main.go")) {
+		t.Error("src/main.go should be checked out")
+	}
+
+	if !cli.FileExists(filepath.Join(wtDir, "Makefile")) {
+		t.Error("root files should always be checked out in cone mode")
+	}
+
+	if cli.FileExists(filepath.Join(wtDir, "docs", "guide.md")) {
+		t.Error("docs/guide.md should not be checked out")
+	}
+
+	if !cli.FileExists(filepath.Join(wtDir, ".wt", "worktree.json")) {
+		t.Error("metadata should be written in a sparse worktree")
+	}
+
+	// The main repo keeps its full checkout
+	if !cli.FileExists(filepath.Join("docs", "guide.md")) {
+		t.Error("main repo should keep docs/guide.md")
+	}
+}
+
+func Test_Create_Sparse_Checkout_Failure_Rolls_Back(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+	commitSparseLayout(t, cli.Dir)
+
+	// Cone mode rejects glob patterns
+	cli.WriteFile("config.json", `{"base": "worktrees", "sparse_checkout": ["src/*.go"]}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "bad-sparse")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "configuring sparse-checkout")
+
+	if cli.FileExists(filepath.Join("worktrees", "bad-sparse")) {
+		t.Error("worktree directory should have been removed on sparse-checkout failure")
+	}
+
+	for _, branch := range listBranches(t, cli.Dir) {
+		if branch == "bad-sparse" {
+			t.Error("branch 'bad-sparse' should have been deleted on rollback")
+		}
+	}
+}
+
+func Test_Create_Post_Create_Cmd_Runs_In_Worktree_With_Hook_Env(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout, stderr, code := cli.Run("--config", "config.json", "create", "--name", "inline-cmd",
+		"--post-create-cmd", `echo "$WT_NAME $WT_ID" > setup.txt && echo done`)
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "hook(post-create-cmd): done")
+
+	content := cli.ReadFile(filepath.Join("worktrees", "inline-cmd", "setup.txt"))
+	if content != "inline-cmd 1\n" {
+		t.Errorf("setup.txt content = %q, want %q", content, "inline-cmd 1\n")
+	}
+}
+
+func Test_Create_Post_Create_Cmd_Runs_After_Hook_File(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteExecutable(".wt/hooks/post-create", "#!/bin/bash\necho hook >> order.txt\n")
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "cmd-order",
+		"--post-create-cmd", "echo cmd >> order.txt")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	content := cli.ReadFile(filepath.Join("worktrees", "cmd-order", "order.txt"))
+	if content != "hook\ncmd\n" {
+		t.Errorf("order.txt content = %q, want %q", content, "hook\ncmd\n")
+	}
+}
+
+func Test_Create_Rollback_On_Post_Create_Cmd_Failure(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "failing-cmd",
+		"--post-create-cmd", "echo broken >&2; exit 3")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "hook(post-create-cmd): broken")
+	AssertContains(t, stderr, "post-create command failed")
+
+	if cli.FileExists(filepath.Join("worktrees", "failing-cmd")) {
+		t.Error("worktree directory should have been removed on command failure")
+	}
+
+	for _, branch := range listBranches(t, cli.Dir) {
+		if branch == "failing-cmd" {
+			t.Error("branch 'failing-cmd' should have been deleted on rollback")
+		}
+	}
+}
+
+func Test_Create_Config_Post_Create_Cmd_Runs_Unless_Overridden_Or_Hooks_Disabled(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees", "post_create_cmd": "echo \"$WT_NAME\" > setup.txt && echo configured"}`)
+
+	stdout := cli.MustRun("--config", "config.json", "create", "--name", "from-config")
+	AssertContains(t, stdout, "hook(post-create-cmd): configured")
+
+	if content := cli.ReadFile(filepath.Join("worktrees", "from-config", "setup.txt")); content != "from-config\n" {
+		t.Errorf("setup.txt content = %q, want %q", content, "from-config\n")
+	}
+
+	stdout = cli.MustRun("--config", "config.json", "create", "--name", "overridden", "--post-create-cmd", "echo flag")
+	AssertContains(t, stdout, "hook(post-create-cmd): flag")
+	AssertNotContains(t, stdout, "configured")
+
+	stdout = cli.MustRun("--config", "config.json", "create", "--name", "no-hooks", "--no-hooks")
+	AssertNotContains(t, stdout, "configured")
+
+	cli.WriteFile("failing.json", `{"base": "worktrees", "post_create_cmd": "exit 2"}`)
+
+	_, stderr, code := cli.Run("--config", "failing.json", "create", "--name", "failing-config-cmd")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "post-create command failed")
+
+	if cli.FileExists(filepath.Join("worktrees", "failing-config-cmd")) {
+		t.Error("worktree should be rolled back when post_create_cmd fails")
+	}
+}
+
+func Test_Create_No_Checkout_Leaves_Working_Tree_Empty(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.WriteExecutable(".wt/hooks/post-create", "#!/bin/sh\nls -A\n")
+
+	stdout := cli.MustRun("--config", "config.json", "create", "--name", "huge", "--no-checkout")
+	wtPath := extractPath(stdout)
+
+	entries, err := os.ReadDir(wtPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+
+	if !slices.Equal(got, []string{".git", ".wt"}) {
+		t.Errorf("expected only .git and .wt in the worktree, got %v", got)
+	}
+
+	info, err := readWorktreeInfo(fs.NewReal(), wtPath)
+	if err != nil {
+		t.Fatalf("metadata should be written: %v", err)
+	}
+
+	if info.Name != "huge" {
+		t.Errorf("name = %q, want huge", info.Name)
+	}
+
+	AssertNotContains(t, stdout, "hook(post-create): README.md")
+
+	if branch := gitOutput(t, wtPath, "rev-parse", "--abbrev-ref", "HEAD"); branch != "huge" {
+		t.Errorf("HEAD should be on branch huge, got %q", branch)
+	}
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--no-checkout", "--with-changes")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --no-checkout with --with-changes")
+
+	// Every file shows up as deleted until something is checked out
+	_, stderr, code = cli.Run("--config", "config.json", "remove", "huge")
+	if code != 1 {
+		t.Fatalf("expected remove without --force to fail, got %d", code)
+	}
+
+	AssertContains(t, stderr, "uncommitted changes")
+}
+
+func Test_Create_Env_Is_Passed_To_Post_Create_Hook(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteExecutable(".wt/hooks/post-create", `#!/bin/sh
+echo "ticket=$TICKET url=$API_URL name=$WT_NAME"
+`)
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout, stderr, code := cli.Run("--config", "config.json", "create", "--name", "with-env",
+		"--env", "TICKET=ABC-12", "--env", "API_URL=http://x?a=b", "--env", "WT_NAME=ignored")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "hook(post-create): ticket=ABC-12 url=http://x?a=b name=with-env")
+}
+
+func Test_Create_Env_Rejects_Invalid_Assignment(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	for _, assignment := range []string{"NOVALUE", "=value", "1KEY=x", "MY-KEY=x"} {
+		_, stderr, code := cli.Run("--config", "config.json", "create", "--env", assignment)
+		if code != 1 {
+			t.Errorf("--env %s: expected exit code 1, got %d", assignment, code)
+		}
+
+		AssertContains(t, stderr, "invalid --env")
+	}
+
+	if cli.FileExists("worktrees") {
+		t.Error("no worktree should have been created")
+	}
+}
+
+func Test_Create_Dry_Run_JSON_Returns_Plan_Without_Creating(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.MustRun("--config", "config.json", "create", "--name", "existing")
+
+	excludeBefore := cli.ReadFile(filepath.Join(".git", "info", "exclude"))
+
+	stdout, stderr, code := cli.Run("--config", "config.json", "create", "--name", "planned", "--dry-run", "--json")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	var raw map[string]any
+
+	err := json.Unmarshal([]byte(stdout), &raw)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	for _, key := range []string{"name", "agent_id", "id", "path", "branch", "from", "start_commit", "would_run_hook"} {
+		if _, ok := raw[key]; !ok {
+			t.Errorf("plan JSON missing key %q: %s", key, stdout)
+		}
+	}
+
+	var plan jsonCreatePlan
+
+	err = json.Unmarshal([]byte(stdout), &plan)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	out, err := testGitCmd("-C", cli.Dir, "rev-parse", "HEAD").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v\n%s", err, out)
+	}
+
+	head := strings.TrimSpace(string(out))
+
+	if plan.Name != "planned" || plan.Branch != "planned" || plan.ID != 2 {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+
+	if plan.Path != filepath.Join(cli.Dir, "worktrees", "planned") {
+		t.Errorf("plan path = %q", plan.Path)
+	}
+
+	if plan.From != "master" || plan.StartCommit != head {
+		t.Errorf("plan from = %q (%q), want master (%q)", plan.From, plan.StartCommit, head)
+	}
+
+	if plan.WouldRunHook {
+		t.Error("would_run_hook should be false without a post-create hook")
+	}
+
+	if cli.FileExists(filepath.Join("worktrees", "planned")) {
+		t.Error("dry run should not create the worktree directory")
+	}
+
+	for _, branch := range listBranches(t, cli.Dir) {
+		if branch == "planned" {
+			t.Error("dry run should not create the branch")
+		}
+	}
+
+	if cli.ReadFile(filepath.Join(".git", "info", "exclude")) != excludeBefore {
+		t.Error("dry run should not modify .git/info/exclude")
+	}
+}
+
+func Test_Create_Dry_Run_Reports_Hook_And_Skips_It(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteExecutable(".wt/hooks/post-create", "#!/bin/bash\ntouch \"$WT_REPO_ROOT/hook-ran\"\n")
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout := cli.MustRun("--config", "config.json", "create", "--dry-run", "--json")
+
+	var plan jsonCreatePlan
+
+	err := json.Unmarshal([]byte(stdout), &plan)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	if !plan.WouldRunHook {
+		t.Error("would_run_hook should be true with an executable post-create hook")
+	}
+
+	if plan.Name == "" || plan.Name != plan.AgentID || plan.ID != 1 {
+		t.Errorf("unexpected generated plan: %+v", plan)
+	}
+
+	if cli.FileExists("hook-ran") {
+		t.Error("dry run must not run the hook")
+	}
+
+	if cli.FileExists("worktrees") {
+		t.Error("dry run should not create the base directory")
+	}
+
+	text := cli.MustRun("--config", "config.json", "create", "--name", "text-plan", "--dry-run")
+	AssertContains(t, text, "Dry run: wt create (nothing was created)")
+	AssertContains(t, text, "name:        text-plan")
+	AssertContains(t, text, "hook:        yes")
+}
+
+func Test_Create_Dry_Run_Reports_Name_Collision(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.MustRun("--config", "config.json", "create", "--name", "taken")
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "taken", "--dry-run", "--json")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "name already in use")
+}
+
+func Test_Create_Dry_Run_And_Switch_Are_Mutually_Exclusive(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	_, stderr, code := cli.Run("create", "--dry-run", "--switch")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --switch and --dry-run together")
+}
+
+func Test_Create_Name_Slug_Config_Sanitizes_Name(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees", "name_slug": {"lowercase": true}}`)
+
+	stdout := cli.MustRun("--config", "config.json", "create", "--name", "Fix Login Bug (café)")
+
+	AssertContains(t, stdout, "name:        fix-login-bug-caf")
+	AssertContains(t, stdout, "branch:      fix-login-bug-caf")
+
+	if !cli.FileExists("worktrees/fix-login-bug-caf/.wt/worktree.json") {
+		t.Error("worktree directory should use the slugified name")
+	}
+
+	branches := listBranches(t, cli.Dir)
+	if !slices.Contains(branches, "fix-login-bug-caf") {
+		t.Errorf("expected branch fix-login-bug-caf, got %v", branches)
+	}
+}
+
+func Test_Create_Name_Slug_Config_Rejects_Empty_Slug(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees", "name_slug": {}}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "日本語")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "slugification")
+
+	if cli.FileExists("worktrees") {
+		t.Error("nothing should be created when the slug is invalid")
+	}
+}
+
+func Test_Create_Without_Name_Slug_Uses_Name_As_Given(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout := cli.MustRun("--config", "config.json", "create", "--name", "Mixed_Case.Name")
+
+	AssertContains(t, stdout, "name:        Mixed_Case.Name")
+}
+
+func Test_Create_Set_Upstream_Configures_Branch_Remote(t *testing.T) {
+	t.Parallel()
+
+	upstream := initRealGitRepo(t, t.TempDir())
+	local := cloneTestRepo(t, upstream, filepath.Join(t.TempDir(), "local"))
+
+	cli := NewCLITesterAt(t, local)
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	writeTestFile(t, cfgPath, `{"base": "worktrees"}`)
+
+	stdout := cli.MustRun("--config", cfgPath, "create", "--name", "pushable", "--set-upstream", "origin")
+	AssertContains(t, stdout, "upstream:    origin/pushable")
+
+	for key, want := range map[string]string{
+		"branch.pushable.remote": "origin",
+		"branch.pushable.merge":  "refs/heads/pushable",
+	} {
+		out, err := testGitCmd("-C", local, "config", "--get", key).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git config --get %s failed: %v\n%s", key, err, out)
+		}
+
+		if got := strings.TrimSpace(string(out)); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+
+	// Nothing was pushed
+	out, err := testGitCmd("-C", upstream, "branch", "--list", "pushable").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch failed: %v\n%s", err, out)
+	}
+
+	if strings.TrimSpace(string(out)) != "" {
+		t.Error("--set-upstream must not push the branch")
+	}
+
+	wtPath := filepath.Join(local, "worktrees", "pushable")
+
+	infoOut := cli.MustRun("--config", cfgPath, "info", "pushable", "--field", "upstream")
+	if strings.TrimSpace(infoOut) != "origin/pushable" {
+		t.Errorf("info --field upstream = %q, want origin/pushable", infoOut)
+	}
+
+	AssertContains(t, cli.ReadFileAt(wtPath, ".wt/worktree.json"), `"upstream": "origin/pushable"`)
+}
+
+func Test_Create_Set_Upstream_Errors_When_Remote_Missing(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "nowhere", "--set-upstream", "origin")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "remote not found")
+
+	if cli.FileExists(filepath.Join("worktrees", "nowhere")) {
+		t.Error("worktree should not be created when the remote is missing")
+	}
+
+	if slices.Contains(listBranches(t, cli.Dir), "nowhere") {
+		t.Error("branch should not be created when the remote is missing")
+	}
+}
+
+func Test_Create_Lock_Locks_Worktree(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout := cli.MustRun("--config", "config.json", "create", "--name", "keeper", "--lock=long-lived experiment")
+	AssertContains(t, stdout, "locked:      yes")
+
+	cli.MustRun("--config", "config.json", "create", "--name", "bare-lock", "--lock")
+	cli.MustRun("--config", "config.json", "create", "--name", "unlocked")
+
+	entries, err := newTestGit().WorktreeListDetailed(context.Background(), cli.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locks := map[string]WorktreeEntry{}
+	for _, entry := range entries {
+		locks[filepath.Base(entry.Path)] = entry
+	}
+
+	if e := locks["keeper"]; !e.Locked || e.LockReason != "long-lived experiment" {
+		t.Errorf("keeper should be locked with reason, got %+v", e)
+	}
+
+	if e := locks["bare-lock"]; !e.Locked || e.LockReason != "" {
+		t.Errorf("bare-lock should be locked without reason, got %+v", e)
+	}
+
+	if locks["unlocked"].Locked {
+		t.Error("worktrees are not locked without --lock")
+	}
+
+	wtPath := filepath.Join(cli.Dir, "worktrees", "keeper")
+	AssertContains(t, cli.ReadFileAt(wtPath, ".wt/worktree.json"), `"locked": true`)
+
+	infoOut := cli.MustRun("--config", "config.json", "info", "keeper")
+	AssertContains(t, infoOut, "locked:      yes (long-lived experiment)")
+
+	var listed []jsonWorktree
+
+	err = json.Unmarshal([]byte(cli.MustRun("--config", "config.json", "ls", "--json")), &listed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, wt := range listed {
+		if wt.Locked != (wt.Name != "unlocked") {
+			t.Errorf("ls --json: %s locked = %v", wt.Name, wt.Locked)
+		}
+	}
+}
+
+func Test_Create_JSON_Reports_Whether_Hook_Ran(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	createJSON := func(args ...string) jsonCreateOutput {
+		t.Helper()
+
+		stdout, stderr, code := cli.Run(append([]string{"--config", "config.json", "create", "--json"}, args...)...)
+		if code != 0 {
+			t.Fatalf("create %v failed with exit code %d\nstderr: %s", args, code, stderr)
+		}
+
+		var result jsonCreateOutput
+
+		err := json.Unmarshal([]byte(stdout), &result)
+		if err != nil {
+			t.Fatalf("stdout is not valid JSON: %v\n%s", err, stdout)
+		}
+
+		return result
+	}
+
+	// No hook file
+	result := createJSON("--name", "no-hook")
+	if result.HookRan || result.HookSkippedReason != hookSkippedNoHook {
+		t.Errorf("without a hook: hook_ran=%v, hook_skipped_reason=%q", result.HookRan, result.HookSkippedReason)
+	}
+
+	cli.WriteExecutable(".wt/hooks/post-create", "#!/bin/bash\ntouch \"$WT_PATH/hook-ran\"\n")
+
+	// Hook present
+	result = createJSON("--name", "with-hook")
+	if !result.HookRan || result.HookSkippedReason != "" {
+		t.Errorf("with a hook: hook_ran=%v, hook_skipped_reason=%q", result.HookRan, result.HookSkippedReason)
+	}
+
+	if !cli.FileExists("worktrees/with-hook/hook-ran") {
+		t.Error("hook should have run")
+	}
+
+	// --no-hooks
+	result = createJSON("--name", "skipped-hook", "--no-hooks")
+	if result.HookRan || result.HookSkippedReason != hookSkippedNoHooksFlag {
+		t.Errorf("with --no-hooks: hook_ran=%v, hook_skipped_reason=%q", result.HookRan, result.HookSkippedReason)
+	}
+
+	if cli.FileExists("worktrees/skipped-hook/hook-ran") {
+		t.Error("hook should not run with --no-hooks")
+	}
+}
+
+func Test_Create_Pool_Has_Contiguous_IDs_Alongside_Concurrent_Creates(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	const poolSize = 10
+
+	const numSingles = 4
+
+	var wg sync.WaitGroup
+
+	start := make(chan struct{})
+	singleIDs := make(chan int, numSingles)
+
+	for i := range numSingles {
+		wg.Go(func() {
+			<-start
+
+			stdout, stderr, code := cli.Run("--config", "config.json", "create", "--name", fmt.Sprintf("single-%d", i), "--json")
+			if code != 0 {
+				t.Errorf("single create %d failed: %s", i, stderr)
+
+				return
+			}
+
+			var out jsonCreateOutput
+
+			err := json.Unmarshal([]byte(stdout), &out)
+			if err != nil {
+				t.Errorf("invalid JSON from single create: %v\n%s", err, stdout)
+
+				return
+			}
+
+			singleIDs <- out.ID
+		})
+	}
+
+	var (
+		poolStdout, poolStderr string
+		poolCode               int
+	)
+
+	wg.Go(func() {
+		<-start
+
+		poolStdout, poolStderr, poolCode = cli.Run("--config", "config.json", "create", "--pool", "agent", "--count", strconv.Itoa(poolSize), "--json")
+	})
+
+	close(start)
+	wg.Wait()
+	close(singleIDs)
+
+	if poolCode != 0 {
+		t.Fatalf("pool create failed (code %d): %s", poolCode, poolStderr)
+	}
+
+	var pool []jsonCreateOutput
+
+	err := json.Unmarshal([]byte(poolStdout), &pool)
+	if err != nil {
+		t.Fatalf("invalid JSON array: %v\n%s", err, poolStdout)
+	}
+
+	if len(pool) != poolSize {
+		t.Fatalf("expected %d pool worktrees, got %d", poolSize, len(pool))
+	}
+
+	seen := map[int]bool{}
+
+	for i, wt := range pool {
+		if want := fmt.Sprintf("agent-%d", i+1); wt.Name != want {
+			t.Errorf("pool[%d].name = %q, want %q", i, wt.Name, want)
+		}
+
+		if wt.ID != pool[0].ID+i {
+			t.Errorf("pool IDs not contiguous: pool[%d].id = %d, first id %d", i, wt.ID, pool[0].ID)
+		}
+
+		if !cli.FileExists(filepath.Join("worktrees", wt.Name, ".wt", "worktree.json")) {
+			t.Errorf("metadata missing for %s", wt.Name)
+		}
+
+		seen[wt.ID] = true
+	}
+
+	for id := range singleIDs {
+		if seen[id] {
+			t.Errorf("single create got ID %d already used by the pool", id)
+		}
+
+		seen[id] = true
+	}
+}
+
+func Test_Create_Pool_Rolls_Back_All_When_One_Fails(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.WriteExecutable(".wt/hooks/post-create", `#!/bin/sh
+[ "$WT_NAME" = "agent-3" ] && exit 1
+exit 0
+`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--pool", "agent", "--count", "4")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "creating pool worktree agent-3 (pool rolled back)")
+
+	for i := 1; i <= 4; i++ {
+		name := fmt.Sprintf("agent-%d", i)
+		if cli.FileExists(filepath.Join("worktrees", name)) {
+			t.Errorf("worktree %s should be rolled back", name)
+		}
+
+		if slices.Contains(listBranches(t, cli.Dir), name) {
+			t.Errorf("branch %s should be deleted", name)
+		}
+	}
+
+	// agent-1 added the exclude lines, and no worktree is left
+	if cli.FileExists(".git/info/exclude") {
+		AssertNotContains(t, cli.ReadFile(".git/info/exclude"), worktreeExcludePattern)
+	}
+}
+
+func Test_Create_Pool_Fails_Before_Creating_When_A_Name_Is_Taken(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.MustRun("--config", "config.json", "create", "--name", "agent-2")
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--pool", "agent", "--count", "3")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "name already in use")
+	AssertContains(t, stderr, "agent-2")
+
+	if cli.FileExists(filepath.Join("worktrees", "agent-1")) {
+		t.Error("no pool worktree should be created when a name is taken")
+	}
+}
+
+func Test_Create_Pool_Validates_Flags(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"create", "--count", "3", "--switch"}, "cannot use --count with --switch"},
+		{[]string{"create", "--pool", "agent", "--count", "0"}, "--count must be at least 1"},
+		{[]string{"create", "--pool", "agent", "--name", "x"}, "cannot use --pool with --name"},
+		{[]string{"create", "--pool", "agent", "--lock"}, "cannot use --pool with --lock"},
+	}
+
+	for _, tt := range tests {
+		_, stderr, code := cli.Run(tt.args...)
+		if code != 1 {
+			t.Errorf("%v: expected exit code 1, got %d", tt.args, code)
+		}
+
+		AssertContains(t, stderr, tt.want)
+	}
+}
+
+func Test_Create_Count_Creates_Named_Or_Generated_Batch(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout := cli.MustRun("--config", "config.json", "create", "--name", "exp", "--count", "2", "--json")
+
+	var named []jsonCreateOutput
+
+	err := json.Unmarshal([]byte(stdout), &named)
+	if err != nil {
+		t.Fatalf("invalid JSON array: %v\n%s", err, stdout)
+	}
+
+	if len(named) != 2 || named[0].Name != "exp-1" || named[1].Name != "exp-2" {
+		t.Fatalf("expected exp-1 and exp-2, got %+v", named)
+	}
+
+	stdout = cli.MustRun("--config", "config.json", "create", "--count", "3", "--json")
+
+	var generated []jsonCreateOutput
+
+	err = json.Unmarshal([]byte(stdout), &generated)
+	if err != nil {
+		t.Fatalf("invalid JSON array: %v\n%s", err, stdout)
+	}
+
+	if len(generated) != 3 {
+		t.Fatalf("expected 3 worktrees, got %d", len(generated))
+	}
+
+	names := map[string]bool{}
+
+	for i, wt := range generated {
+		if wt.ID != named[1].ID+1+i {
+			t.Errorf("generated[%d].id = %d, want %d", i, wt.ID, named[1].ID+1+i)
+		}
+
+		if names[wt.Name] {
+			t.Errorf("generated name %q used twice", wt.Name)
+		}
+
+		names[wt.Name] = true
+
+		if !cli.FileExists(filepath.Join("worktrees", wt.Name, ".wt", "worktree.json")) {
+			t.Errorf("metadata missing for %s", wt.Name)
+		}
+	}
+
+	stdout = cli.MustRun("--config", "config.json", "create", "--count", "2")
+
+	if strings.Count(stdout, "Created worktree:") != 2 {
+		t.Errorf("expected two created worktrees in text output, got:\n%s", stdout)
+	}
+}
+
+func Test_Create_Count_Rolls_Back_Generated_Batch_When_One_Fails(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.WriteExecutable(".wt/hooks/post-create", `#!/bin/sh
+[ "$WT_ID" = "2" ] && exit 1
+exit 0
+`)
+
+	excludeBefore := cli.ReadFile(".git/info/exclude")
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--count", "3", "--readme", "Fix the bug")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "creating worktree 2 of 3 (batch rolled back)")
+
+	if got := cli.ReadFile(".git/info/exclude"); got != excludeBefore {
+		t.Errorf("exclude file not restored after rollback\nbefore:\n%s\nafter:\n%s", excludeBefore, got)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(cli.Dir, "worktrees"))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 0 {
+		t.Errorf("no worktree should be left after a failed batch, found %d", len(entries))
+	}
+
+	if branches := listBranches(t, cli.Dir); len(branches) != 1 {
+		t.Errorf("only the main branch should be left, got %v", branches)
+	}
+}
+
+func Test_Create_Branch_Description_Sets_Git_Config(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.MustRun("--config", "config.json", "create", "--name", "login-fix", "--branch-description", "Fix the login redirect loop")
+
+	out, err := testGitCmd("-C", cli.Dir, "config", "--get", "branch.login-fix.description").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git config --get failed: %v\n%s", err, out)
+	}
+
+	if got := strings.TrimSpace(string(out)); got != "Fix the login redirect loop" {
+		t.Errorf("branch description = %q, want %q", got, "Fix the login redirect loop")
+	}
+
+	infoOut := cli.MustRun("--config", "config.json", "info", "login-fix", "--field", "branch_description")
+	if strings.TrimSpace(infoOut) != "Fix the login redirect loop" {
+		t.Errorf("info --field branch_description = %q", infoOut)
+	}
+
+	// info reads the description from git, so later edits are reflected
+	out, err = testGitCmd("-C", cli.Dir, "config", "branch.login-fix.description", "Edited later").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git config failed: %v\n%s", err, out)
+	}
+
+	AssertContains(t, cli.MustRun("--config", "config.json", "info", "login-fix"), "description: Edited later")
+}
+
+func Test_Create_Branch_Description_Rolls_Back_When_Config_Write_Fails(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	// A stale config.lock makes every git config write fail
+	cli.WriteFile(".git/config.lock", "")
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--name", "described", "--branch-description", "note")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "setting branch description")
+
+	if cli.FileExists(filepath.Join("worktrees", "described")) {
+		t.Error("worktree should be rolled back")
+	}
+
+	if slices.Contains(listBranches(t, cli.Dir), "described") {
+		t.Error("branch should be deleted")
+	}
+}
+
+func Test_Create_Start_Clean_Contains_Only_Committed_Tree(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees", "template_dir": "template"}`)
+	cli.WriteFile("template/.env", "SECRET=1")
+	gitCommitInDir(t, cli.Dir, ".gitignore", "build/\ntemplate/\n", "ignore build")
+
+	parentPath := extractPath(cli.MustRun("--config", "config.json", "create", "--name", "parent"))
+
+	// Local state in the parent worktree that must not show up in the child
+	parent := NewCLITesterAt(t, parentPath)
+	parent.WriteFile("build/artifact.bin", "binary")
+	parent.WriteFile("scratch.txt", "untracked")
+	parent.WriteFile("README.md", "modified")
+
+	stdout, stderr, code := parent.Run("--config", "../../config.json", "create", "--name", "child", "--start-clean")
+	if code != 0 {
+		t.Fatalf("create --start-clean failed (code %d): %s", code, stderr)
+	}
+
+	childPath := extractPath(stdout)
+
+	if !parent.FileExists(".env") {
+		t.Error("template_dir should still be copied into a plain create")
+	}
+
+	for _, rel := range []string{"build/artifact.bin", "scratch.txt", ".env"} {
+		if parent.FileExistsAt(childPath, rel) {
+			t.Errorf("%s should not be in the new worktree", rel)
+		}
+	}
+
+	out, err := testGitCmd("-C", childPath, "status", "--porcelain", "--ignored", "--untracked-files=all").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git status failed: %v\n%s", err, out)
+	}
+
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		if line != "" && !strings.HasPrefix(line, "!! .wt/") {
+			t.Errorf("unexpected file in clean worktree: %q", line)
+		}
+	}
+}
+
+func Test_Create_Start_Clean_Rejects_Flags_That_Add_Files(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	for _, flag := range []string{"--with-changes", "--readme=do it"} {
+		_, stderr, code := cli.Run("--config", "config.json", "create", "--start-clean", flag)
+		if code != 1 {
+			t.Errorf("%s: expected exit code 1, got %d", flag, code)
+		}
+
+		AssertContains(t, stderr, "cannot use --start-clean with "+strings.SplitN(flag, "=", 2)[0])
+	}
+
+	if cli.FileExists("worktrees") {
+		t.Error("nothing should be created")
+	}
+}
+
+func Test_Create_Eval_Prints_Quoted_Cd_Line(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "my 'work' trees"}`)
+
+	line := cli.MustRun("--config", "config.json", "create", "--name", "eval-wt", "--eval")
+
+	wtPath := filepath.Join(cli.Dir, "my 'work' trees", "eval-wt")
+
+	if line != "export WT_PATH="+shQuote(wtPath)+"; cd "+shQuote(wtPath) {
+		t.Fatalf("unexpected eval line: %q", line)
+	}
+
+	// The line must survive eval in a real shell
+	out, err := exec.Command("sh", "-c", `eval "$1" && pwd && printf '%s\n' "$WT_PATH"`, "sh", line).CombinedOutput()
+	if err != nil {
+		t.Fatalf("eval failed: %v\n%s", err, out)
+	}
+
+	resolved, _ := filepath.EvalSymlinks(wtPath)
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+
+	if len(lines) != 2 || (lines[0] != wtPath && lines[0] != resolved) || lines[1] != wtPath {
+		t.Errorf("expected shell in %q with WT_PATH set, got %q", wtPath, out)
+	}
+}
+
+func Test_Create_Switch_And_Eval_Send_Hook_Output_To_Stderr(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+	cli.WriteExecutable(".wt/hooks/post-create", "#!/bin/sh\necho hook says hi\n")
+
+	for _, flag := range []string{"--switch", "--eval"} {
+		stdout, stderr, code := cli.Run("--config", "config.json", "create", flag)
+		if code != 0 {
+			t.Fatalf("%s: expected exit code 0, got %d\nstderr: %s", flag, code, stderr)
+		}
+
+		if lines := strings.Split(strings.TrimSpace(stdout), "\n"); len(lines) != 1 {
+			t.Errorf("%s: expected a single stdout line, got %q", flag, stdout)
+		}
+
+		AssertNotContains(t, stdout, "hook says hi")
+		AssertContains(t, stderr, "hook says hi")
+	}
+}
+
+func Test_Create_Eval_Fish_Quotes_For_Fish(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "work trees"}`)
+
+	line := cli.MustRun("--config", "config.json", "create", "--name", "fish-wt", "--eval=fish")
+
+	wtPath := filepath.Join(cli.Dir, "work trees", "fish-wt")
+	if line != "set -gx WT_PATH '"+wtPath+"'; cd '"+wtPath+"'" {
+		t.Errorf("unexpected eval line: %q", line)
+	}
+
+	if got := fishQuote(`it's a \ path`); got != `'it\'s a \\ path'` {
+		t.Errorf("fishQuote = %s", got)
+	}
+}
+
+func Test_Create_Eval_Rejects_Invalid_Shell_And_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := cli.Run("--config", "config.json", "create", "--eval=powershell")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "invalid --eval shell")
+
+	for _, flag := range []string{"--json", "--switch", "--dry-run"} {
+		_, stderr, code = cli.Run("--config", "config.json", "create", "--eval", flag)
+		if code != 1 {
+			t.Errorf("%s: expected exit code 1, got %d", flag, code)
+		}
+
+		AssertContains(t, stderr, "cannot use --eval with "+flag)
+	}
+
+	if cli.FileExists("worktrees") {
+		t.Error("no worktree should be created")
+	}
+}
+
+func Test_Create_Base_Flag_Overrides_Config_Base(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout := cli.MustRun("--config", "config.json", "create", "--name", "relative-wt", "--base", "elsewhere")
+
+	AssertContains(t, stdout, "path:        "+filepath.Join(cli.Dir, "elsewhere", "relative-wt"))
+
+	if !cli.FileExists("elsewhere/relative-wt/.wt/worktree.json") || cli.FileExists("worktrees") {
+		t.Error("worktree should be created in the --base directory only")
+	}
+
+	// An absolute base gets a subdirectory named after the repository
+	absBase := filepath.Join(t.TempDir(), "abs")
+
+	stdout = cli.MustRun("--config", "config.json", "create", "--name", "absolute-wt", "--base", absBase, "--json")
+
+	var out struct {
+		Path string `json:"path"`
+	}
+
+	err := json.Unmarshal([]byte(stdout), &out)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	want := filepath.Join(absBase, filepath.Base(cli.Dir), "absolute-wt")
+	if out.Path != want {
+		t.Errorf("expected path %q, got %q", want, out.Path)
+	}
+
+	// The configured base is unchanged for later commands
+	stdout = cli.MustRun("--config", "config.json", "create", "--name", "default-wt")
+
+	AssertContains(t, stdout, "path:        "+filepath.Join(cli.Dir, "worktrees", "default-wt"))
+}
+
+func Test_Create_Base_Flag_Rejects_Empty_Value(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	_, stderr, code := cli.Run("create", "--base", "")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "--base must not be empty")
+}
+
+func Test_Create_Agent_Hint_Prints_WT_Variables_To_Stderr(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout, stderr, code := cli.Run("--config", "config.json", "create", "--json", "--agent-hint", "--name", "hinted")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	var result jsonCreateOutput
+
+	err := json.Unmarshal([]byte(stdout), &result)
+	if err != nil {
+		t.Fatalf("stdout is not pure JSON: %v\nstdout: %s", err, stdout)
+	}
+
+	AssertNotContains(t, stdout, "WT_HINT")
+
+	var hints []string
+
+	for line := range strings.Lines(stderr) {
+		if hint, ok := strings.CutPrefix(strings.TrimSuffix(line, "\n"), "WT_HINT: "); ok {
+			hints = append(hints, hint)
+		}
+	}
+
+	want := []string{
+		"WT_ID=" + strconv.Itoa(result.ID),
+		"WT_AGENT_ID=" + result.AgentID,
+		"WT_NAME=hinted",
+		"WT_PATH=" + result.Path,
+		"WT_BASE_BRANCH=master",
+		"WT_REPO_ROOT=" + cli.Dir,
+	}
+	if !slices.Equal(hints, want) {
+		t.Errorf("hint lines = %q, want %q", hints, want)
+	}
+
+	// Without the flag nothing is printed
+	_, stderr, _ = cli.Run("--config", "config.json", "create", "--switch", "--name", "quiet")
+	AssertNotContains(t, stderr, "WT_HINT")
+}
+
+func Test_Create_Agent_Hint_Rejects_Dry_Run_And_Pool(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	for _, args := range [][]string{{"--dry-run"}, {"--pool", "p", "--count", "2"}} {
+		_, stderr, code := cli.Run(append([]string{"create", "--agent-hint"}, args...)...)
+		if code != 1 {
+			t.Errorf("%v: expected exit code 1, got %d", args, code)
+		}
+
+		AssertContains(t, stderr, "cannot use --agent-hint with "+args[0])
+	}
+}
+
+func Test_Create_Porcelain_Prints_Key_Value_Lines_Only(t *testing.T) {
+	t.Parallel()
+
+	cli := NewCLITester(t)
+	initRealGitRepo(t, cli.Dir)
+
+	cli.WriteExecutable(".wt/hooks/post-create", "#!/bin/bash\necho hook says hello\n")
+	cli.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout, stderr, code := cli.Run("--config", "config.json", "create", "--name", "porcelain-wt", "--porcelain")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	info, err := readWorktreeInfo(fs.NewReal(), filepath.Join(cli.Dir, "worktrees", "porcelain-wt"))
+	if err != nil {
+		t.Fatalf("reading metadata: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"status\tcreated",
+		"name\tporcelain-wt",
+		"agent_id\t" + info.AgentID,
+		"id\t" + strconv.Itoa(info.ID),
+		"path\t" + filepath.Join(cli.Dir, "worktrees", "porcelain-wt"),
+		"branch\tporcelain-wt",
+		"from\t" + info.BaseBranch,
+		"locked\tfalse",
+	}, "\n") + "\n"
+
+	if stdout != want {
+		t.Errorf("stdout =\n%s\nwant\n%s", stdout, want)
+	}
+
+	AssertContains(t, stderr, "hook says hello")
+
+	for _, conflict := range []string{"--json", "--switch", "--eval", "--dry-run"} {
+		_, stderr, code := cli.Run("--config", "config.json", "create", "--porcelain", conflict)
+		if code != 1 {
+			t.Errorf("%s: expected exit code 1, got %d", conflict, code)
+		}
+
+		AssertContains(t, stderr, "cannot use --porcelain with "+conflict)
+	}
+}
+
+func Test_Create_Branch_Prefix_Names_Branch_Apart_From_Directory(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees", "branch_prefix": "agent/"}`)
+
+	stdout := c.MustRun("--config", "config.json", "create", "--name", "feat")
+	AssertContains(t, stdout, "branch:      agent/feat")
+
+	wtPath := extractPath(stdout)
+	if wtPath != filepath.Join(c.Dir, "worktrees", "feat") {
+		t.Errorf("expected path without the prefix, got %s", wtPath)
+	}
+
+	if branch := gitOutput(t, wtPath, "rev-parse", "--abbrev-ref", "HEAD"); branch != "agent/feat" {
+		t.Errorf("expected branch agent/feat checked out, got %q", branch)
+	}
+
+	info, err := readWorktreeInfo(fs.NewReal(), wtPath)
+	if err != nil {
+		t.Fatalf("reading metadata: %v", err)
+	}
+
+	if info.Name != "feat" || info.Branch != "agent/feat" {
+		t.Errorf("expected name feat and branch agent/feat in metadata, got %q and %q", info.Name, info.Branch)
+	}
+
+	var out jsonCreateOutput
+
+	err = json.Unmarshal([]byte(c.MustRun("--config", "config.json", "create", "--name", "other", "--json")), &out)
+	if err != nil {
+		t.Fatalf("parsing create JSON: %v", err)
+	}
+
+	if out.Branch != "agent/other" {
+		t.Errorf("expected JSON branch agent/other, got %q", out.Branch)
+	}
+
+	// Renaming keeps the prefix, removing deletes the prefixed branch
+	AssertContains(t, c.MustRun("--config", "config.json", "rename", "other", "renamed"), "branch:      agent/renamed")
+	c.MustRun("--config", "config.json", "remove", "renamed", "--with-branch", "--force")
+
+	if slices.Contains(listBranches(t, c.Dir), "agent/renamed") {
+		t.Error("expected remove --with-branch to delete agent/renamed")
+	}
+
+	// The prefixed branch is what collides
+	out2, err := testGitCmd("-C", c.Dir, "branch", "agent/taken").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch failed: %v\n%s", err, out2)
+	}
+
+	_, stderr, code := c.Run("--config", "config.json", "create", "--name", "taken")
+	if code != 1 {
+		t.Errorf("expected exit code 1 when agent/taken exists, got %d", code)
+	}
+
+	AssertContains(t, stderr, "agent/taken")
+}
+
+func Test_Create_Lock_Timeout_Fails_Fast_When_Lock_Is_Held(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	lock, err := fs.NewLocker(fs.NewReal()).LockWithTimeout(context.Background(), filepath.Join(c.Dir, ".git", "wt.lock"))
+	if err != nil {
+		t.Fatalf("taking lock: %v", err)
+	}
+
+	defer func() { _ = lock.Close() }()
+
+	start := time.Now()
+	_, stderr, code := c.Run("--config", "config.json", "--lock-timeout", "200ms", "create", "--name", "blocked")
+
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "could not acquire the create lock within 200ms")
+
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("create should give up after about 200ms, took %s", elapsed)
+	}
+
+	if c.FileExists("worktrees/blocked") {
+		t.Error("no worktree should be created without the lock")
+	}
+
+	_, stderr, code = c.Run("--config", "config.json", "--lock-timeout", "0", "rename", "x", "y")
+	if code != 1 {
+		t.Fatalf("expected rename to fail, got %d", code)
+	}
+
+	AssertContains(t, stderr, "could not acquire the create lock within 0s")
+}
+
+func Test_Create_Lock_Timeout_Recovers_After_Holder_Crashes(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	// exec keeps the locked fd in the process we kill, with no child
+	// left holding a copy of it
+	holder := exec.Command("bash", "-c", `exec 200>.git/wt.lock && flock 200 && touch holding && exec sleep 60`)
+	holder.Dir = c.Dir
+
+	err := holder.Start()
+	if err != nil {
+		t.Fatalf("starting lock holder: %v", err)
+	}
+
+	defer func() { _ = holder.Process.Kill() }()
+
+	for deadline := time.Now().Add(5 * time.Second); !c.FileExists("holding"); {
+		if time.Now().After(deadline) {
+			t.Fatal("lock holder did not take the lock")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_, stderr, code := c.Run("--config", "config.json", "--lock-timeout", "100ms", "create", "--name", "while-held")
+	if code != 1 {
+		t.Fatalf("expected create to fail while the lock is held, got %d", code)
+	}
+
+	AssertContains(t, stderr, "could not acquire the create lock")
+
+	_ = holder.Process.Kill()
+	_ = holder.Wait()
+
+	c.MustRun("--config", "config.json", "--lock-timeout", "1s", "create", "--name", "after-crash")
+}
+
+func Test_Lock_Timeout_Rejects_Invalid_Values(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	for _, value := range []string{"soon", "-1s"} {
+		_, stderr, code := c.Run("--lock-timeout", value, "ls")
+		if code != 1 {
+			t.Errorf("--lock-timeout %s: expected exit code 1, got %d", value, code)
+		}
+
+		AssertContains(t, stderr, "invalid lock timeout")
+	}
+
+	c.Env["WT_LOCK_TIMEOUT"] = "never"
+
+	_, stderr, code := c.Run("ls")
+	if code != 1 {
+		t.Errorf("WT_LOCK_TIMEOUT=never: expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, `WT_LOCK_TIMEOUT="never"`)
+}
@@ -0,0 +1,420 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// Doctor check statuses.
+const (
+	doctorStatusOK   = "ok"
+	doctorStatusWarn = "warn"
+	doctorStatusSkip = "skip"
+	doctorStatusFail = "fail"
+)
+
+var (
+	// errNoDeviceID is returned when a stat result carries no device ID
+	// (non-Unix filesystems or fakes).
+	errNoDeviceID = errors.New("device ID not available")
+
+	// errDoctorChecksFailed is returned when any check has status fail.
+	errDoctorChecksFailed = errors.New("some doctor checks failed")
+)
+
+// DoctorCmd returns the doctor command.
+func DoctorCmd(cfg Config, fsys fs.FS, git *Git, env map[string]string) *Command {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	flags.BoolP("help", "h", false, "Show help")
+	flags.Bool("json", false, "Output as JSON")
+	flags.Bool("run-hooks", false, "Also run each valid hook once with WT_DRY_RUN=1")
+	flags.Bool("fix", false, "Repair problems that are safe to repair")
+
+	return &Command{
+		Flags: flags,
+		Usage: "doctor [flags]",
+		Short: "Diagnose common setup problems",
+		Long: `Check the repository and configuration for problems that make wt
+commands fail in non-obvious ways. Each check reports ok, warn, skip or
+fail. Warnings do not change the exit code; any failed check makes it 1.
+
+Checks:
+  same_filesystem  For a relative base, the repository's .git directory and
+                   the base directory are on the same filesystem. Mounts or
+                   symlinks in between can make worktrees slow, or make
+                   'git worktree add' fail.
+  create_lock      The lock file .git/wt.lock, taken by create and rename,
+                   is not held by another process and can be locked.
+  hook:<name>      For each hook in the hooks directory (.wt/hooks, or
+                   hooks_dir; pre-create, post-create, pre-delete): it is
+                   executable and its #! interpreter exists. Skipped if
+                   the hook is absent.
+
+With --run-hooks, each hook that passes is also run once with the usual
+WT_* variables (describing a placeholder worktree) plus WT_DRY_RUN=1, so
+hook authors can test them without creating a worktree. WT_PATH and the
+working directory are an empty temporary directory, removed afterwards. Hooks should check WT_DRY_RUN and skip side effects. A non-zero
+exit fails the check. Hook output goes to stderr.
+
+The create lock is an flock on .git/wt.lock, which the kernel releases when
+its holder exits, so a lock file left behind by a crashed process does not
+block anything and is never deleted. A lock that stays held points to a
+wt process that is still running (or hung). With --fix, a wt.lock that
+cannot be locked because it is not a regular file (e.g. an empty
+directory) is removed; nothing can hold such a lock.`,
+		Exec: func(ctx context.Context, _ io.Reader, stdout, stderr io.Writer, _ []string) error {
+			jsonOutput, _ := flags.GetBool("json")
+			runHooks, _ := flags.GetBool("run-hooks")
+			fix, _ := flags.GetBool("fix")
+
+			return execDoctor(ctx, stdout, stderr, cfg, fsys, git, env, jsonOutput, runHooks, fix)
+		},
+	}
+}
+
+// doctorCheck is the result of a single doctor check.
+type doctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+func execDoctor(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	env map[string]string,
+	jsonOutput, runHooks, fix bool,
+) error {
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	gitCommonDir, err := git.GitCommonDir(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return fmt.Errorf("cannot determine git directory: %w", err)
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	hooksDir, err := resolveHooksDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	checks := []doctorCheck{
+		checkSameFilesystem(fsys, cfg, gitCommonDir, baseDir),
+		checkCreateLock(ctx, fsys, gitCommonDir, fix),
+	}
+
+	for _, hookName := range hookNames {
+		checks = append(checks, checkHook(ctx, stderr, fsys, env, mainRepoRoot, hooksDir, hookName, runHooks))
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		encodeErr := enc.Encode(checks)
+		if encodeErr != nil {
+			return fmt.Errorf("encoding JSON: %w", encodeErr)
+		}
+	} else {
+		for _, check := range checks {
+			fprintf(stdout, "%-5s %s: %s\n", check.Status, check.Name, check.Message)
+		}
+	}
+
+	for _, check := range checks {
+		if check.Status == doctorStatusFail {
+			return errDoctorChecksFailed
+		}
+	}
+
+	return nil
+}
+
+// checkSameFilesystem compares the device IDs of the git directory and the
+// base directory. A base that does not exist yet is compared by its nearest
+// existing parent, which is where create would make it.
+func checkSameFilesystem(fsys fs.FS, cfg Config, gitCommonDir, baseDir string) doctorCheck {
+	check := doctorCheck{Name: "same_filesystem"}
+
+	if IsAbsolutePath(cfg.Base) {
+		check.Status = doctorStatusSkip
+		check.Message = fmt.Sprintf("base %s is absolute", cfg.Base)
+
+		return check
+	}
+
+	gitStat, err := statDevice(fsys, gitCommonDir)
+	if err != nil {
+		check.Status = doctorStatusWarn
+		check.Message = fmt.Sprintf("cannot stat %s: %v", gitCommonDir, err)
+
+		return check
+	}
+
+	existing := nearestExistingDir(fsys, baseDir)
+
+	baseStat, err := statDevice(fsys, existing)
+	if err != nil {
+		check.Status = doctorStatusWarn
+		check.Message = fmt.Sprintf("cannot stat %s: %v", existing, err)
+
+		return check
+	}
+
+	if gitStat.Dev != baseStat.Dev {
+		check.Status = doctorStatusWarn
+		check.Message = fmt.Sprintf("%s and base directory %s are on different filesystems (devices %d and %d); "+
+			"check for mounts or symlinks, worktrees there may be slow or fail to create",
+			gitCommonDir, baseDir, gitStat.Dev, baseStat.Dev)
+
+		return check
+	}
+
+	check.Status = doctorStatusOK
+	check.Message = fmt.Sprintf("%s and base directory %s are on the same filesystem", gitCommonDir, baseDir)
+
+	return check
+}
+
+// doctorLockProbeTimeout is how long checkCreateLock tries to take the
+// create lock. Creates hold it only briefly, so a lock still held after this
+// is reported.
+const doctorLockProbeTimeout = 500 * time.Millisecond
+
+// checkCreateLock checks that the create lock can be taken. It takes and
+// releases the lock like any other wt command would, so it is safe to run
+// while other wt processes are running. With fix, a lock path that is not a
+// regular file is removed, since no process can hold a lock on it; a regular
+// lock file is never removed, because a process waiting on it would then
+// lock a different file than the next one.
+func checkCreateLock(ctx context.Context, fsys fs.FS, gitCommonDir string, fix bool) doctorCheck {
+	check := doctorCheck{Name: "create_lock"}
+	lockPath := worktreeLockPath(gitCommonDir)
+
+	info, err := fsys.Stat(lockPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			check.Status = doctorStatusOK
+			check.Message = fmt.Sprintf("no lock file at %s", lockPath)
+
+			return check
+		}
+
+		check.Status = doctorStatusFail
+		check.Message = fmt.Sprintf("cannot stat %s: %v", lockPath, err)
+
+		return check
+	}
+
+	if !info.Mode().IsRegular() {
+		if !fix {
+			check.Status = doctorStatusFail
+			check.Message = fmt.Sprintf("%s is not a regular file, creates cannot lock it (fix with: wt doctor --fix)", lockPath)
+
+			return check
+		}
+
+		removeErr := fsys.Remove(lockPath)
+		if removeErr != nil {
+			check.Status = doctorStatusFail
+			check.Message = fmt.Sprintf("%s is not a regular file and cannot be removed: %v", lockPath, removeErr)
+
+			return check
+		}
+
+		check.Status = doctorStatusOK
+		check.Message = fmt.Sprintf("removed %s, which was not a regular file", lockPath)
+
+		return check
+	}
+
+	lockCtx, lockCancel := context.WithTimeout(ctx, doctorLockProbeTimeout)
+	defer lockCancel()
+
+	lock, err := fs.NewLocker(fsys).LockWithTimeout(lockCtx, lockPath)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			check.Status = doctorStatusWarn
+			check.Message = fmt.Sprintf("%s is held by another process (a running wt create or rename); "+
+				"if none is running, look for a hung wt process", lockPath)
+
+			return check
+		}
+
+		check.Status = doctorStatusFail
+		check.Message = fmt.Sprintf("cannot lock %s: %v", lockPath, err)
+
+		return check
+	}
+
+	_ = lock.Close()
+
+	check.Status = doctorStatusOK
+	check.Message = fmt.Sprintf("%s is not held", lockPath)
+
+	return check
+}
+
+// checkHook checks that <hooksDir>/<hookName> is executable and that its
+// interpreter exists, and with runHook runs it once with WT_DRY_RUN=1.
+func checkHook(
+	ctx context.Context,
+	stderr io.Writer,
+	fsys fs.FS,
+	env map[string]string,
+	mainRepoRoot, hooksDir, hookName string,
+	runHook bool,
+) doctorCheck {
+	check := doctorCheck{Name: "hook:" + hookName}
+
+	if hooksDir == "" {
+		check.Status = doctorStatusSkip
+		check.Message = "hooks disabled by --no-hooks"
+
+		return check
+	}
+
+	hookPath := filepath.Join(hooksDir, hookName)
+
+	info, err := fsys.Stat(hookPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			check.Status = doctorStatusSkip
+			check.Message = "no hook at " + hookPath
+
+			return check
+		}
+
+		check.Status = doctorStatusFail
+		check.Message = fmt.Sprintf("cannot stat %s: %v", hookPath, err)
+
+		return check
+	}
+
+	if info.IsDir() || info.Mode()&0o111 == 0 {
+		check.Status = doctorStatusFail
+		check.Message = fmt.Sprintf("%s is not executable (fix with: chmod +x %s)", hookPath, hookPath)
+
+		return check
+	}
+
+	if interpreter := hookInterpreter(fsys, hookPath); interpreter != "" && filepath.IsAbs(interpreter) {
+		if _, statErr := fsys.Stat(interpreter); statErr != nil {
+			check.Status = doctorStatusFail
+			check.Message = fmt.Sprintf("%s: interpreter %s not found", hookPath, interpreter)
+
+			return check
+		}
+	}
+
+	check.Status = doctorStatusOK
+	check.Message = hookPath + " is executable"
+
+	if !runHook {
+		return check
+	}
+
+	// A hook that ignores WT_DRY_RUN only touches a throwaway directory
+	dryRunPath, err := os.MkdirTemp("", "wt-doctor-")
+	if err != nil {
+		check.Status = doctorStatusFail
+		check.Message = fmt.Sprintf("dry run failed: creating temp dir: %v", err)
+
+		return check
+	}
+
+	defer func() { _ = fsys.RemoveAll(dryRunPath) }()
+
+	dryRunInfo := &WorktreeInfo{Name: "doctor-dry-run", AgentID: "doctor-dry-run"}
+	wtEnv := hookEnv(dryRunInfo, dryRunPath, mainRepoRoot)
+	wtEnv["WT_DRY_RUN"] = "1"
+
+	err = execHook(ctx, hookName, []string{hookPath}, env, wtEnv, dryRunPath, stderr, stderr)
+	if err != nil {
+		check.Status = doctorStatusFail
+		check.Message = fmt.Sprintf("dry run failed: %v", err)
+
+		return check
+	}
+
+	check.Message += ", dry run passed"
+
+	return check
+}
+
+// hookInterpreter returns the interpreter named on the #! line of the file
+// at path, or "" if there is none or it cannot be read.
+func hookInterpreter(fsys fs.FS, path string) string {
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	line, _, _ := strings.Cut(string(data), "\n")
+
+	shebang, ok := strings.CutPrefix(line, "#!")
+	if !ok {
+		return ""
+	}
+
+	fields := strings.Fields(shebang)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return fields[0]
+}
+
+// nearestExistingDir returns path, or its closest ancestor that exists.
+func nearestExistingDir(fsys fs.FS, path string) string {
+	for {
+		_, err := fsys.Stat(path)
+		if err == nil || !errors.Is(err, os.ErrNotExist) {
+			return path
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+
+		path = parent
+	}
+}
+
+// statDevice returns the raw stat of path (following symlinks), whose Dev
+// field identifies the device holding it.
+func statDevice(fsys fs.FS, path string) (*syscall.Stat_t, error) {
+	info, err := fsys.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat: %w", err)
+	}
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, errNoDeviceID
+	}
+
+	return stat, nil
+}
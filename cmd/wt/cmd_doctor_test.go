@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+)
+
+func Test_Doctor_Reports_Same_Filesystem_For_Relative_Base(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	// The base does not exist yet; its parent (the repo) is compared instead
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout := c.MustRun("--config", "config.json", "doctor")
+	AssertContains(t, stdout, "ok    same_filesystem:")
+	AssertContains(t, stdout, "are on the same filesystem")
+}
+
+func Test_Doctor_JSON_Skips_Filesystem_Check_For_Absolute_Base(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "`+t.TempDir()+`"}`)
+
+	stdout := c.MustRun("--config", "config.json", "doctor", "--json")
+
+	var checks []doctorCheck
+
+	err := json.Unmarshal([]byte(stdout), &checks)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	if len(checks) == 0 || checks[0].Name != "same_filesystem" || checks[0].Status != doctorStatusSkip {
+		t.Errorf("expected skipped same_filesystem check, got %+v", checks)
+	}
+}
+
+// doctorChecksByName runs doctor --json with args and returns the checks
+// by name, along with the exit code.
+func doctorChecksByName(t *testing.T, c *CLI, args ...string) (map[string]doctorCheck, int) {
+	t.Helper()
+
+	stdout, stderr, code := c.Run(append([]string{"doctor", "--json"}, args...)...)
+
+	var checks []doctorCheck
+
+	err := json.Unmarshal([]byte(stdout), &checks)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s\nstderr: %s", err, stdout, stderr)
+	}
+
+	byName := make(map[string]doctorCheck, len(checks))
+	for _, check := range checks {
+		byName[check.Name] = check
+	}
+
+	return byName, code
+}
+
+func Test_Doctor_Checks_Present_Absent_And_Non_Executable_Hooks(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteExecutable(".wt/hooks/post-create", "#!/bin/sh\nexit 0\n")
+
+	checks, code := doctorChecksByName(t, c)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+
+	if got := checks["hook:post-create"]; got.Status != doctorStatusOK {
+		t.Errorf("post-create: expected ok, got %+v", got)
+	}
+
+	if got := checks["hook:pre-delete"]; got.Status != doctorStatusSkip {
+		t.Errorf("pre-delete: expected skip for absent hook, got %+v", got)
+	}
+
+	c.WriteFile(".wt/hooks/pre-delete", "#!/bin/sh\nexit 0\n")
+
+	checks, code = doctorChecksByName(t, c)
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for a non-executable hook, got %d", code)
+	}
+
+	got := checks["hook:pre-delete"]
+	if got.Status != doctorStatusFail {
+		t.Errorf("pre-delete: expected fail, got %+v", got)
+	}
+
+	AssertContains(t, got.Message, "chmod +x")
+}
+
+func Test_Doctor_Fails_Hook_With_Missing_Interpreter(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteExecutable(".wt/hooks/post-create", "#!/nonexistent/bash\nexit 0\n")
+
+	checks, code := doctorChecksByName(t, c)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	got := checks["hook:post-create"]
+	if got.Status != doctorStatusFail {
+		t.Fatalf("expected fail, got %+v", got)
+	}
+
+	AssertContains(t, got.Message, "interpreter /nonexistent/bash not found")
+}
+
+func Test_Doctor_Run_Hooks_Runs_Them_With_WT_DRY_RUN(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteExecutable(".wt/hooks/post-create", `#!/bin/sh
+echo "dry run: $WT_DRY_RUN"
+[ "$WT_DRY_RUN" = "1" ]
+`)
+	c.WriteExecutable(".wt/hooks/pre-delete", "#!/bin/sh\necho broken >&2\nexit 2\n")
+
+	stdout, stderr, code := c.Run("doctor", "--run-hooks")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for a failing hook, got %d\nstdout: %s", code, stdout)
+	}
+
+	AssertContains(t, stdout, "ok    hook:post-create:")
+	AssertContains(t, stdout, "dry run passed")
+	AssertContains(t, stdout, "fail  hook:pre-delete: dry run failed")
+	AssertContains(t, stderr, "hook(post-create): dry run: 1")
+	AssertContains(t, stderr, "hook(pre-delete): broken")
+}
+
+func Test_Doctor_Run_Hooks_Uses_Temp_Dir_As_WT_PATH(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	// A hook that ignores WT_DRY_RUN and writes into its worktree
+	c.WriteExecutable(".wt/hooks/post-create", `#!/bin/sh
+echo "path: $WT_PATH"
+echo "cwd: $(pwd)"
+touch "$WT_PATH/created-by-hook" created-in-cwd
+`)
+
+	_, stderr, code := c.Run("doctor", "--run-hooks")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertNotContains(t, stderr, "path: "+c.Dir+"\n")
+	AssertNotContains(t, stderr, "cwd: "+c.Dir+"\n")
+
+	for _, name := range []string{"created-by-hook", "created-in-cwd"} {
+		if _, err := os.Stat(filepath.Join(c.Dir, name)); err == nil {
+			t.Errorf("hook wrote %s into the repository", name)
+		}
+	}
+
+	_, rest, _ := strings.Cut(stderr, "path: ")
+	dryRunPath, _, _ := strings.Cut(rest, "\n")
+	if dryRunPath == "" {
+		t.Fatalf("hook did not print WT_PATH\nstderr: %s", stderr)
+	}
+
+	if _, err := os.Stat(dryRunPath); !os.IsNotExist(err) {
+		t.Errorf("expected temp dir %q to be removed, stat: %v", dryRunPath, err)
+	}
+}
+
+func Test_Doctor_Create_Lock_Left_Behind_Is_Not_Held_And_Kept(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	checks, _ := doctorChecksByName(t, c)
+	AssertContains(t, checks["create_lock"].Message, "no lock file at")
+
+	// A lock file left behind by a crashed process, not held by anyone
+	c.WriteFile(".git/wt.lock", "")
+
+	checks, code := doctorChecksByName(t, c, "--fix")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+
+	got := checks["create_lock"]
+	if got.Status != doctorStatusOK {
+		t.Errorf("expected ok, got %+v", got)
+	}
+
+	AssertContains(t, got.Message, "is not held")
+
+	if !c.FileExists(".git/wt.lock") {
+		t.Error("--fix should keep a regular lock file")
+	}
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "after-stale-lock")
+}
+
+func Test_Doctor_Create_Lock_Warns_When_Held(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	lock, err := fs.NewLocker(fs.NewReal()).LockWithTimeout(context.Background(), filepath.Join(c.Dir, ".git", "wt.lock"))
+	if err != nil {
+		t.Fatalf("taking lock: %v", err)
+	}
+
+	defer func() { _ = lock.Close() }()
+
+	checks, code := doctorChecksByName(t, c, "--fix")
+	if code != 0 {
+		t.Fatalf("expected exit code 0 for a warning, got %d", code)
+	}
+
+	got := checks["create_lock"]
+	if got.Status != doctorStatusWarn {
+		t.Errorf("expected warn, got %+v", got)
+	}
+
+	AssertContains(t, got.Message, "is held by another process")
+}
+
+func Test_Doctor_Fix_Removes_Create_Lock_That_Is_Not_A_File(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	err := fs.NewReal().MkdirAll(filepath.Join(c.Dir, ".git", "wt.lock"), 0o755)
+	if err != nil {
+		t.Fatalf("creating directory: %v", err)
+	}
+
+	checks, code := doctorChecksByName(t, c)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, checks["create_lock"].Message, "fix with: wt doctor --fix")
+
+	checks, code = doctorChecksByName(t, c, "--fix")
+	if code != 0 {
+		t.Fatalf("expected exit code 0 after --fix, got %d", code)
+	}
+
+	AssertContains(t, checks["create_lock"].Message, "removed")
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "after-fix")
+}
+
+func Test_Doctor_Returns_Error_When_Not_In_Git_Repo(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+
+	_, stderr, code := c.Run("doctor")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "not a git repository")
+}
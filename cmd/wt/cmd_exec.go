@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// Errors for exec command.
+var (
+	errExecCommandRequired = errors.New("command is required (usage: wt exec [flags] <command> [args...])")
+	errExecFailed          = errors.New("command failed")
+)
+
+// execNotStartedCode is the exit code reported when the command could not be
+// started at all (e.g. not found), matching the shell's convention.
+const execNotStartedCode = 127
+
+// execSignalCodeBase plus the signal number is the exit code reported when
+// the command was killed by a signal, as in the shell.
+const execSignalCodeBase = 128
+
+// ExecCmd returns the exec command.
+func ExecCmd(cfg Config, fsys fs.FS, git *Git, env map[string]string) *Command {
+	flags := flag.NewFlagSet("exec", flag.ContinueOnError)
+	flags.SetInterspersed(false) // flags after the command belong to it
+	flags.BoolP("help", "h", false, "Show help")
+	flags.Bool("json", false, "Output a JSON array of per-worktree results (with captured output)")
+
+	return &Command{
+		Flags: flags,
+		Usage: "exec [flags] <command> [args...]",
+		Short: "Run a command in every worktree",
+		Long: `Run a command in each wt-managed worktree of the repository, one after
+another. The command is run directly (not through a shell) with the
+worktree as its working directory and the same WT_* environment as hooks.
+
+Flags must come before the command; everything from the command on is
+passed to it unchanged.
+
+Without --json, each worktree's output is shown under a "==> <name>" header.
+With --json, output is captured and a JSON array is printed at the end with
+name, path, exit_code, duration_ms, stdout and stderr per worktree.
+
+A command that cannot be started is reported with exit_code 127, one
+killed by a signal with 128 plus the signal number, as in the shell.
+
+The exit code is 1 if the command failed in any worktree.`,
+		Exec: func(ctx context.Context, _ io.Reader, stdout, stderr io.Writer, args []string) error {
+			jsonOutput, _ := flags.GetBool("json")
+
+			return execExec(ctx, stdout, stderr, cfg, fsys, git, env, args, jsonOutput)
+		},
+	}
+}
+
+// execResult is the outcome of running the command in one worktree.
+type execResult struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	ExitCode   int    `json:"exit_code"`
+	DurationMs int64  `json:"duration_ms"`
+	Stdout     string `json:"stdout"`
+	Stderr     string `json:"stderr"`
+	Error      string `json:"error,omitempty"` // Set when the command could not be started
+}
+
+func execExec(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	env map[string]string,
+	argv []string,
+	jsonOutput bool,
+) error {
+	if len(argv) == 0 {
+		return errExecCommandRequired
+	}
+
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("scanning worktrees: %w", err)
+	}
+
+	results := make([]execResult, 0, len(worktrees))
+	failed := 0
+
+	for _, wt := range worktrees {
+		if ctx.Err() != nil {
+			break
+		}
+
+		var result execResult
+
+		if jsonOutput {
+			var outBuf, errBuf bytes.Buffer
+
+			result = runInWorktree(ctx, &outBuf, &errBuf, env, &wt.WorktreeInfo, wt.Path, mainRepoRoot, argv)
+			result.Stdout = outBuf.String()
+			result.Stderr = errBuf.String()
+		} else {
+			fprintf(stdout, "==> %s (%s)\n", wt.Name, wt.Path)
+
+			result = runInWorktree(ctx, stdout, stderr, env, &wt.WorktreeInfo, wt.Path, mainRepoRoot, argv)
+
+			if result.Error != "" {
+				fprintln(stderr, "error:", result.Error)
+			}
+		}
+
+		if result.ExitCode != 0 {
+			failed++
+		}
+
+		results = append(results, result)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		encodeErr := enc.Encode(results)
+		if encodeErr != nil {
+			return fmt.Errorf("encoding JSON: %w", encodeErr)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%w in %d of %d worktrees", errExecFailed, failed, len(results))
+	}
+
+	return ctx.Err()
+}
+
+// runInWorktree runs argv in wtPath with the hook environment and reports
+// its exit code and duration. Output goes to stdout and stderr as is.
+func runInWorktree(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	env map[string]string,
+	info *WorktreeInfo,
+	wtPath, mainRepoRoot string,
+	argv []string,
+) execResult {
+	result := execResult{Name: info.Name, Path: wtPath}
+
+	cmd := newChildCmd(ctx, wtPath, argv, env, hookEnv(info, wtPath, mainRepoRoot))
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	var exitErr *exec.ExitError
+
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+		result.ExitCode = exitErr.ExitCode()
+	case errors.As(err, &exitErr):
+		// Killed by a signal: 128+signal, like the shell reports it
+		result.ExitCode = execSignalCodeBase
+
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			result.ExitCode += int(status.Signal())
+		}
+	default:
+		result.ExitCode = execNotStartedCode
+		result.Error = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// setupExecWorktrees creates worktrees "good" and "bad" and a script outside
+// them that fails (exit 3) only in the worktree named "bad".
+func setupExecWorktrees(t *testing.T, c *CLI) string {
+	t.Helper()
+
+	initRepoWithConfig(t, c)
+
+	c.MustRun("--config", "config.json", "create", "--name", "good")
+	c.MustRun("--config", "config.json", "create", "--name", "bad")
+
+	c.WriteExecutable("check.sh", `#!/bin/sh
+if [ "$WT_NAME" = "bad" ]; then
+  echo "broken in $WT_NAME" >&2
+  exit 3
+fi
+echo "ok in $WT_NAME"
+`)
+
+	return filepath.Join(c.Dir, "check.sh")
+}
+
+func Test_Exec_JSON_Reports_Per_Worktree_Results(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	script := setupExecWorktrees(t, c)
+
+	stdout, stderr, code := c.Run("--config", "config.json", "exec", "--json", script)
+	if code != 1 {
+		t.Fatalf("expected exit code 1 when a worktree fails, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stderr, "command failed in 1 of 2 worktrees")
+
+	var results []execResult
+
+	err := json.Unmarshal([]byte(stdout), &results)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %+v", len(results), results)
+	}
+
+	byName := map[string]execResult{}
+	for _, r := range results {
+		byName[r.Name] = r
+	}
+
+	good, bad := byName["good"], byName["bad"]
+
+	if good.ExitCode != 0 || good.Stdout != "ok in good\n" || good.Stderr != "" {
+		t.Errorf("unexpected result for good: %+v", good)
+	}
+
+	if bad.ExitCode != 3 || bad.Stdout != "" || bad.Stderr != "broken in bad\n" {
+		t.Errorf("unexpected result for bad: %+v", bad)
+	}
+
+	if good.Path != filepath.Join(c.Dir, "worktrees", "good") {
+		t.Errorf("path = %q, want worktree path", good.Path)
+	}
+
+	if good.DurationMs < 0 || bad.DurationMs < 0 {
+		t.Errorf("negative duration: %+v", results)
+	}
+}
+
+func Test_Exec_Succeeds_When_Command_Succeeds_Everywhere(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	c.MustRun("--config", "config.json", "create", "--name", "one")
+	c.MustRun("--config", "config.json", "create", "--name", "two")
+
+	// Flags after the command are passed to it, not parsed by exec
+	stdout := c.MustRun("--config", "config.json", "exec", "/bin/sh", "-c", "echo in $WT_NAME")
+
+	AssertContains(t, stdout, "==> one (")
+	AssertContains(t, stdout, "in one")
+	AssertContains(t, stdout, "==> two (")
+	AssertContains(t, stdout, "in two")
+}
+
+func Test_Exec_Reports_Command_That_Cannot_Start(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	c.MustRun("--config", "config.json", "create", "--name", "one")
+
+	stdout, _, code := c.Run("--config", "config.json", "exec", "--json", filepath.Join(c.Dir, "missing"))
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	var results []execResult
+
+	err := json.Unmarshal([]byte(stdout), &results)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	if len(results) != 1 || results[0].ExitCode != execNotStartedCode || results[0].Error == "" {
+		t.Errorf("expected not-started result, got %+v", results)
+	}
+}
+
+func Test_Exec_Reports_Signal_As_128_Plus_Signal(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	c.MustRun("--config", "config.json", "create", "--name", "one")
+
+	stdout, _, code := c.Run("--config", "config.json", "exec", "--json", "/bin/sh", "-c", "kill -TERM $$")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	var results []execResult
+
+	err := json.Unmarshal([]byte(stdout), &results)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	if len(results) != 1 || results[0].ExitCode != 128+int(syscall.SIGTERM) || results[0].Error != "" {
+		t.Errorf("expected exit code %d, got %+v", 128+int(syscall.SIGTERM), results)
+	}
+}
+
+func Test_Exec_Looks_Up_Command_In_Environment_PATH(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	c.MustRun("--config", "config.json", "create", "--name", "one")
+
+	toolDir := t.TempDir()
+	writeExecutableFile(t, filepath.Join(toolDir, "wt-test-tool"), []byte("#!/bin/sh\necho tool ran\n"))
+
+	c.Env["PATH"] = toolDir + string(os.PathListSeparator) + os.Getenv("PATH")
+
+	AssertContains(t, c.MustRun("--config", "config.json", "exec", "wt-test-tool"), "tool ran")
+
+	// Not found in that PATH: not started
+	c.Env["PATH"] = t.TempDir()
+
+	stdout, _, code := c.Run("--config", "config.json", "exec", "--json", "wt-test-tool")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stdout, `"exit_code": 127`)
+}
+
+func Test_Exec_Requires_Command(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	_, stderr, code := c.Run("--config", "config.json", "exec")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "command is required")
+}
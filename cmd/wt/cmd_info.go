@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/calvinalkan/agent-task/pkg/fs"
 	flag "github.com/spf13/pflag"
@@ -16,8 +19,12 @@ import (
 // Errors for info command.
 var (
 	errNotInWorktree        = errors.New("this is a regular branch, not a worktree (use wt list to find worktrees)")
-	errInvalidField         = errors.New("invalid field (valid: name, agent_id, id, path, base_branch, created)")
+	errInvalidField         = errors.New("invalid field (valid: name, agent_id, id, path, base_branch, created, branch, age_seconds, upstream, source, locked, branch_description, default_target, is_merged)")
 	errWorktreeNotFoundInfo = errors.New("worktree not found")
+	errAmbiguousIdentifier  = errors.New("ambiguous identifier")
+	errEmptyTimeFormat      = errors.New("--time-format must not be empty (use rfc3339, unix, date or a Go time layout)")
+	errInvalidRepoField     = errors.New("invalid field for --repo (valid: repo_root, current_branch, dirty, base_dir, worktree_count)")
+	errRepoWithIdentifier   = errors.New("--repo cannot be combined with an identifier")
 )
 
 // InfoCmd returns the info command.
@@ -25,7 +32,10 @@ func InfoCmd(cfg Config, fsys fs.FS, git *Git) *Command {
 	flags := flag.NewFlagSet("info", flag.ContinueOnError)
 	flags.BoolP("help", "h", false, "Show help")
 	flags.Bool("json", false, "Output as JSON")
-	flags.String("field", "", "Output single field: name, agent_id, id, path, base_branch, created")
+	flags.Bool("repo", false, "Show repository facts instead of a worktree's")
+	addFormatFlag(flags)
+	flags.String("time-format", timeFormatRFC3339, "Format of created: rfc3339, unix, date or a Go time layout (e.g. 2006-01-02 15:04)")
+	flags.String("field", "", "Output single field: name, agent_id, id, path, base_branch, created, branch, age_seconds, upstream, source, locked, branch_description, default_target, is_merged")
 
 	return &Command{
 		Flags: flags,
@@ -37,16 +47,49 @@ Without arguments, shows info for the current worktree (must be inside a
 wt-managed worktree created by 'wt create').
 
 With an identifier argument, looks up any worktree by:
-  • name      - the worktree directory/branch name
-  • agent_id  - the generated identifier (e.g., swift-fox)  
   • id        - the numeric ID (e.g., 3)
+  • name      - the worktree directory name
+  • agent_id  - the generated identifier (e.g., swift-fox)
+  • branch    - the branch checked out in the worktree
+
+If the identifier matches different worktrees by different keys (e.g. one
+worktree's name and another's branch), it is rejected as ambiguous; use
+the numeric id instead.
 
 Examples:
   wt info                     # Current worktree
   wt info swift-fox           # Lookup by name or agent_id
   wt info 3                   # Lookup by numeric ID
   wt info --field id          # Get worktree ID for port allocation
-  wt info foo --field path    # Get path for a specific worktree`,
+  wt info foo --field path    # Get path for a specific worktree
+
+--time-format changes how created is printed, in every output format:
+rfc3339 (default, e.g. 2025-01-04T10:30:00Z), unix (epoch seconds), date
+(2025-01-04), or any Go time layout. Times are always in UTC.
+  wt info --field created --time-format unix
+
+--format yaml prints the JSON fields as YAML (--json is short for
+--format json). Outside a worktree, both print {"error": "not a worktree",
+"is_worktree": false} to stdout (and nothing to stderr) and exit 1.
+
+The JSON output and --field also provide age_seconds, the whole seconds
+since the worktree was created, for alerting on old worktrees, and
+default_target, the branch wt merge merges into without --into, and
+is_merged, whether the branch has commits of its own that are all in
+default_target (the same check wt clean uses to pick worktrees to remove).
+is_merged is only computed for --json, --format and --field is_merged; if
+git cannot tell, it is null (--field prints unknown).
+
+source is the directory wt create ran from (WT_SOURCE in hooks), e.g.
+another worktree for a nested one; it is empty for worktrees created
+before it was recorded.
+
+--repo shows facts about the repository at the current directory instead,
+inside or outside a worktree: repo_root (top level of the current
+checkout), current_branch (empty if detached), dirty, base_dir (resolved
+worktree base directory) and worktree_count (wt-managed worktrees). It
+supports --json, --format and --field with those names.
+  wt info --repo --field base_dir`,
 		Exec: func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, args []string) error {
 			return execInfo(ctx, stdin, stdout, stderr, cfg, fsys, git, flags, args)
 		},
@@ -56,15 +99,33 @@ Examples:
 func execInfo(
 	ctx context.Context,
 	_ io.Reader,
-	stdout, _ io.Writer,
+	stdout, stderr io.Writer,
 	cfg Config,
 	fsys fs.FS,
 	git *Git,
 	flags *flag.FlagSet,
 	args []string,
 ) error {
-	jsonOutput, _ := flags.GetBool("json")
 	field, _ := flags.GetString("field")
+	timeFormat, _ := flags.GetString("time-format")
+	repo, _ := flags.GetBool("repo")
+
+	if timeFormat == "" {
+		return errEmptyTimeFormat
+	}
+
+	format, err := outputFormat(flags)
+	if err != nil {
+		return err
+	}
+
+	if repo {
+		if len(args) > 0 {
+			return errRepoWithIdentifier
+		}
+
+		return execInfoRepo(ctx, stdout, cfg, fsys, git, format, field)
+	}
 
 	// Get main repo root (works from inside worktrees too)
 	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
@@ -72,6 +133,14 @@ func execInfo(
 		return err
 	}
 
+	// Git's view of the worktrees, for branch lookup and the branch field
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	gitIndex := newGitWorktreeIndex(entries)
+
 	var info WorktreeInfo
 
 	var wtPath string
@@ -80,14 +149,21 @@ func execInfo(
 		// Lookup by identifier
 		identifier := args[0]
 
-		baseDir := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+		baseDir, baseErr := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+		if baseErr != nil {
+			return baseErr
+		}
 
 		worktrees, findErr := findWorktreesWithPaths(fsys, baseDir)
 		if findErr != nil {
 			return fmt.Errorf("scanning worktrees: %w", findErr)
 		}
 
-		wt, found := findWorktreeByIdentifier(worktrees, identifier)
+		wt, found, lookupErr := findWorktreeByIdentifier(worktrees, gitIndex, identifier, identifierKeysAll...)
+		if lookupErr != nil {
+			return lookupErr
+		}
+
 		if !found {
 			return fmt.Errorf("%w: %s", errWorktreeNotFoundInfo, identifier)
 		}
@@ -98,6 +174,16 @@ func execInfo(
 		// Current worktree mode
 		wtPath, err = findWorktreeRoot(fsys, cfg.EffectiveCwd)
 		if err != nil {
+			if format != formatText && field == "" {
+				// Expected case for tooling: report it as data, still exit 1
+				encodeErr := outputInfoNotWorktree(stdout, format)
+				if encodeErr != nil {
+					return encodeErr
+				}
+
+				return fmt.Errorf("%w: %w", errReported, errNotInWorktree)
+			}
+
 			return errNotInWorktree
 		}
 
@@ -107,39 +193,125 @@ func execInfo(
 		}
 	}
 
+	if warning := schemaWarning(&info, wtPath); warning != "" {
+		fprintln(stderr, warning)
+	}
+
+	// Join with git's view of the worktree for the checked-out branch
+	entry, _ := gitIndex.lookup(wtPath)
+	output := newInfoJSON(&info, wtPath, entry, time.Now())
+	output.Created = formatInfoTime(info.Created, timeFormat)
+	output.DefaultTarget, _ = resolveMergeTarget(cfg, &info)
+
+	// Read lazily, so 'git branch --edit-description' after create shows up
+	if entry.Branch != "" {
+		output.BranchDescription = git.BranchDescription(ctx, wtPath, entry.Branch)
+	}
+
+	// is_merged costs a few git calls and is not in the text output
+	if (format != formatText && field == "") || field == "is_merged" {
+		output.IsMerged = infoIsMerged(ctx, git, mainRepoRoot, &info, entry.Branch, output.DefaultTarget)
+	}
+
 	// If --field is specified, output only that field
 	if field != "" {
-		return outputField(stdout, &info, wtPath, field)
+		return outputField(stdout, output, field)
 	}
 
 	// Full output
-	if jsonOutput {
-		return outputInfoJSON(stdout, &info, wtPath)
+	switch format {
+	case formatJSON:
+		return outputInfoJSON(stdout, output)
+	case formatYAML:
+		return encodeYAML(stdout, output)
 	}
 
-	return outputInfoText(stdout, &info, wtPath)
+	return outputInfoText(stdout, output)
 }
 
-// findWorktreeByIdentifier searches worktrees by name, agent_id, or numeric id.
-func findWorktreeByIdentifier(worktrees []WorktreeWithPath, identifier string) (WorktreeWithPath, bool) {
-	// Try numeric ID first
-	id, err := strconv.Atoi(identifier)
-	if err == nil {
-		for _, wt := range worktrees {
-			if wt.ID == id {
-				return wt, true
-			}
+// infoIsMerged reports whether branch is fully merged into target (see
+// worktreeMerged), or nil if that could not be determined. A detached HEAD
+// (empty branch) is not merged.
+func infoIsMerged(ctx context.Context, git *Git, dir string, info *WorktreeInfo, branch, target string) *bool {
+	merged := false
+
+	if branch != "" {
+		var err error
+
+		merged, err = worktreeMerged(ctx, git, dir, info, branch, target)
+		if err != nil {
+			return nil
 		}
 	}
 
-	// Try name or agent_id
-	for _, wt := range worktrees {
-		if wt.Name == identifier || wt.AgentID == identifier {
-			return wt, true
+	return &merged
+}
+
+// Keys a worktree identifier can match, in precedence order.
+const (
+	identifierKeyID      = "id"
+	identifierKeyName    = "name"
+	identifierKeyAgentID = "agent_id"
+	identifierKeyBranch  = "branch"
+)
+
+// identifierKeysAll are the keys info accepts.
+var identifierKeysAll = []string{identifierKeyID, identifierKeyName, identifierKeyAgentID, identifierKeyBranch}
+
+// findWorktreeByIdentifier searches worktrees by the given keys (numeric id,
+// name, agent_id, and/or the branch git reports for the worktree). Matching
+// the same worktree by several keys is fine; matching different worktrees
+// returns errAmbiguousIdentifier naming each candidate.
+func findWorktreeByIdentifier(
+	worktrees []WorktreeWithPath,
+	gitIndex gitWorktreeIndex,
+	identifier string,
+	keys ...string,
+) (WorktreeWithPath, bool, error) {
+	var (
+		matches    []WorktreeWithPath
+		candidates []string
+	)
+
+	id, idErr := strconv.Atoi(identifier)
+
+	for _, key := range keys {
+		for _, wt := range worktrees {
+			var ok bool
+
+			switch key {
+			case identifierKeyID:
+				ok = idErr == nil && wt.ID == id
+			case identifierKeyName:
+				ok = wt.Name == identifier
+			case identifierKeyAgentID:
+				ok = wt.AgentID == identifier
+			case identifierKeyBranch:
+				entry, known := gitIndex.lookup(wt.Path)
+				ok = known && entry.Branch == identifier
+			}
+
+			if !ok {
+				continue
+			}
+
+			candidates = append(candidates, fmt.Sprintf("%s of %s", key, wt.Name))
+
+			if !slices.ContainsFunc(matches, func(m WorktreeWithPath) bool { return m.Path == wt.Path }) {
+				matches = append(matches, wt)
+			}
 		}
 	}
 
-	return WorktreeWithPath{}, false
+	switch len(matches) {
+	case 0:
+		return WorktreeWithPath{}, false, nil
+	case 1:
+		return matches[0], true, nil
+	default:
+		return WorktreeWithPath{}, false, fmt.Errorf("%w '%s': matches %s (use the numeric id)",
+			errAmbiguousIdentifier, identifier, strings.Join(candidates, ", "))
+	}
 }
 
 // findWorktreeRoot walks up from startDir looking for .wt/worktree.json.
@@ -165,7 +337,7 @@ func findWorktreeRoot(fsys fs.FS, startDir string) (string, error) {
 	}
 }
 
-func outputField(stdout io.Writer, info *WorktreeInfo, path, field string) error {
+func outputField(stdout io.Writer, info *infoJSON, field string) error {
 	switch field {
 	case "name":
 		fprintln(stdout, info.Name)
@@ -174,11 +346,31 @@ func outputField(stdout io.Writer, info *WorktreeInfo, path, field string) error
 	case "id":
 		fprintln(stdout, info.ID)
 	case "path":
-		fprintln(stdout, path)
+		fprintln(stdout, info.Path)
+	case "branch":
+		fprintln(stdout, info.Branch)
 	case "base_branch":
 		fprintln(stdout, info.BaseBranch)
 	case "created":
-		fprintln(stdout, info.Created.Format("2006-01-02T15:04:05Z"))
+		fprintln(stdout, info.Created)
+	case "age_seconds":
+		fprintln(stdout, info.AgeSeconds)
+	case "upstream":
+		fprintln(stdout, info.Upstream)
+	case "source":
+		fprintln(stdout, info.Source)
+	case "locked":
+		fprintln(stdout, info.Locked)
+	case "branch_description":
+		fprintln(stdout, info.BranchDescription)
+	case "default_target":
+		fprintln(stdout, info.DefaultTarget)
+	case "is_merged":
+		if info.IsMerged == nil {
+			fprintln(stdout, "unknown")
+		} else {
+			fprintln(stdout, *info.IsMerged)
+		}
 	default:
 		return fmt.Errorf("%w: %s", errInvalidField, field)
 	}
@@ -186,13 +378,38 @@ func outputField(stdout io.Writer, info *WorktreeInfo, path, field string) error
 	return nil
 }
 
-func outputInfoText(stdout io.Writer, info *WorktreeInfo, path string) error {
+func outputInfoText(stdout io.Writer, info *infoJSON) error {
 	fprintf(stdout, "name:        %s\n", info.Name)
 	fprintf(stdout, "agent_id:    %s\n", info.AgentID)
 	fprintf(stdout, "id:          %d\n", info.ID)
-	fprintf(stdout, "path:        %s\n", path)
+	fprintf(stdout, "path:        %s\n", info.Path)
+	fprintf(stdout, "branch:      %s\n", info.Branch)
 	fprintf(stdout, "base_branch: %s\n", info.BaseBranch)
-	fprintf(stdout, "created:     %s\n", info.Created.Format("2006-01-02T15:04:05Z"))
+	fprintf(stdout, "created:     %s\n", info.Created)
+
+	if info.Upstream != "" {
+		fprintf(stdout, "upstream:    %s\n", info.Upstream)
+	}
+
+	if info.Source != "" {
+		fprintf(stdout, "source:      %s\n", info.Source)
+	}
+
+	if info.Locked {
+		if info.LockReason != "" {
+			fprintf(stdout, "locked:      yes (%s)\n", info.LockReason)
+		} else {
+			fprintln(stdout, "locked:      yes")
+		}
+	}
+
+	if info.BranchDescription != "" {
+		fprintf(stdout, "description: %s\n", info.BranchDescription)
+	}
+
+	if len(info.Labels) > 0 {
+		fprintf(stdout, "labels:      %s\n", formatLabels(info.Labels))
+	}
 
 	return nil
 }
@@ -202,20 +419,204 @@ type infoJSON struct {
 	AgentID    string `json:"agent_id"`
 	ID         int    `json:"id"`
 	Path       string `json:"path"`
+	Branch     string `json:"branch"`
 	BaseBranch string `json:"base_branch"`
 	Created    string `json:"created"`
+	AgeSeconds int64  `json:"age_seconds"`
+	Upstream   string `json:"upstream,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Locked     bool   `json:"locked"`
+	LockReason string `json:"lock_reason,omitempty"`
+
+	// From git config, not metadata
+	BranchDescription string `json:"branch_description,omitempty"`
+
+	// Branch wt merge targets by default (see resolveMergeTarget)
+	DefaultTarget string `json:"default_target"`
+
+	// Branch is fully merged into DefaultTarget (see worktreeMerged); nil
+	// (null) if that could not be determined
+	IsMerged *bool `json:"is_merged"`
+
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
-func outputInfoJSON(stdout io.Writer, info *WorktreeInfo, path string) error {
-	output := infoJSON{
+// newInfoJSON builds the info view from metadata and git's worktree entry.
+// entry may be the zero value if git doesn't know the worktree; now is the
+// reference time for age_seconds (passed in so tests can pin it). The lock
+// state comes from git, so it reflects 'git worktree lock/unlock' after create.
+func newInfoJSON(info *WorktreeInfo, path string, entry WorktreeEntry, now time.Time) *infoJSON {
+	// Clamp to zero so clock skew never reports a negative age
+	age := max(int64(now.Sub(info.Created)/time.Second), 0)
+
+	return &infoJSON{
 		Name:       info.Name,
 		AgentID:    info.AgentID,
 		ID:         info.ID,
 		Path:       path,
+		Branch:     entry.Branch,
 		BaseBranch: info.BaseBranch,
-		Created:    info.Created.Format("2006-01-02T15:04:05Z"),
+		Created:    formatInfoTime(info.Created, timeFormatRFC3339),
+		AgeSeconds: age,
+		Upstream:   info.Upstream,
+		Source:     info.Source,
+		Locked:     entry.Locked,
+		LockReason: entry.LockReason,
+		Labels:     info.Labels,
+	}
+}
+
+// Named layouts for info --time-format.
+const (
+	timeFormatRFC3339 = "rfc3339"
+	timeFormatUnix    = "unix"
+	timeFormatDate    = "date"
+)
+
+// formatInfoTime formats t in UTC for info output. layout is one of the
+// named formats or a Go time layout.
+func formatInfoTime(t time.Time, layout string) string {
+	switch layout {
+	case timeFormatRFC3339:
+		return t.UTC().Format("2006-01-02T15:04:05Z")
+	case timeFormatUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case timeFormatDate:
+		return t.UTC().Format(time.DateOnly)
+	default:
+		return t.UTC().Format(layout)
 	}
+}
+
+// infoRepoJSON is the info --repo view: facts about the repository at the
+// effective working directory, independent of worktree context.
+type infoRepoJSON struct {
+	RepoRoot      string `json:"repo_root"`
+	CurrentBranch string `json:"current_branch"`
+	Dirty         bool   `json:"dirty"`
+	BaseDir       string `json:"base_dir"`
+	WorktreeCount int    `json:"worktree_count"`
+}
+
+func execInfoRepo(
+	ctx context.Context,
+	stdout io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	format, field string,
+) error {
+	repoRoot, err := git.RepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	branch, err := git.CurrentBranch(ctx, repoRoot)
+	if err != nil {
+		return err
+	}
+
+	dirty, err := git.IsDirty(ctx, repoRoot)
+	if err != nil {
+		return err
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("scanning worktrees: %w", err)
+	}
+
+	output := &infoRepoJSON{
+		RepoRoot:      repoRoot,
+		CurrentBranch: branch,
+		Dirty:         dirty,
+		BaseDir:       baseDir,
+		WorktreeCount: len(worktrees),
+	}
+
+	if field != "" {
+		return outputRepoField(stdout, output, field)
+	}
+
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		encodeErr := enc.Encode(output)
+		if encodeErr != nil {
+			return fmt.Errorf("encoding JSON: %w", encodeErr)
+		}
+
+		return nil
+	case formatYAML:
+		return encodeYAML(stdout, output)
+	}
+
+	fprintf(stdout, "repo_root:      %s\n", output.RepoRoot)
+	fprintf(stdout, "current_branch: %s\n", output.CurrentBranch)
+	fprintf(stdout, "dirty:          %t\n", output.Dirty)
+	fprintf(stdout, "base_dir:       %s\n", output.BaseDir)
+	fprintf(stdout, "worktree_count: %d\n", output.WorktreeCount)
+
+	return nil
+}
+
+func outputRepoField(stdout io.Writer, info *infoRepoJSON, field string) error {
+	switch field {
+	case "repo_root":
+		fprintln(stdout, info.RepoRoot)
+	case "current_branch":
+		fprintln(stdout, info.CurrentBranch)
+	case "dirty":
+		fprintln(stdout, info.Dirty)
+	case "base_dir":
+		fprintln(stdout, info.BaseDir)
+	case "worktree_count":
+		fprintln(stdout, info.WorktreeCount)
+	default:
+		return fmt.Errorf("%w: %s", errInvalidRepoField, field)
+	}
+
+	return nil
+}
+
+// infoNotWorktreeJSON is emitted by info --json outside a worktree, so tooling
+// can distinguish this expected case from crashes.
+type infoNotWorktreeJSON struct {
+	Error      string `json:"error"`
+	IsWorktree bool   `json:"is_worktree"`
+}
+
+func outputInfoNotWorktree(stdout io.Writer, format string) error {
+	notWorktree := infoNotWorktreeJSON{Error: "not a worktree", IsWorktree: false}
+
+	if format == formatYAML {
+		return encodeYAML(stdout, notWorktree)
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+
+	encodeErr := enc.Encode(notWorktree)
+	if encodeErr != nil {
+		return fmt.Errorf("encoding JSON: %w", encodeErr)
+	}
+
+	return nil
+}
 
+func outputInfoJSON(stdout io.Writer, output *infoJSON) error {
 	enc := json.NewEncoder(stdout)
 	enc.SetIndent("", "  ")
 
@@ -4,8 +4,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
 )
 
 func Test_Info_Shows_Help_When_Help_Flag(t *testing.T) {
@@ -601,3 +605,492 @@ func Test_Info_Lookup_Multiple_Worktrees_Finds_Correct_One(t *testing.T) {
 
 	AssertContains(t, stdout, "name:        wt-gamma")
 }
+
+func Test_Info_Shows_Checked_Out_Branch_From_Git(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := c.Run("--config", "config.json", "create", "--name", "branch-info")
+	if code != 0 {
+		t.Fatalf("create failed: %s", stderr)
+	}
+
+	wtPath := filepath.Join(c.Dir, "worktrees", "branch-info")
+
+	// Switch the worktree to a different branch behind wt's back
+	out, err := testGitCmd("-C", wtPath, "checkout", "-b", "renamed-branch").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git checkout failed: %v\n%s", err, out)
+	}
+
+	stdout, stderr, code := c.Run("--config", "config.json", "info", "branch-info", "--field", "branch")
+	if code != 0 {
+		t.Fatalf("info failed: %s", stderr)
+	}
+
+	if strings.TrimSpace(stdout) != "renamed-branch" {
+		t.Errorf("expected branch 'renamed-branch', got %q", strings.TrimSpace(stdout))
+	}
+
+	stdout, _, _ = c.Run("--config", "config.json", "info", "branch-info")
+	AssertContains(t, stdout, "branch:      renamed-branch")
+}
+
+func Test_Info_JSON_Outside_Worktree_Emits_Structured_Error(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	stdout, stderr, code := c.Run("info", "--json")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	var result map[string]any
+
+	err := json.Unmarshal([]byte(stdout), &result)
+	if err != nil {
+		t.Fatalf("stdout is not valid JSON: %v\nstdout: %s", err, stdout)
+	}
+
+	if len(result) != 2 || result["error"] != "not a worktree" || result["is_worktree"] != false {
+		t.Errorf("unexpected JSON error shape: %v", result)
+	}
+
+	// The error is reported once, on stdout
+	if stderr != "" {
+		t.Errorf("expected empty stderr, got %q", stderr)
+	}
+}
+
+func Test_Info_Text_Outside_Worktree_Keeps_Stdout_Empty(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	stdout, _, code := c.Run("info")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	if stdout != "" {
+		t.Errorf("expected empty stdout, got %q", stdout)
+	}
+}
+
+func Test_newInfoJSON_Computes_Age_Seconds_From_Now(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2025, 1, 4, 10, 0, 0, 0, time.UTC)
+	info := WorktreeInfo{Name: "swift-fox", Created: created}
+
+	got := newInfoJSON(&info, "/wt/swift-fox", WorktreeEntry{}, created.Add(90*time.Minute+500*time.Millisecond))
+	if got.AgeSeconds != 5400 {
+		t.Errorf("AgeSeconds = %d, want 5400", got.AgeSeconds)
+	}
+
+	// A created timestamp in the future (clock skew) never yields a negative age
+	got = newInfoJSON(&info, "/wt/swift-fox", WorktreeEntry{}, created.Add(-time.Minute))
+	if got.AgeSeconds != 0 {
+		t.Errorf("AgeSeconds = %d, want 0 for future created time", got.AgeSeconds)
+	}
+}
+
+func Test_Info_Age_Seconds_In_JSON_And_Field(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "old-wt")
+
+	// Backdate the worktree by two hours
+	wtPath := filepath.Join(c.Dir, "worktrees", "old-wt")
+
+	info, err := readWorktreeInfo(fs.NewReal(), wtPath)
+	if err != nil {
+		t.Fatalf("failed to read worktree info: %v", err)
+	}
+
+	info.Created = time.Now().UTC().Add(-2 * time.Hour)
+
+	err = writeWorktreeInfo(fs.NewReal(), wtPath, &info)
+	if err != nil {
+		t.Fatalf("failed to write worktree info: %v", err)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "info", "old-wt", "--field", "age_seconds")
+
+	age, err := strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
+	if err != nil {
+		t.Fatalf("age_seconds is not an integer: %q", stdout)
+	}
+
+	if age < 7200 || age > 7260 {
+		t.Errorf("age_seconds = %d, want about 7200", age)
+	}
+
+	var output infoJSON
+
+	err = json.Unmarshal([]byte(c.MustRun("--config", "config.json", "info", "old-wt", "--json")), &output)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+
+	if output.AgeSeconds < 7200 || output.AgeSeconds > 7260 {
+		t.Errorf("JSON age_seconds = %d, want about 7200", output.AgeSeconds)
+	}
+}
+
+func Test_Info_Lookup_By_Branch_Name(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "wt-dir")
+
+	wtPath := filepath.Join(c.Dir, "worktrees", "wt-dir")
+
+	out, err := testGitCmd("-C", wtPath, "checkout", "-b", "feature/login").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git checkout failed: %v\n%s", err, out)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "info", "feature/login", "--field", "path")
+	if stdout != wtPath {
+		t.Errorf("expected path %q, got %q", wtPath, stdout)
+	}
+
+	// The name keeps working too
+	stdout = c.MustRun("--config", "config.json", "info", "wt-dir", "--field", "branch")
+	if stdout != "feature/login" {
+		t.Errorf("expected branch feature/login, got %q", stdout)
+	}
+}
+
+func Test_Info_Lookup_Rejects_Identifier_Matching_Different_Worktrees(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "alpha")
+	c.MustRun("--config", "config.json", "create", "--name", "beta")
+
+	// Give beta a branch named like the alpha worktree
+	betaPath := filepath.Join(c.Dir, "worktrees", "beta")
+
+	out, err := testGitCmd("-C", c.Dir, "branch", "-m", "alpha", "alpha-old").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch -m failed: %v\n%s", err, out)
+	}
+
+	out, err = testGitCmd("-C", betaPath, "checkout", "-b", "alpha").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git checkout failed: %v\n%s", err, out)
+	}
+
+	_, stderr, code := c.Run("--config", "config.json", "info", "alpha")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "ambiguous identifier 'alpha'")
+	AssertContains(t, stderr, "name of alpha")
+	AssertContains(t, stderr, "branch of beta")
+
+	// Numeric id still resolves
+	stdout := c.MustRun("--config", "config.json", "info", "2", "--field", "name")
+	if stdout != "beta" {
+		t.Errorf("expected beta, got %q", stdout)
+	}
+}
+
+func Test_Info_Format_YAML(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "yaml-info"))
+	created := c.MustRun("--config", "config.json", "info", "yaml-info", "--field", "created")
+
+	stdout := c.MustRun("--config", "config.json", "info", "yaml-info", "--format", "yaml")
+
+	AssertContains(t, stdout, "name: yaml-info\n")
+	AssertContains(t, stdout, "id: 1\n")
+	AssertContains(t, stdout, "path: "+wtPath+"\n")
+	AssertContains(t, stdout, "base_branch: master\n")
+	AssertContains(t, stdout, `created: "`+created+`"`)
+	AssertContains(t, stdout, "locked: false\n")
+	AssertContains(t, stdout, "default_target: master")
+}
+
+func Test_Info_Format_YAML_Not_In_Worktree_Reports_Data(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	stdout, stderr, code := c.Run("info", "--format", "yaml")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stdout, `error: not a worktree`)
+	AssertContains(t, stdout, "is_worktree: false")
+
+	if stderr != "" {
+		t.Errorf("expected empty stderr, got %q", stderr)
+	}
+}
+
+func Test_Info_Format_Rejects_Invalid_Value(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	_, stderr, code := c.Run("info", "--format", "toml")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "invalid format (valid: text, json, yaml)")
+}
+
+func Test_Info_Time_Format_Unix_Prints_Epoch_Seconds(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "epoch-wt")
+
+	created, err := time.Parse(time.RFC3339, c.MustRun("--config", "config.json", "info", "epoch-wt", "--field", "created"))
+	if err != nil {
+		t.Fatalf("default created is not RFC3339: %v", err)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "info", "epoch-wt", "--field", "created", "--time-format", "unix")
+	if stdout != strconv.FormatInt(created.Unix(), 10) {
+		t.Errorf("unix created = %q, want %d", stdout, created.Unix())
+	}
+
+	var info infoJSON
+
+	err = json.Unmarshal([]byte(c.MustRun("--config", "config.json", "info", "epoch-wt", "--json", "--time-format", "unix")), &info)
+	if err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+
+	if info.Created != stdout {
+		t.Errorf("JSON created = %q, want %q", info.Created, stdout)
+	}
+}
+
+func Test_Info_Time_Format_Custom_Layout_And_Date(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "layout-wt")
+
+	created, err := time.Parse(time.RFC3339, c.MustRun("--config", "config.json", "info", "layout-wt", "--field", "created"))
+	if err != nil {
+		t.Fatalf("default created is not RFC3339: %v", err)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "info", "layout-wt", "--field", "created", "--time-format", "02 Jan 2006 15:04")
+	if want := created.Format("02 Jan 2006 15:04"); stdout != want {
+		t.Errorf("custom layout created = %q, want %q", stdout, want)
+	}
+
+	stdout = c.MustRun("--config", "config.json", "info", "layout-wt", "--time-format", "date")
+	AssertContains(t, stdout, "created:     "+created.Format(time.DateOnly))
+}
+
+func Test_Info_Is_Merged_Reflects_Default_Target(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	mergedPath := createMergedWorktree(t, c, "merged-wt")
+
+	unmergedPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "unmerged-wt"))
+	gitCommitInDir(t, unmergedPath, "wip.txt", "wip", "add wip")
+
+	c.MustRun("--config", "config.json", "create", "--name", "fresh-wt")
+
+	for name, want := range map[string]bool{"merged-wt": true, "unmerged-wt": false, "fresh-wt": false} {
+		var output infoJSON
+
+		err := json.Unmarshal([]byte(c.MustRun("--config", "config.json", "info", name, "--json")), &output)
+		if err != nil {
+			t.Fatalf("%s: invalid JSON output: %v", name, err)
+		}
+
+		if output.IsMerged == nil || *output.IsMerged != want {
+			t.Errorf("%s: is_merged = %v, want %v", name, output.IsMerged, want)
+		}
+	}
+
+	// --field and the current-worktree mode agree with the lookup
+	stdout := c.MustRun("--config", filepath.Join(c.Dir, "config.json"), "-C", mergedPath, "info", "--field", "is_merged")
+	if strings.TrimSpace(stdout) != "true" {
+		t.Errorf("--field is_merged = %q, want true", stdout)
+	}
+}
+
+func Test_Info_Is_Merged_Is_Null_When_Git_Check_Fails(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees", "merge_into": "broken"}`)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "broken-target"))
+	gitCommitInDir(t, wtPath, "wip.txt", "wip", "add wip")
+
+	// A branch pointing at a blob exists but is not a commit, so
+	// merge-base --is-ancestor fails
+	blob, err := testGitCmd("-C", c.Dir, "hash-object", "-w", "config.json").Output()
+	if err != nil {
+		t.Fatalf("git hash-object failed: %v", err)
+	}
+
+	c.WriteFile(".git/refs/heads/broken", string(blob))
+
+	stdout := c.MustRun("--config", "config.json", "info", "broken-target", "--json")
+	AssertContains(t, stdout, `"is_merged": null`)
+
+	stdout = c.MustRun("--config", "config.json", "info", "broken-target", "--field", "is_merged")
+	if strings.TrimSpace(stdout) != "unknown" {
+		t.Errorf("--field is_merged = %q, want unknown", stdout)
+	}
+
+	AssertContains(t, c.MustRun("--config", "config.json", "info", "broken-target"), "name:        broken-target")
+}
+
+func Test_Info_Repo_Reports_Repository_Facts_Inside_And_Outside_Worktrees(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "repo-a"))
+	c.MustRun("--config", "config.json", "create", "--name", "repo-b")
+
+	// The untracked config.json makes the main checkout dirty
+	var output infoRepoJSON
+
+	err := json.Unmarshal([]byte(c.MustRun("--config", "config.json", "info", "--repo", "--json")), &output)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+
+	want := infoRepoJSON{
+		RepoRoot:      c.Dir,
+		CurrentBranch: "master",
+		Dirty:         true,
+		BaseDir:       filepath.Join(c.Dir, "worktrees"),
+		WorktreeCount: 2,
+	}
+	if output != want {
+		t.Errorf("info --repo --json = %+v, want %+v", output, want)
+	}
+
+	configPath := filepath.Join(c.Dir, "config.json")
+
+	stdout := c.MustRun("--config", configPath, "-C", wtPath, "info", "--repo")
+
+	AssertContains(t, stdout, "repo_root:      "+wtPath+"\n")
+	AssertContains(t, stdout, "current_branch: repo-a\n")
+	AssertContains(t, stdout, "dirty:          false\n")
+	AssertContains(t, stdout, "base_dir:       "+filepath.Join(c.Dir, "worktrees")+"\n")
+	AssertContains(t, stdout, "worktree_count: 2")
+
+	stdout = c.MustRun("--config", configPath, "-C", wtPath, "info", "--repo", "--field", "worktree_count")
+	if stdout != "2" {
+		t.Errorf("--field worktree_count = %q, want 2", stdout)
+	}
+}
+
+func Test_Info_Repo_Rejects_Identifier_And_Worktree_Fields(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	_, stderr, code := c.Run("info", "--repo", "some-wt")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "--repo cannot be combined with an identifier")
+
+	_, stderr, code = c.Run("info", "--repo", "--field", "name")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "invalid field for --repo")
+}
+
+func Test_Info_Shows_Source_Of_Nested_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	parent, child, _ := createWorktreeTree(t, c)
+	cfgPath := filepath.Join(c.Dir, "config.json")
+
+	source := strings.TrimSpace(c.MustRun("--config", cfgPath, "info", "child", "--field", "source"))
+	if !isSamePath(source, parent) {
+		t.Errorf("child source = %q, want %q", source, parent)
+	}
+
+	stdout := c.MustRun("--config", cfgPath, "info", "parent", "--field", "source")
+	if !isSamePath(strings.TrimSpace(stdout), c.Dir) {
+		t.Errorf("parent source = %q, want the repo root %q", stdout, c.Dir)
+	}
+
+	AssertContains(t, c.MustRun("--config", cfgPath, "info", "child"), "source:      "+source)
+
+	var worktrees []jsonWorktree
+
+	err := json.Unmarshal([]byte(c.MustRun("--config", cfgPath, "ls", "--json")), &worktrees)
+	if err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+
+	for _, wt := range worktrees {
+		if wt.Name == "grandchild" && !isSamePath(wt.Source, child) {
+			t.Errorf("grandchild source = %q, want %q", wt.Source, child)
+		}
+	}
+}
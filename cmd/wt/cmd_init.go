@@ -31,7 +31,7 @@ Add the output to your shell's config file:
   eval "$(wt init bash)"   # Add to ~/.bashrc
 
 This enables:
-  wt switch <name|id>      Change directory to a worktree
+  wt switch <name|id>      Change directory to a worktree (- for the newest)
   wt create --switch       Create worktree and cd into it
   wt create -s             Short form of --switch
 
@@ -63,11 +63,13 @@ func execInit(stdout io.Writer, args []string) error {
 
 // bashInitScript is the shell function that wraps wt for bash.
 // It handles:
-// - wt [global-flags] switch <name|id>: cd to worktree
-// - wt [global-flags] create --switch/-s [...]: create and cd to worktree
-// - All other commands: pass through to wt binary.
+//   - wt [global-flags] switch <name|id>: cd to worktree
+//     (not with --json, which prints the worktree as JSON instead)
+//   - wt [global-flags] create --switch/-s [...]: create and cd to worktree
+//     (not with --json, which prints JSON with switch_path instead)
+//   - All other commands: pass through to wt binary.
 const bashInitScript = `wt() {
-  local cmd="" cmd_pos=0 pos=0 skip_next=false has_switch=false
+  local cmd="" cmd_pos=0 pos=0 skip_next=false has_switch=false has_json=false
 
   # Find the command and check for --switch/-s flag
   for arg in "$@"; do
@@ -85,6 +87,9 @@ const bashInitScript = `wt() {
       --switch|-s)
         has_switch=true
         ;;
+      --json)
+        has_json=true
+        ;;
       -*)
         # Unknown flag - could be command-specific, stop looking for cmd
         if [[ -z "$cmd" ]]; then
@@ -102,7 +107,7 @@ const bashInitScript = `wt() {
     ((pos++))
   done
 
-  if [[ "$cmd" == "switch" ]]; then
+  if [[ "$cmd" == "switch" && "$has_json" == "false" ]]; then
     local identifier="${@:$((cmd_pos + 2)):1}"
     if [[ -z "$identifier" ]]; then
       echo "error: missing worktree identifier (usage: wt switch <name|id>)" >&2
@@ -110,13 +115,13 @@ const bashInitScript = `wt() {
     fi
     local global_flags=("${@:1:$cmd_pos}")
     local dir
-    if dir="$(command wt "${global_flags[@]}" info "$identifier" --field path 2>&1)"; then
+    if dir="$(command wt "${global_flags[@]}" switch "$identifier" 2>&1)"; then
       cd "$dir" || return 1
     else
       echo "$dir" >&2
       return 1
     fi
-  elif [[ "$cmd" == "create" && "$has_switch" == "true" ]]; then
+  elif [[ "$cmd" == "create" && "$has_switch" == "true" && "$has_json" == "false" ]]; then
     local dir
     if dir="$(command wt "$@" 2>&1)"; then
       cd "$dir" || return 1
@@ -63,7 +63,7 @@ func Test_Init_Bash_Outputs_Valid_Shell_Function(t *testing.T) {
 	}
 }
 
-func Test_Init_Bash_Switch_Calls_Info_With_Field_Path(t *testing.T) {
+func Test_Init_Bash_Switch_Calls_Wt_Switch(t *testing.T) {
 	t.Parallel()
 
 	c := NewCLITester(t)
@@ -74,9 +74,9 @@ func Test_Init_Bash_Switch_Calls_Info_With_Field_Path(t *testing.T) {
 		t.Fatalf("expected exit code 0, got %d", code)
 	}
 
-	// The switch handler should use wt info with --field path
-	if !strings.Contains(stdout, "info") || !strings.Contains(stdout, "--field path") {
-		t.Errorf("switch should call 'wt info <id> --field path'\noutput:\n%s", stdout)
+	// The switch handler should cd to the path printed by the wt binary
+	if !strings.Contains(stdout, `switch "$identifier"`) {
+		t.Errorf("switch should call 'wt switch <id>'\noutput:\n%s", stdout)
 	}
 }
 
@@ -151,4 +151,26 @@ func Test_Init_Bash_Handles_Create_With_Switch_Flag(t *testing.T) {
 	if !strings.Contains(stdout, `"create"`) {
 		t.Errorf("should handle create command\noutput:\n%s", stdout)
 	}
+
+	// create --switch --json prints JSON, so it must not cd
+	if !strings.Contains(stdout, `"$has_json" == "false"`) {
+		t.Errorf("should not cd for create --switch --json\noutput:\n%s", stdout)
+	}
+}
+
+func Test_Init_Bash_Passes_Switch_With_JSON_Through(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+
+	stdout, _, code := c.Run("init", "bash")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+
+	// switch --json prints JSON, so it must not cd either
+	if !strings.Contains(stdout, `"$cmd" == "switch" && "$has_json" == "false"`) {
+		t.Errorf("should not cd for switch --json\noutput:\n%s", stdout)
+	}
 }
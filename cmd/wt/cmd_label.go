@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// Errors for labels (create --label, ls --label and wt label).
+var (
+	errLabelArgsRequired = errors.New("worktree identifier is required (usage: wt label <identifier> [--set key=value] [--unset key])")
+	errInvalidLabel      = errors.New("invalid label (expected key=value, key of letters, digits and . _ - /)")
+	errInvalidLabelKey   = errors.New("invalid label key (letters, digits and . _ - /)")
+)
+
+// labelKeyPattern matches a valid label key. It cannot contain filter
+// operators, so ls --filter label.<key>=<value> stays parseable.
+var labelKeyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+
+// LabelCmd returns the label command.
+func LabelCmd(cfg Config, fsys fs.FS, git *Git) *Command {
+	flags := flag.NewFlagSet("label", flag.ContinueOnError)
+	flags.BoolP("help", "h", false, "Show help")
+	flags.StringArray("set", nil, "Set label key=value (repeatable)")
+	flags.StringArray("unset", nil, "Remove label key (repeatable)")
+
+	return &Command{
+		Flags: flags,
+		Usage: "label <identifier> [flags]",
+		Short: "Show or change a worktree's labels",
+		Long: `Show or change the key/value labels of a worktree, e.g. ticket=JIRA-123.
+
+<identifier> is looked up like wt info does: numeric id, name, agent_id or
+checked-out branch. --set key=value adds or replaces a label and --unset
+key removes one (both repeatable; --unset runs first). Labels are stored
+in .wt/worktree.json and can also be set with wt create --label.
+
+The worktree's labels are then printed as key=value lines, sorted by key.
+wt ls --label key=value lists the worktrees with a label, and labels are
+part of wt ls --json and wt info --json.`,
+		Exec: func(ctx context.Context, _ io.Reader, stdout, _ io.Writer, args []string) error {
+			if len(args) != 1 {
+				return errLabelArgsRequired
+			}
+
+			set, _ := flags.GetStringArray("set")
+			unset, _ := flags.GetStringArray("unset")
+
+			return execLabel(ctx, stdout, cfg, fsys, git, args[0], set, unset)
+		},
+	}
+}
+
+func execLabel(
+	ctx context.Context,
+	stdout io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	identifier string,
+	set, unset []string,
+) error {
+	setLabels, err := parseLabels(set)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range unset {
+		if !labelKeyPattern.MatchString(key) {
+			return fmt.Errorf("%w: %q", errInvalidLabelKey, key)
+		}
+	}
+
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("scanning worktrees: %w", err)
+	}
+
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	wt, found, err := findWorktreeByIdentifier(worktrees, newGitWorktreeIndex(entries), identifier, identifierKeysAll...)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("%w: %s", errWorktreeNotFound, identifier)
+	}
+
+	info := wt.WorktreeInfo
+
+	if len(set) > 0 || len(unset) > 0 {
+		labels := maps.Clone(info.Labels)
+		if labels == nil {
+			labels = make(map[string]string, len(setLabels))
+		}
+
+		for _, key := range unset {
+			delete(labels, key)
+		}
+
+		maps.Copy(labels, setLabels)
+
+		// Keep files without labels free of an empty "labels" object
+		if len(labels) == 0 {
+			labels = nil
+		}
+
+		info.Labels = labels
+
+		err = writeWorktreeInfo(fsys, wt.Path, &info)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(info.Labels)) {
+		fprintf(stdout, "%s=%s\n", key, info.Labels[key])
+	}
+
+	return nil
+}
+
+// parseLabels parses key=value label assignments. The value may be empty
+// or contain "="; a later assignment to the same key wins.
+func parseLabels(assignments []string) (map[string]string, error) {
+	labels := make(map[string]string, len(assignments))
+
+	for _, assignment := range assignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok || !labelKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("%w: %q", errInvalidLabel, assignment)
+		}
+
+		labels[key] = value
+	}
+
+	return labels, nil
+}
+
+// formatLabels renders labels as sorted key=value pairs joined by ", ".
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+
+	return strings.Join(pairs, ", ")
+}
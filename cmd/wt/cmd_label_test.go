@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+)
+
+func Test_Label_Sets_And_Unsets_Labels_In_Metadata(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "feature", "--label", "ticket=JIRA-123", "--label", "team=core"))
+
+	info, err := readWorktreeInfo(fs.NewReal(), wtPath)
+	if err != nil {
+		t.Fatalf("reading metadata: %v", err)
+	}
+
+	if want := map[string]string{"ticket": "JIRA-123", "team": "core"}; !maps.Equal(info.Labels, want) {
+		t.Errorf("expected labels %v, got %v", want, info.Labels)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "label", "feature", "--set", "ticket=JIRA-456", "--set", "owner=a=b", "--unset", "team")
+	if stdout != "owner=a=b\nticket=JIRA-456" {
+		t.Errorf("unexpected labels output: %q", stdout)
+	}
+
+	var infoOut struct {
+		Labels map[string]string `json:"labels"`
+	}
+
+	err = json.Unmarshal([]byte(c.MustRun("--config", "config.json", "info", "feature", "--json")), &infoOut)
+	if err != nil {
+		t.Fatalf("parsing info JSON: %v", err)
+	}
+
+	if want := map[string]string{"ticket": "JIRA-456", "owner": "a=b"}; !maps.Equal(infoOut.Labels, want) {
+		t.Errorf("expected info labels %v, got %v", want, infoOut.Labels)
+	}
+
+	AssertContains(t, c.MustRun("--config", "config.json", "info", "feature"), "labels:      owner=a=b, ticket=JIRA-456")
+
+	// Removing the last labels drops the key from the file
+	c.MustRun("--config", "config.json", "label", "feature", "--unset", "ticket", "--unset", "owner")
+	AssertNotContains(t, c.ReadFileAt(wtPath, ".wt/worktree.json"), "labels")
+
+	_, stderr, code := c.Run("--config", "config.json", "label", "feature", "--set", "no-value")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "invalid label")
+
+	_, stderr, code = c.Run("--config", "config.json", "label", "missing")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "worktree not found: missing")
+}
+
+func Test_List_Label_Filters_By_Label(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	c.MustRun("--config", "config.json", "create", "--name", "one", "--label", "ticket=A-1", "--label", "team=core")
+	c.MustRun("--config", "config.json", "create", "--name", "two", "--label", "ticket=A-2", "--label", "team=core")
+	c.MustRun("--config", "config.json", "create", "--name", "three")
+
+	var rows []jsonWorktree
+
+	err := json.Unmarshal([]byte(c.MustRun("--config", "config.json", "ls", "--json", "--label", "team=core")), &rows)
+	if err != nil {
+		t.Fatalf("parsing ls JSON: %v", err)
+	}
+
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		names = append(names, row.Name)
+	}
+
+	if strings.Join(names, ",") != "one,two" {
+		t.Errorf("expected one and two, got %v", names)
+	}
+
+	if rows[0].Labels["ticket"] != "A-1" {
+		t.Errorf("expected ticket label A-1 in JSON, got %v", rows[0].Labels)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--label", "team=core", "--label", "ticket=A-2")
+	AssertContains(t, stdout, "two")
+	AssertNotContains(t, stdout, "one")
+
+	stdout = c.MustRun("--config", "config.json", "ls", "--filter", "label.ticket!~A-")
+	AssertContains(t, stdout, "three")
+	AssertNotContains(t, stdout, "two")
+
+	_, stderr, code := c.Run("--config", "config.json", "ls", "--label", "team")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "invalid label")
+}
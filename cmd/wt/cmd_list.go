@@ -1,24 +1,63 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/calvinalkan/agent-task/pkg/fs"
 	flag "github.com/spf13/pflag"
 )
 
+// errJSONAndJSONLMutuallyExclusive is returned when both --json and --jsonl are specified.
+var errJSONAndJSONLMutuallyExclusive = errors.New("cannot use --json and --jsonl together")
+
+// errFormatAndJSONLMutuallyExclusive is returned when both --format and --jsonl are specified.
+var errFormatAndJSONLMutuallyExclusive = errors.New("cannot use --format and --jsonl together")
+
+// errSortAndJSONLMutuallyExclusive is returned when --sort or --reverse is used with --jsonl.
+var errSortAndJSONLMutuallyExclusive = errors.New("cannot use --sort or --reverse with --jsonl (it streams unsorted)")
+
+// errInvalidListSort is returned for an unknown --sort key.
+var errInvalidListSort = errors.New("invalid --sort (valid: created, name, id)")
+
+// errInvalidStale is returned for a --stale value that is not a positive duration.
+var errInvalidStale = errors.New("invalid --stale (use a positive duration like 7d, 12h or 30m)")
+
+// ls --sort keys.
+const (
+	listSortCreated = "created"
+	listSortName    = "name"
+	listSortID      = "id"
+)
+
 // LsCmd returns the ls command.
 func LsCmd(cfg Config, fsys fs.FS, git *Git) *Command {
 	flags := flag.NewFlagSet("ls", flag.ContinueOnError)
 	flags.BoolP("help", "h", false, "Show help")
 	flags.Bool("json", false, "Output as JSON")
+	addFormatFlag(flags)
+	flags.Bool("jsonl", false, "Output one JSON object per line, streamed as worktrees are found")
+	flags.Bool("include-unmanaged", false, "Also show git worktrees without wt metadata")
+	flags.Bool("debug", false, "Show the scanned base directory and where each entry came from")
+	flags.BoolP("long", "l", false, "Also show each worktree's HEAD commit (short SHA and subject)")
+	flags.StringArray("filter", nil, "Only list worktrees matching `<field><op><value>` (repeatable, ANDed)")
+	flags.String("base-branch", "", "Only list worktrees created from this base branch")
+	flags.StringArray("label", nil, "Only list worktrees with this key=value label (repeatable, ANDed)")
+	flags.String("stale", "", "Only list worktrees created more than `duration` ago (e.g. 7d, 12h)")
+	flags.String("sort", listSortCreated, "Sort worktrees by created, name or id")
+	flags.Bool("reverse", false, "Reverse the sort order")
+	flags.Bool("pick", false, "Choose a worktree from a menu and print only its path")
 
 	return &Command{
 		Flags: flags,
@@ -27,17 +66,153 @@ func LsCmd(cfg Config, fsys fs.FS, git *Git) *Command {
 		Long: `List all worktrees managed by wt for the current repository.
 
 Only shows worktrees that have .wt/worktree.json metadata (created by wt).
-Output columns: NAME, PATH, CREATED (relative age).
-
-Use --json for machine-readable output suitable for scripting.`,
+Output columns: NAME, PATH, CREATED (relative age), STATE. When run from
+inside a worktree, that entry is marked with '*' (is_current in JSON).
+
+STATE flags worktrees that need attention: CONFLICTED if there are
+unresolved conflicts, REBASING if a rebase is in progress. It is empty
+for worktrees in a normal state (omitted from JSON). In JSON, "locked" is
+true for worktrees locked with 'git worktree lock' (e.g. by create --lock),
+"prunable" is true for entries git considers removable (e.g. their
+directory was deleted; see wt prune --from-list), and "default_target" is
+the branch wt merge would merge into (see wt merge).
+
+With --include-unmanaged, linked git worktrees without wt metadata (e.g.
+created with plain 'git worktree add') are listed too, marked (unmanaged)
+and with "managed": false in JSON. The main worktree is not listed.
+Worktrees in the base directory whose directory was deleted have no
+metadata left; they are always listed this way, as prunable.
+
+With --long, each entry also shows the commit its HEAD points at: HEAD
+(short SHA) and SUBJECT columns, "head" and "subject" in JSON. This reads
+the commits with one extra git call, so it is off by default.
+
+With --debug, the resolved base directory that was scanned is printed to
+stderr, and each JSON entry gets a "debug" object with that base_dir, the
+source it was found by (base_scan or git_worktree_list) and whether git
+knows it as a worktree (git_known). Useful when worktrees created under a
+different base are missing.
+
+With --filter, only worktrees matching every given predicate are listed.
+A predicate is <field><op><value>, e.g. id>5, base_branch=develop,
+name~foo or created<24h. Operators: = and != for all fields, ~ and !~
+(substring) for text fields, < <= > >= for id and ages. Fields: name,
+agent_id, path, branch, base_branch, state (text), id (number), locked,
+prunable, is_current, managed (true/false), and created or age (the worktree's age,
+as a duration like 30m, 24h or 7d). label.<key> is the text of a label
+(empty if the worktree does not have it). --base-branch <branch> is short
+for --filter base_branch=<branch>, and --label <key>=<value> for --filter
+label.<key>=<value>. --stale <duration> lists only worktrees created longer
+ago than <duration>, e.g. --stale 7d to find abandoned ones; it is short
+for --filter age><duration>, but the duration must be positive.
+
+Worktrees are sorted by --sort: created (the default, oldest first), name
+or id, ties broken by id. --reverse reverses the order. Unmanaged
+worktrees are listed after the managed ones. --jsonl streams entries in
+the order they are found, so it cannot be sorted.
+
+With --pick, the listed worktrees (after --filter and --sort) are shown as
+a numbered menu on stderr, and a selection is read from stdin: a number,
+a name, or part of exactly one name. Only the selected worktree's path is
+printed to stdout, so cd "$(wt ls --pick)" works as a picker. This needs
+an interactive terminal on stdin; otherwise it fails, and the worktree
+should be named instead (wt switch <name>).
+
+Use --json (or --format json) for machine-readable output suitable for
+scripting; --format yaml prints the same fields as YAML. For very
+large numbers of worktrees, --jsonl writes each entry as a single-line JSON
+object as soon as it is read, instead of building one array in memory.`,
 		Exec: func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, _ []string) error {
 			return execList(ctx, stdin, stdout, stderr, cfg, fsys, git, flags)
 		},
 	}
 }
 
-func execList(ctx context.Context, _ io.Reader, stdout, stderr io.Writer, cfg Config, fsys fs.FS, git *Git, flags *flag.FlagSet) error {
+func execList(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, cfg Config, fsys fs.FS, git *Git, flags *flag.FlagSet) error {
 	jsonOutput, _ := flags.GetBool("json")
+	jsonlOutput, _ := flags.GetBool("jsonl")
+	includeUnmanaged, _ := flags.GetBool("include-unmanaged")
+	debug, _ := flags.GetBool("debug")
+	long, _ := flags.GetBool("long")
+	filterExprs, _ := flags.GetStringArray("filter")
+	sortKey, _ := flags.GetString("sort")
+	reverse, _ := flags.GetBool("reverse")
+	pick, _ := flags.GetBool("pick")
+
+	if jsonOutput && jsonlOutput {
+		return errJSONAndJSONLMutuallyExclusive
+	}
+
+	if jsonlOutput && (flags.Changed("sort") || reverse) {
+		return errSortAndJSONLMutuallyExclusive
+	}
+
+	if pick {
+		if jsonOutput || jsonlOutput || flags.Changed("format") {
+			return errPickFormatConflict
+		}
+
+		if cfg.NonInteractive || !readerIsTerminal(stdin) {
+			return errPickRequiresTerminal
+		}
+	}
+
+	switch sortKey {
+	case listSortCreated, listSortName, listSortID:
+	default:
+		return fmt.Errorf("%w: %q", errInvalidListSort, sortKey)
+	}
+
+	if flags.Changed("base-branch") {
+		baseBranch, _ := flags.GetString("base-branch")
+		filterExprs = append(filterExprs, "base_branch="+baseBranch)
+	}
+
+	if flags.Changed("stale") {
+		stale, _ := flags.GetString("stale")
+
+		age, parseErr := parseFilterDuration(stale)
+		if parseErr != nil || age <= 0 {
+			return fmt.Errorf("%w: %q", errInvalidStale, stale)
+		}
+
+		filterExprs = append(filterExprs, "age>"+stale)
+	}
+
+	labelAssignments, _ := flags.GetStringArray("label")
+	for _, assignment := range labelAssignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok || !labelKeyPattern.MatchString(key) {
+			return fmt.Errorf("%w: %q", errInvalidLabel, assignment)
+		}
+
+		filterExprs = append(filterExprs, labelFilterPrefix+key+"="+value)
+	}
+
+	if jsonlOutput && flags.Changed("format") {
+		return errFormatAndJSONLMutuallyExclusive
+	}
+
+	format, err := outputFormat(flags)
+	if err != nil {
+		return err
+	}
+
+	filters := make([]listFilter, 0, len(filterExprs))
+
+	for _, expr := range filterExprs {
+		filter, filterErr := parseListFilter(expr)
+		if filterErr != nil {
+			return filterErr
+		}
+
+		filters = append(filters, filter)
+	}
+
+	now := time.Now()
+	keep := func(row *jsonWorktree) bool {
+		return matchListFilters(filters, row, now)
+	}
 
 	// Get main repo root (works from inside worktrees too)
 	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
@@ -46,19 +221,398 @@ func execList(ctx context.Context, _ io.Reader, stdout, stderr io.Writer, cfg Co
 	}
 
 	// Find worktrees
-	baseDir := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	gitIndex := newGitWorktreeIndex(entries)
+
+	var commits map[string]CommitSummary
+	if long {
+		commits = headCommits(ctx, git, mainRepoRoot, entries)
+	}
+
+	if debug {
+		fprintf(stderr, "debug: scanned base directory %s (base: %s)\n", baseDir, cfg.Base)
+	}
+
+	// Not being inside a worktree is fine, nothing is marked current then
+	currentPath, findErr := findWorktreeRoot(fsys, cfg.EffectiveCwd)
+	if findErr != nil {
+		// Might still be inside an unmanaged worktree
+		currentPath, _ = git.RepoRoot(ctx, cfg.EffectiveCwd)
+	}
+
+	defaultTarget := func(info *WorktreeInfo) string {
+		target, _ := resolveMergeTarget(cfg, info)
+
+		return target
+	}
+
+	managedRow := func(wt WorktreeWithPath) jsonWorktree {
+		if warning := schemaWarning(&wt.WorktreeInfo, wt.Path); warning != "" {
+			fprintln(stderr, warning)
+		}
+
+		entry, gitKnown := gitIndex.lookup(wt.Path)
+
+		row := jsonWorktree{
+			Name:       wt.Name,
+			AgentID:    wt.AgentID,
+			ID:         wt.ID,
+			Path:       wt.Path,
+			Branch:     entry.Branch,
+			BaseBranch: wt.BaseBranch,
+			Source:     wt.Source,
+			Created:    wt.Created,
+			IsCurrent:  isSamePath(wt.Path, currentPath),
+			State:      worktreeState(ctx, fsys, git, wt.Path),
+			Locked:     entry.Locked,
+			Prunable:   entry.Prunable,
+			Managed:    true,
+
+			DefaultTarget: defaultTarget(&wt.WorktreeInfo),
+			Labels:        wt.Labels,
+		}
+
+		setHeadCommit(&row, commits, entry.HEAD)
+
+		if debug {
+			row.Debug = &jsonListDebug{BaseDir: baseDir, Source: listSourceBaseScan, GitKnown: gitKnown}
+		}
+
+		return row
+	}
+
+	if jsonlOutput {
+		return streamListJSONL(ctx, stdout, fsys, git, baseDir, entries, commits, mainRepoRoot, currentPath, includeUnmanaged, debug, managedRow, keep)
+	}
 
 	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
 	if err != nil {
 		return fmt.Errorf("scanning worktrees: %w", err)
 	}
 
+	sortWorktrees(worktrees, sortKey, reverse)
+
+	rows := make([]jsonWorktree, 0, len(worktrees))
+	managedPaths := make([]string, 0, len(worktrees))
+
+	for _, wt := range worktrees {
+		rows = append(rows, managedRow(wt))
+		managedPaths = append(managedPaths, wt.Path)
+	}
+
+	unmanaged := unmanagedRows(ctx, fsys, git, entries, commits, managedPaths, baseDir, mainRepoRoot, currentPath, includeUnmanaged)
+
+	if debug {
+		for i := range unmanaged {
+			unmanaged[i].Debug = &jsonListDebug{BaseDir: baseDir, Source: listSourceGitWorktreeList, GitKnown: true}
+		}
+	}
+
+	rows = append(rows, unmanaged...)
+
+	rows = slices.DeleteFunc(rows, func(row jsonWorktree) bool {
+		return !keep(&row)
+	})
+
+	if pick {
+		picked, pickErr := pickWorktree(stdin, stderr, rows)
+		if pickErr != nil {
+			return pickErr
+		}
+
+		fprintln(stdout, picked.Path)
+
+		return nil
+	}
+
 	// Output
-	if jsonOutput {
-		return outputListJSON(stdout, worktrees)
+	switch format {
+	case formatJSON:
+		return outputListJSON(stdout, rows)
+	case formatYAML:
+		return encodeYAML(stdout, rows)
+	}
+
+	return outputListTable(stdout, stderr, rows, long)
+}
+
+// sortWorktrees sorts worktrees in place by key (created, name or id), with
+// ties broken by id, descending if reverse is set.
+func sortWorktrees(worktrees []WorktreeWithPath, key string, reverse bool) {
+	slices.SortStableFunc(worktrees, func(a, b WorktreeWithPath) int {
+		var c int
+
+		switch key {
+		case listSortCreated:
+			c = a.Created.Compare(b.Created)
+		case listSortName:
+			c = strings.Compare(a.Name, b.Name)
+		}
+
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+
+		if reverse {
+			return -c
+		}
+
+		return c
+	})
+}
+
+// streamListJSONL writes one JSON object per line for each worktree as it is
+// read from baseDir, followed by the rows of unmanagedRows. Rows for which keep returns false are skipped. Only the paths of
+// managed worktrees are kept in memory.
+func streamListJSONL(
+	ctx context.Context,
+	stdout io.Writer,
+	fsys fs.FS,
+	git *Git,
+	baseDir string,
+	entries []WorktreeEntry,
+	commits map[string]CommitSummary,
+	mainRepoRoot, currentPath string,
+	includeUnmanaged, debug bool,
+	managedRow func(WorktreeWithPath) jsonWorktree,
+	keep func(*jsonWorktree) bool,
+) error {
+	enc := json.NewEncoder(stdout)
+	managedPaths := []string{}
+
+	err := walkWorktrees(fsys, baseDir, func(wt WorktreeWithPath) error {
+		managedPaths = append(managedPaths, wt.Path)
+
+		row := managedRow(wt)
+		if !keep(&row) {
+			return nil
+		}
+
+		encodeErr := enc.Encode(row)
+		if encodeErr != nil {
+			return fmt.Errorf("encoding JSON: %w", encodeErr)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range unmanagedRows(ctx, fsys, git, entries, commits, managedPaths, baseDir, mainRepoRoot, currentPath, includeUnmanaged) {
+		if !keep(&row) {
+			continue
+		}
+
+		if debug {
+			row.Debug = &jsonListDebug{BaseDir: baseDir, Source: listSourceGitWorktreeList, GitKnown: true}
+		}
+
+		encodeErr := enc.Encode(row)
+		if encodeErr != nil {
+			return fmt.Errorf("encoding JSON: %w", encodeErr)
+		}
+	}
+
+	return nil
+}
+
+// unmanagedRows returns list rows for linked git worktrees that are not
+// among the managed worktree paths (no .wt/worktree.json in the base
+// directory). Unless includeUnmanaged is set, only prunable entries directly
+// in baseDir are included: managed worktrees whose directory was deleted, so
+// their metadata is gone with it. The main worktree and bare entries are
+// never included.
+func unmanagedRows(
+	ctx context.Context,
+	fsys fs.FS,
+	git *Git,
+	entries []WorktreeEntry,
+	commits map[string]CommitSummary,
+	managedPaths []string,
+	baseDir, mainRepoRoot, currentPath string,
+	includeUnmanaged bool,
+) []jsonWorktree {
+	rows := make([]jsonWorktree, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.Bare || isSamePath(entry.Path, mainRepoRoot) {
+			continue
+		}
+
+		isManaged := slices.ContainsFunc(managedPaths, func(path string) bool {
+			return isSamePath(path, entry.Path)
+		})
+		if isManaged {
+			continue
+		}
+
+		if !includeUnmanaged && (!entry.Prunable || !isSamePath(filepath.Dir(entry.Path), baseDir)) {
+			continue
+		}
+
+		row := jsonWorktree{
+			Name:      filepath.Base(entry.Path),
+			Path:      entry.Path,
+			Branch:    entry.Branch,
+			IsCurrent: isSamePath(entry.Path, currentPath),
+			State:     worktreeState(ctx, fsys, git, entry.Path),
+			Locked:    entry.Locked,
+			Prunable:  entry.Prunable,
+			Managed:   false,
+		}
+
+		setHeadCommit(&row, commits, entry.HEAD)
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// headCommits looks up the HEAD commit of every worktree in entries with a
+// single git call. Errors are not fatal for ls: entries just show no commit.
+// If the batch fails (e.g. one HEAD is not a readable commit), each commit
+// is looked up on its own, so only the broken entries lose theirs.
+func headCommits(ctx context.Context, git *Git, mainRepoRoot string, entries []WorktreeEntry) map[string]CommitSummary {
+	heads := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.HEAD != "" && !entry.Bare {
+			heads = append(heads, entry.HEAD)
+		}
+	}
+
+	commits, err := git.CommitSummaries(ctx, mainRepoRoot, heads)
+	if err == nil {
+		return commits
+	}
+
+	commits = make(map[string]CommitSummary, len(heads))
+
+	for _, head := range heads {
+		commit, headErr := git.CommitSummaries(ctx, mainRepoRoot, []string{head})
+		if headErr == nil {
+			maps.Copy(commits, commit)
+		}
+	}
+
+	return commits
+}
+
+// setHeadCommit fills in row's head and subject from commits (nil without
+// --long).
+func setHeadCommit(row *jsonWorktree, commits map[string]CommitSummary, head string) {
+	commit, ok := commits[head]
+	if !ok {
+		return
+	}
+
+	row.Head = commit.ShortSHA
+	row.Subject = commit.Subject
+}
+
+// Worktree states reported by ls for worktrees that need attention.
+const (
+	worktreeStateConflicted = "CONFLICTED"
+	worktreeStateRebasing   = "REBASING"
+)
+
+// worktreeState reports whether the worktree at wtPath has unresolved
+// conflicts or a rebase in progress. Returns "" for a worktree in a normal
+// state, or when its git dir cannot be found (e.g. stale metadata).
+//
+// ls calls this for every row, so it only stats git's state files and asks
+// git for the conflicting files when a rebase, merge, cherry-pick or revert
+// is stopped. Conflicts left by git stash pop are not reported.
+func worktreeState(ctx context.Context, fsys fs.FS, git *Git, wtPath string) string {
+	gitDir, ok := worktreeGitDir(fsys, wtPath)
+	if !ok {
+		return ""
+	}
+
+	rebasing := rebaseInGitDir(fsys, gitDir)
+
+	if rebasing || gitDirHas(fsys, gitDir, "MERGE_HEAD", "CHERRY_PICK_HEAD", "REVERT_HEAD") {
+		conflicts, err := git.ConflictingFiles(ctx, wtPath)
+		if err == nil && len(conflicts) > 0 {
+			return worktreeStateConflicted
+		}
+	}
+
+	if rebasing {
+		return worktreeStateRebasing
+	}
+
+	return ""
+}
+
+// worktreeGitDir returns the git dir of the worktree at wtPath without
+// running git: .git itself for the main worktree, else the path in the
+// "gitdir: <path>" line of the .git file of a linked worktree.
+func worktreeGitDir(fsys fs.FS, wtPath string) (string, bool) {
+	dotGit := filepath.Join(wtPath, ".git")
+
+	stat, err := fsys.Stat(dotGit)
+	if err != nil {
+		return "", false
+	}
+
+	if stat.IsDir() {
+		return dotGit, true
+	}
+
+	data, err := fsys.ReadFile(dotGit)
+	if err != nil {
+		return "", false
+	}
+
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", false
+	}
+
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(wtPath, gitDir)
+	}
+
+	return gitDir, true
+}
+
+// rebaseInProgress reports whether git has a rebase in progress in the
+// worktree at wtPath.
+func rebaseInProgress(ctx context.Context, fsys fs.FS, git *Git, wtPath string) bool {
+	gitDir, err := git.GitDir(ctx, wtPath)
+	if err != nil {
+		return false
 	}
 
-	return outputListTable(stdout, stderr, worktrees)
+	return rebaseInGitDir(fsys, gitDir)
+}
+
+// rebaseInGitDir reports whether git has a rebase in progress in the
+// worktree whose git dir is gitDir.
+func rebaseInGitDir(fsys fs.FS, gitDir string) bool {
+	return gitDirHas(fsys, gitDir, "rebase-merge", "rebase-apply")
+}
+
+// gitDirHas reports whether any of names exists in gitDir.
+func gitDirHas(fsys fs.FS, gitDir string, names ...string) bool {
+	return slices.ContainsFunc(names, func(name string) bool {
+		_, err := fsys.Stat(filepath.Join(gitDir, name))
+
+		return err == nil
+	})
 }
 
 // WorktreeWithPath combines WorktreeInfo with its filesystem path.
@@ -68,7 +622,16 @@ type WorktreeWithPath struct {
 	Path string `json:"path"`
 }
 
-// findWorktreesWithPaths scans baseDir for wt-managed worktrees and returns them with paths.
+// metadataReaders bounds the concurrent metadata reads of
+// findWorktreesWithPaths. The reads wait on I/O rather than CPU, so this
+// is not tied to GOMAXPROCS.
+const metadataReaders = 16
+
+// findWorktreesWithPaths scans baseDir for wt-managed worktrees and returns
+// them with paths, in directory order. The metadata files are read by up to
+// metadataReaders goroutines at once, since on network filesystems each read
+// mostly waits. Directories without valid metadata are skipped, as in
+// walkWorktrees.
 func findWorktreesWithPaths(fsys fs.FS, baseDir string) ([]WorktreeWithPath, error) {
 	entries, err := fsys.ReadDir(baseDir)
 	if err != nil {
@@ -79,7 +642,66 @@ func findWorktreesWithPaths(fsys fs.FS, baseDir string) ([]WorktreeWithPath, err
 		return nil, fmt.Errorf("reading directory: %w", err)
 	}
 
-	result := make([]WorktreeWithPath, 0, len(entries))
+	paths := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			paths = append(paths, filepath.Join(baseDir, entry.Name()))
+		}
+	}
+
+	// Each worker writes only its own slots, so no locking is needed
+	read := make([]WorktreeWithPath, len(paths))
+	valid := make([]bool, len(paths))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(metadataReaders, len(paths)) {
+		wg.Go(func() {
+			for i := range next {
+				info, readErr := readWorktreeInfo(fsys, paths[i])
+				if readErr != nil {
+					// Not a wt-managed worktree, skip
+					continue
+				}
+
+				read[i] = WorktreeWithPath{WorktreeInfo: info, Path: paths[i]}
+				valid[i] = true
+			}
+		})
+	}
+
+	for i := range paths {
+		next <- i
+	}
+
+	close(next)
+	wg.Wait()
+
+	var result []WorktreeWithPath
+
+	for i, wt := range read {
+		if valid[i] {
+			result = append(result, wt)
+		}
+	}
+
+	return result, nil
+}
+
+// walkWorktrees calls fn for each wt-managed worktree in baseDir, in
+// directory order, as its metadata is read. A missing baseDir has no
+// worktrees. Iteration stops at the first error returned by fn.
+func walkWorktrees(fsys fs.FS, baseDir string, fn func(WorktreeWithPath) error) error {
+	entries, err := fsys.ReadDir(baseDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("reading directory: %w", err)
+	}
 
 	for _, entry := range entries {
 		if !entry.IsDir() {
@@ -94,28 +716,97 @@ func findWorktreesWithPaths(fsys fs.FS, baseDir string) ([]WorktreeWithPath, err
 			continue
 		}
 
-		result = append(result, WorktreeWithPath{
+		err = fn(WorktreeWithPath{
 			WorktreeInfo: info,
 			Path:         wtPath,
 		})
+		if err != nil {
+			return err
+		}
 	}
 
-	return result, nil
+	return nil
+}
+
+// gitWorktreeIndex maps worktree paths to git's view of them, so metadata
+// scanned from the base directory can be joined with "git worktree list".
+type gitWorktreeIndex map[string]WorktreeEntry
+
+func newGitWorktreeIndex(entries []WorktreeEntry) gitWorktreeIndex {
+	index := make(gitWorktreeIndex, len(entries))
+	for _, entry := range entries {
+		index[filepath.Clean(entry.Path)] = entry
+	}
+
+	return index
+}
+
+// lookup finds the git entry for path. Git reports symlink-resolved paths,
+// so the resolved form of path is tried when the literal path is unknown.
+func (idx gitWorktreeIndex) lookup(path string) (WorktreeEntry, bool) {
+	entry, ok := idx[filepath.Clean(path)]
+	if ok {
+		return entry, true
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return WorktreeEntry{}, false
+	}
+
+	entry, ok = idx[resolved]
+
+	return entry, ok
 }
 
-func outputListTable(stdout, stderr io.Writer, worktrees []WorktreeWithPath) error {
-	if len(worktrees) == 0 {
+// isSamePath reports whether a and b refer to the same directory, resolving
+// symlinks when the literal paths differ.
+func isSamePath(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+
+	resolvedA, errA := filepath.EvalSymlinks(a)
+	resolvedB, errB := filepath.EvalSymlinks(b)
+
+	return errA == nil && errB == nil && resolvedA == resolvedB
+}
+
+func outputListTable(stdout, stderr io.Writer, rows []jsonWorktree, long bool) error {
+	if len(rows) == 0 {
 		fprintln(stderr, "No worktrees found. Create one with: wt create")
 
 		return nil
 	}
 
 	// Header
-	fprintf(stdout, "%-15s %-50s %s\n", "NAME", "PATH", "CREATED")
+	if long {
+		fprintf(stdout, "  %-15s %-50s %-15s %-10s %-9s %s\n", "NAME", "PATH", "CREATED", "STATE", "HEAD", "SUBJECT")
+	} else {
+		fprintf(stdout, "  %-15s %-50s %-15s %s\n", "NAME", "PATH", "CREATED", "STATE")
+	}
 
-	for _, wt := range worktrees {
-		age := formatAge(wt.Created)
-		fprintf(stdout, "%-15s %-50s %s\n", wt.Name, wt.Path, age)
+	for _, row := range rows {
+		marker := " "
+		if row.IsCurrent {
+			marker = "*"
+		}
+
+		age := "(unmanaged)"
+		if row.Managed {
+			age = formatAge(row.Created)
+		}
+
+		line := fmt.Sprintf("%s %-15s %-50s %-15s %s", marker, row.Name, row.Path, age, row.State)
+		if long {
+			line = fmt.Sprintf("%s %-15s %-50s %-15s %-10s %-9s %s", marker, row.Name, row.Path, age, row.State, row.Head, row.Subject)
+		}
+
+		fprintln(stdout, strings.TrimRight(line, " "))
 	}
 
 	return nil
@@ -151,34 +842,93 @@ func formatAge(t time.Time) string {
 	}
 }
 
-// jsonWorktree is the JSON output format for a worktree.
+// jsonWorktree is the JSON output format for a worktree, and the row type
+// rendered by the table. Unmanaged worktrees have no wt metadata, so their
+// metadata fields are omitted.
 type jsonWorktree struct {
 	Name       string    `json:"name"`
 	AgentID    string    `json:"agent_id"`
 	ID         int       `json:"id"`
 	Path       string    `json:"path"`
+	Branch     string    `json:"branch"`
 	BaseBranch string    `json:"base_branch"`
+	Source     string    `json:"source,omitempty"`
 	Created    time.Time `json:"created"`
+	IsCurrent  bool      `json:"is_current"`
+	State      string    `json:"state,omitempty"`
+	Locked     bool      `json:"locked"`
+	Prunable   bool      `json:"prunable"`
+	Managed    bool      `json:"managed"`
+
+	// Branch wt merge targets by default (managed worktrees only)
+	DefaultTarget string `json:"default_target,omitempty"`
+
+	// Labels from the metadata (managed worktrees only)
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// HEAD commit's short SHA and subject; only set with --long
+	Head    string `json:"head,omitempty"`
+	Subject string `json:"subject,omitempty"`
+
+	// Only set with --debug
+	Debug *jsonListDebug `json:"debug,omitempty"`
 }
 
-func outputListJSON(output io.Writer, worktrees []WorktreeWithPath) error {
-	result := make([]jsonWorktree, len(worktrees))
+// unmanagedJSONWorktree is jsonWorktree for unmanaged rows, which have no
+// metadata: agent_id, id, base_branch and created are left out instead of
+// being zero. Its fields must match jsonWorktree's.
+type unmanagedJSONWorktree struct {
+	Name          string            `json:"name"`
+	AgentID       string            `json:"agent_id,omitzero"`
+	ID            int               `json:"id,omitzero"`
+	Path          string            `json:"path"`
+	Branch        string            `json:"branch"`
+	BaseBranch    string            `json:"base_branch,omitzero"`
+	Source        string            `json:"source,omitempty"`
+	Created       time.Time         `json:"created,omitzero"`
+	IsCurrent     bool              `json:"is_current"`
+	State         string            `json:"state,omitempty"`
+	Locked        bool              `json:"locked"`
+	Prunable      bool              `json:"prunable"`
+	Managed       bool              `json:"managed"`
+	DefaultTarget string            `json:"default_target,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Head          string            `json:"head,omitempty"`
+	Subject       string            `json:"subject,omitempty"`
+	Debug         *jsonListDebug    `json:"debug,omitempty"`
+}
 
-	for i, wt := range worktrees {
-		result[i] = jsonWorktree{
-			Name:       wt.Name,
-			AgentID:    wt.AgentID,
-			ID:         wt.ID,
-			Path:       wt.Path,
-			BaseBranch: wt.BaseBranch,
-			Created:    wt.Created,
-		}
+// MarshalJSON keeps every key of managed rows, even when zero, and leaves
+// the metadata-only ones out for unmanaged rows.
+func (w jsonWorktree) MarshalJSON() ([]byte, error) {
+	if !w.Managed {
+		return json.Marshal(unmanagedJSONWorktree(w))
 	}
 
+	// Same fields without the method, so this does not recurse
+	type managedJSONWorktree jsonWorktree
+
+	return json.Marshal(managedJSONWorktree(w))
+}
+
+// Sources ls --debug reports for how an entry was discovered.
+const (
+	listSourceBaseScan        = "base_scan"
+	listSourceGitWorktreeList = "git_worktree_list"
+)
+
+// jsonListDebug explains where an ls entry came from.
+type jsonListDebug struct {
+	BaseDir  string `json:"base_dir"`  // Base directory that was scanned for metadata
+	Source   string `json:"source"`    // listSourceBaseScan or listSourceGitWorktreeList
+	GitKnown bool   `json:"git_known"` // Whether "git worktree list" includes the path
+}
+
+func outputListJSON(output io.Writer, rows []jsonWorktree) error {
 	enc := json.NewEncoder(output)
 	enc.SetIndent("", "  ")
 
-	encodeErr := enc.Encode(result)
+	encodeErr := enc.Encode(rows)
 	if encodeErr != nil {
 		return fmt.Errorf("encoding JSON: %w", encodeErr)
 	}
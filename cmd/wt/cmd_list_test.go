@@ -2,8 +2,10 @@ package main
 
 import (
 	"encoding/json"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -738,3 +740,1033 @@ func Test_List_From_Inside_Worktree_Shows_All_Worktrees(t *testing.T) {
 		t.Errorf("expected 2 worktrees when listing from inside worktree, got %d", len(worktrees))
 	}
 }
+
+func Test_List_JSON_Includes_Checked_Out_Branch(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	c.MustRun("--config", "config.json", "create", "--name", "branch-list")
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--json")
+
+	var worktrees []jsonWorktree
+
+	err := json.Unmarshal([]byte(stdout), &worktrees)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	if len(worktrees) != 1 {
+		t.Fatalf("expected 1 worktree, got %d", len(worktrees))
+	}
+
+	if worktrees[0].Branch != "branch-list" {
+		t.Errorf("expected branch 'branch-list', got %q", worktrees[0].Branch)
+	}
+}
+
+func Test_List_Long_Includes_Head_Commit(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	c.MustRun("--config", "config.json", "create", "--name", "wt-plain")
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "wt-commit"))
+	gitCommitInDir(t, wtPath, "feature.txt", "feature", "Add feature file")
+
+	out, err := testGitCmd("-C", wtPath, "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("git rev-parse failed: %v", err)
+	}
+
+	featureSHA := strings.TrimSpace(string(out))
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--long", "--json")
+
+	var worktrees []jsonWorktree
+
+	err = json.Unmarshal([]byte(stdout), &worktrees)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	subjects := map[string]string{}
+
+	for _, wt := range worktrees {
+		if wt.Head == "" {
+			t.Errorf("%s: expected head to be set", wt.Name)
+		}
+
+		subjects[wt.Name] = wt.Subject
+	}
+
+	if subjects["wt-plain"] != "initial commit" || subjects["wt-commit"] != "Add feature file" {
+		t.Errorf("unexpected subjects: %v", subjects)
+	}
+
+	stdout = c.MustRun("--config", "config.json", "ls", "-l")
+
+	AssertContains(t, stdout, "HEAD")
+	AssertContains(t, stdout, "SUBJECT")
+	AssertContains(t, stdout, featureSHA)
+	AssertContains(t, stdout, "Add feature file")
+
+	// Without --long no commit is read
+	stdout = c.MustRun("--config", "config.json", "ls", "--json")
+
+	AssertNotContains(t, stdout, `"head"`)
+	AssertNotContains(t, stdout, `"subject"`)
+	AssertNotContains(t, c.MustRun("--config", "config.json", "ls"), "SUBJECT")
+}
+
+func Test_List_Long_Keeps_Commits_When_One_HEAD_Is_Unreadable(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "healthy")
+	c.MustRun("--config", "config.json", "create", "--name", "broken")
+
+	// Detach broken at a commit that does not exist
+	c.WriteFile(".git/worktrees/broken/HEAD", strings.Repeat("1", 40)+"\n")
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--long", "--json")
+
+	var worktrees []jsonWorktree
+
+	err := json.Unmarshal([]byte(stdout), &worktrees)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	subjects := map[string]string{}
+	for _, wt := range worktrees {
+		subjects[wt.Name] = wt.Subject
+	}
+
+	if want := map[string]string{"healthy": "initial commit", "broken": ""}; !maps.Equal(subjects, want) {
+		t.Errorf("subjects = %v, want %v", subjects, want)
+	}
+}
+
+func Test_List_Marks_Current_Worktree_From_Inside_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	c.MustRun("--config", "config.json", "create", "--name", "wt-first")
+	c.MustRun("--config", "config.json", "create", "--name", "wt-second")
+
+	c.WriteFile(filepath.Join("worktrees", "wt-second", "config.json"), c.ReadFile("config.json"))
+
+	wtPath := filepath.Join(c.Dir, "worktrees", "wt-second")
+
+	stdout, stderr, code := c.RunInDir(wtPath, "--config", "config.json", "ls", "--json")
+	if code != 0 {
+		t.Fatalf("list from worktree failed: %s", stderr)
+	}
+
+	var worktrees []jsonWorktree
+
+	err := json.Unmarshal([]byte(stdout), &worktrees)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	current := 0
+
+	for _, wt := range worktrees {
+		if wt.IsCurrent {
+			current++
+
+			if wt.Name != "wt-second" {
+				t.Errorf("expected wt-second to be current, got %s", wt.Name)
+			}
+		}
+	}
+
+	if current != 1 {
+		t.Errorf("expected exactly 1 current worktree, got %d", current)
+	}
+
+	table, stderr, code := c.RunInDir(filepath.Join(wtPath, ".wt"), "--config", "../config.json", "ls")
+	if code != 0 {
+		t.Fatalf("list from worktree subdir failed: %s", stderr)
+	}
+
+	AssertContains(t, table, "* wt-second")
+	AssertNotContains(t, table, "* wt-first")
+}
+
+func Test_List_From_Main_Repo_Marks_No_Worktree_Current(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	c.MustRun("--config", "config.json", "create", "--name", "wt-only")
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--json")
+
+	var worktrees []jsonWorktree
+
+	err := json.Unmarshal([]byte(stdout), &worktrees)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	if len(worktrees) != 1 || worktrees[0].IsCurrent {
+		t.Errorf("expected one non-current worktree, got %+v", worktrees)
+	}
+
+	AssertNotContains(t, c.MustRun("--config", "config.json", "ls"), "*")
+}
+
+// listStates returns the state reported by "ls --json" for each worktree name.
+func listStates(t *testing.T, c *CLI) map[string]string {
+	t.Helper()
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--json")
+
+	var worktrees []jsonWorktree
+
+	err := json.Unmarshal([]byte(stdout), &worktrees)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	states := make(map[string]string, len(worktrees))
+	for _, wt := range worktrees {
+		states[wt.Name] = wt.State
+	}
+
+	return states
+}
+
+func Test_List_Shows_Conflicted_Worktree_Mid_Rebase(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "stuck-wt"))
+	c.MustRun("--config", "config.json", "create", "--name", "fine-wt")
+
+	gitCommitInDir(t, wtPath, "shared.txt", "worktree version\n", "Worktree change")
+	gitCommitInDir(t, c.Dir, "shared.txt", "master version\n", "Master change")
+
+	_, err := testGitCmd("-C", wtPath, "rebase", "master").CombinedOutput()
+	if err == nil {
+		t.Fatal("expected rebase to stop on a conflict")
+	}
+
+	states := listStates(t, c)
+
+	if states["stuck-wt"] != "CONFLICTED" {
+		t.Errorf("expected stuck-wt to be CONFLICTED, got %q", states["stuck-wt"])
+	}
+
+	if states["fine-wt"] != "" {
+		t.Errorf("expected fine-wt to have no state, got %q", states["fine-wt"])
+	}
+
+	stdout := c.MustRun("--config", "config.json", "ls")
+	AssertContains(t, stdout, "STATE")
+	AssertContains(t, stdout, "CONFLICTED")
+}
+
+func Test_List_Shows_Conflicted_Worktree_Mid_Merge(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "merging-wt"))
+
+	gitCommitInDir(t, wtPath, "shared.txt", "worktree version\n", "Worktree change")
+	gitCommitInDir(t, c.Dir, "shared.txt", "master version\n", "Master change")
+
+	_, err := testGitCmd("-C", wtPath, "merge", "master").CombinedOutput()
+	if err == nil {
+		t.Fatal("expected merge to stop on a conflict")
+	}
+
+	if states := listStates(t, c); states["merging-wt"] != "CONFLICTED" {
+		t.Errorf("expected merging-wt to be CONFLICTED, got %q", states["merging-wt"])
+	}
+}
+
+func Test_List_Shows_Rebasing_Worktree_Without_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "paused-wt"))
+
+	gitCommitInDir(t, wtPath, "feature.txt", "feature\n", "Feature work")
+	gitCommitInDir(t, c.Dir, "other.txt", "other\n", "Master work")
+
+	// Stop the rebase at the first commit without any conflict
+	cmd := testGitCmd("-C", wtPath, "rebase", "-i", "master")
+	cmd.Env = append(cmd.Env, "GIT_SEQUENCE_EDITOR=sed -i -e s/^pick/edit/")
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git rebase -i failed: %v\n%s", err, out)
+	}
+
+	states := listStates(t, c)
+
+	if states["paused-wt"] != "REBASING" {
+		t.Errorf("expected paused-wt to be REBASING, got %q", states["paused-wt"])
+	}
+
+	AssertContains(t, c.MustRun("--config", "config.json", "ls"), "REBASING")
+}
+
+func Test_List_Include_Unmanaged_Shows_Plain_Git_Worktrees(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "managed-wt")
+
+	plainPath := filepath.Join(c.Dir, "plain-wt")
+
+	out, err := testGitCmd("-C", c.Dir, "worktree", "add", "-b", "plain-branch", plainPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git worktree add failed: %v\n%s", err, out)
+	}
+
+	// Without the flag only managed worktrees are listed
+	stdout := c.MustRun("--config", "config.json", "ls")
+	AssertContains(t, stdout, "managed-wt")
+	AssertNotContains(t, stdout, "plain-wt")
+
+	stdout = c.MustRun("--config", "config.json", "ls", "--include-unmanaged")
+	AssertContains(t, stdout, "managed-wt")
+	AssertContains(t, stdout, "plain-wt")
+	AssertContains(t, stdout, "(unmanaged)")
+
+	stdout = c.MustRun("--config", "config.json", "ls", "--include-unmanaged", "--json")
+
+	var worktrees []jsonWorktree
+
+	err = json.Unmarshal([]byte(stdout), &worktrees)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	if len(worktrees) != 2 {
+		t.Fatalf("expected 2 worktrees (main repo excluded), got %d: %s", len(worktrees), stdout)
+	}
+
+	byName := make(map[string]jsonWorktree, len(worktrees))
+	for _, wt := range worktrees {
+		byName[wt.Name] = wt
+	}
+
+	if !byName["managed-wt"].Managed || byName["managed-wt"].ID != 1 {
+		t.Errorf("managed-wt should be managed with id 1, got %+v", byName["managed-wt"])
+	}
+
+	plain := byName["plain-wt"]
+	if plain.Managed || plain.Branch != "plain-branch" || plain.Path != plainPath {
+		t.Errorf("unexpected unmanaged entry: %+v", plain)
+	}
+
+	var raw []map[string]any
+
+	err = json.Unmarshal([]byte(stdout), &raw)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+
+	for _, entry := range raw {
+		if entry["name"] != "plain-wt" {
+			continue
+		}
+
+		if entry["managed"] != false {
+			t.Errorf("expected managed: false, got %v", entry["managed"])
+		}
+
+		if _, ok := entry["created"]; ok {
+			t.Errorf("unmanaged entry should not have created: %v", entry)
+		}
+	}
+}
+
+func Test_List_JSON_Keeps_Zero_Metadata_Keys_For_Managed_Worktrees(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "no-base")
+
+	// Metadata written before base_branch was recorded
+	metaPath := "worktrees/no-base/.wt/worktree.json"
+	c.WriteFile(metaPath, strings.Replace(c.ReadFile(metaPath), `"base_branch": "master"`, `"base_branch": ""`, 1))
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--json")
+
+	var raw []map[string]any
+
+	err := json.Unmarshal([]byte(stdout), &raw)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	if len(raw) != 1 {
+		t.Fatalf("expected 1 worktree, got %d: %s", len(raw), stdout)
+	}
+
+	for _, key := range []string{"agent_id", "id", "base_branch", "created"} {
+		if _, ok := raw[0][key]; !ok {
+			t.Errorf("managed entry should have %s: %v", key, raw[0])
+		}
+	}
+
+	if raw[0]["base_branch"] != "" {
+		t.Errorf("base_branch = %v, want empty", raw[0]["base_branch"])
+	}
+}
+
+func Test_List_Include_Unmanaged_Marks_Current_Unmanaged_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	plainPath := filepath.Join(c.Dir, "plain-wt")
+
+	out, err := testGitCmd("-C", c.Dir, "worktree", "add", "-b", "plain-branch", plainPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git worktree add failed: %v\n%s", err, out)
+	}
+
+	stdout, stderr, code := c.RunInDir(plainPath, "--config", "../config.json", "ls", "--include-unmanaged")
+	if code != 0 {
+		t.Fatalf("ls failed: %s", stderr)
+	}
+
+	AssertContains(t, stdout, "* plain-wt")
+}
+
+func Test_List_Debug_Reports_Base_Dir_And_Source(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "managed-wt")
+
+	plainPath := filepath.Join(c.Dir, "plain-wt")
+
+	out, err := testGitCmd("-C", c.Dir, "worktree", "add", "-b", "plain-branch", plainPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git worktree add failed: %v\n%s", err, out)
+	}
+
+	baseDir := filepath.Join(c.Dir, "worktrees")
+
+	stdout, stderr, code := c.Run("--config", "config.json", "ls", "--json", "--include-unmanaged", "--debug")
+	if code != 0 {
+		t.Fatalf("ls failed: %s", stderr)
+	}
+
+	AssertContains(t, stderr, "debug: scanned base directory "+baseDir)
+
+	var worktrees []jsonWorktree
+
+	err = json.Unmarshal([]byte(stdout), &worktrees)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	for _, wt := range worktrees {
+		if wt.Debug == nil {
+			t.Fatalf("expected debug info for %s", wt.Name)
+		}
+
+		if wt.Debug.BaseDir != baseDir || !wt.Debug.GitKnown {
+			t.Errorf("unexpected debug info for %s: %+v", wt.Name, *wt.Debug)
+		}
+
+		want := listSourceBaseScan
+		if wt.Name == "plain-wt" {
+			want = listSourceGitWorktreeList
+		}
+
+		if wt.Debug.Source != want {
+			t.Errorf("%s: expected source %s, got %s", wt.Name, want, wt.Debug.Source)
+		}
+	}
+
+	// Without --debug the output is unchanged
+	stdout, stderr, _ = c.Run("--config", "config.json", "ls", "--json")
+	AssertNotContains(t, stdout, `"debug"`)
+	AssertNotContains(t, stderr, "debug:")
+}
+
+func Test_List_JSONL_Writes_One_Object_Per_Line(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	c.MustRun("--config", "config.json", "create", "--name", "wt-alpha")
+	c.MustRun("--config", "config.json", "create", "--name", "wt-beta")
+
+	out, err := testGitCmd("-C", c.Dir, "worktree", "add", "-b", "plain", filepath.Join(c.Dir, "plain-wt")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git worktree add failed: %v\n%s", err, out)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--jsonl", "--include-unmanaged")
+
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d:\n%s", len(lines), stdout)
+	}
+
+	names := make(map[string]bool)
+
+	for _, line := range lines {
+		var wt jsonWorktree
+
+		err := json.Unmarshal([]byte(line), &wt)
+		if err != nil {
+			t.Fatalf("line is not valid JSON: %v\n%s", err, line)
+		}
+
+		names[wt.Name] = wt.Managed
+	}
+
+	if !names["wt-alpha"] || !names["wt-beta"] {
+		t.Errorf("managed worktrees missing from output: %v", names)
+	}
+
+	if managed, ok := names["plain-wt"]; !ok || managed {
+		t.Errorf("unmanaged worktree should be listed with managed=false: %v", names)
+	}
+}
+
+func Test_List_JSONL_Empty_Writes_Nothing_And_Conflicts_With_JSON(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout, stderr, code := c.Run("--config", "config.json", "ls", "--jsonl")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	if stdout != "" || stderr != "" {
+		t.Errorf("expected no output, got stdout %q, stderr %q", stdout, stderr)
+	}
+
+	_, stderr, code = c.Run("--config", "config.json", "ls", "--jsonl", "--json")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --json and --jsonl together")
+}
+
+func Test_List_Filter_Combines_Predicates(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	c.MustRun("--config", "config.json", "create", "--name", "fox-one")
+	c.MustRun("--config", "config.json", "create", "--name", "fox-two")
+	c.MustRun("--config", "config.json", "create", "--name", "bear")
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--filter", "name~fox", "--filter", "id>1", "--filter", "created<1h")
+	AssertContains(t, stdout, "fox-two")
+	AssertNotContains(t, stdout, "fox-one")
+	AssertNotContains(t, stdout, "bear")
+
+	stdout = c.MustRun("--config", "config.json", "ls", "--jsonl", "--filter", "name=bear")
+	if strings.Contains(stdout, `"name":"fox`) || !strings.Contains(stdout, `"name":"bear"`) {
+		t.Errorf("expected only bear in JSONL output, got:\n%s", stdout)
+	}
+
+	_, stderr, code := c.Run("--config", "config.json", "ls", "--filter", "colour=red")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for unknown field, got %d", code)
+	}
+
+	AssertContains(t, stderr, `unknown filter field "colour"`)
+}
+
+func Test_List_Stale_Shows_Only_Old_Worktrees(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	fsys := fs.NewReal()
+	now := time.Now().UTC()
+
+	for i, wtData := range []struct {
+		name       string
+		baseBranch string
+		age        time.Duration
+	}{
+		{"abandoned", "master", 10 * 24 * time.Hour},
+		{"old-develop", "develop", 8 * 24 * time.Hour},
+		{"fresh", "master", time.Hour},
+	} {
+		wtPath := filepath.Join(c.Dir, "worktrees", wtData.name)
+
+		err := os.MkdirAll(wtPath, 0o750)
+		if err != nil {
+			t.Fatalf("failed to create worktree dir: %v", err)
+		}
+
+		info := WorktreeInfo{
+			Name:       wtData.name,
+			AgentID:    wtData.name,
+			ID:         i + 1,
+			BaseBranch: wtData.baseBranch,
+			Created:    now.Add(-wtData.age),
+		}
+
+		err = writeWorktreeInfo(fsys, wtPath, &info)
+		if err != nil {
+			t.Fatalf("failed to write worktree info: %v", err)
+		}
+	}
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--stale", "7d")
+	AssertContains(t, stdout, "abandoned")
+	AssertContains(t, stdout, "old-develop")
+	AssertNotContains(t, stdout, "fresh")
+
+	stdout = c.MustRun("--config", "config.json", "ls", "--stale", "12h", "--base-branch", "master", "--json")
+
+	var worktrees []jsonWorktree
+
+	err := json.Unmarshal([]byte(stdout), &worktrees)
+	if err != nil {
+		t.Fatalf("failed to parse JSON: %v\n%s", err, stdout)
+	}
+
+	if len(worktrees) != 1 || worktrees[0].Name != "abandoned" {
+		t.Errorf("expected only abandoned, got %+v", worktrees)
+	}
+
+	for _, value := range []string{"0d", "-1h", "soon"} {
+		_, stderr, code := c.Run("--config", "config.json", "ls", "--stale", value)
+		if code != 1 {
+			t.Errorf("--stale %s: expected exit code 1, got %d", value, code)
+		}
+
+		AssertContains(t, stderr, "invalid --stale")
+	}
+}
+
+func Test_List_Sorts_By_Created_Name_Or_ID_And_Filters_By_Base_Branch(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	fsys := fs.NewReal()
+	now := time.Now().UTC()
+
+	worktreesData := []struct {
+		name       string
+		id         int
+		baseBranch string
+		age        time.Duration
+	}{
+		{"alpha", 3, "master", time.Hour},
+		{"bravo", 1, "develop", 3 * time.Hour},
+		{"charlie", 2, "master", 2 * time.Hour},
+	}
+
+	for _, wtData := range worktreesData {
+		wtPath := filepath.Join(c.Dir, "worktrees", wtData.name)
+
+		err := os.MkdirAll(wtPath, 0o750)
+		if err != nil {
+			t.Fatalf("failed to create worktree dir: %v", err)
+		}
+
+		info := WorktreeInfo{
+			Name:       wtData.name,
+			AgentID:    wtData.name,
+			ID:         wtData.id,
+			BaseBranch: wtData.baseBranch,
+			Created:    now.Add(-wtData.age),
+		}
+
+		err = writeWorktreeInfo(fsys, wtPath, &info)
+		if err != nil {
+			t.Fatalf("failed to write worktree info: %v", err)
+		}
+	}
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	listNames := func(args ...string) []string {
+		t.Helper()
+
+		stdout := c.MustRun(append([]string{"--config", "config.json", "ls", "--json"}, args...)...)
+
+		var worktrees []jsonWorktree
+
+		err := json.Unmarshal([]byte(stdout), &worktrees)
+		if err != nil {
+			t.Fatalf("failed to parse JSON: %v\n%s", err, stdout)
+		}
+
+		names := make([]string, 0, len(worktrees))
+		for _, wt := range worktrees {
+			names = append(names, wt.Name)
+		}
+
+		return names
+	}
+
+	cases := []struct {
+		args []string
+		want []string
+	}{
+		{nil, []string{"bravo", "charlie", "alpha"}},
+		{[]string{"--reverse"}, []string{"alpha", "charlie", "bravo"}},
+		{[]string{"--sort", "name"}, []string{"alpha", "bravo", "charlie"}},
+		{[]string{"--sort", "id", "--reverse"}, []string{"alpha", "charlie", "bravo"}},
+		{[]string{"--base-branch", "master", "--sort", "name"}, []string{"alpha", "charlie"}},
+		{[]string{"--base-branch", "missing"}, []string{}},
+	}
+
+	for _, tc := range cases {
+		if got := listNames(tc.args...); !slices.Equal(got, tc.want) {
+			t.Errorf("ls %v: expected %v, got %v", tc.args, tc.want, got)
+		}
+	}
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--json", "--base-branch", "missing")
+	if strings.TrimSpace(stdout) != "[]" {
+		t.Errorf("expected [] for no matches, got %q", stdout)
+	}
+
+	_, stderr, code := c.Run("--config", "config.json", "ls", "--sort", "age")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for an invalid sort key, got %d", code)
+	}
+
+	AssertContains(t, stderr, "invalid --sort")
+
+	_, stderr, code = c.Run("--config", "config.json", "ls", "--jsonl", "--reverse")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for --reverse with --jsonl, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --sort or --reverse with --jsonl")
+}
+
+// ttyReader is stdin input that reports itself as a terminal.
+type ttyReader struct {
+	*strings.Reader
+}
+
+func (ttyReader) Stat() (os.FileInfo, error) {
+	return ttyFileInfo{}, nil
+}
+
+// ttyFileInfo is the os.FileInfo of a character device.
+type ttyFileInfo struct{}
+
+func (ttyFileInfo) Name() string       { return "tty" }
+func (ttyFileInfo) Size() int64        { return 0 }
+func (ttyFileInfo) Mode() os.FileMode  { return os.ModeDevice | os.ModeCharDevice }
+func (ttyFileInfo) ModTime() time.Time { return time.Time{} }
+func (ttyFileInfo) IsDir() bool        { return false }
+func (ttyFileInfo) Sys() any           { return nil }
+
+func Test_List_Pick_Prints_Path_Of_Selected_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	c.MustRun("--config", "config.json", "create", "--name", "fox-one")
+	c.MustRun("--config", "config.json", "create", "--name", "bear")
+
+	for selection, want := range map[string]string{"2\n": "bear", "fox\n": "fox-one", "bear": "bear"} {
+		stdout, stderr, code := c.RunWithInput(ttyReader{strings.NewReader(selection)}, "--config", "config.json", "ls", "--pick")
+		if code != 0 {
+			t.Fatalf("selection %q: expected exit code 0, got %d\nstderr: %s", selection, code, stderr)
+		}
+
+		if stdout != filepath.Join(c.Dir, "worktrees", want)+"\n" {
+			t.Errorf("selection %q: expected only the path of %s, got %q", selection, want, stdout)
+		}
+
+		AssertContains(t, stderr, "1) fox-one")
+		AssertContains(t, stderr, "2) bear")
+		AssertContains(t, stderr, "Select worktree [1-2")
+	}
+
+	_, stderr, code := c.RunWithInput(ttyReader{strings.NewReader("9\n")}, "--config", "config.json", "ls", "--pick")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for an out-of-range selection, got %d", code)
+	}
+
+	AssertContains(t, stderr, "no worktree matches: 9")
+}
+
+func Test_List_Pick_Requires_Terminal(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "bear")
+
+	stdout, stderr, code := c.RunWithInput([]string{"1"}, "--config", "config.json", "ls", "--pick")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 without a terminal, got %d", code)
+	}
+
+	if stdout != "" {
+		t.Errorf("expected no stdout, got %q", stdout)
+	}
+
+	AssertContains(t, stderr, "needs an interactive terminal")
+	AssertContains(t, stderr, "wt switch <name>")
+
+	_, stderr, code = c.RunWithInput(ttyReader{strings.NewReader("1\n")}, "--config", "config.json", "ls", "--pick", "--json")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for --pick with --json, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --pick with")
+}
+
+func Test_List_Format_YAML_Outputs_Same_Fields_As_JSON(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "yaml-wt"))
+
+	var rows []jsonWorktree
+
+	err := json.Unmarshal([]byte(c.MustRun("--config", "config.json", "ls", "--json")), &rows)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("parsing ls --json: %v (%d rows)", err, len(rows))
+	}
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--format", "yaml")
+
+	AssertContains(t, stdout, "- name: yaml-wt\n")
+	AssertContains(t, stdout, "  path: "+wtPath+"\n")
+	AssertContains(t, stdout, "  id: 1\n")
+	AssertContains(t, stdout, "  managed: true\n")
+	AssertContains(t, stdout, `  created: "`+rows[0].Created.Format(time.RFC3339Nano)+`"`)
+	AssertNotContains(t, stdout, "{")
+}
+
+func Test_List_Format_YAML_Empty_Is_Empty_Sequence(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout, stderr, code := c.Run("--config", "config.json", "ls", "--format", "yaml")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr)
+	}
+
+	if stdout != "[]\n" {
+		t.Errorf("expected empty YAML sequence, got %q", stdout)
+	}
+
+	if stderr != "" {
+		t.Errorf("expected no stderr, got %q", stderr)
+	}
+}
+
+func Test_List_Format_JSON_Matches_JSON_Flag(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "same-json")
+
+	viaFlag := c.MustRun("--config", "config.json", "ls", "--json")
+	viaFormat := c.MustRun("--config", "config.json", "ls", "--format", "json")
+
+	if viaFlag != viaFormat {
+		t.Errorf("--format json differs from --json:\n%s\nvs\n%s", viaFormat, viaFlag)
+	}
+}
+
+func Test_List_Format_Rejects_Invalid_And_Conflicting_Values(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"ls", "--format", "xml"}, `invalid format (valid: text, json, yaml): "xml"`},
+		{[]string{"ls", "--json", "--format", "yaml"}, "cannot use --json with --format yaml"},
+		{[]string{"ls", "--jsonl", "--format", "json"}, "cannot use --format and --jsonl together"},
+	}
+
+	for _, tt := range tests {
+		_, stderr, code := c.Run(append([]string{"--config", "config.json"}, tt.args...)...)
+		if code != 1 {
+			t.Errorf("%v: expected exit code 1, got %d", tt.args, code)
+		}
+
+		AssertContains(t, stderr, tt.wantErr)
+	}
+}
+
+func Test_List_Shows_Worktree_With_BOM_Prefixed_Metadata(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "bom-wt")
+
+	metaPath := "worktrees/bom-wt/.wt/worktree.json"
+	c.WriteFile(metaPath, "\ufeff"+c.ReadFile(metaPath)+"\n\n")
+
+	AssertContains(t, c.MustRun("--config", "config.json", "ls"), "bom-wt")
+}
+
+func Test_List_JSON_Reports_Prunable_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "healthy")
+	c.MustRun("--config", "config.json", "create", "--name", "gone")
+
+	gonePath := filepath.Join(c.Dir, "worktrees", "gone")
+
+	err := os.RemoveAll(gonePath)
+	if err != nil {
+		t.Fatalf("failed to remove worktree dir: %v", err)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "ls", "--json", "--include-unmanaged")
+
+	var worktrees []jsonWorktree
+
+	err = json.Unmarshal([]byte(stdout), &worktrees)
+	if err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+
+	prunable := map[string]bool{}
+	for _, wt := range worktrees {
+		prunable[wt.Name] = wt.Prunable
+	}
+
+	if want := map[string]bool{"healthy": false, "gone": true}; !maps.Equal(prunable, want) {
+		t.Errorf("prunable = %v, want %v", prunable, want)
+	}
+
+	AssertContains(t, stdout, `"prunable": false`)
+
+	stdout = c.MustRun("--config", "config.json", "ls", "--include-unmanaged", "--filter", "prunable=true")
+	AssertContains(t, stdout, gonePath)
+	AssertNotContains(t, stdout, "healthy")
+
+	// The deleted worktree was in the base directory, so it is listed
+	// without --include-unmanaged too
+	stdout = c.MustRun("--config", "config.json", "ls", "--filter", "prunable=true")
+	AssertContains(t, stdout, gonePath)
+
+	stdout = c.MustRun("--config", "config.json", "ls", "--jsonl", "--filter", "prunable=true")
+	AssertContains(t, stdout, gonePath)
+}
+
+func Test_List_Hides_Prunable_Worktree_Outside_Base_Without_Include_Unmanaged(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	outside := filepath.Join(c.Dir, "elsewhere", "plain")
+	out, err := testGitCmd("-C", c.Dir, "worktree", "add", "-b", "plain", outside).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git worktree add failed: %v\n%s", err, out)
+	}
+
+	err = os.RemoveAll(outside)
+	if err != nil {
+		t.Fatalf("failed to remove worktree dir: %v", err)
+	}
+
+	AssertNotContains(t, c.MustRun("--config", "config.json", "ls"), outside)
+	AssertContains(t, c.MustRun("--config", "config.json", "ls", "--include-unmanaged"), outside)
+}
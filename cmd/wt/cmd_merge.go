@@ -3,10 +3,16 @@ package main
 import (
 	"context"
 	"crypto/rand"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,29 +22,67 @@ import (
 
 // Errors for merge command.
 var (
-	errReadingMergeMetadata  = errors.New("reading worktree metadata")
-	errValidatingBranches    = errors.New("validating branches")
-	errCheckingMergeWorktree = errors.New("checking worktree status")
-	errCheckingTargetBranch  = errors.New("checking target branch")
-	errRebasingOnto          = errors.New("rebasing onto")
-	errMergingInto           = errors.New("merging into")
-	errMergeConflict         = errors.New("conflict during rebase")
-	errTargetBranchNotExist  = errors.New("branch does not exist")
-	errAlreadyOnTarget       = errors.New("already on target branch, nothing to merge")
-	errUncommittedChanges    = errors.New("uncommitted changes")
-	errTargetHasChanges      = errors.New("has uncommitted changes")
-	errMergeCancelled        = errors.New("merge cancelled")
-	errAcquiringMergeLock    = errors.New("acquiring merge lock")
-	errMergeLockTimedOut     = errors.New("timed out waiting for merge lock - another merge may be stuck")
+	errReadingMergeMetadata   = errors.New("reading worktree metadata")
+	errValidatingBranches     = errors.New("validating branches")
+	errCheckingMergeWorktree  = errors.New("checking worktree status")
+	errCheckingTargetBranch   = errors.New("checking target branch")
+	errRebasingOnto           = errors.New("rebasing onto")
+	errMergingInto            = errors.New("merging into")
+	errMergeConflict          = errors.New("conflict during rebase")
+	errTargetBranchNotExist   = errors.New("branch does not exist")
+	errAlreadyOnTarget        = errors.New("already on target branch, nothing to merge")
+	errUncommittedChanges     = errors.New("uncommitted changes")
+	errTargetHasChanges       = errors.New("has uncommitted changes")
+	errMergeCancelled         = errors.New("merge cancelled")
+	errAcquiringMergeLock     = errors.New("acquiring merge lock")
+	errMergeLockTimedOut      = errors.New("timed out waiting for merge lock - another merge may be stuck")
+	errIntoAndIntoDefault     = errors.New("cannot use --into and --into-default together")
+	errTargetInSameWorktree   = errors.New("target branch is checked out in this worktree instead of its own branch")
+	errBranchRequiresTarget   = errors.New("--branch requires --into or --into-default")
+	errDeleteBranchOnly       = errors.New("--delete-branch can only be used with --branch")
+	errBranchCheckedOut       = errors.New("branch is checked out in a worktree")
+	errPreparingMergeCheckout = errors.New("preparing temporary checkout")
+	errCreatingTargetWorktree = errors.New("merge succeeded, but creating a worktree on the target branch failed")
+	errMergeInProgress        = errors.New("a merge is in progress; finish the rebase with 'git rebase --continue' or run 'wt merge --abort'")
+	errNoMergeInProgress      = errors.New("no merge in progress")
+	errWritingMergeState      = errors.New("writing merge state")
+	errIntoWorktreeNoBranch   = errors.New("has no branch checked out")
+	errTargetNotBranch        = errors.New("is not a branch (use a local or remote-tracking branch)")
+	errTargetDiverged         = errors.New("has diverged from")
+	errMergeDetached          = errors.New("HEAD is detached, nothing to merge (check out a branch first: git switch -c <name>)")
+	errMergeCommitConflict    = errors.New("conflict during merge")
+	errMessageRequiresMode    = errors.New("--message requires --merge-commit or --squash")
+	errMergeStrategyConflict  = errors.New("cannot combine merge strategies (use one of --rebase, --merge-commit, --squash)")
+	errAuthorRequiresMode     = errors.New("--author requires --merge-commit or --squash")
+	errInvalidAuthor          = errors.New("invalid --author (expected \"Name <email>\")")
+	errRemoteRequiresPush     = errors.New("--remote requires --push")
+	errPushRemoteNotFound     = errors.New("push remote does not exist")
+	errPushFailed             = errors.New("merge succeeded, but pushing the target branch failed")
 )
 
 // MergeCmd returns the merge command.
 func MergeCmd(cfg Config, fsys fs.FS, git *Git, env map[string]string) *Command {
 	flags := flag.NewFlagSet("merge", flag.ContinueOnError)
 	flags.BoolP("help", "h", false, "Show help")
-	flags.String("into", "", "Merge into this branch instead of base_branch")
+	flags.String("into", "", "Merge into this branch (or worktree's branch) instead of base_branch")
+	flags.Bool("into-default", false, "Merge into the repository's default branch instead of base_branch")
 	flags.Bool("keep", false, "Keep worktree after merge (skip cleanup)")
+	flags.Bool("ignore-untracked", false, "Merge even if the worktree has untracked files (removed with it unless --keep)")
 	flags.Bool("dry-run", false, "Show what would happen without executing")
+	flags.String("branch", "", "Merge this branch (without a worktree) instead of the current worktree's")
+	flags.Bool("delete-branch", false, "With --branch: delete the branch after merging")
+	flags.Bool("create-worktree", false, "After merging, create a worktree on the target branch if it has none")
+	flags.Bool("push", false, "After merging, push the target branch (git push)")
+	flags.String("remote", "", "With --push: push to this remote (default: the target's upstream remote, else origin)")
+	flags.Bool("abort", false, "Abort an interrupted merge: abort its rebase and clear the merge state")
+	flags.Bool("rebase", false, "Rebase onto the target and fast-forward it (the default)")
+	flags.Bool("merge-commit", false, "Create a merge commit (git merge --no-ff) instead of rebasing and fast-forwarding")
+	flags.Bool("no-rebase", false, "Same as --merge-commit")
+	flags.Bool("squash", false, "Squash the branch's commits into one new commit on the target")
+	flags.StringP("message", "m", "", "With --merge-commit or --squash: commit message (default: generated)")
+	flags.String("author", "", "With --merge-commit or --squash: author of the new commit, as \"Name <email>\"")
+	flags.Bool("no-trailer", false, "Omit the Squashed-from/Merged-from trailers merge_message_trailer adds")
+	addPorcelainFlag(flags)
 
 	return &Command{
 		Flags: flags,
@@ -46,17 +90,256 @@ func MergeCmd(cfg Config, fsys fs.FS, git *Git, env map[string]string) *Command
 		Short: "Merge worktree branch into base branch",
 		Long: `Merge the current worktree's branch into its base branch (or --into target).
 
+The default target is the worktree's merge_into (set by create --merge-into),
+else the default_merge_target config key (merge_into also works), else the
+worktree's base_branch. ls and info show it as default_target in JSON.
+--dry-run and errors about the target say which of these (or --into) it
+came from.
+
+--into also accepts a wt-managed worktree's id, name or agent_id, and then
+merges into the branch checked out there (e.g. another agent's worktree).
+Worktrees are tried first; otherwise the value is used as a branch name.
+A remote-tracking branch (--into origin/main) merges into the local branch
+of the same name, which is first created from it (tracking it) or
+fast-forwarded to it, under the merge lock; a local branch with commits
+the remote-tracking one lacks is refused. A local branch created this way
+is deleted again if the merge fails. Tags and commits are not branches and
+are refused.
+
+Use --into-default to target the repository's default branch (origin/HEAD,
+init.defaultBranch, main or master), e.g. when the worktree was branched
+from a feature branch but should integrate into main.
+
 Performs a rebase onto the target branch followed by a fast-forward merge.
 After successful merge, the worktree and branch are removed unless --keep is used.
 
+The worktree must have no uncommitted changes. With --ignore-untracked,
+untracked files (e.g. scratch or build output) do not count: they are not
+merged and, unless --keep is used, are deleted along with the worktree.
+Staged or modified tracked files still block the merge.
+
+With --merge-commit (or --no-rebase), the branch is not rebased: it is
+merged into the target with a merge commit (git merge --no-ff), whose
+message is "Merge worktree <name> into <target>" ("Merge branch <branch>
+into <target>" with --branch) unless --message is given. If the target is
+checked out in a worktree, the merge runs there; otherwise the commit is
+created without a checkout (git merge-tree). On conflicts the merge is
+aborted, leaving the target branch and its worktree unchanged.
+
+With --squash, all commits of the branch become one new commit on the
+target (its only parent is the target tip). The message is the worktree
+name (the branch name with --branch) followed by the squashed commit
+subjects, unless --message is given. The commit is built without touching
+any checkout and only then fast-forwards the target (and its worktree, if
+checked out), so conflicts leave the target unchanged too.
+
+With the merge_message_trailer config set to true, the message of a
+squash commit (generated or --message) ends in a "Squashed-from: <sha>"
+trailer for each squashed commit, and that of a merge commit in a
+"Merged-from: <sha>" trailer for each merged one, so the original commits
+can still be found after the branch is deleted. --no-trailer leaves them
+out for one merge; with --rebase there is no message and it does nothing.
+
+--rebase (the default), --merge-commit and --squash cannot be combined.
+With either of the latter, --author "Name <email>" sets the author of the
+new commit (e.g. the agent or the reviewer); git's configured identity is
+still the committer, and the author too without --author.
+
+The target branch's reflog entries read "wt merge <name>" (or "wt merge
+--branch <branch>"), so 'git reflog <target>' shows which merge moved it
+and where it was before.
+
+With --porcelain, stdout gets only "key<TAB>value" lines once the merge
+succeeded: "status<TAB>merged", then name, path, branch, target,
+strategy (rebase, merge-commit or squash) and removed (true/false); with
+--branch just branch, target, strategy and branch_deleted. Progress, hook
+output and warnings go to stderr. It cannot be combined with --dry-run or
+--abort.
+
 If multiple merges to the same target happen concurrently, the command
-automatically retries with exponential backoff.`,
+automatically retries with exponential backoff.
+
+With --branch, an existing branch that has no worktree (e.g. its worktree
+was already removed) is merged instead, typically from the main repo. The
+target must be given with --into or --into-default. The rebase runs in a
+temporary checkout inside the git directory, which is removed afterwards,
+also on conflicts (resolve them in a checkout of the branch); one left by a
+merge that was killed is removed on the next run. The branch is kept
+unless --delete-branch is given.
+
+With --push, the target branch is pushed to the branch of the same name
+on its upstream's remote (origin if it has none, or --remote) once the
+merge succeeded. The remote must exist; this is checked before merging. A
+failed push (e.g. rejected as non-fast-forward) does not undo the merge:
+it is reported, cleanup still runs and the exit code is 1, so push again
+with git push.
+
+With --create-worktree, a wt-managed worktree is created on the target
+branch after the merge (like wt create, named after the branch) so work can
+continue on the result. This is skipped if the target branch is already
+checked out somewhere.
+
+While a merge runs, .wt/merge-state.json records it in the worktree. If a
+merge was interrupted (the state file is left behind) or a rebase is in
+progress in the worktree, a new merge refuses to start. Finish the rebase
+with 'git rebase --continue', or use --abort to abort it and clear the
+state.`,
 		Exec: func(ctx context.Context, _ io.Reader, stdout, stderr io.Writer, _ []string) error {
 			return execMerge(ctx, stdout, stderr, cfg, fsys, git, env, flags)
 		},
 	}
 }
 
+// Merge strategies of wt merge.
+const (
+	mergeStrategyRebase      = "rebase"       // Rebase onto the target, then fast-forward (default)
+	mergeStrategyMergeCommit = "merge-commit" // git merge --no-ff
+	mergeStrategySquash      = "squash"       // One new commit with the branch's changes
+)
+
+// mergeStrategy is how wt merge integrates the branch into the target.
+type mergeStrategy struct {
+	mode    string
+	message string // Commit message for merge-commit and squash (--message or a default)
+
+	// Author of the merge or squash commit (--author); empty uses git's identity
+	authorName  string
+	authorEmail string
+
+	// Append a Squashed-from (squash) or Merged-from (merge commit) trailer
+	// per commit of the branch (merge_message_trailer config, unless
+	// --no-trailer)
+	trailer bool
+}
+
+// authorPattern matches an --author value: "Name <email>".
+var authorPattern = regexp.MustCompile(`^([^<>]*[^<>\s])\s*<([^<>\s]+@[^<>\s]+)>$`)
+
+// parseMergeStrategy reads --rebase, --merge-commit/--no-rebase, --squash,
+// --message, --author and --no-trailer.
+func parseMergeStrategy(cfg Config, flags *flag.FlagSet) (mergeStrategy, error) {
+	strategy := mergeStrategy{mode: mergeStrategyRebase}
+
+	rebase, _ := flags.GetBool("rebase")
+	mergeCommit, _ := flags.GetBool("merge-commit")
+	noRebase, _ := flags.GetBool("no-rebase")
+	squash, _ := flags.GetBool("squash")
+
+	var chosen []string
+
+	if rebase {
+		chosen = append(chosen, "--rebase")
+	}
+
+	if mergeCommit || noRebase {
+		chosen = append(chosen, "--merge-commit")
+		strategy.mode = mergeStrategyMergeCommit
+	}
+
+	if squash {
+		chosen = append(chosen, "--squash")
+		strategy.mode = mergeStrategySquash
+	}
+
+	if len(chosen) > 1 {
+		return mergeStrategy{}, fmt.Errorf("%w: %s", errMergeStrategyConflict, strings.Join(chosen, " and "))
+	}
+
+	strategy.message, _ = flags.GetString("message")
+	if flags.Changed("message") && strategy.mode == mergeStrategyRebase {
+		return mergeStrategy{}, errMessageRequiresMode
+	}
+
+	if flags.Changed("author") {
+		if strategy.mode == mergeStrategyRebase {
+			return mergeStrategy{}, errAuthorRequiresMode
+		}
+
+		author, _ := flags.GetString("author")
+
+		match := authorPattern.FindStringSubmatch(strings.TrimSpace(author))
+		if match == nil {
+			return mergeStrategy{}, fmt.Errorf("%w: %q", errInvalidAuthor, author)
+		}
+
+		strategy.authorName, strategy.authorEmail = match[1], match[2]
+	}
+
+	noTrailer, _ := flags.GetBool("no-trailer")
+	strategy.trailer = cfg.MergeMessageTrailer && !noTrailer
+
+	return strategy, nil
+}
+
+// setMessage sets the commit message unless --message gave one: "Merge
+// <kind> <name> into <target>" for a merge commit, and name followed by the
+// squashed commit subjects for a squash. With the trailer, the message
+// (either one) then gets a "Squashed-from: <sha>" or "Merged-from: <sha>"
+// line per commit, since the branch holding them is usually deleted
+// afterwards.
+func (s *mergeStrategy) setMessage(kind, name, target string, commits []BranchCommit) {
+	if s.message == "" {
+		s.message = s.defaultMessage(kind, name, target, commits)
+	}
+
+	if !s.trailer || len(commits) == 0 {
+		return
+	}
+
+	key := "Squashed-from"
+	if s.mode == mergeStrategyMergeCommit {
+		key = "Merged-from"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(s.message + "\n")
+
+	for _, commit := range commits {
+		sb.WriteString("\n" + key + ": " + commit.SHA)
+	}
+
+	s.message = sb.String()
+}
+
+// defaultMessage returns the generated commit message of the strategy.
+func (s *mergeStrategy) defaultMessage(kind, name, target string, commits []BranchCommit) string {
+	switch s.mode {
+	case mergeStrategyMergeCommit:
+		return "Merge " + kind + " " + name + " into " + target
+	case mergeStrategySquash:
+		var sb strings.Builder
+
+		sb.WriteString(name)
+
+		if len(commits) > 0 {
+			sb.WriteString("\n")
+		}
+
+		for _, commit := range commits {
+			sb.WriteString("\n* " + commit.Subject)
+		}
+
+		return sb.String()
+	default:
+		return ""
+	}
+}
+
+// messageCommits returns the commits a merge of branch onto onto lists in
+// its message: the squashed ones for --squash, and the merged ones for
+// --merge-commit with the trailer. It returns nil otherwise.
+func messageCommits(ctx context.Context, git *Git, dir string, strategy mergeStrategy, onto, branch string) ([]BranchCommit, error) {
+	switch {
+	case strategy.mode == mergeStrategySquash:
+	case strategy.mode == mergeStrategyMergeCommit && strategy.trailer:
+	default:
+		return nil, nil
+	}
+
+	return git.BranchCommits(ctx, dir, onto, branch)
+}
+
 const (
 	maxMergeRetries  = 3
 	mergeBaseDelay   = 100 * time.Millisecond
@@ -74,8 +357,59 @@ func execMerge(
 	flags *flag.FlagSet,
 ) error {
 	into, _ := flags.GetString("into")
+	intoDefault, _ := flags.GetBool("into-default")
 	keep, _ := flags.GetBool("keep")
 	dryRun, _ := flags.GetBool("dry-run")
+	ignoreUntracked, _ := flags.GetBool("ignore-untracked")
+	push, _ := flags.GetBool("push")
+	pushRemote, _ := flags.GetString("remote")
+
+	if into != "" && intoDefault {
+		return errIntoAndIntoDefault
+	}
+
+	if flags.Changed("remote") && !push {
+		return errRemoteRequiresPush
+	}
+
+	strategy, err := parseMergeStrategy(cfg, flags)
+	if err != nil {
+		return err
+	}
+
+	// --porcelain keeps stdout to its key/value lines, the rest goes to stderr
+	var porcelain io.Writer
+
+	if usePorcelain, _ := flags.GetBool("porcelain"); usePorcelain {
+		for _, conflict := range []string{"dry-run", "abort"} {
+			if flags.Changed(conflict) {
+				return fmt.Errorf("%w --%s", errPorcelainConflict, conflict)
+			}
+		}
+
+		porcelain, stdout = stdout, stderr
+	}
+
+	if !dryRun {
+		stdout = informational(cfg, stdout)
+	}
+
+	branch, _ := flags.GetString("branch")
+	deleteBranch, _ := flags.GetBool("delete-branch")
+	createWorktree, _ := flags.GetBool("create-worktree")
+
+	if branch != "" {
+		return execMergeBranch(ctx, stdout, stderr, porcelain, cfg, fsys, git, env, branch, into, intoDefault, deleteBranch, createWorktree, dryRun, strategy, push, pushRemote)
+	}
+
+	if deleteBranch {
+		return errDeleteBranchOnly
+	}
+
+	abort, _ := flags.GetBool("abort")
+	if abort {
+		return execMergeAbort(ctx, stdout, fsys, git, cfg.EffectiveCwd)
+	}
 
 	// PHASE 1: ALL CHECKS (fail fast, no side effects)
 
@@ -85,124 +419,719 @@ func execMerge(
 		return err
 	}
 
+	// Refuse to start over an interrupted merge or a rebase in progress
+	_, stateErr := fsys.Stat(mergeStatePath(cfg.EffectiveCwd))
+	if stateErr == nil || rebaseInProgress(ctx, fsys, git, cfg.EffectiveCwd) {
+		return errMergeInProgress
+	}
+
 	// Get current branch (feature)
 	featureBranch, err := git.CurrentBranch(ctx, cfg.EffectiveCwd)
 	if err != nil {
 		return fmt.Errorf("%w: getting current branch: %w", errReadingMergeMetadata, err)
 	}
 
+	if featureBranch == "" {
+		return errMergeDetached
+	}
+
 	// Determine target branch
-	targetBranch := info.BaseBranch
-	if into != "" {
+	targetBranch, targetSource := resolveMergeTarget(cfg, &info)
+
+	switch {
+	case into != "":
 		targetBranch = into
+		targetSource = "--into"
+	case intoDefault:
+		targetBranch, err = git.DefaultBranch(ctx, cfg.EffectiveCwd)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errValidatingBranches, err)
+		}
+
+		targetSource = "--into-default"
 	}
 
-	// 2. Validate branches
-	exists, err := git.BranchExists(ctx, cfg.EffectiveCwd, targetBranch)
-	if err != nil {
-		return fmt.Errorf("%w: %w", errValidatingBranches, err)
+	requestedTarget := targetBranch
+
+	// --into may name a worktree; merge into its branch then
+	if into != "" {
+		mainRepoRoot, rootErr := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+		if rootErr != nil {
+			return fmt.Errorf("%w: %w", errReadingMergeMetadata, rootErr)
+		}
+
+		targetBranch, err = resolveIntoTarget(ctx, cfg, fsys, git, mainRepoRoot, into)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errValidatingBranches, err)
+		}
 	}
 
-	if !exists {
-		return fmt.Errorf("%w: '%s' %w (check branch name or use --into)", errValidatingBranches, targetBranch, errTargetBranchNotExist)
+	// Normalize spellings like HEAD, refs/heads/x or @{u} to the local branch
+	targetBranch = git.ResolveBranchName(ctx, cfg.EffectiveCwd, targetBranch)
+
+	// 2. Validate branches; a remote-tracking target merges into its local branch
+	targetBranch, remote, err := resolveTargetBranch(ctx, git, cfg.EffectiveCwd, targetBranch)
+	if err != nil {
+		return fmt.Errorf("%w: %w (target from %s)", errValidatingBranches, err, targetSource)
 	}
 
 	if featureBranch == targetBranch {
-		return fmt.Errorf("%w: %w '%s'", errValidatingBranches, errAlreadyOnTarget, targetBranch)
+		if featureBranch != info.BranchName() {
+			return fmt.Errorf("%w: %w '%s' (switch back with: git switch %s)",
+				errValidatingBranches, errTargetInSameWorktree, targetBranch, info.BranchName())
+		}
+
+		switch {
+		case requestedTarget != targetBranch:
+			return fmt.Errorf("%w: %w '%s' (%s '%s' resolves to the current branch)",
+				errValidatingBranches, errAlreadyOnTarget, targetBranch, targetSource, requestedTarget)
+		case targetSource == "--into-default":
+			return fmt.Errorf("%w: %w '%s' (--into-default resolves to the current branch)",
+				errValidatingBranches, errAlreadyOnTarget, targetBranch)
+		default:
+			return fmt.Errorf("%w: %w '%s'", errValidatingBranches, errAlreadyOnTarget, targetBranch)
+		}
 	}
 
-	// 3. Check current worktree clean
-	dirty, err := git.IsDirty(ctx, cfg.EffectiveCwd)
+	// 3. Check current worktree clean (untracked files only count without --ignore-untracked)
+	status, err := git.Status(ctx, cfg.EffectiveCwd)
 	if err != nil {
 		return fmt.Errorf("%w: %w", errCheckingMergeWorktree, err)
 	}
 
-	if dirty {
+	if status.HasTrackedChanges() {
 		return fmt.Errorf("%w: %w (commit or stash before merging)", errCheckingMergeWorktree, errUncommittedChanges)
 	}
 
+	if status.Untracked > 0 && !ignoreUntracked {
+		return fmt.Errorf("%w: %w: untracked files (commit or stash before merging, or use --ignore-untracked)", errCheckingMergeWorktree, errUncommittedChanges)
+	}
+
 	// Get main repo root
 	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
 	if err != nil {
 		return fmt.Errorf("%w: %w", errReadingMergeMetadata, err)
 	}
 
+	// Resolved before merging, so a bad hooks_dir fails before anything changes
+	hooksDir, err := resolveHooksDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
 	// Get git common directory for lock file
 	gitCommonDir, err := git.GitCommonDir(ctx, cfg.EffectiveCwd)
 	if err != nil {
 		return fmt.Errorf("%w: %w", errReadingMergeMetadata, err)
 	}
 
-	// 4. Check target worktree clean (if checked out somewhere)
-	targetWtPath, err := git.FindWorktreeForBranch(ctx, cfg.EffectiveCwd, targetBranch)
+	// 4. Find target worktree (if checked out somewhere); whether it is
+	// clean is checked under the merge lock
+	targetWtPath, err := findTargetWorktree(ctx, git, cfg.EffectiveCwd, targetBranch)
 	if err != nil {
-		return fmt.Errorf("%w '%s': %w", errCheckingTargetBranch, targetBranch, err)
+		return err
+	}
+
+	commits, err := messageCommits(ctx, git, cfg.EffectiveCwd, strategy, remote.rebaseOnto(targetBranch), featureBranch)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errReadingMergeMetadata, err)
+	}
+
+	strategy.setMessage("worktree", info.Name, targetBranch, commits)
+
+	if push {
+		pushRemote, err = resolvePushRemote(ctx, git, mainRepoRoot, targetBranch, pushRemote, remote)
+		if err != nil {
+			return err
+		}
+	} else {
+		pushRemote = ""
+	}
+
+	// Get commit count for dry-run output
+	commitCount, err := git.CommitsBetween(ctx, cfg.EffectiveCwd, remote.rebaseOnto(targetBranch), featureBranch)
+	if err != nil {
+		// Non-fatal, use 0 for dry-run output
+		commitCount = 0
+	}
+
+	// Handle dry-run
+	if dryRun {
+		err = checkTargetClean(ctx, git, targetBranch, targetWtPath)
+		if err != nil {
+			return err
+		}
+
+		return printDryRun(stdout, featureBranch, targetBranch, targetSource, targetWtPath, mainRepoRoot, cfg.EffectiveCwd, info.Name, pushRemote, commitCount, remote, strategy, keep, createWorktree)
+	}
+
+	// PHASE 2: EXECUTE (with retry loop)
+
+	// Label the target's reflog entries so a bad merge is easy to find
+	reflogAction := "wt merge " + info.Name
+
+	// 5-6. Under the lock: create or fast-forward the local branch of a
+	// remote-tracking target, then rebase + merge
+	locker := fs.NewLocker(fsys)
+	lockPath := mergeLockPath(gitCommonDir)
+
+	// Worktrees created before wt excluded it would show it as untracked
+	if _, warning := ensureExcluded(fsys, gitCommonDir, mergeStateExcludePattern); warning != "" {
+		fprintln(stderr, warning)
 	}
 
+	err = writeMergeState(fsys, cfg.EffectiveCwd, &mergeState{
+		Branch:  featureBranch,
+		Target:  targetBranch,
+		Started: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = mergeWithLock(ctx, stdout, stderr, git, reflogAction, locker, lockPath, cfg.EffectiveCwd, targetWtPath, featureBranch, targetBranch, strategy, remote)
+
+	// mergeWithLock aborts failed rebases and merges itself, so the state only
+	// survives if the process dies mid-merge
+	removeErr := fsys.Remove(mergeStatePath(cfg.EffectiveCwd))
+	if removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+		fprintln(stderr, "warning: removing merge state:", removeErr)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fprintf(stdout, "Merged %s into %s\n", featureBranch, targetBranch)
+
+	// 7. Push the target (--push); a failure is returned after cleanup, the
+	// merge stays
+	pushErr := pushMergedTarget(ctx, stdout, git, mainRepoRoot, targetBranch, pushRemote)
+
+	// 8. Cleanup (unless --keep)
+	removed := false
+
+	if keep {
+		fprintln(stdout, "Worktree kept:", cfg.EffectiveCwd)
+	} else {
+		hookRunner := NewHookRunner(fsys, mainRepoRoot, hooksDir, env, stdout, stderr)
+
+		cleanupErr := CleanupWorktree(ctx, stdout, git, hookRunner, &info, cfg.EffectiveCwd, mainRepoRoot, true, true, true)
+		if cleanupErr != nil {
+			// Merge succeeded but cleanup failed - warn but don't fail
+			fprintln(stderr, "warning: cleanup failed:", cleanupErr)
+			fprintln(stderr, "run 'wt remove", info.Name, "--with-branch' to clean up manually")
+		}
+
+		removed = cleanupErr == nil || errors.Is(cleanupErr, errBranchKept)
+	}
+
+	if porcelain != nil {
+		printPorcelain(porcelain,
+			"status", "merged",
+			"name", info.Name,
+			"path", cfg.EffectiveCwd,
+			"branch", featureBranch,
+			"target", targetBranch,
+			"strategy", strategy.mode,
+			"removed", strconv.FormatBool(removed),
+		)
+	}
+
+	// 9. If --create-worktree: continue on the merged target branch
+	if createWorktree {
+		return errors.Join(pushErr, createTargetWorktree(ctx, stdout, stderr, cfg, fsys, git, env, mainRepoRoot, targetBranch, targetWtPath))
+	}
+
+	if pushErr != nil {
+		return pushErr
+	}
+
+	return nil
+}
+
+// createTargetWorktree creates a wt-managed worktree on targetBranch after a
+// merge, unless the branch is already checked out at targetWtPath.
+func createTargetWorktree(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	env map[string]string,
+	mainRepoRoot, targetBranch, targetWtPath string,
+) error {
 	if targetWtPath != "" {
-		// Only check for uncommitted tracked changes, not untracked files
-		// Untracked files (like newly created worktree directories) don't affect merges
-		targetDirty, dirtyErr := git.HasUncommittedTrackedChanges(ctx, targetWtPath)
-		if dirtyErr != nil {
-			return fmt.Errorf("%w '%s': %w", errCheckingTargetBranch, targetBranch, dirtyErr)
+		fprintf(stdout, "Not creating a worktree: '%s' is already checked out at %s\n", targetBranch, targetWtPath)
+
+		return nil
+	}
+
+	// The merged worktree may be gone by now, so create from the main repo
+	cfg.EffectiveCwd = mainRepoRoot
+
+	opts := createOptions{
+		customName:     strings.ReplaceAll(targetBranch, "/", "-"),
+		fromBranch:     targetBranch,
+		existingBranch: targetBranch,
+	}
+
+	err := execCreate(ctx, stdout, stderr, cfg, fsys, git, env, opts)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCreatingTargetWorktree, err)
+	}
+
+	return nil
+}
+
+// mergeState is persisted in .wt/merge-state.json while a merge runs.
+type mergeState struct {
+	Branch  string    `json:"branch"`
+	Target  string    `json:"target"`
+	Started time.Time `json:"started"`
+}
+
+// mergeStatePath returns the path of the merge state file in wtPath.
+func mergeStatePath(wtPath string) string {
+	return filepath.Join(wtPath, ".wt", "merge-state.json")
+}
+
+func writeMergeState(fsys fs.FS, wtPath string, state *mergeState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %w", errWritingMergeState, err)
+	}
+
+	err = fsys.WriteFile(mergeStatePath(wtPath), data, 0o644)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errWritingMergeState, err)
+	}
+
+	return nil
+}
+
+// execMergeAbort aborts a rebase left in progress in wtPath and removes the
+// merge state file. It fails if there is neither.
+func execMergeAbort(ctx context.Context, stdout io.Writer, fsys fs.FS, git *Git, wtPath string) error {
+	_, stateErr := fsys.Stat(mergeStatePath(wtPath))
+	hasState := stateErr == nil
+	rebasing := rebaseInProgress(ctx, fsys, git, wtPath)
+
+	if !hasState && !rebasing {
+		return errNoMergeInProgress
+	}
+
+	if rebasing {
+		err := git.RebaseAbort(ctx, wtPath)
+		if err != nil {
+			return err
+		}
+
+		fprintln(stdout, "Aborted rebase in", wtPath)
+	}
+
+	if hasState {
+		err := fsys.Remove(mergeStatePath(wtPath))
+		if err != nil {
+			return fmt.Errorf("removing merge state: %w", err)
+		}
+
+		fprintln(stdout, "Removed merge state:", mergeStatePath(wtPath))
+	}
+
+	return nil
+}
+
+// findTargetWorktree returns the worktree path where targetBranch is checked
+// out ("" if none).
+func findTargetWorktree(ctx context.Context, git *Git, dir, targetBranch string) (string, error) {
+	targetWtPath, err := git.FindWorktreeForBranch(ctx, dir, targetBranch)
+	if err != nil {
+		return "", fmt.Errorf("%w '%s': %w", errCheckingTargetBranch, targetBranch, err)
+	}
+
+	return targetWtPath, nil
+}
+
+// checkTargetClean fails if the target worktree at targetWtPath has
+// uncommitted tracked changes. Real merges run it under the merge lock:
+// outside it, another merge may be fast-forwarding the same worktree, which
+// shows up as transient changes or a held index.lock.
+func checkTargetClean(ctx context.Context, git *Git, targetBranch, targetWtPath string) error {
+	if targetWtPath == "" {
+		return nil
+	}
+
+	// Only check for uncommitted tracked changes, not untracked files
+	// Untracked files (like newly created worktree directories) don't affect merges
+	targetDirty, err := git.HasUncommittedTrackedChanges(ctx, targetWtPath)
+	if err != nil {
+		return fmt.Errorf("%w '%s': %w", errCheckingTargetBranch, targetBranch, err)
+	}
+
+	if targetDirty {
+		return fmt.Errorf("%w '%s': '%s' %w (commit or stash there first)", errCheckingTargetBranch, targetBranch, targetWtPath, errTargetHasChanges)
+	}
+
+	return nil
+}
+
+// resolveIntoTarget returns the branch --into refers to. A wt-managed
+// worktree matching into by id, name or agent_id resolves to the branch
+// checked out in it; anything else is returned unchanged as a branch name.
+func resolveIntoTarget(ctx context.Context, cfg Config, fsys fs.FS, git *Git, mainRepoRoot, into string) (string, error) {
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return "", err
+	}
+
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return "", fmt.Errorf("scanning worktrees: %w", err)
+	}
+
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return "", err
+	}
+
+	gitIndex := newGitWorktreeIndex(entries)
+
+	wt, found, err := findWorktreeByIdentifier(worktrees, gitIndex, into, identifierKeyID, identifierKeyName, identifierKeyAgentID)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return into, nil
+	}
+
+	entry, _ := gitIndex.lookup(wt.Path)
+	if entry.Branch == "" {
+		return "", fmt.Errorf("--into: worktree '%s' %w", wt.Name, errIntoWorktreeNoBranch)
+	}
+
+	return entry.Branch, nil
+}
+
+// execMergeBranch merges a branch that has no worktree into the target,
+// using a temporary checkout for the rebase. The porcelain record goes to
+// porcelain if it is not nil.
+func execMergeBranch(
+	ctx context.Context,
+	stdout, stderr, porcelain io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	env map[string]string,
+	branch, into string,
+	intoDefault, deleteBranch, createWorktree, dryRun bool,
+	strategy mergeStrategy,
+	push bool,
+	pushRemote string,
+) error {
+	if into == "" && !intoDefault {
+		return errBranchRequiresTarget
+	}
+
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errReadingMergeMetadata, err)
+	}
+
+	gitCommonDir, err := git.GitCommonDir(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errReadingMergeMetadata, err)
+	}
+
+	var targetBranch string
+
+	if intoDefault {
+		targetBranch, err = git.DefaultBranch(ctx, mainRepoRoot)
+	} else {
+		targetBranch, err = resolveIntoTarget(ctx, cfg, fsys, git, mainRepoRoot, into)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", errValidatingBranches, err)
+	}
+
+	branch = git.ResolveBranchName(ctx, mainRepoRoot, branch)
+	targetBranch = git.ResolveBranchName(ctx, mainRepoRoot, targetBranch)
+
+	// Validate both branches
+	exists, err := git.BranchExists(ctx, mainRepoRoot, branch)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errValidatingBranches, err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%w: '%s' %w", errValidatingBranches, branch, errTargetBranchNotExist)
+	}
+
+	targetBranch, remote, err := resolveTargetBranch(ctx, git, mainRepoRoot, targetBranch)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errValidatingBranches, err)
+	}
+
+	if branch == targetBranch {
+		return fmt.Errorf("%w: %w '%s'", errValidatingBranches, errAlreadyOnTarget, targetBranch)
+	}
+
+	// Temporary checkout lives in the git dir, so it never shows up in ls
+	tmpPath := filepath.Join(gitCommonDir, "wt-merge", strings.ReplaceAll(branch, "/", "-"))
+
+	// A branch with a worktree must be merged from that worktree. One left
+	// at tmpPath by an earlier run is removed below, under the merge lock
+	branchWtPath, err := git.FindWorktreeForBranch(ctx, mainRepoRoot, branch)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errValidatingBranches, err)
+	}
+
+	if branchWtPath != "" && !isSamePath(branchWtPath, tmpPath) {
+		return fmt.Errorf("%w: '%s' %w at %s (run wt merge from there)", errValidatingBranches, branch, errBranchCheckedOut, branchWtPath)
+	}
+
+	targetWtPath, err := findTargetWorktree(ctx, git, mainRepoRoot, targetBranch)
+	if err != nil {
+		return err
+	}
+
+	commits, err := messageCommits(ctx, git, mainRepoRoot, strategy, remote.rebaseOnto(targetBranch), branch)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errReadingMergeMetadata, err)
+	}
+
+	strategy.setMessage("branch", branch, targetBranch, commits)
+
+	if push {
+		pushRemote, err = resolvePushRemote(ctx, git, mainRepoRoot, targetBranch, pushRemote, remote)
+		if err != nil {
+			return err
 		}
+	} else {
+		pushRemote = ""
+	}
 
-		if targetDirty {
-			return fmt.Errorf("%w '%s': '%s' %w (commit or stash there first)", errCheckingTargetBranch, targetBranch, targetWtPath, errTargetHasChanges)
+	if dryRun {
+		err = checkTargetClean(ctx, git, targetBranch, targetWtPath)
+		if err != nil {
+			return err
 		}
+
+		commitCount, countErr := git.CommitsBetween(ctx, mainRepoRoot, remote.rebaseOnto(targetBranch), branch)
+		if countErr != nil {
+			commitCount = 0
+		}
+
+		fprintln(stdout, "Dry run: wt merge --branch", branch, "→", targetBranch)
+		fprintln(stdout)
+		fprintln(stdout, "Would execute:")
+
+		step := printDryRunRemoteTarget(stdout, 1, targetBranch, remote)
+
+		if strategy.mode != mergeStrategyRebase {
+			printDryRunCommitStep(stdout, step, strategy, branch, targetBranch, targetWtPath, commitCount)
+
+			step++
+		} else {
+			fprintf(stdout, "  %d. Rebase '%s' onto '%s' in a temporary checkout (%d commits to replay)\n", step, branch, targetBranch, commitCount)
+			fprintf(stdout, "  %d. Fast-forward '%s' to '%s'\n", step+1, targetBranch, branch)
+
+			step += 2
+		}
+
+		if pushRemote != "" {
+			fprintf(stdout, "  %d. Push '%s' to %s\n", step, targetBranch, pushRemote)
+			step++
+		}
+
+		if deleteBranch {
+			fprintf(stdout, "  %d. Delete branch '%s'\n", step, branch)
+			step++
+		}
+
+		if createWorktree {
+			printDryRunCreateWorktree(stdout, step, targetBranch, targetWtPath)
+		}
+
+		return nil
+	}
+
+	reflogAction := "wt merge --branch " + branch
+
+	// The lock covers the temporary checkout too, so a stale one can only be
+	// left by a run that died
+	lock, err := acquireMergeLock(ctx, stderr, fs.NewLocker(fsys), mergeLockPath(gitCommonDir))
+	if err != nil {
+		return err
+	}
+
+	mergeErr := mergeSyncedTarget(ctx, stdout, stderr, git, reflogAction, mainRepoRoot, targetBranch, targetWtPath, remote, func() error {
+		return mergeInTempCheckout(ctx, stderr, fsys, git, reflogAction, mainRepoRoot, tmpPath, targetWtPath, branch, targetBranch, strategy)
+	})
+
+	closeErr := lock.Close()
+	if closeErr != nil {
+		fprintln(stderr, "warning: failed to release merge lock:", closeErr)
+	}
+
+	if mergeErr != nil {
+		return mergeErr
+	}
+
+	fprintln(stdout, "Merged", branch, "into", targetBranch)
+
+	pushErr := pushMergedTarget(ctx, stdout, git, mainRepoRoot, targetBranch, pushRemote)
+
+	if deleteBranch {
+		// Force is safe: the branch is now contained in targetBranch, while
+		// "git branch -d" would only check it against the main repo's HEAD
+		err = git.BranchDelete(ctx, mainRepoRoot, branch, true)
+		if err != nil {
+			return err
+		}
+
+		fprintln(stdout, "Deleted branch:", branch)
+	}
+
+	if porcelain != nil {
+		printPorcelain(porcelain,
+			"status", "merged",
+			"branch", branch,
+			"target", targetBranch,
+			"strategy", strategy.mode,
+			"branch_deleted", strconv.FormatBool(deleteBranch),
+		)
+	}
+
+	if createWorktree {
+		return errors.Join(pushErr, createTargetWorktree(ctx, stdout, stderr, cfg, fsys, git, env, mainRepoRoot, targetBranch, targetWtPath))
 	}
 
-	// Get commit count for dry-run output
-	commitCount, err := git.CommitsBetween(ctx, cfg.EffectiveCwd, targetBranch, featureBranch)
+	return pushErr
+}
+
+// mergeInTempCheckout merges branch into targetBranch from a temporary
+// checkout at tmpPath, removing one left there by an earlier run first. The
+// checkout is always removed afterwards, so conflict messages point at the
+// branch rather than at it. The caller holds the merge lock.
+func mergeInTempCheckout(
+	ctx context.Context,
+	stderr io.Writer,
+	fsys fs.FS,
+	git *Git,
+	reflogAction, mainRepoRoot, tmpPath, targetWtPath, branch, targetBranch string,
+	strategy mergeStrategy,
+) error {
+	err := removeStaleMergeCheckout(ctx, stderr, fsys, git, mainRepoRoot, tmpPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPreparingMergeCheckout, err)
+	}
+
+	err = git.WorktreeAddExisting(ctx, mainRepoRoot, tmpPath, branch)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPreparingMergeCheckout, err)
+	}
+
+	mergeErr := mergeLocked(ctx, git, reflogAction, tmpPath, targetWtPath, branch, targetBranch, strategy)
+
+	var conflict *conflictError
+	if errors.As(mergeErr, &conflict) {
+		conflict.branch = branch
+	}
+
+	// Always drop the temporary checkout, whether or not the merge worked
+	removeErr := git.WorktreeRemove(ctx, mainRepoRoot, tmpPath, true)
+	if removeErr != nil {
+		fprintln(stderr, "warning: removing temporary checkout:", removeErr)
+	}
+
+	return mergeErr
+}
+
+// removeStaleMergeCheckout removes the temporary checkout at tmpPath that a
+// merge --branch which died before cleaning up left behind, if any.
+func removeStaleMergeCheckout(ctx context.Context, stderr io.Writer, fsys fs.FS, git *Git, mainRepoRoot, tmpPath string) error {
+	paths, err := git.WorktreeList(ctx, mainRepoRoot)
 	if err != nil {
-		// Non-fatal, use 0 for dry-run output
-		commitCount = 0
+		return err
 	}
 
-	// Handle dry-run
-	if dryRun {
-		return printDryRun(stdout, featureBranch, targetBranch, targetWtPath, mainRepoRoot, cfg.EffectiveCwd, info.Name, commitCount, keep)
+	registered := slices.ContainsFunc(paths, func(path string) bool {
+		return isSamePath(path, tmpPath)
+	})
+
+	if registered {
+		fprintln(stderr, "Removing stale temporary checkout:", tmpPath)
+
+		return git.WorktreeRemove(ctx, mainRepoRoot, tmpPath, true)
 	}
 
-	// PHASE 2: EXECUTE (with retry loop)
+	// Not a worktree (anymore), but the directory would make git refuse it
+	return fsys.RemoveAll(tmpPath)
+}
 
-	// 5. Rebase + Merge with lock
-	locker := fs.NewLocker(fsys)
-	lockPath := mergeLockPath(gitCommonDir)
+// resolvePushRemote returns the remote merge --push pushes target to:
+// explicit (--remote) if given, else the remote of target's upstream, else
+// the remote of a remote-tracking target whose local branch does not exist
+// yet, else origin. It fails if that remote is not configured.
+func resolvePushRemote(ctx context.Context, git *Git, dir, target, explicit string, tracking *remoteTarget) (string, error) {
+	remote := explicit
+
+	if remote == "" {
+		remote = "origin"
+
+		if _, upstreamRemote, err := git.Upstream(ctx, dir, target); err == nil && upstreamRemote != "" {
+			remote = upstreamRemote
+		} else if tracking != nil {
+			remote, _, _ = strings.Cut(strings.TrimPrefix(tracking.ref, "refs/remotes/"), "/")
+		}
+	}
 
-	err = mergeWithLock(ctx, stderr, git, locker, lockPath, cfg.EffectiveCwd, targetWtPath, featureBranch, targetBranch)
+	exists, err := git.RemoteExists(ctx, dir, remote)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	fprintln(stdout, "Merged", featureBranch, "into", targetBranch)
+	if !exists {
+		return "", fmt.Errorf("%w: '%s' (set one with --remote)", errPushRemoteNotFound, remote)
+	}
 
-	// 6. Cleanup (unless --keep)
-	if keep {
-		fprintln(stdout, "Worktree kept:", cfg.EffectiveCwd)
+	return remote, nil
+}
 
+// pushMergedTarget pushes target to remote after a merge, or does nothing
+// if remote is "" (no --push). The merge is never undone; a failed push is
+// returned as errPushFailed.
+func pushMergedTarget(ctx context.Context, stdout io.Writer, git *Git, dir, target, remote string) error {
+	if remote == "" {
 		return nil
 	}
 
-	hookRunner := NewHookRunner(fsys, mainRepoRoot, env, stdout, stderr)
-
-	cleanupErr := CleanupWorktree(ctx, stdout, git, hookRunner, &info, cfg.EffectiveCwd, mainRepoRoot, true, true)
-	if cleanupErr != nil {
-		// Merge succeeded but cleanup failed - warn but don't fail
-		fprintln(stderr, "warning: cleanup failed:", cleanupErr)
-		fprintln(stderr, "run 'wt remove", info.Name, "--with-branch' to clean up manually")
+	err := git.Push(ctx, dir, remote, target)
+	if err != nil {
+		return fmt.Errorf("%w (the merge was kept; retry with: git push %s %s): %w", errPushFailed, remote, target, err)
 	}
 
+	fprintf(stdout, "Pushed %s to %s\n", target, remote)
+
 	return nil
 }
 
+// mergeWithLock merges featureBranch from wtPath into targetBranch under
+// the merge lock, syncing a remote-tracking target first (see
+// mergeSyncedTarget).
 func mergeWithLock(
 	ctx context.Context,
-	stderr io.Writer,
+	stdout, stderr io.Writer,
 	git *Git,
+	reflogAction string,
 	locker *fs.Locker,
 	lockPath string,
 	wtPath, targetWtPath, featureBranch, targetBranch string,
+	strategy mergeStrategy,
+	remote *remoteTarget,
 ) error {
 	// Acquire merge lock with timeout and retries
 	lock, err := acquireMergeLock(ctx, stderr, locker, lockPath)
@@ -217,6 +1146,71 @@ func mergeWithLock(
 		}
 	}()
 
+	return mergeSyncedTarget(ctx, stdout, stderr, git, reflogAction, wtPath, targetBranch, targetWtPath, remote, func() error {
+		return mergeLocked(ctx, git, reflogAction, wtPath, targetWtPath, featureBranch, targetBranch, strategy)
+	})
+}
+
+// mergeSyncedTarget brings the local targetBranch in sync with a
+// remote-tracking target (see syncRemoteTarget), then runs merge. A local
+// branch created for it is deleted again if merge fails, so a failed merge
+// leaves no new branch behind. The caller holds the merge lock, so no other
+// merge moves the target in between.
+func mergeSyncedTarget(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	git *Git,
+	reflogAction, dir, targetBranch, targetWtPath string,
+	remote *remoteTarget,
+	merge func() error,
+) error {
+	err := syncRemoteTarget(ctx, stdout, git, reflogAction, dir, targetBranch, targetWtPath, remote)
+	if err != nil {
+		return err
+	}
+
+	mergeErr := merge()
+	if mergeErr == nil || remote == nil || !remote.create {
+		return mergeErr
+	}
+
+	// Still roll back after a signal cancelled ctx
+	deleteErr := git.BranchDelete(context.WithoutCancel(ctx), dir, targetBranch, true)
+	if deleteErr != nil {
+		return errors.Join(mergeErr, fmt.Errorf("deleting branch '%s' created from '%s': %w", targetBranch, remote.name, deleteErr))
+	}
+
+	fprintf(stderr, "Deleted branch '%s' again (created from '%s')\n", targetBranch, remote.name)
+
+	return mergeErr
+}
+
+// mergeLocked merges featureBranch from wtPath into targetBranch with the
+// given strategy. The caller holds the merge lock.
+func mergeLocked(
+	ctx context.Context,
+	git *Git,
+	reflogAction, wtPath, targetWtPath, featureBranch, targetBranch string,
+	strategy mergeStrategy,
+) error {
+	git = git.WithReflogAction(reflogAction)
+
+	err := checkTargetClean(ctx, git, targetBranch, targetWtPath)
+	if err != nil {
+		return err
+	}
+
+	if strategy.authorName != "" {
+		git = git.WithAuthor(strategy.authorName, strategy.authorEmail)
+	}
+
+	switch strategy.mode {
+	case mergeStrategyMergeCommit:
+		return mergeCommitInto(ctx, git, reflogAction, wtPath, targetWtPath, featureBranch, targetBranch, strategy.message)
+	case mergeStrategySquash:
+		return squashInto(ctx, git, reflogAction, wtPath, targetWtPath, featureBranch, targetBranch, strategy.message)
+	}
+
 	// Rebase onto target (under lock, so target can't move)
 	err = git.Rebase(ctx, wtPath, targetBranch)
 	if err != nil {
@@ -248,8 +1242,115 @@ func mergeWithLock(
 		// Target is checked out in another worktree - merge there
 		err = git.Merge(ctx, targetWtPath, featureBranch, true)
 	} else {
-		// Target is not checked out anywhere - use local push to update the branch
-		err = git.PushLocal(ctx, wtPath, featureBranch, targetBranch)
+		// Target is not checked out anywhere - fast-forward the branch ref
+		err = git.FastForwardBranch(ctx, wtPath, targetBranch, featureBranch, reflogAction)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errMergingInto, targetBranch, err)
+	}
+
+	return nil
+}
+
+// mergeCommitInto merges featureBranch into targetBranch with a merge
+// commit. A checked-out target is merged in its worktree; otherwise the
+// commit is built with merge-tree and the branch ref moved to it. On
+// conflicts the target is left unchanged.
+func mergeCommitInto(
+	ctx context.Context,
+	git *Git,
+	reflogAction, wtPath, targetWtPath, featureBranch, targetBranch, message string,
+) error {
+	if targetWtPath != "" {
+		err := git.MergeNoFF(ctx, targetWtPath, featureBranch, message)
+		if err == nil {
+			return nil
+		}
+
+		if !isConflict(err) {
+			return fmt.Errorf("%w %s: %w", errMergingInto, targetBranch, err)
+		}
+
+		files, filesErr := git.ConflictingFiles(ctx, targetWtPath)
+
+		// Abort the merge to leave the target worktree as it was
+		abortErr := git.MergeAbort(ctx, targetWtPath)
+
+		return errors.Join(
+			&conflictError{target: targetBranch, files: files, strategy: mergeStrategyMergeCommit},
+			filesErr,
+			abortErr,
+		)
+	}
+
+	targetSHA, err := git.RevParse(ctx, wtPath, "refs/heads/"+targetBranch)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errMergingInto, targetBranch, err)
+	}
+
+	tree, conflicts, err := git.MergeTree(ctx, wtPath, targetSHA, featureBranch)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errMergingInto, targetBranch, err)
+	}
+
+	if len(conflicts) > 0 {
+		return &conflictError{target: targetBranch, files: conflicts, strategy: mergeStrategyMergeCommit}
+	}
+
+	commit, err := git.CommitTree(ctx, wtPath, tree, message, targetSHA, featureBranch)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errMergingInto, targetBranch, err)
+	}
+
+	// The merge commit has the target tip as first parent, so this is a
+	// fast-forward unless the target moved meanwhile
+	err = git.FastForwardBranch(ctx, wtPath, targetBranch, commit, reflogAction)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errMergingInto, targetBranch, err)
+	}
+
+	return nil
+}
+
+// squashInto adds one commit with the changes of featureBranch to
+// targetBranch. The commit is built with merge-tree, so conflicts leave
+// everything unchanged; a checked-out target is then fast-forwarded in its
+// worktree, any other one by moving the branch ref.
+func squashInto(
+	ctx context.Context,
+	git *Git,
+	reflogAction, wtPath, targetWtPath, featureBranch, targetBranch, message string,
+) error {
+	targetSHA, err := git.RevParse(ctx, wtPath, "refs/heads/"+targetBranch)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errMergingInto, targetBranch, err)
+	}
+
+	// Nothing to squash if the target already contains the branch
+	contained, err := git.IsAncestor(ctx, wtPath, featureBranch, targetSHA)
+	if err != nil || contained {
+		return err
+	}
+
+	tree, conflicts, err := git.MergeTree(ctx, wtPath, targetSHA, featureBranch)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errMergingInto, targetBranch, err)
+	}
+
+	if len(conflicts) > 0 {
+		return &conflictError{target: targetBranch, files: conflicts, strategy: mergeStrategySquash}
+	}
+
+	commit, err := git.CommitTree(ctx, wtPath, tree, message, targetSHA)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errMergingInto, targetBranch, err)
+	}
+
+	if targetWtPath != "" {
+		err = git.Merge(ctx, targetWtPath, commit, true)
+	} else {
+		err = git.FastForwardBranch(ctx, wtPath, targetBranch, commit, reflogAction)
 	}
 
 	if err != nil {
@@ -334,11 +1435,21 @@ func isConflict(err error) bool {
 
 // conflictError wraps conflict information with resolution hints.
 type conflictError struct {
-	target string
-	files  []string
+	target   string
+	files    []string
+	strategy string // mergeStrategyMergeCommit or mergeStrategySquash, which leave the target unchanged; "" for a rebase
+	branch   string // Set for merge --branch, whose temporary checkout is gone by the time this is shown
 }
 
 func (e *conflictError) Error() string {
+	if e.branch != "" {
+		return e.branchError()
+	}
+
+	if e.strategy != "" {
+		return e.mergeCommitError()
+	}
+
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("%s %s: %s", errRebasingOnto, e.target, errMergeConflict))
@@ -358,7 +1469,57 @@ func (e *conflictError) Error() string {
 	return sb.String()
 }
 
+// mergeCommitError is the message for a conflicting --merge-commit or
+// --squash merge, which left the target branch unchanged.
+func (e *conflictError) mergeCommitError() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%s %s: %s", errMergingInto, e.target, errMergeCommitConflict))
+
+	if len(e.files) > 0 {
+		sb.WriteString(" in ")
+		sb.WriteString(strings.Join(e.files, ", "))
+	}
+
+	sb.WriteString("\n\nThe merge was aborted; '" + e.target + "' is unchanged. To resolve:\n")
+	sb.WriteString("  1. git merge " + e.target + " (in this worktree)\n")
+	sb.WriteString("  2. Fix conflicts, git add <fixed-files>, git commit\n")
+	sb.WriteString("  3. Run wt merge --" + e.strategy + " again")
+
+	return sb.String()
+}
+
+// branchError is the message for a conflicting merge --branch. Its temporary
+// checkout was removed, so conflicts have to be resolved in a checkout of
+// the branch.
+func (e *conflictError) branchError() string {
+	var sb strings.Builder
+
+	step, conflict, op, command, finish, flag := errRebasingOnto, errMergeConflict, "rebase", "git rebase "+e.target, "git rebase --continue", ""
+	if e.strategy != "" {
+		step, conflict, op, command, finish, flag = errMergingInto, errMergeCommitConflict, "merge", "git merge "+e.target, "git commit", " --"+e.strategy
+	}
+
+	sb.WriteString(fmt.Sprintf("%s %s: %s", step, e.target, conflict))
+
+	if len(e.files) > 0 {
+		sb.WriteString(" in ")
+		sb.WriteString(strings.Join(e.files, ", "))
+	}
+
+	sb.WriteString("\n\nThe " + op + " was aborted; '" + e.branch + "' and '" + e.target + "' are unchanged. To resolve:\n")
+	sb.WriteString("  1. Check out '" + e.branch + "' and run: " + command + "\n")
+	sb.WriteString("  2. Fix conflicts, git add <fixed-files>, " + finish + "\n")
+	sb.WriteString("  3. Run wt merge --branch " + e.branch + flag + " again")
+
+	return sb.String()
+}
+
 func (e *conflictError) Unwrap() error {
+	if e.strategy != "" {
+		return errMergeCommitConflict
+	}
+
 	return errMergeConflict
 }
 
@@ -368,15 +1529,27 @@ func formatConflictError(target string, files []string) error {
 
 func printDryRun(
 	stdout io.Writer,
-	feature, target, targetWtPath, mainRepoRoot, wtPath, name string,
+	feature, target, targetSource, targetWtPath, mainRepoRoot, wtPath, name, pushRemote string,
 	commitCount int,
-	keep bool,
+	remote *remoteTarget,
+	strategy mergeStrategy,
+	keep, createWorktree bool,
 ) error {
 	fprintln(stdout, "Dry run: wt merge", feature, "→", target)
 	fprintln(stdout)
+	fprintf(stdout, "Target: %s (from %s)\n", target, targetSource)
+	fprintln(stdout)
 	fprintln(stdout, "Checks:")
 	fprintln(stdout, "  ✓ Current worktree is clean")
-	fprintf(stdout, "  ✓ Target branch '%s' exists\n", target)
+
+	switch {
+	case remote == nil:
+		fprintf(stdout, "  ✓ Target branch '%s' exists\n", target)
+	case remote.create:
+		fprintf(stdout, "  ✓ Remote-tracking branch '%s' exists (no local branch '%s' yet)\n", remote.name, target)
+	default:
+		fprintf(stdout, "  ✓ Target branch '%s' exists and can fast-forward to '%s'\n", target, remote.name)
+	}
 
 	if targetWtPath != "" {
 		fprintf(stdout, "  ✓ Target worktree %s is clean\n", targetWtPath)
@@ -385,23 +1558,28 @@ func printDryRun(
 	fprintln(stdout)
 	fprintln(stdout, "Would execute:")
 
-	step := 1
+	step := printDryRunRemoteTarget(stdout, 1, target, remote)
 
-	commitDesc := "commits"
-	if commitCount == 1 {
-		commitDesc = "commit"
-	}
+	if strategy.mode != mergeStrategyRebase {
+		printDryRunCommitStep(stdout, step, strategy, feature, target, targetWtPath, commitCount)
+		step++
+	} else {
+		fprintf(stdout, "  %d. Rebase '%s' onto '%s' (%s to replay)\n", step, feature, target, pluralizeCommits(commitCount))
+		step++
 
-	fprintf(stdout, "  %d. Rebase '%s' onto '%s' (%d %s to replay)\n", step, feature, target, commitCount, commitDesc)
-	step++
+		mergeLocation := mainRepoRoot
+		if targetWtPath != "" {
+			mergeLocation = targetWtPath
+		}
 
-	mergeLocation := mainRepoRoot
-	if targetWtPath != "" {
-		mergeLocation = targetWtPath
+		fprintf(stdout, "  %d. Fast-forward '%s' to '%s' (in %s)\n", step, target, feature, mergeLocation)
+		step++
 	}
 
-	fprintf(stdout, "  %d. Fast-forward '%s' to '%s' (in %s)\n", step, target, feature, mergeLocation)
-	step++
+	if pushRemote != "" {
+		fprintf(stdout, "  %d. Push '%s' to %s\n", step, target, pushRemote)
+		step++
+	}
 
 	if !keep {
 		fprintf(stdout, "  %d. Run pre-delete hooks\n", step)
@@ -411,6 +1589,11 @@ func printDryRun(
 		step++
 
 		fprintf(stdout, "  %d. Delete branch: %s\n", step, name)
+		step++
+	}
+
+	if createWorktree {
+		printDryRunCreateWorktree(stdout, step, target, targetWtPath)
 	}
 
 	fprintln(stdout)
@@ -419,6 +1602,203 @@ func printDryRun(
 	return nil
 }
 
+// printDryRunCommitStep prints the merge step of a --merge-commit or
+// --squash dry run, with the commit message that would be used.
+func printDryRunCommitStep(stdout io.Writer, step int, strategy mergeStrategy, feature, target, targetWtPath string, commitCount int) {
+	location := "without a checkout"
+	if targetWtPath != "" {
+		location = "in " + targetWtPath
+	}
+
+	if strategy.mode == mergeStrategySquash {
+		fprintf(stdout, "  %d. Squash %s of '%s' into one commit on '%s' (%s)\n", step, pluralizeCommits(commitCount), feature, target, location)
+	} else {
+		fprintf(stdout, "  %d. Merge commit: merge '%s' into '%s' with --no-ff (%s, %s)\n", step, feature, target, pluralizeCommits(commitCount), location)
+	}
+
+	for i, line := range strings.Split(strategy.message, "\n") {
+		label := "     Message: "
+		if i > 0 {
+			label = "              "
+		}
+
+		fprintln(stdout, strings.TrimRight(label+line, " "))
+	}
+
+	if strategy.authorName != "" {
+		fprintf(stdout, "     Author:  %s <%s>\n", strategy.authorName, strategy.authorEmail)
+	}
+}
+
+func pluralizeCommits(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+
+	return fmt.Sprintf("%d commits", n)
+}
+
+// printDryRunCreateWorktree prints the --create-worktree step of a dry run.
+func printDryRunCreateWorktree(stdout io.Writer, step int, target, targetWtPath string) {
+	if targetWtPath != "" {
+		fprintf(stdout, "  %d. Skip creating a worktree ('%s' is already checked out at %s)\n", step, target, targetWtPath)
+
+		return
+	}
+
+	fprintf(stdout, "  %d. Create worktree on '%s'\n", step, target)
+}
+
+// remoteTarget is a remote-tracking merge target (--into origin/main). The
+// merge goes into the matching local branch, which is created from the
+// remote-tracking branch or fast-forwarded to it first.
+type remoteTarget struct {
+	name   string // As given, e.g. origin/main
+	ref    string // e.g. refs/remotes/origin/main
+	create bool   // The local branch does not exist yet
+	behind int    // Commits the existing local branch is behind
+}
+
+// rebaseOnto returns what the feature branch is rebased onto once the local
+// target is in sync: the remote-tracking branch if there is one.
+func (r *remoteTarget) rebaseOnto(targetBranch string) string {
+	if r == nil {
+		return targetBranch
+	}
+
+	return r.ref
+}
+
+// resolveTargetBranch validates the merge target. A local branch is returned
+// as is. A remote-tracking branch resolves to the local branch of the same
+// name, with a remoteTarget describing how to bring it up to date; other
+// refs git can resolve (tags, commits) are rejected, since a merge needs a
+// branch to fast-forward.
+func resolveTargetBranch(ctx context.Context, git *Git, dir, target string) (string, *remoteTarget, error) {
+	exists, err := git.BranchExists(ctx, dir, target)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if exists {
+		return target, nil, nil
+	}
+
+	ref, localBranch, ok := git.RemoteTrackingBranch(ctx, dir, target)
+	if !ok {
+		if _, revErr := git.RevParse(ctx, dir, target); revErr == nil {
+			return "", nil, fmt.Errorf("'%s' %w", target, errTargetNotBranch)
+		}
+
+		return "", nil, fmt.Errorf("'%s' %w (check branch name or use --into)", target, errTargetBranchNotExist)
+	}
+
+	remote := &remoteTarget{name: target, ref: ref}
+
+	exists, err = git.BranchExists(ctx, dir, localBranch)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !exists {
+		remote.create = true
+
+		return localBranch, remote, nil
+	}
+
+	// The local branch must not have commits the remote-tracking one lacks
+	ahead, err := git.CommitsBetween(ctx, dir, ref, localBranch)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if ahead > 0 {
+		return "", nil, fmt.Errorf("local branch '%s' %w '%s' (%d local commits; merge into '%s' instead)",
+			localBranch, errTargetDiverged, target, ahead, localBranch)
+	}
+
+	remote.behind, err = git.CommitsBetween(ctx, dir, localBranch, ref)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return localBranch, remote, nil
+}
+
+// syncRemoteTarget creates targetBranch from a remote-tracking target or
+// fast-forwards it (in targetWtPath if it is checked out there). It does
+// nothing for local targets or a local branch that is up to date.
+// reflogAction labels the branch's reflog entries.
+func syncRemoteTarget(ctx context.Context, stdout io.Writer, git *Git, reflogAction, dir, targetBranch, targetWtPath string, remote *remoteTarget) error {
+	git = git.WithReflogAction(reflogAction)
+
+	switch {
+	case remote == nil || (!remote.create && remote.behind == 0):
+		return nil
+	case remote.create:
+		err := git.BranchCreate(ctx, dir, targetBranch, remote.ref)
+		if err != nil {
+			return err
+		}
+
+		fprintf(stdout, "Created branch '%s' from '%s'\n", targetBranch, remote.name)
+
+		return nil
+	}
+
+	var err error
+
+	if targetWtPath != "" {
+		err = checkTargetClean(ctx, git, targetBranch, targetWtPath)
+		if err == nil {
+			err = git.Merge(ctx, targetWtPath, remote.ref, true)
+		}
+	} else {
+		err = git.FastForwardBranch(ctx, dir, targetBranch, remote.ref, reflogAction+": fast-forward from "+remote.name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("fast-forwarding '%s' to '%s': %w", targetBranch, remote.name, err)
+	}
+
+	fprintf(stdout, "Fast-forwarded '%s' to '%s'\n", targetBranch, remote.name)
+
+	return nil
+}
+
+// printDryRunRemoteTarget prints the step that brings the local branch of a
+// remote-tracking target up to date, if any, and returns the next step.
+func printDryRunRemoteTarget(stdout io.Writer, step int, target string, remote *remoteTarget) int {
+	switch {
+	case remote == nil:
+		return step
+	case remote.create:
+		fprintf(stdout, "  %d. Create local branch '%s' from '%s'\n", step, target, remote.name)
+	case remote.behind == 1:
+		fprintf(stdout, "  %d. Fast-forward '%s' to '%s' (1 commit behind)\n", step, target, remote.name)
+	case remote.behind > 1:
+		fprintf(stdout, "  %d. Fast-forward '%s' to '%s' (%d commits behind)\n", step, target, remote.name, remote.behind)
+	default:
+		fprintf(stdout, "  %d. Use local branch '%s' (up to date with '%s')\n", step, target, remote.name)
+	}
+
+	return step + 1
+}
+
+// resolveMergeTarget returns the branch a worktree merges into without
+// --into or --into-default, and where that came from: the worktree's own
+// merge_into, then the configured default_merge_target, then its base_branch.
+func resolveMergeTarget(cfg Config, info *WorktreeInfo) (string, string) {
+	switch {
+	case info.MergeInto != "":
+		return info.MergeInto, "worktree merge_into"
+	case cfg.DefaultMergeTarget != "":
+		return cfg.DefaultMergeTarget, "config default_merge_target"
+	default:
+		return info.BaseBranch, "base_branch"
+	}
+}
+
 // mergeLockPath returns the path to the lock file for merge operations.
 // Placed in git common directory so all worktrees share the same lock.
 func mergeLockPath(gitCommonDir string) string {
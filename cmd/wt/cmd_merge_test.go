@@ -1,11 +1,15 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
 )
 
 // initRepoWithConfig initializes a git repo and commits the config.json file
@@ -141,6 +145,51 @@ func Test_Merge_Returns_Error_When_Worktree_Has_Uncommitted_Changes(t *testing.T
 	AssertContains(t, stderr, "commit or stash")
 }
 
+func Test_Merge_Ignore_Untracked_Merges_Worktree_With_Untracked_Files(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	stdout, stderr, code := c.Run("--config", "config.json", "create", "--name", "scratch")
+	if code != 0 {
+		t.Fatalf("create failed: %s", stderr)
+	}
+
+	wtPath := extractPath(stdout)
+
+	gitCommitInDir(t, wtPath, "feature.txt", "feature\n", "Add feature")
+	writeTestFile(t, filepath.Join(wtPath, "notes.txt"), "scratch\n")
+
+	c2 := NewCLITesterAt(t, wtPath)
+	cfgPath := filepath.Join(c.Dir, "config.json")
+
+	_, stderr, code = c2.Run("--config", cfgPath, "merge")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 with untracked files, got %d", code)
+	}
+
+	AssertContains(t, stderr, "untracked files")
+	AssertContains(t, stderr, "--ignore-untracked")
+
+	_, stderr, code = c2.Run("--config", cfgPath, "merge", "--ignore-untracked")
+	if code != 0 {
+		t.Fatalf("merge --ignore-untracked failed: %s", stderr)
+	}
+
+	if !gitBranchContainsFile(t, c.Dir, "master", "feature.txt") {
+		t.Error("feature commit should be merged into master")
+	}
+
+	if gitBranchContainsFile(t, c.Dir, "master", "notes.txt") {
+		t.Error("untracked file must not be merged")
+	}
+
+	if statTestPath(wtPath) {
+		t.Error("worktree should be removed after merge")
+	}
+}
+
 func Test_Merge_Simple_Merge_Success(t *testing.T) {
 	t.Parallel()
 
@@ -881,3 +930,1503 @@ func Test_Merge_Includes_Merge_In_Global_Commands(t *testing.T) {
 		t.Error("merge command should be listed in global help")
 	}
 }
+
+// setupIntoDefaultWorktree creates a develop branch and a worktree branched
+// from it with one commit, returning the worktree path.
+func setupIntoDefaultWorktree(t *testing.T, c *CLI) string {
+	t.Helper()
+
+	out, err := testGitCmd("-C", c.Dir, "branch", "develop").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch develop failed: %v\n%s", err, out)
+	}
+
+	stdout, stderr, code := c.Run("--config", "config.json", "create", "--name", "feature-branch", "--from-branch", "develop")
+	if code != 0 {
+		t.Fatalf("create failed: %s", stderr)
+	}
+
+	wtPath := extractPath(stdout)
+	gitCommitInDir(t, wtPath, "feature.txt", "feature content", "Add feature")
+
+	return wtPath
+}
+
+func Test_Merge_Into_Default_Targets_Master(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	wtPath := setupIntoDefaultWorktree(t, c)
+
+	stdout, stderr, code := NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge", "--into-default")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Merged feature-branch into master")
+
+	if !gitBranchContainsFile(t, c.Dir, "master", "feature.txt") {
+		t.Error("feature.txt should be on master after merge")
+	}
+
+	if gitBranchContainsFile(t, c.Dir, "develop", "feature.txt") {
+		t.Error("feature.txt should NOT be on develop (the base_branch)")
+	}
+}
+
+func Test_Merge_Into_Default_Targets_Main(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	out, err := testGitCmd("-C", c.Dir, "branch", "-m", "master", "main").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch -m failed: %v\n%s", err, out)
+	}
+
+	wtPath := setupIntoDefaultWorktree(t, c)
+
+	stdout, stderr, code := NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge", "--into-default")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Merged feature-branch into main")
+
+	if !gitBranchContainsFile(t, c.Dir, "main", "feature.txt") {
+		t.Error("feature.txt should be on main after merge")
+	}
+}
+
+func Test_Merge_Into_Default_DryRun_Shows_Resolved_Target(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	wtPath := setupIntoDefaultWorktree(t, c)
+
+	stdout, stderr, code := NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge", "--into-default", "--dry-run")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Dry run: wt merge feature-branch → master")
+	AssertContains(t, stdout, "Target: master (from --into-default)")
+}
+
+func Test_Merge_Into_And_Into_Default_Are_Mutually_Exclusive(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	wtPath := setupIntoDefaultWorktree(t, c)
+
+	_, stderr, code := NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge", "--into", "develop", "--into-default")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --into and --into-default together")
+}
+
+func Test_Merge_Into_Alias_Of_Current_Branch_Reports_Already_On_Target(t *testing.T) {
+	t.Parallel()
+
+	for _, into := range []string{"HEAD", "@", "refs/heads/feature-branch"} {
+		t.Run(into, func(t *testing.T) {
+			t.Parallel()
+
+			c := NewCLITester(t)
+			initRepoWithConfig(t, c)
+
+			stdout, stderr, code := c.Run("--config", "config.json", "create", "--name", "feature-branch")
+			if code != 0 {
+				t.Fatalf("create failed: %s", stderr)
+			}
+
+			wtPath := extractPath(stdout)
+
+			_, stderr, code = NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge", "--into", into)
+
+			if code != 1 {
+				t.Errorf("expected exit code 1, got %d", code)
+			}
+
+			AssertContains(t, stderr, "already on target branch, nothing to merge 'feature-branch'")
+			AssertContains(t, stderr, "--into '"+into+"' resolves to the current branch")
+		})
+	}
+}
+
+func Test_Merge_Into_Default_Resolving_To_Current_Branch_Reports_Already_On_Target(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	stdout, stderr, code := c.Run("--config", "config.json", "create", "--name", "feature-branch")
+	if code != 0 {
+		t.Fatalf("create failed: %s", stderr)
+	}
+
+	wtPath := extractPath(stdout)
+
+	out, err := testGitCmd("-C", c.Dir, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/feature-branch").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git symbolic-ref failed: %v\n%s", err, out)
+	}
+
+	_, stderr, code = NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge", "--into-default")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "already on target branch")
+	AssertContains(t, stderr, "--into-default resolves to the current branch")
+}
+
+func Test_Merge_Returns_Error_When_Target_Checked_Out_In_Same_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	out, err := testGitCmd("-C", c.Dir, "branch", "develop").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch develop failed: %v\n%s", err, out)
+	}
+
+	stdout, stderr, code := c.Run("--config", "config.json", "create", "--name", "feature-branch", "--from-branch", "develop")
+	if code != 0 {
+		t.Fatalf("create failed: %s", stderr)
+	}
+
+	wtPath := extractPath(stdout)
+
+	// Switch the worktree onto its own target branch
+	out, err = testGitCmd("-C", wtPath, "switch", "develop").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git switch failed: %v\n%s", err, out)
+	}
+
+	_, stderr, code = NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "target branch is checked out in this worktree instead of its own branch 'develop'")
+	AssertContains(t, stderr, "git switch feature-branch")
+}
+
+// createBranchWithoutWorktree creates branch with one commit adding filename,
+// leaving the main repo back on master and no worktree for the branch.
+func createBranchWithoutWorktree(t *testing.T, repoDir, branch, filename string) {
+	t.Helper()
+
+	out, err := testGitCmd("-C", repoDir, "checkout", "-q", "-b", branch).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git checkout -b failed: %v\n%s", err, out)
+	}
+
+	gitCommitInDir(t, repoDir, filename, "branch content", "Add "+filename)
+
+	out, err = testGitCmd("-C", repoDir, "checkout", "-q", "master").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git checkout master failed: %v\n%s", err, out)
+	}
+}
+
+func Test_Merge_Branch_Without_Worktree_Into_Target(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	createBranchWithoutWorktree(t, c.Dir, "orphan", "orphan.txt")
+
+	// Move master forward so the branch actually needs a rebase
+	out, err := testGitCmd("-C", c.Dir, "branch", "side").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch failed: %v\n%s", err, out)
+	}
+
+	gitCommitInDir(t, c.Dir, "master.txt", "master content", "Master work")
+
+	stdout, stderr, code := c.Run("--config", "config.json", "merge", "--branch", "orphan", "--into", "master")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Merged orphan into master")
+
+	if !gitBranchContainsFile(t, c.Dir, "master", "orphan.txt") {
+		t.Error("orphan.txt should be on master after merge")
+	}
+
+	if !c.FileExists("orphan.txt") {
+		t.Error("main repo checkout of master should be fast-forwarded")
+	}
+
+	// Branch is kept by default, temporary checkout is gone
+	if !slices.Contains(listBranches(t, c.Dir), "orphan") {
+		t.Error("branch should be kept without --delete-branch")
+	}
+
+	out, err = testGitCmd("-C", c.Dir, "worktree", "list", "--porcelain").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git worktree list failed: %v\n%s", err, out)
+	}
+
+	AssertNotContains(t, string(out), "wt-merge")
+}
+
+func Test_Merge_Branch_Delete_Branch_Into_Unchecked_Out_Target(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	out, err := testGitCmd("-C", c.Dir, "branch", "develop").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch failed: %v\n%s", err, out)
+	}
+
+	createBranchWithoutWorktree(t, c.Dir, "feature/leftover", "leftover.txt")
+
+	stdout, stderr, code := c.Run("--config", "config.json", "merge", "--branch", "feature/leftover", "--into", "develop", "--delete-branch")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Deleted branch: feature/leftover")
+
+	if !gitBranchContainsFile(t, c.Dir, "develop", "leftover.txt") {
+		t.Error("leftover.txt should be on develop after merge")
+	}
+
+	if gitBranchContainsFile(t, c.Dir, "master", "leftover.txt") {
+		t.Error("master should be untouched")
+	}
+
+	if slices.Contains(listBranches(t, c.Dir), "feature/leftover") {
+		t.Error("branch should be deleted with --delete-branch")
+	}
+}
+
+func Test_Merge_Branch_Requires_Target(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	createBranchWithoutWorktree(t, c.Dir, "orphan", "orphan.txt")
+
+	_, stderr, code := c.Run("--config", "config.json", "merge", "--branch", "orphan")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "--branch requires --into or --into-default")
+}
+
+func Test_Merge_Branch_Rejects_Branch_With_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	stdout := c.MustRun("--config", "config.json", "create", "--name", "has-worktree")
+	wtPath := extractPath(stdout)
+
+	_, stderr, code := c.Run("--config", "config.json", "merge", "--branch", "has-worktree", "--into", "master")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "branch is checked out in a worktree at "+wtPath)
+}
+
+func Test_Merge_Branch_Removes_Stale_Temporary_Checkout(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	createBranchWithoutWorktree(t, c.Dir, "orphan", "orphan.txt")
+
+	// What a merge --branch that died mid-merge leaves behind
+	stalePath := filepath.Join(c.Dir, ".git", "wt-merge", "orphan")
+
+	out, err := testGitCmd("-C", c.Dir, "worktree", "add", stalePath, "orphan").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git worktree add failed: %v\n%s", err, out)
+	}
+
+	stdout, stderr, code := c.Run("--config", "config.json", "merge", "--branch", "orphan", "--into", "master")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stderr, "Removing stale temporary checkout: "+stalePath)
+	AssertContains(t, stdout, "Merged orphan into master")
+	AssertNotContains(t, gitOutput(t, c.Dir, "worktree", "list", "--porcelain"), "wt-merge")
+}
+
+func Test_Merge_Branch_Conflict_Points_To_Branch_Not_Temporary_Checkout(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	createBranchWithoutWorktree(t, c.Dir, "orphan", "conflict.txt")
+	gitCommitInDir(t, c.Dir, "conflict.txt", "master content", "Master change")
+
+	_, stderr, code := c.Run("--config", "config.json", "merge", "--branch", "orphan", "--into", "master")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stderr, "conflict during rebase in conflict.txt")
+	AssertContains(t, stderr, "Check out 'orphan' and run: git rebase master")
+	AssertContains(t, stderr, "Run wt merge --branch orphan again")
+	AssertNotContains(t, stderr, "this worktree")
+	AssertNotContains(t, gitOutput(t, c.Dir, "worktree", "list", "--porcelain"), "wt-merge")
+}
+
+func Test_Merge_Create_Worktree_Opens_Target_Branch(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	out, err := testGitCmd("-C", c.Dir, "branch", "release/1.0").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch failed: %v\n%s", err, out)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "create", "--name", "fix")
+	wtPath := extractPath(stdout)
+
+	gitCommitInDir(t, wtPath, "fix.txt", "fix content", "Add fix")
+
+	c2 := NewCLITesterAt(t, wtPath)
+
+	stdout, stderr, code := c2.Run("--config", "../../config.json", "merge", "--into", "release/1.0", "--create-worktree")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Merged fix into release/1.0")
+	AssertContains(t, stdout, "Created worktree:")
+	AssertContains(t, stdout, "branch:      release/1.0")
+
+	newPath := extractPath(stdout)
+	if newPath != filepath.Join(c.Dir, "worktrees", "release-1.0") {
+		t.Fatalf("unexpected worktree path %q", newPath)
+	}
+
+	// The new worktree is managed and on the merged target branch
+	if !c.FileExists("worktrees/release-1.0/.wt/worktree.json") {
+		t.Error("created worktree should have wt metadata")
+	}
+
+	if !c.FileExists("worktrees/release-1.0/fix.txt") {
+		t.Error("created worktree should contain the merged commit")
+	}
+
+	branch, err := testGitCmd("-C", newPath, "branch", "--show-current").Output()
+	if err != nil {
+		t.Fatalf("git branch --show-current failed: %v", err)
+	}
+
+	if strings.TrimSpace(string(branch)) != "release/1.0" {
+		t.Errorf("expected worktree on release/1.0, got %q", branch)
+	}
+
+	// The merged worktree is still cleaned up
+	if c.FileExists("worktrees/fix") {
+		t.Error("merged worktree should be removed")
+	}
+}
+
+func Test_Merge_Create_Worktree_Skips_When_Target_Checked_Out(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	stdout := c.MustRun("--config", "config.json", "create", "--name", "feature")
+	wtPath := extractPath(stdout)
+
+	gitCommitInDir(t, wtPath, "feature.txt", "feature content", "Add feature")
+
+	// master is checked out in the main repo
+	c2 := NewCLITesterAt(t, wtPath)
+
+	stdout, stderr, code := c2.Run("--config", "../../config.json", "merge", "--create-worktree")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Not creating a worktree: 'master' is already checked out at "+c.Dir)
+	AssertNotContains(t, stdout, "Created worktree:")
+}
+
+func Test_Merge_Branch_Create_Worktree_DryRun_Shows_Step(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	out, err := testGitCmd("-C", c.Dir, "branch", "develop").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch failed: %v\n%s", err, out)
+	}
+
+	createBranchWithoutWorktree(t, c.Dir, "leftover", "leftover.txt")
+
+	stdout := c.MustRun("--config", "config.json", "merge", "--branch", "leftover", "--into", "develop", "--create-worktree", "--dry-run")
+	AssertContains(t, stdout, "3. Create worktree on 'develop'")
+
+	if c.FileExists("worktrees/develop") {
+		t.Error("dry run should not create a worktree")
+	}
+
+	stdout = c.MustRun("--config", "config.json", "merge", "--branch", "leftover", "--into", "develop", "--create-worktree")
+	AssertContains(t, stdout, "Merged leftover into develop")
+
+	if !c.FileExists("worktrees/develop/leftover.txt") {
+		t.Error("worktree on develop should contain the merged commit")
+	}
+}
+
+func Test_Merge_Refuses_When_Merge_State_Left_Behind(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	stdout := c.MustRun("--config", "config.json", "create", "--name", "crashed")
+	wtPath := extractPath(stdout)
+
+	gitCommitInDir(t, wtPath, "feature.txt", "feature content", "Add feature")
+
+	// Simulate a merge that died after writing its state
+	c2 := NewCLITesterAt(t, wtPath)
+	c2.WriteFile(".wt/merge-state.json", `{"branch": "crashed", "target": "master"}`)
+
+	_, stderr, code := c2.Run("--config", "../../config.json", "merge")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "a merge is in progress")
+	AssertContains(t, stderr, "wt merge --abort")
+
+	if gitBranchContainsFile(t, c.Dir, "master", "feature.txt") {
+		t.Error("master should be untouched")
+	}
+
+	stdout = c2.MustRun("--config", "../../config.json", "merge", "--abort")
+	AssertContains(t, stdout, "Removed merge state")
+
+	if c2.FileExists(".wt/merge-state.json") {
+		t.Error("merge state should be removed by --abort")
+	}
+
+	stdout = c2.MustRun("--config", "../../config.json", "merge", "--keep")
+	AssertContains(t, stdout, "Merged crashed into master")
+
+	if c2.FileExists(".wt/merge-state.json") {
+		t.Error("merge state should not be left after a successful merge")
+	}
+}
+
+func Test_Merge_Refuses_And_Aborts_Rebase_In_Progress(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	stdout := c.MustRun("--config", "config.json", "create", "--name", "rebasing")
+	wtPath := extractPath(stdout)
+
+	gitCommitInDir(t, c.Dir, "conflict.txt", "master version", "Master change")
+	gitCommitInDir(t, wtPath, "conflict.txt", "feature version", "Feature change")
+
+	// Leave a conflicted rebase behind, as a crash mid-merge would
+	out, err := testGitCmd("-C", wtPath, "rebase", "master").CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected rebase to stop on a conflict\n%s", out)
+	}
+
+	c2 := NewCLITesterAt(t, wtPath)
+
+	_, stderr, code := c2.Run("--config", "../../config.json", "merge")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "a merge is in progress")
+
+	stdout = c2.MustRun("--config", "../../config.json", "merge", "--abort")
+	AssertContains(t, stdout, "Aborted rebase in")
+
+	if rebaseInProgress(t.Context(), fs.NewReal(), newTestGit(), wtPath) {
+		t.Error("rebase should be aborted")
+	}
+
+	_, stderr, code = c2.Run("--config", "../../config.json", "merge", "--abort")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 when nothing to abort, got %d", code)
+	}
+
+	AssertContains(t, stderr, "no merge in progress")
+}
+
+func Test_Merge_Into_Worktree_Name_Targets_Its_Branch(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	stdout := c.MustRun("--config", "config.json", "create", "--name", "feature-a")
+	wtA := extractPath(stdout)
+
+	stdout = c.MustRun("--config", "config.json", "create", "--name", "feature-b")
+	wtB := extractPath(stdout)
+
+	// The sibling's checked-out branch differs from its name
+	out, err := testGitCmd("-C", wtB, "switch", "-c", "integration").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git switch failed: %v\n%s", err, out)
+	}
+
+	gitCommitInDir(t, wtA, "feature.txt", "feature content", "Add feature")
+
+	cA := NewCLITesterAt(t, wtA)
+
+	stdout, stderr, code := cA.Run("--config", "config.json", "merge", "--into", "feature-b", "--keep")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Merged feature-a into integration")
+
+	if !gitBranchContainsFile(t, c.Dir, "integration", "feature.txt") {
+		t.Error("feature.txt should be on the sibling worktree's branch")
+	}
+
+	if !statTestPath(filepath.Join(wtB, "feature.txt")) {
+		t.Error("sibling worktree checkout should be fast-forwarded")
+	}
+
+	if gitBranchContainsFile(t, c.Dir, "master", "feature.txt") {
+		t.Error("feature.txt should NOT be on master")
+	}
+
+	// The numeric id works too, and a plain branch name still falls through
+	gitCommitInDir(t, wtA, "more.txt", "more content", "More work")
+
+	stdout = cA.MustRun("--config", "config.json", "merge", "--into", "2", "--dry-run")
+	AssertContains(t, stdout, "Target: integration (from --into)")
+
+	stdout = cA.MustRun("--config", "config.json", "merge", "--into", "master", "--dry-run")
+	AssertContains(t, stdout, "Target: master (from --into)")
+}
+
+func Test_resolveMergeTarget_Precedence(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		cfg        Config
+		info       WorktreeInfo
+		wantTarget string
+		wantSource string
+	}{
+		{"base branch", Config{}, WorktreeInfo{BaseBranch: "main"}, "main", "base_branch"},
+		{
+			"config default_merge_target", Config{DefaultMergeTarget: "develop"},
+			WorktreeInfo{BaseBranch: "main"}, "develop", "config default_merge_target",
+		},
+		{
+			"worktree merge_into", Config{DefaultMergeTarget: "develop"},
+			WorktreeInfo{BaseBranch: "main", MergeInto: "release"}, "release", "worktree merge_into",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			target, source := resolveMergeTarget(tt.cfg, &tt.info)
+			if target != tt.wantTarget || source != tt.wantSource {
+				t.Errorf("resolveMergeTarget() = (%q, %q), want (%q, %q)", target, source, tt.wantTarget, tt.wantSource)
+			}
+		})
+	}
+}
+
+func Test_Merge_Default_Target_Precedence_In_Ls_Info_And_Merge(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	for _, branch := range []string{"develop", "release"} {
+		out, err := testGitCmd("-C", c.Dir, "branch", branch).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git branch %s failed: %v\n%s", branch, err, out)
+		}
+	}
+
+	c.WriteFile("merge-config.json", `{"base": "worktrees", "merge_into": "develop"}`)
+
+	c.MustRun("--config", "config.json", "create", "--name", "from-base")
+	c.MustRun("--config", "merge-config.json", "create", "--name", "from-config")
+
+	stdout := c.MustRun("--config", "merge-config.json", "create", "--name", "from-worktree", "--merge-into", "release")
+	wtPath := extractPath(stdout)
+
+	var rows []jsonWorktree
+
+	err := json.Unmarshal([]byte(c.MustRun("--config", "config.json", "ls", "--json")), &rows)
+	if err != nil {
+		t.Fatalf("invalid ls JSON: %v", err)
+	}
+
+	targets := map[string]string{}
+	for _, row := range rows {
+		targets[row.Name] = row.DefaultTarget
+	}
+
+	// merge-config.json uses merge_into, the older name of
+	// default_merge_target. Without it in the config used by ls, only the
+	// worktree's own
+	// merge_into overrides base_branch
+	if targets["from-base"] != "master" || targets["from-config"] != "master" || targets["from-worktree"] != "release" {
+		t.Errorf("ls default_target = %v", targets)
+	}
+
+	got := strings.TrimSpace(c.MustRun("--config", "merge-config.json", "info", "from-config", "--field", "default_target"))
+	if got != "develop" {
+		t.Errorf("info default_target with config merge_into = %q, want develop", got)
+	}
+
+	gitCommitInDir(t, wtPath, "release.txt", "release content", "Release work")
+
+	cWt := NewCLITesterAt(t, wtPath)
+
+	stdout = cWt.MustRun("--config", "merge-config.json", "merge", "--dry-run")
+	AssertContains(t, stdout, "Target: release (from worktree merge_into)")
+
+	cWt.MustRun("--config", "merge-config.json", "merge", "--keep")
+
+	if !gitBranchContainsFile(t, c.Dir, "release", "release.txt") {
+		t.Error("release.txt should be merged into the worktree's merge_into branch")
+	}
+
+	if gitBranchContainsFile(t, c.Dir, "develop", "release.txt") {
+		t.Error("release.txt should NOT be on the configured merge_into branch")
+	}
+}
+
+func Test_Merge_Reports_Target_Source_For_Config_Default_Merge_Target(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	out, err := testGitCmd("-C", c.Dir, "branch", "develop").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch develop failed: %v\n%s", err, out)
+	}
+
+	// default_merge_target wins over the older merge_into
+	c.WriteFile("develop.json", `{"base": "worktrees", "default_merge_target": "develop", "merge_into": "no-such-branch"}`)
+	c.WriteFile("missing.json", `{"base": "worktrees", "default_merge_target": "no-such-branch"}`)
+
+	developConfig := filepath.Join(c.Dir, "develop.json")
+	missingConfig := filepath.Join(c.Dir, "missing.json")
+
+	wtPath := extractPath(c.MustRun("--config", developConfig, "create", "--name", "feature"))
+	gitCommitInDir(t, wtPath, "feature.txt", "feature content", "Feature work")
+
+	cWt := NewCLITesterAt(t, wtPath)
+
+	_, stderr, code := cWt.Run("--config", missingConfig, "merge", "--keep")
+	if code != 1 {
+		t.Errorf("expected exit code 1 for a missing configured target, got %d", code)
+	}
+
+	AssertContains(t, stderr, "'no-such-branch' branch does not exist")
+	AssertContains(t, stderr, "(target from config default_merge_target)")
+
+	stdout := cWt.MustRun("--config", developConfig, "merge", "--dry-run")
+	AssertContains(t, stdout, "Target: develop (from config default_merge_target)")
+
+	// The success line keeps its plain form for scripts that parse it
+	stdout = cWt.MustRun("--config", developConfig, "merge", "--keep")
+	if !strings.HasPrefix(stdout, "Merged feature into develop\n") {
+		t.Errorf("expected a plain Merged line, got %q", stdout)
+	}
+
+	if !gitBranchContainsFile(t, c.Dir, "develop", "feature.txt") {
+		t.Error("feature.txt should be merged into the configured default_merge_target branch")
+	}
+}
+
+// createRemoteTrackingBranch makes origin/<branch> a remote-tracking branch
+// one commit (adding filename) ahead of master, without a local branch.
+func createRemoteTrackingBranch(t *testing.T, repoDir, branch, filename string) {
+	t.Helper()
+
+	createBranchWithoutWorktree(t, repoDir, branch, filename)
+
+	for _, args := range [][]string{
+		{"remote", "add", "origin", repoDir},
+		{"update-ref", "refs/remotes/origin/" + branch, branch},
+		{"branch", "-D", branch},
+	} {
+		out, err := testGitCmd(append([]string{"-C", repoDir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func Test_Merge_Into_Remote_Tracking_Branch_Creates_Local_Branch(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+	createRemoteTrackingBranch(t, c.Dir, "release", "release.txt")
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "feature-branch"))
+	gitCommitInDir(t, wtPath, "feature.txt", "feature content", "Add feature")
+
+	wtCli := NewCLITesterAt(t, wtPath)
+
+	stdout := wtCli.MustRun("--config", "../config.json", "merge", "--into", "origin/release", "--dry-run")
+
+	AssertContains(t, stdout, "Dry run: wt merge feature-branch → release")
+	AssertContains(t, stdout, "Remote-tracking branch 'origin/release' exists (no local branch 'release' yet)")
+	AssertContains(t, stdout, "1. Create local branch 'release' from 'origin/release'")
+	AssertContains(t, stdout, "2. Rebase 'feature-branch' onto 'release' (1 commit to replay)")
+
+	if slices.Contains(listBranches(t, c.Dir), "release") {
+		t.Fatal("dry run should not create the local branch")
+	}
+
+	stdout = wtCli.MustRun("--config", "../config.json", "merge", "--into", "origin/release")
+
+	AssertContains(t, stdout, "Created branch 'release' from 'origin/release'")
+	AssertContains(t, stdout, "Merged feature-branch into release")
+
+	for _, file := range []string{"release.txt", "feature.txt"} {
+		if !gitBranchContainsFile(t, c.Dir, "release", file) {
+			t.Errorf("%s should be on release after merge", file)
+		}
+	}
+
+	out, err := testGitCmd("-C", c.Dir, "rev-parse", "--abbrev-ref", "release@{upstream}").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "origin/release" {
+		t.Errorf("expected release to track origin/release, got %q (%v)", out, err)
+	}
+}
+
+func Test_Merge_Into_Remote_Tracking_Branch_Fast_Forwards_Local_Branch(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+	createRemoteTrackingBranch(t, c.Dir, "release", "release.txt")
+
+	// Local release is behind origin/release
+	createBranch(t, c.Dir, "release")
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "feature-branch"))
+	gitCommitInDir(t, wtPath, "feature.txt", "feature content", "Add feature")
+
+	wtCli := NewCLITesterAt(t, wtPath)
+
+	stdout := wtCli.MustRun("--config", "../config.json", "merge", "--into", "origin/release", "--dry-run")
+
+	AssertContains(t, stdout, "Target branch 'release' exists and can fast-forward to 'origin/release'")
+	AssertContains(t, stdout, "1. Fast-forward 'release' to 'origin/release' (1 commit behind)")
+	AssertNotContains(t, stdout, "Create local branch")
+
+	stdout = wtCli.MustRun("--config", "../config.json", "merge", "--into", "origin/release")
+
+	AssertContains(t, stdout, "Fast-forwarded 'release' to 'origin/release'")
+	AssertContains(t, stdout, "Merged feature-branch into release")
+
+	for _, file := range []string{"release.txt", "feature.txt"} {
+		if !gitBranchContainsFile(t, c.Dir, "release", file) {
+			t.Errorf("%s should be on release after merge", file)
+		}
+	}
+}
+
+// reflogSubjects returns the messages of branch's reflog, newest first.
+func reflogSubjects(t *testing.T, dir, branch string) []string {
+	t.Helper()
+
+	out, err := testGitCmd("-C", dir, "reflog", "show", "--format=%gs", "refs/heads/"+branch).Output()
+	if err != nil {
+		t.Fatalf("git reflog failed: %v", err)
+	}
+
+	return strings.Split(strings.TrimSpace(string(out)), "\n")
+}
+
+func Test_Merge_Writes_Wt_Merge_To_Target_Reflog(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+	createBranch(t, c.Dir, "develop")
+
+	// master is checked out in the main repo, develop nowhere
+	for _, target := range []string{"master", "develop"} {
+		name := "feature-" + target
+
+		wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", name, "--from-branch", target))
+		gitCommitInDir(t, wtPath, name+".txt", "content", "Add "+name)
+
+		NewCLITesterAt(t, wtPath).MustRun("--config", "../config.json", "merge", "--into", target)
+
+		reflog := reflogSubjects(t, c.Dir, target)
+		if !strings.HasPrefix(reflog[0], "wt merge "+name) {
+			t.Errorf("%s: expected newest reflog entry from wt merge %s, got %q", target, name, reflog)
+		}
+	}
+
+	createBranchWithoutWorktree(t, c.Dir, "leftover", "leftover.txt")
+	c.MustRun("--config", "config.json", "merge", "--branch", "leftover", "--into", "develop")
+
+	if reflog := reflogSubjects(t, c.Dir, "develop"); reflog[0] != "wt merge --branch leftover" {
+		t.Errorf("expected newest develop reflog entry %q, got %q", "wt merge --branch leftover", reflog)
+	}
+}
+
+func Test_Merge_Into_Remote_Tracking_Branch_Rejects_Diverged_Local_Branch(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+	createRemoteTrackingBranch(t, c.Dir, "release", "release.txt")
+	createBranchWithoutWorktree(t, c.Dir, "release", "local.txt")
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "feature-branch"))
+	gitCommitInDir(t, wtPath, "feature.txt", "feature content", "Add feature")
+
+	_, stderr, code := NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge", "--into", "origin/release")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "local branch 'release' has diverged from 'origin/release'")
+
+	if gitBranchContainsFile(t, c.Dir, "release", "feature.txt") {
+		t.Error("release should be unchanged")
+	}
+}
+
+func Test_Merge_Into_Tag_Reports_Not_A_Branch(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	out, err := testGitCmd("-C", c.Dir, "tag", "v1.0").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git tag failed: %v\n%s", err, out)
+	}
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "feature-branch"))
+
+	_, stderr, code := NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge", "--into", "v1.0")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "'v1.0' is not a branch")
+}
+
+func Test_Merge_Branch_Into_Remote_Tracking_Branch_Creates_Local_Branch(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+	createRemoteTrackingBranch(t, c.Dir, "release", "release.txt")
+	createBranchWithoutWorktree(t, c.Dir, "orphan", "orphan.txt")
+
+	stdout := c.MustRun("--config", "config.json", "merge", "--branch", "orphan", "--into", "origin/release", "--dry-run")
+
+	AssertContains(t, stdout, "1. Create local branch 'release' from 'origin/release'")
+	AssertContains(t, stdout, "2. Rebase 'orphan' onto 'release'")
+
+	stdout = c.MustRun("--config", "config.json", "merge", "--branch", "orphan", "--into", "origin/release")
+
+	AssertContains(t, stdout, "Created branch 'release' from 'origin/release'")
+	AssertContains(t, stdout, "Merged orphan into release")
+
+	if !gitBranchContainsFile(t, c.Dir, "release", "orphan.txt") {
+		t.Error("orphan.txt should be on release after merge")
+	}
+}
+
+func Test_Merge_Branch_Into_Remote_Tracking_Branch_Deletes_Created_Branch_On_Conflict(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+	createRemoteTrackingBranch(t, c.Dir, "release", "conflict.txt")
+
+	out, err := testGitCmd("-C", c.Dir, "checkout", "-q", "-b", "orphan").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git checkout -b failed: %v\n%s", err, out)
+	}
+
+	gitCommitInDir(t, c.Dir, "conflict.txt", "orphan content", "Add conflicting file")
+
+	out, err = testGitCmd("-C", c.Dir, "checkout", "-q", "master").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git checkout master failed: %v\n%s", err, out)
+	}
+
+	stdout, stderr, code := c.Run("--config", "config.json", "merge", "--branch", "orphan", "--into", "origin/release")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+
+	AssertContains(t, stdout, "Created branch 'release' from 'origin/release'")
+	AssertContains(t, stderr, "conflict during rebase")
+	AssertContains(t, stderr, "Deleted branch 'release' again (created from 'origin/release')")
+
+	if slices.Contains(listBranches(t, c.Dir), "release") {
+		t.Error("local release branch created for the merge should be deleted again")
+	}
+}
+
+// gitOutput runs git in dir and returns its trimmed stdout.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	out, err := testGitCmd(append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		t.Fatalf("git %v failed: %v", args, err)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+func Test_Merge_Merge_Commit_Creates_Merge_Commit_Without_Rebase(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+	createBranch(t, c.Dir, "develop")
+
+	// master is checked out in the main repo, develop nowhere
+	for _, target := range []string{"master", "develop"} {
+		name := "feature-" + target
+
+		wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", name, "--from-branch", target))
+		gitCommitInDir(t, wtPath, name+".txt", "content", "Add "+name)
+		featureSHA := gitOutput(t, wtPath, "rev-parse", "HEAD")
+
+		// Move the target on, so a fast-forward is impossible
+		moveTarget := []string{"commit", "-q", "--allow-empty", "-m", "Target moved"}
+		if target == "develop" {
+			moveTarget = []string{"branch", "-f", "develop", "master"}
+		}
+
+		gitOutput(t, c.Dir, moveTarget...)
+		oldTarget := gitOutput(t, c.Dir, "rev-parse", target)
+
+		args := []string{"--config", "../config.json", "merge", "--into", target, "--merge-commit", "--keep"}
+		if target == "develop" {
+			args = append(args, "--message", "Integrate feature")
+		}
+
+		stdout := NewCLITesterAt(t, wtPath).MustRun(args...)
+		AssertContains(t, stdout, "Merged "+name+" into "+target)
+
+		parents := strings.Fields(gitOutput(t, c.Dir, "rev-list", "--parents", "-n", "1", target))
+		if len(parents) != 3 || parents[1] != oldTarget || parents[2] != featureSHA {
+			t.Errorf("%s: expected merge commit with parents %s %s, got %v", target, oldTarget, featureSHA, parents)
+		}
+
+		wantMessage := "Merge worktree " + name + " into " + target
+		if target == "develop" {
+			wantMessage = "Integrate feature"
+		}
+
+		if subject := gitOutput(t, c.Dir, "log", "-1", "--format=%s", target); subject != wantMessage {
+			t.Errorf("%s: merge commit message = %q, want %q", target, subject, wantMessage)
+		}
+
+		if head := gitOutput(t, wtPath, "rev-parse", "HEAD"); head != featureSHA {
+			t.Errorf("%s: feature branch should not be rebased, HEAD moved to %s", target, head)
+		}
+
+		if !gitBranchContainsFile(t, c.Dir, target, name+".txt") {
+			t.Errorf("%s: should contain %s.txt after merge", target, name)
+		}
+	}
+}
+
+func Test_Merge_Merge_Commit_Conflict_Aborts_And_Leaves_Target_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+	createBranch(t, c.Dir, "develop")
+
+	for _, target := range []string{"master", "develop"} {
+		name := "clash-" + target
+
+		wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", name, "--from-branch", target))
+		gitCommitInDir(t, wtPath, "shared.txt", "from the worktree", "Worktree change")
+
+		// Commit a conflicting change to the target
+		if target == "master" {
+			gitCommitInDir(t, c.Dir, "shared.txt", "from "+target, "Target change")
+		} else {
+			tmpPath := filepath.Join(t.TempDir(), "develop")
+
+			out, err := testGitCmd("-C", c.Dir, "worktree", "add", "-q", tmpPath, "develop").CombinedOutput()
+			if err != nil {
+				t.Fatalf("git worktree add failed: %v\n%s", err, out)
+			}
+
+			gitCommitInDir(t, tmpPath, "shared.txt", "from "+target, "Target change")
+
+			out, err = testGitCmd("-C", c.Dir, "worktree", "remove", tmpPath).CombinedOutput()
+			if err != nil {
+				t.Fatalf("git worktree remove failed: %v\n%s", err, out)
+			}
+		}
+
+		before := gitOutput(t, c.Dir, "rev-parse", target)
+
+		_, stderr, code := NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge", "--into", target, "--merge-commit")
+		if code != 1 {
+			t.Fatalf("%s: expected exit code 1, got %d", target, code)
+		}
+
+		AssertContains(t, stderr, "conflict during merge in shared.txt")
+		AssertContains(t, stderr, "is unchanged")
+
+		if after := gitOutput(t, c.Dir, "rev-parse", target); after != before {
+			t.Errorf("%s: target moved from %s to %s", target, before, after)
+		}
+
+		if status := gitOutput(t, c.Dir, "status", "--porcelain", "--untracked-files=no"); status != "" {
+			t.Errorf("%s: main worktree should be unmodified, got status %q", target, status)
+		}
+
+		if !c.FileExists(filepath.Join("worktrees", name, ".wt", "worktree.json")) {
+			t.Errorf("%s: worktree should be kept after a conflict", target)
+		}
+	}
+}
+
+func Test_Merge_Merge_Commit_Dry_Run_And_Flag_Validation(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "planned"))
+	gitCommitInDir(t, wtPath, "planned.txt", "content", "Add planned")
+
+	c2 := NewCLITesterAt(t, wtPath)
+
+	stdout := c2.MustRun("--config", "../config.json", "merge", "--no-rebase", "--dry-run")
+
+	AssertContains(t, stdout, "Merge commit: merge 'planned' into 'master' with --no-ff (1 commit, in "+c.Dir+")")
+	AssertContains(t, stdout, "Message: Merge worktree planned into master")
+	AssertNotContains(t, stdout, "Rebase")
+	AssertNotContains(t, stdout, "Fast-forward")
+
+	_, stderr, code := c2.Run("--config", "../config.json", "merge", "--message", "text")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "--message requires --merge-commit")
+}
+
+func Test_Merge_Squash_Creates_Single_Commit_On_Target(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+	createBranch(t, c.Dir, "develop")
+
+	// master is checked out in the main repo, develop nowhere
+	for _, target := range []string{"master", "develop"} {
+		name := "squashed-" + target
+
+		wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", name, "--from-branch", target))
+		gitCommitInDir(t, wtPath, name+"-1.txt", "one", "First change")
+		gitCommitInDir(t, wtPath, name+"-2.txt", "two", "Second change")
+		featureSHA := gitOutput(t, wtPath, "rev-parse", "HEAD")
+		oldTarget := gitOutput(t, c.Dir, "rev-parse", target)
+
+		args := []string{"--config", "../config.json", "merge", "--into", target, "--squash", "--keep"}
+		if target == "develop" {
+			args = append(args, "-m", "Squashed feature")
+		}
+
+		stdout := NewCLITesterAt(t, wtPath).MustRun(args...)
+		AssertContains(t, stdout, "Merged "+name+" into "+target)
+
+		parents := strings.Fields(gitOutput(t, c.Dir, "rev-list", "--parents", "-n", "1", target))
+		if len(parents) != 2 || parents[1] != oldTarget {
+			t.Errorf("%s: expected one commit on top of %s, got %v", target, oldTarget, parents)
+		}
+
+		wantMessage := name + "\n\n* First change\n* Second change"
+		if target == "develop" {
+			wantMessage = "Squashed feature"
+		}
+
+		if message := gitOutput(t, c.Dir, "log", "-1", "--format=%B", target); message != wantMessage {
+			t.Errorf("%s: commit message = %q, want %q", target, message, wantMessage)
+		}
+
+		if head := gitOutput(t, wtPath, "rev-parse", "HEAD"); head != featureSHA {
+			t.Errorf("%s: feature branch should be unchanged, HEAD moved to %s", target, head)
+		}
+
+		for _, file := range []string{name + "-1.txt", name + "-2.txt"} {
+			if !gitBranchContainsFile(t, c.Dir, target, file) {
+				t.Errorf("%s: should contain %s after squash", target, file)
+			}
+		}
+	}
+
+	if status := gitOutput(t, c.Dir, "status", "--porcelain", "--untracked-files=no"); status != "" {
+		t.Errorf("main worktree should be clean after squash, got status %q", status)
+	}
+}
+
+func Test_Merge_Squash_Trailer_Lists_Squashed_Commits(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+	c.WriteFile("config.json", `{"base": "worktrees", "merge_message_trailer": true}`)
+	gitCommitFile(t, c.Dir, "config.json")
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "traced"))
+	gitCommitInDir(t, wtPath, "one.txt", "one", "First change")
+	first := gitOutput(t, wtPath, "rev-parse", "HEAD")
+	gitCommitInDir(t, wtPath, "two.txt", "two", "Second change")
+	second := gitOutput(t, wtPath, "rev-parse", "HEAD")
+
+	NewCLITesterAt(t, wtPath).MustRun("--config", "config.json", "merge", "--squash")
+
+	want := "traced\n\n* First change\n* Second change\n\nSquashed-from: " + first + "\nSquashed-from: " + second
+	if message := gitOutput(t, c.Dir, "log", "-1", "--format=%B", "master"); message != want {
+		t.Errorf("commit message = %q, want %q", message, want)
+	}
+
+	// git parses it as a trailer block
+	trailers := gitOutput(t, c.Dir, "log", "-1", "--format=%(trailers:key=Squashed-from,valueonly)", "master")
+	if trailers != first+"\n"+second {
+		t.Errorf("expected Squashed-from trailers %s and %s, got %q", first, second, trailers)
+	}
+
+	if slices.Contains(listBranches(t, c.Dir), "traced") {
+		t.Error("branch traced should be deleted after the merge")
+	}
+
+	// A merge commit gets a Merged-from trailer per merged commit
+	wtPath = extractPath(c.MustRun("--config", "config.json", "create", "--name", "joined"))
+	gitCommitInDir(t, wtPath, "three.txt", "three", "Third change")
+	third := gitOutput(t, wtPath, "rev-parse", "HEAD")
+
+	NewCLITesterAt(t, wtPath).MustRun("--config", "config.json", "merge", "--merge-commit")
+
+	want = "Merge worktree joined into master\n\nMerged-from: " + third
+	if message := gitOutput(t, c.Dir, "log", "-1", "--format=%B", "master"); message != want {
+		t.Errorf("commit message = %q, want %q", message, want)
+	}
+
+	trailers = gitOutput(t, c.Dir, "log", "-1", "--format=%(trailers:key=Merged-from,valueonly)", "master")
+	if trailers != third {
+		t.Errorf("expected Merged-from trailer %s, got %q", third, trailers)
+	}
+
+	// --no-trailer leaves it out, also with --message
+	wtPath = extractPath(c.MustRun("--config", "config.json", "create", "--name", "untraced"))
+	gitCommitInDir(t, wtPath, "four.txt", "four", "Fourth change")
+
+	NewCLITesterAt(t, wtPath).MustRun("--config", "config.json", "merge", "--squash", "--no-trailer", "-m", "Untraced")
+
+	if message := gitOutput(t, c.Dir, "log", "-1", "--format=%B", "master"); message != "Untraced" {
+		t.Errorf("commit message = %q, want %q", message, "Untraced")
+	}
+}
+
+func Test_Merge_Squash_Conflict_Leaves_Target_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "clash"))
+	gitCommitInDir(t, wtPath, "shared.txt", "from the worktree", "Worktree change")
+	gitCommitInDir(t, c.Dir, "shared.txt", "from master", "Target change")
+
+	before := gitOutput(t, c.Dir, "rev-parse", "master")
+
+	_, stderr, code := NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge", "--squash")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "conflict during merge in shared.txt")
+	AssertContains(t, stderr, "Run wt merge --squash again")
+
+	if after := gitOutput(t, c.Dir, "rev-parse", "master"); after != before {
+		t.Errorf("target moved from %s to %s", before, after)
+	}
+
+	if !c.FileExists(filepath.Join("worktrees", "clash", ".wt", "worktree.json")) {
+		t.Error("worktree should be kept after a conflict")
+	}
+}
+
+func Test_Merge_Squash_Dry_Run_And_Strategy_Exclusivity(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "planned"))
+	gitCommitInDir(t, wtPath, "a.txt", "a", "Add a")
+	gitCommitInDir(t, wtPath, "b.txt", "b", "Add b")
+
+	c2 := NewCLITesterAt(t, wtPath)
+
+	stdout := c2.MustRun("--config", "../config.json", "merge", "--squash", "--dry-run")
+
+	AssertContains(t, stdout, "Squash 2 commits of 'planned' into one commit on 'master' (in "+c.Dir+")")
+	AssertContains(t, stdout, "Message: planned\n")
+	AssertContains(t, stdout, "* Add a\n")
+	AssertContains(t, stdout, "* Add b\n")
+	AssertNotContains(t, stdout, "Rebase")
+
+	for _, pair := range [][]string{{"--squash", "--merge-commit"}, {"--rebase", "--squash"}, {"--rebase", "--no-rebase"}} {
+		_, stderr, code := c2.Run(append([]string{"--config", "../config.json", "merge", "--dry-run"}, pair...)...)
+		if code != 1 {
+			t.Errorf("%v: expected exit code 1, got %d", pair, code)
+		}
+
+		AssertContains(t, stderr, "cannot combine merge strategies")
+	}
+}
+
+func Test_Merge_Squash_Author_Sets_Commit_Author(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "attributed"))
+	gitCommitInDir(t, wtPath, "attributed.txt", "content", "Add attributed")
+
+	committer := gitOutput(t, c.Dir, "log", "-1", "--format=%cn <%ce>", "master")
+
+	c2 := NewCLITesterAt(t, wtPath)
+
+	for _, args := range [][]string{
+		{"--author", "Jane Agent"},
+		{"--author", "<jane@example.com>"},
+		{"--squash", "--author", "Jane Agent <jane>"},
+	} {
+		_, stderr, code := c2.Run(append([]string{"--config", "../config.json", "merge", "--squash"}, args...)...)
+		if code != 1 {
+			t.Errorf("%v: expected exit code 1, got %d", args, code)
+		}
+
+		AssertContains(t, stderr, "invalid --author")
+	}
+
+	_, stderr, code := c2.Run("--config", "../config.json", "merge", "--author", "Jane Agent <jane@example.com>")
+	if code != 1 {
+		t.Errorf("expected exit code 1 without --squash, got %d", code)
+	}
+
+	AssertContains(t, stderr, "--author requires --merge-commit or --squash")
+
+	c2.MustRun("--config", "../config.json", "merge", "--squash", "--author", "Jane Agent <jane@example.com>")
+
+	if author := gitOutput(t, c.Dir, "log", "-1", "--format=%an <%ae>", "master"); author != "Jane Agent <jane@example.com>" {
+		t.Errorf("author = %q, want Jane Agent <jane@example.com>", author)
+	}
+
+	if got := gitOutput(t, c.Dir, "log", "-1", "--format=%cn <%ce>", "master"); got != committer {
+		t.Errorf("committer = %q, want the configured identity %q", got, committer)
+	}
+}
+
+func Test_Merge_Porcelain_Prints_Key_Value_Lines_Only(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "scripted"))
+	gitCommitInDir(t, wtPath, "scripted.txt", "content", "Add scripted")
+
+	c2 := NewCLITesterAt(t, wtPath)
+
+	for _, conflict := range []string{"--dry-run", "--abort"} {
+		_, stderr, code := c2.Run("--config", "../config.json", "merge", "--porcelain", conflict)
+		if code != 1 {
+			t.Errorf("%s: expected exit code 1, got %d", conflict, code)
+		}
+
+		AssertContains(t, stderr, "cannot use --porcelain with "+conflict)
+	}
+
+	stdout, stderr, code := c2.Run("--config", "../config.json", "merge", "--squash", "--porcelain")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	want := strings.Join([]string{
+		"status\tmerged",
+		"name\tscripted",
+		"path\t" + wtPath,
+		"branch\tscripted",
+		"target\tmaster",
+		"strategy\tsquash",
+		"removed\ttrue",
+	}, "\n") + "\n"
+
+	if stdout != want {
+		t.Errorf("stdout =\n%s\nwant\n%s", stdout, want)
+	}
+
+	AssertContains(t, stderr, "Merged scripted into master")
+	AssertContains(t, stderr, "Removed worktree: "+wtPath)
+}
+
+// addBareOrigin adds a new bare repository as the origin remote of repoDir
+// and returns its path.
+func addBareOrigin(t *testing.T, repoDir string) string {
+	t.Helper()
+
+	remotePath := t.TempDir()
+
+	for _, args := range [][]string{
+		{"init", "--bare", "--quiet", remotePath},
+		{"-C", repoDir, "remote", "add", "origin", remotePath},
+	} {
+		out, err := testGitCmd(args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	return remotePath
+}
+
+func Test_Merge_Push_Pushes_Target_After_Merge(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+	remotePath := addBareOrigin(t, c.Dir)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "feature"))
+	gitCommitInDir(t, wtPath, "feature.txt", "feature content", "Add feature")
+
+	wtCli := NewCLITesterAt(t, wtPath)
+
+	stdout := wtCli.MustRun("--config", "../config.json", "merge", "--push", "--dry-run")
+	AssertContains(t, stdout, "3. Push 'master' to origin")
+	AssertContains(t, stdout, "4. Run pre-delete hooks")
+
+	_, stderr, code := wtCli.Run("--config", "../config.json", "merge", "--remote", "origin")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for --remote without --push, got %d", code)
+	}
+
+	AssertContains(t, stderr, "--remote requires --push")
+
+	_, stderr, code = wtCli.Run("--config", "../config.json", "merge", "--push", "--remote", "upstream")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for a missing remote, got %d", code)
+	}
+
+	AssertContains(t, stderr, "push remote does not exist: 'upstream'")
+
+	if gitBranchContainsFile(t, c.Dir, "master", "feature.txt") {
+		t.Fatal("nothing should be merged when the push remote is missing")
+	}
+
+	stdout = wtCli.MustRun("--config", "../config.json", "merge", "--push")
+	AssertContains(t, stdout, "Merged feature into master")
+	AssertContains(t, stdout, "Pushed master to origin")
+
+	if !gitBranchContainsFile(t, remotePath, "master", "feature.txt") {
+		t.Error("feature.txt should be on the remote's master after --push")
+	}
+}
+
+func Test_Merge_Push_Failure_Keeps_Merge(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRepoWithConfig(t, c)
+	remotePath := addBareOrigin(t, c.Dir)
+
+	rejectHook := filepath.Join(remotePath, "hooks", "pre-receive")
+
+	err := os.WriteFile(rejectHook, []byte("#!/bin/sh\necho rejected by test >&2\nexit 1\n"), 0o755)
+	if err != nil {
+		t.Fatalf("writing pre-receive hook: %v", err)
+	}
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "feature"))
+	gitCommitInDir(t, wtPath, "feature.txt", "feature content", "Add feature")
+
+	wtCli := NewCLITesterAt(t, wtPath)
+
+	stdout, stderr, code := wtCli.Run("--config", "../config.json", "merge", "--push", "--keep")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 for a rejected push, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Merged feature into master")
+	AssertContains(t, stderr, "merge succeeded, but pushing the target branch failed")
+	AssertContains(t, stderr, "git push origin master")
+	AssertContains(t, stderr, "rejected by test")
+
+	if !gitBranchContainsFile(t, c.Dir, "master", "feature.txt") {
+		t.Error("the local merge should be kept when the push fails")
+	}
+}
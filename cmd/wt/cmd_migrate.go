@@ -0,0 +1,350 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// Errors for migrate command.
+var (
+	errMigrateToRequired      = errors.New("--to or --metadata is required (usage: wt migrate --to <new-base> or wt migrate --metadata)")
+	errMigrateModeConflict    = errors.New("cannot use --metadata with --to")
+	errMigrateFromWorktree    = errors.New("cannot migrate from inside a worktree (run from the main repository)")
+	errMigrateSameBase        = errors.New("worktrees are already in this base directory")
+	errMigrateDestinationUsed = errors.New("destination already exists")
+	errMigrateRolledBack      = errors.New("migrate failed, moved worktrees were moved back")
+)
+
+// MigrateCmd returns the migrate command.
+func MigrateCmd(cfg Config, fsys fs.FS, git *Git) *Command {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.BoolP("help", "h", false, "Show help")
+	flags.String("to", "", "New base directory (same format as the base config key)")
+	flags.Bool("metadata", false, "Rewrite older .wt/worktree.json files in the current schema version instead")
+	flags.Bool("dry-run", false, "Only print what would be moved or rewritten")
+
+	return &Command{
+		Flags: flags,
+		Usage: "migrate (--to <new-base> | --metadata) [flags]",
+		Short: "Move all worktrees to a new base directory, or upgrade their metadata",
+		Long: `Move every wt-managed worktree of this repository from the current base
+directory to a new one, then set base in the project config.
+
+Each worktree is moved with 'git worktree move', so git keeps tracking it
+at the new path (locked worktrees are moved too). Like the base config key,
+a relative --to is resolved against the repository root, and an absolute
+one (or ~/...) gets a subdirectory named after the repository.
+
+The new base is written to .wt/config.json in the repository root, like
+'wt config set base'. A config file passed with --config is not changed.
+
+Nothing is moved if any destination already exists. If a move fails, the
+worktrees moved so far are moved back.
+
+With --metadata, nothing is moved: the .wt/worktree.json of each worktree
+whose schema_version is older than the one this wt writes (or missing, in
+files from before schema_version existed) is rewritten in the current
+version. Files of a newer version are left alone with a warning. Other
+commands already read older files; this only makes the files current.
+
+Examples:
+  wt migrate --to ~/code/worktrees --dry-run
+  wt migrate --to .worktrees
+  wt migrate --metadata`,
+		Exec: func(ctx context.Context, _ io.Reader, stdout, stderr io.Writer, _ []string) error {
+			to, _ := flags.GetString("to")
+			dryRun, _ := flags.GetBool("dry-run")
+
+			if !dryRun {
+				stdout = informational(cfg, stdout)
+			}
+
+			if metadata, _ := flags.GetBool("metadata"); metadata {
+				if flags.Changed("to") {
+					return errMigrateModeConflict
+				}
+
+				return execMigrateMetadata(ctx, stdout, stderr, cfg, fsys, git, dryRun)
+			}
+
+			if !flags.Changed("to") {
+				return errMigrateToRequired
+			}
+
+			return execMigrate(ctx, stdout, stderr, cfg, fsys, git, to, dryRun)
+		},
+	}
+}
+
+// migrateMove is one planned worktree move.
+type migrateMove struct {
+	from   string
+	to     string
+	locked bool
+}
+
+func execMigrate(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	to string,
+	dryRun bool,
+) error {
+	value, err := parseConfigValue("base", to)
+	if err != nil {
+		return err
+	}
+
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	// Moving the worktree the shell is in would leave it in a deleted directory
+	if _, findErr := findWorktreeRoot(fsys, cfg.EffectiveCwd); findErr == nil {
+		return errMigrateFromWorktree
+	}
+
+	oldBaseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	newCfg := cfg
+	newCfg.Base = to
+
+	newBaseDir, err := resolveWorktreeBaseDir(newCfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	if isSamePath(oldBaseDir, newBaseDir) || filepath.Clean(oldBaseDir) == filepath.Clean(newBaseDir) {
+		return fmt.Errorf("%w: %s", errMigrateSameBase, newBaseDir)
+	}
+
+	// Hold the create lock so no worktree is created in the old base meanwhile
+	if !dryRun {
+		gitCommonDir, dirErr := git.GitCommonDir(ctx, mainRepoRoot)
+		if dirErr != nil {
+			return fmt.Errorf("cannot determine git directory: %w", dirErr)
+		}
+
+		lock, lockErr := acquireCreateLock(ctx, fsys, gitCommonDir, cfg.LockTimeout)
+		if lockErr != nil {
+			return lockErr
+		}
+
+		defer func() { _ = lock.Close() }()
+	}
+
+	moves, err := planMigrate(ctx, stderr, fsys, git, mainRepoRoot, oldBaseDir, newBaseDir)
+	if err != nil {
+		return err
+	}
+
+	configPath := filepath.Join(mainRepoRoot, ".wt", "config.json")
+
+	if dryRun {
+		for _, move := range moves {
+			fprintf(stdout, "Would move %s -> %s\n", move.from, move.to)
+		}
+
+		fprintf(stdout, "Would set base = %s in %s\n", to, configPath)
+
+		return nil
+	}
+
+	err = runMigrate(ctx, fsys, git, mainRepoRoot, newBaseDir, moves, func() error {
+		return setConfigKey(fsys, configPath, "base", value)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, move := range moves {
+		fprintf(stdout, "Moved %s -> %s\n", move.from, move.to)
+	}
+
+	if len(moves) == 0 {
+		fprintln(stdout, "No worktrees to move")
+	}
+
+	fprintf(stdout, "Set base = %s in %s\n", to, configPath)
+
+	return nil
+}
+
+// execMigrateMetadata rewrites the metadata of every worktree stored in an
+// older schema_version, under the create lock so no other wt command
+// rewrites it meanwhile.
+func execMigrateMetadata(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	dryRun bool,
+) error {
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		gitCommonDir, dirErr := git.GitCommonDir(ctx, mainRepoRoot)
+		if dirErr != nil {
+			return fmt.Errorf("cannot determine git directory: %w", dirErr)
+		}
+
+		lock, lockErr := acquireCreateLock(ctx, fsys, gitCommonDir, cfg.LockTimeout)
+		if lockErr != nil {
+			return lockErr
+		}
+
+		defer func() { _ = lock.Close() }()
+	}
+
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("scanning worktrees: %w", err)
+	}
+
+	upgraded := 0
+
+	for _, wt := range worktrees {
+		if warning := schemaWarning(&wt.WorktreeInfo, wt.Path); warning != "" {
+			fprintln(stderr, warning+" (not rewritten)")
+
+			continue
+		}
+
+		version, versionErr := storedSchemaVersion(fsys, wt.Path)
+		if versionErr != nil {
+			return versionErr
+		}
+
+		if version == worktreeSchemaVersion {
+			continue
+		}
+
+		upgraded++
+
+		if dryRun {
+			fprintf(stdout, "Would upgrade %s (schema_version %d -> %d)\n", wt.Path, version, worktreeSchemaVersion)
+
+			continue
+		}
+
+		err = writeWorktreeInfo(fsys, wt.Path, &wt.WorktreeInfo)
+		if err != nil {
+			return err
+		}
+
+		fprintf(stdout, "Upgraded %s (schema_version %d -> %d)\n", wt.Path, version, worktreeSchemaVersion)
+	}
+
+	if upgraded == 0 {
+		fprintf(stdout, "All worktree metadata is at schema_version %d\n", worktreeSchemaVersion)
+	}
+
+	return nil
+}
+
+// planMigrate lists the moves from oldBaseDir to newBaseDir. Directories git
+// does not know as worktrees are skipped with a warning (see wt prune); an
+// existing destination fails the whole migrate before anything is moved.
+func planMigrate(
+	ctx context.Context,
+	stderr io.Writer,
+	fsys fs.FS,
+	git *Git,
+	mainRepoRoot, oldBaseDir, newBaseDir string,
+) ([]migrateMove, error) {
+	worktrees, err := findWorktreesWithPaths(fsys, oldBaseDir)
+	if err != nil {
+		return nil, fmt.Errorf("scanning worktrees: %w", err)
+	}
+
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	gitIndex := newGitWorktreeIndex(entries)
+	moves := make([]migrateMove, 0, len(worktrees))
+
+	for _, wt := range worktrees {
+		entry, ok := gitIndex.lookup(wt.Path)
+		if !ok {
+			fprintf(stderr, "warning: skipping %s: not a git worktree (see wt prune)\n", wt.Path)
+
+			continue
+		}
+
+		dst := filepath.Join(newBaseDir, filepath.Base(wt.Path))
+
+		if _, statErr := fsys.Stat(dst); statErr == nil {
+			return nil, fmt.Errorf("%w: %s (moving %s)", errMigrateDestinationUsed, dst, wt.Name)
+		}
+
+		moves = append(moves, migrateMove{from: wt.Path, to: dst, locked: entry.Locked})
+	}
+
+	return moves, nil
+}
+
+// runMigrate performs moves, then finish (the config update). If a move or
+// finish fails, the worktrees already moved are moved back.
+func runMigrate(
+	ctx context.Context,
+	fsys fs.FS,
+	git *Git,
+	mainRepoRoot, newBaseDir string,
+	moves []migrateMove,
+	finish func() error,
+) error {
+	err := fsys.MkdirAll(newBaseDir, 0o750)
+	if err != nil {
+		return fmt.Errorf("cannot create base directory: %w", err)
+	}
+
+	var done []migrateMove
+
+	for _, move := range moves {
+		err = git.WorktreeMove(ctx, mainRepoRoot, move.from, move.to, move.locked)
+		if err != nil {
+			break
+		}
+
+		done = append(done, move)
+	}
+
+	if err == nil {
+		err = finish()
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	// Undo in reverse order; use a fresh context so an interrupt still rolls back
+	for _, move := range slices.Backward(done) {
+		_ = git.WorktreeMove(context.WithoutCancel(ctx), mainRepoRoot, move.to, move.from, move.locked)
+	}
+
+	return fmt.Errorf("%w: %w", errMigrateRolledBack, err)
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Migrate_Moves_Worktrees_From_Relative_To_Absolute_Base(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile(".wt/config.json", `{"base": "worktrees"}`)
+
+	oldAlpha := extractPath(c.MustRun("create", "--name", "alpha"))
+	oldBeta := extractPath(c.MustRun("create", "--name", "beta", "--lock"))
+
+	newBase := t.TempDir()
+	newBaseDir := filepath.Join(newBase, getRepoName(c.Dir))
+
+	stdout, stderr, code := c.Run("migrate", "--to", newBase)
+	if code != 0 {
+		t.Fatalf("migrate failed (code %d)\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+
+	AssertContains(t, stdout, "Moved "+oldAlpha+" -> "+filepath.Join(newBaseDir, "alpha"))
+	AssertContains(t, stdout, "Set base = "+newBase)
+
+	for _, name := range []string{"alpha", "beta"} {
+		if !c.FileExistsAt(newBaseDir, name+"/.wt/worktree.json") {
+			t.Errorf("%s metadata not found in new base", name)
+		}
+	}
+
+	if c.FileExists("worktrees/alpha") || c.FileExists("worktrees/beta") {
+		t.Errorf("old worktree directories still exist: %s, %s", oldAlpha, oldBeta)
+	}
+
+	// git tracks the new paths, and wt (now reading the new base) finds them
+	out, err := testGitCmd("-C", c.Dir, "worktree", "list", "--porcelain").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git worktree list failed: %v\n%s", err, out)
+	}
+
+	AssertContains(t, string(out), filepath.Join(newBaseDir, "alpha"))
+	AssertContains(t, string(out), filepath.Join(newBaseDir, "beta")+"\nHEAD")
+	AssertContains(t, string(out), "locked")
+
+	var config map[string]any
+
+	err = json.Unmarshal([]byte(c.ReadFile(".wt/config.json")), &config)
+	if err != nil {
+		t.Fatalf("parsing config: %v", err)
+	}
+
+	if config["base"] != newBase {
+		t.Errorf("config base = %v, want %s", config["base"], newBase)
+	}
+
+	path := c.MustRun("info", "beta", "--field", "path")
+	if path != filepath.Join(newBaseDir, "beta") {
+		t.Errorf("info path = %q, want %q", path, filepath.Join(newBaseDir, "beta"))
+	}
+
+	ls := c.MustRun("ls")
+	AssertContains(t, ls, "alpha")
+	AssertContains(t, ls, "beta")
+}
+
+func Test_Migrate_Dry_Run_Changes_Nothing(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile(".wt/config.json", `{"base": "worktrees"}`)
+
+	oldPath := extractPath(c.MustRun("create", "--name", "stay"))
+
+	stdout, stderr, code := c.Run("migrate", "--to", "elsewhere", "--dry-run")
+	if code != 0 {
+		t.Fatalf("migrate --dry-run failed (code %d): %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Would move "+oldPath+" -> "+filepath.Join(c.Dir, "elsewhere", "stay"))
+	AssertContains(t, stdout, "Would set base = elsewhere")
+
+	if !c.FileExists("worktrees/stay/.wt/worktree.json") {
+		t.Error("worktree should not be moved by --dry-run")
+	}
+
+	if c.FileExists("elsewhere") {
+		t.Error("new base should not be created by --dry-run")
+	}
+
+	AssertContains(t, c.ReadFile(".wt/config.json"), `"worktrees"`)
+}
+
+func Test_Migrate_Fails_Before_Moving_When_Destination_Exists(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile(".wt/config.json", `{"base": "worktrees"}`)
+
+	c.MustRun("create", "--name", "first")
+	c.MustRun("create", "--name", "second")
+	c.WriteFile("elsewhere/second/keep.txt", "in the way")
+
+	_, stderr, code := c.Run("migrate", "--to", "elsewhere")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "destination already exists")
+
+	for _, name := range []string{"first", "second"} {
+		if !c.FileExists("worktrees/" + name + "/.wt/worktree.json") {
+			t.Errorf("%s should not have been moved", name)
+		}
+	}
+
+	AssertContains(t, c.ReadFile(".wt/config.json"), `"worktrees"`)
+}
+
+func Test_Migrate_Requires_To(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	_, stderr, code := c.Run("migrate")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "--to or --metadata is required")
+}
+
+func Test_Migrate_Rejects_Same_Base(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile(".wt/config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := c.Run("migrate", "--to", "./worktrees")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "already in this base directory")
+}
+
+func Test_Migrate_Refuses_To_Run_Inside_A_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile(".wt/config.json", `{"base": "worktrees"}`)
+
+	wtPath := extractPath(c.MustRun("create", "--name", "inside"))
+
+	_, stderr, code := c.RunInDir(wtPath, "migrate", "--to", "elsewhere")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot migrate from inside a worktree")
+}
+
+func Test_Migrate_Moves_Worktrees_Back_When_Config_Update_Fails(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "undo-me")
+
+	// A directory where the project config should be makes the write fail
+	c.WriteFile(".wt/config.json/blocker", "")
+
+	_, stderr, code := c.Run("--config", "config.json", "migrate", "--to", "elsewhere")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "moved worktrees were moved back")
+
+	if !c.FileExists("worktrees/undo-me/.wt/worktree.json") {
+		t.Error("worktree should be back in the old base")
+	}
+
+	if c.FileExists("elsewhere/undo-me") {
+		t.Error("worktree should not remain in the new base")
+	}
+
+	out, err := testGitCmd("-C", c.Dir, "worktree", "list").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git worktree list failed: %v\n%s", err, out)
+	}
+
+	AssertContains(t, string(out), filepath.Join("worktrees", "undo-me"))
+}
+
+func Test_Migrate_Metadata_Upgrades_Legacy_Worktree_Json(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile(".wt/config.json", `{"base": "worktrees"}`)
+
+	legacyPath := extractPath(c.MustRun("create", "--name", "legacy"))
+	futurePath := extractPath(c.MustRun("create", "--name", "future"))
+
+	// Files written before schema_version existed, and by a newer wt
+	c.WriteFile("worktrees/legacy/.wt/worktree.json", `{"name": "legacy", "agent_id": "old-cat", "id": 1}`)
+	c.WriteFile("worktrees/future/.wt/worktree.json", `{"schema_version": 99, "name": "future", "agent_id": "new-cat", "id": 2}`)
+
+	_, stderr, code := c.Run("ls")
+	if code != 0 {
+		t.Fatalf("ls failed (code %d): %s", code, stderr)
+	}
+
+	AssertContains(t, stderr, "warning: "+filepath.Join(futurePath, ".wt", "worktree.json")+" has schema_version 99")
+
+	stdout := c.MustRun("migrate", "--metadata", "--dry-run")
+	AssertContains(t, stdout, "Would upgrade "+legacyPath+" (schema_version 0 -> 1)")
+	AssertNotContains(t, c.ReadFile("worktrees/legacy/.wt/worktree.json"), "schema_version")
+
+	stdout, stderr, code = c.Run("migrate", "--metadata")
+	if code != 0 {
+		t.Fatalf("migrate --metadata failed (code %d)\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+
+	AssertContains(t, stdout, "Upgraded "+legacyPath+" (schema_version 0 -> 1)")
+	AssertContains(t, stderr, "newer than the supported 1 (upgrade wt); reading it best-effort (not rewritten)")
+
+	var legacy map[string]any
+
+	err := json.Unmarshal([]byte(c.ReadFile("worktrees/legacy/.wt/worktree.json")), &legacy)
+	if err != nil {
+		t.Fatalf("parsing upgraded metadata: %v", err)
+	}
+
+	if legacy["schema_version"] != float64(1) || legacy["agent_id"] != "old-cat" {
+		t.Errorf("expected upgraded metadata with schema_version 1, got %v", legacy)
+	}
+
+	AssertContains(t, c.ReadFile("worktrees/future/.wt/worktree.json"), `"schema_version": 99`)
+
+	c.WriteFile("worktrees/future/.wt/worktree.json", `{"schema_version": 1, "name": "future", "agent_id": "new-cat", "id": 2}`)
+	AssertContains(t, c.MustRun("migrate", "--metadata"), "All worktree metadata is at schema_version 1")
+
+	_, stderr, code = c.Run("migrate", "--metadata", "--to", t.TempDir())
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --metadata with --to")
+}
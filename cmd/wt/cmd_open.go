@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// Errors for open command.
+var (
+	errOpenIdentifierRequired = errors.New("worktree identifier is required (usage: wt open <name|id|agent_id|->)")
+	errNoEditor               = errors.New("no editor configured (set WT_EDITOR, VISUAL or EDITOR, or use --print to get the path)")
+	errEditorFailed           = errors.New("editor failed")
+)
+
+// editorEnvVars are the variables naming the editor wt open launches, in
+// precedence order.
+var editorEnvVars = []string{"WT_EDITOR", "VISUAL", "EDITOR"}
+
+// OpenCmd returns the open command.
+func OpenCmd(cfg Config, fsys fs.FS, git *Git, env map[string]string) *Command {
+	flags := flag.NewFlagSet("open", flag.ContinueOnError)
+	flags.BoolP("help", "h", false, "Show help")
+	flags.Bool("print", false, "Only print the worktree's path instead of launching the editor")
+
+	return &Command{
+		Flags: flags,
+		Usage: "open <identifier> [flags]",
+		Short: "Open a worktree in your editor",
+		Long: `Open a worktree in an editor.
+
+The identifier is looked up like wt switch does: numeric id, name, agent_id
+or checked-out branch, or - for the most recently created worktree.
+
+The editor is $WT_EDITOR, else $VISUAL, else $EDITOR. Like git, it runs
+through /bin/sh, so it may include arguments (e.g. "code --wait"), with
+the worktree directory as its last argument and working directory. wt open
+waits for it to exit and fails if it exits non-zero. If none of the
+variables is set, wt open fails.
+
+With --print, only the worktree's path is printed and nothing is launched,
+so a shell function can open it its own way.`,
+		Exec: func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, args []string) error {
+			if len(args) != 1 {
+				return errOpenIdentifierRequired
+			}
+
+			printOnly, _ := flags.GetBool("print")
+
+			return execOpen(ctx, stdin, stdout, stderr, cfg, fsys, git, env, args[0], printOnly)
+		},
+	}
+}
+
+func execOpen(
+	ctx context.Context,
+	stdin io.Reader,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	env map[string]string,
+	identifier string,
+	printOnly bool,
+) error {
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("scanning worktrees: %w", err)
+	}
+
+	wt, err := findSwitchTarget(ctx, git, mainRepoRoot, worktrees, identifier)
+	if err != nil {
+		return err
+	}
+
+	if printOnly {
+		fprintln(stdout, wt.Path)
+
+		return nil
+	}
+
+	editor := ""
+
+	for _, name := range editorEnvVars {
+		if env[name] != "" {
+			editor = env[name]
+
+			break
+		}
+	}
+
+	if editor == "" {
+		return errNoEditor
+	}
+
+	// "$@" keeps the path one argument, whatever the editor string contains
+	cmd := newChildCmd(ctx, wt.Path, []string{"/bin/sh", "-c", editor + ` "$@"`, editor, wt.Path}, env)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", errEditorFailed, editor, err)
+	}
+
+	return nil
+}
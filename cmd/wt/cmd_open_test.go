@@ -0,0 +1,67 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func Test_Open_Launches_Editor_In_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "feature"))
+
+	// The editor records its arguments and working directory
+	c.WriteExecutable("editor.sh", "#!/bin/sh\necho \"$1|$2|$PWD\" > \""+filepath.Join(c.Dir, "opened.txt")+"\"\n")
+
+	c.Env["EDITOR"] = "false"
+	c.Env["VISUAL"] = filepath.Join(c.Dir, "editor.sh") + " --wait"
+
+	c.MustRun("--config", "config.json", "open", "feature")
+
+	AssertContains(t, c.ReadFile("opened.txt"), "--wait|"+wtPath+"|"+wtPath)
+
+	// WT_EDITOR wins, and a failing editor fails the command
+	c.Env["WT_EDITOR"] = "false"
+
+	_, stderr, code := c.Run("--config", "config.json", "open", "feature")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "editor failed: false")
+}
+
+func Test_Open_Print_Outputs_Path_And_No_Editor_Fails(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "feature"))
+
+	c.MustRun("--config", "config.json", "create", "--name", "other")
+
+	// -C resolves the repository from inside another worktree
+	if got := c.MustRun("-C", filepath.Join(c.Dir, "worktrees", "other"), "--config", filepath.Join(c.Dir, "config.json"), "open", "feature", "--print"); got != wtPath {
+		t.Errorf("expected %s, got %q", wtPath, got)
+	}
+
+	_, stderr, code := c.Run("--config", "config.json", "open", "feature")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "no editor configured (set WT_EDITOR, VISUAL or EDITOR")
+
+	_, stderr, code = c.Run("--config", "config.json", "open", "missing", "--print")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "worktree not found: missing")
+}
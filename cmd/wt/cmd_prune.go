@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// Errors for prune command.
+var (
+	errPruneDryRunAndForce = errors.New("cannot use --dry-run and --force together")
+	errRemovingStaleDir    = errors.New("removing stale directory")
+)
+
+// PruneCmd returns the prune command.
+func PruneCmd(cfg Config, fsys fs.FS, git *Git) *Command {
+	flags := flag.NewFlagSet("prune", flag.ContinueOnError)
+	flags.BoolP("help", "h", false, "Show help")
+	flags.Bool("dry-run", false, "Only report what would be pruned (default)")
+	flags.BoolP("force", "f", false, "Remove stale directories and prune git worktree metadata")
+	flags.Bool("from-list", false, "Only prune the entries git worktree list reports as prunable")
+
+	return &Command{
+		Flags: flags,
+		Usage: "prune [flags]",
+		Short: "Clean up stale worktrees",
+		Long: `Find and clean up stale worktrees for the current repository.
+
+Two kinds of stale state are reported:
+  - directories in the base directory with .wt/worktree.json metadata that
+    git no longer knows as worktrees (e.g. after deleting .git/worktrees)
+  - git worktree entries whose directory no longer exists
+
+Linked git worktrees without .wt/worktree.json (e.g. from plain 'git
+worktree add') are listed as unmanaged but never pruned. So are other
+directories in the base directory without readable .wt/worktree.json
+metadata: only directories that still have it are ever removed.
+
+By default nothing is changed: prune only lists what it would do. Use
+--force to remove the stale directories and run 'git worktree prune'. Both
+end with a count, e.g. "Pruned 2 stale worktrees."
+
+With --from-list, only the entries 'git worktree list --porcelain' marks
+as prunable are handled (the ones wt ls --json shows with "prunable":
+true); the base directory is not scanned, so stale directories are left
+alone.`,
+		Exec: func(ctx context.Context, _ io.Reader, stdout, _ io.Writer, _ []string) error {
+			dryRun, _ := flags.GetBool("dry-run")
+			force, _ := flags.GetBool("force")
+			fromList, _ := flags.GetBool("from-list")
+
+			if dryRun && force {
+				return errPruneDryRunAndForce
+			}
+
+			// Without --force the report is the output
+			if force {
+				stdout = informational(cfg, stdout)
+			}
+
+			return execPrune(ctx, stdout, cfg, fsys, git, force, fromList)
+		},
+	}
+}
+
+func execPrune(ctx context.Context, stdout io.Writer, cfg Config, fsys fs.FS, git *Git, force, fromList bool) error {
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	// --from-list acts on git's prunable entries only, without scanning
+	var worktrees []WorktreeWithPath
+
+	if !fromList {
+		worktrees, err = findWorktreesWithPaths(fsys, baseDir)
+		if err != nil {
+			return fmt.Errorf("scanning worktrees: %w", err)
+		}
+	}
+
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	gitIndex := newGitWorktreeIndex(entries)
+
+	// Directories with wt metadata that git does not track as worktrees
+	staleDirs := make([]string, 0, len(worktrees))
+
+	for _, wt := range worktrees {
+		if _, ok := gitIndex.lookup(wt.Path); !ok {
+			staleDirs = append(staleDirs, wt.Path)
+		}
+	}
+
+	// Other directories git does not know are not ours to delete
+	if !fromList {
+		keptDirs, scanErr := dirsWithoutMetadata(fsys, baseDir, worktrees, gitIndex)
+		if scanErr != nil {
+			return fmt.Errorf("scanning worktrees: %w", scanErr)
+		}
+
+		for _, dir := range keptDirs {
+			fprintln(stdout, "Not a wt worktree (no .wt/worktree.json, kept):", dir)
+		}
+	}
+
+	// Git worktree entries whose directory is gone
+	prunable := make([]WorktreeEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.Prunable {
+			prunable = append(prunable, entry)
+		}
+	}
+
+	// Linked git worktrees without wt metadata are reported but never pruned
+	if !fromList {
+		for _, entry := range unmanagedEntries(entries, worktrees, mainRepoRoot) {
+			fprintln(stdout, "Unmanaged git worktree (no .wt/worktree.json, kept):", entry.Path)
+		}
+	}
+
+	if len(staleDirs) == 0 && len(prunable) == 0 {
+		fprintln(stdout, "Nothing to prune.")
+
+		return nil
+	}
+
+	if !force {
+		for _, dir := range staleDirs {
+			fprintln(stdout, "Would remove stale directory:", dir)
+		}
+
+		for _, entry := range prunable {
+			fprintf(stdout, "Would prune git worktree entry: %s (%s)\n", entry.Path, entry.PrunableReason)
+		}
+
+		apply := "wt prune --force"
+		if fromList {
+			apply = "wt prune --from-list --force"
+		}
+
+		fprintln(stdout)
+		fprintf(stdout, "Would prune %s. Run '%s' to apply.\n", pluralizeStale(len(staleDirs)+len(prunable)), apply)
+
+		return nil
+	}
+
+	var removeErrs []error
+
+	pruned := 0
+
+	for _, dir := range staleDirs {
+		// The metadata may have gone since the scan
+		if _, statErr := fsys.Stat(filepath.Join(dir, ".wt", "worktree.json")); statErr != nil {
+			fprintln(stdout, "Not a wt worktree (no .wt/worktree.json, kept):", dir)
+
+			continue
+		}
+
+		removeErr := fsys.RemoveAll(dir)
+		if removeErr != nil {
+			removeErrs = append(removeErrs, fmt.Errorf("%w %s: %w", errRemovingStaleDir, dir, removeErr))
+
+			continue
+		}
+
+		fprintln(stdout, "Removed stale directory:", dir)
+
+		pruned++
+	}
+
+	pruneErr := git.WorktreePrune(ctx, mainRepoRoot)
+	if pruneErr == nil {
+		for _, entry := range prunable {
+			fprintln(stdout, "Pruned git worktree entry:", entry.Path)
+		}
+
+		pruned += len(prunable)
+	}
+
+	fprintf(stdout, "Pruned %s.\n", pluralizeStale(pruned))
+
+	return errors.Join(append(removeErrs, pruneErr)...)
+}
+
+// unmanagedEntries returns the linked git worktrees that exist on disk but
+// are not among the wt-managed worktrees. The main worktree, bare entries
+// and prunable entries (directory gone) are not included.
+func unmanagedEntries(entries []WorktreeEntry, worktrees []WorktreeWithPath, mainRepoRoot string) []WorktreeEntry {
+	var result []WorktreeEntry
+
+	for _, entry := range entries {
+		if entry.Bare || entry.Prunable || isSamePath(entry.Path, mainRepoRoot) {
+			continue
+		}
+
+		isManaged := slices.ContainsFunc(worktrees, func(wt WorktreeWithPath) bool {
+			return isSamePath(wt.Path, entry.Path)
+		})
+		if !isManaged {
+			result = append(result, entry)
+		}
+	}
+
+	return result
+}
+
+// pluralizeStale returns "1 stale worktree" or "N stale worktrees".
+func pluralizeStale(n int) string {
+	if n == 1 {
+		return "1 stale worktree"
+	}
+
+	return fmt.Sprintf("%d stale worktrees", n)
+}
+
+// dirsWithoutMetadata returns the directories in baseDir that have no
+// readable .wt/worktree.json and are not git worktrees either, e.g. a
+// checkout or notes left there by hand. Prune lists them but never removes
+// them.
+func dirsWithoutMetadata(fsys fs.FS, baseDir string, worktrees []WorktreeWithPath, gitIndex gitWorktreeIndex) ([]string, error) {
+	entries, err := fsys.ReadDir(baseDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading directory: %w", err)
+	}
+
+	var dirs []string
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(baseDir, entry.Name())
+
+		managed := slices.ContainsFunc(worktrees, func(wt WorktreeWithPath) bool { return wt.Path == dir })
+		if _, tracked := gitIndex.lookup(dir); managed || tracked {
+			continue
+		}
+
+		dirs = append(dirs, dir)
+	}
+
+	return dirs, nil
+}
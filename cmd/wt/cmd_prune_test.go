@@ -0,0 +1,249 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// createStaleWorktreeDir creates a worktree and then deletes git's admin
+// directory for it, leaving a wt-managed directory git no longer tracks.
+func createStaleWorktreeDir(t *testing.T, c *CLI, name string) string {
+	t.Helper()
+
+	c.MustRun("--config", "config.json", "create", "--name", name)
+
+	err := os.RemoveAll(filepath.Join(c.Dir, ".git", "worktrees", name))
+	if err != nil {
+		t.Fatalf("failed to remove git worktree admin dir: %v", err)
+	}
+
+	return filepath.Join(c.Dir, "worktrees", name)
+}
+
+func Test_Prune_Reports_Nothing_When_Clean(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "healthy")
+
+	stdout := c.MustRun("--config", "config.json", "prune")
+
+	AssertContains(t, stdout, "Nothing to prune.")
+}
+
+func Test_Prune_Without_Force_Lists_Stale_Directory_But_Keeps_It(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stalePath := createStaleWorktreeDir(t, c, "stale-wt")
+
+	for _, args := range [][]string{{"prune"}, {"prune", "--dry-run"}} {
+		stdout := c.MustRun(append([]string{"--config", "config.json"}, args...)...)
+
+		AssertContains(t, stdout, "Would remove stale directory: "+stalePath)
+		AssertContains(t, stdout, "wt prune --force")
+
+		if _, err := os.Stat(stalePath); err != nil {
+			t.Errorf("%v: stale directory should not be removed: %v", args, err)
+		}
+	}
+}
+
+func Test_Prune_Force_Removes_Stale_Directory(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stalePath := createStaleWorktreeDir(t, c, "stale-wt")
+	c.MustRun("--config", "config.json", "create", "--name", "healthy")
+
+	stdout := c.MustRun("--config", "config.json", "prune", "--force")
+
+	AssertContains(t, stdout, "Removed stale directory: "+stalePath)
+
+	if _, err := os.Stat(stalePath); !os.IsNotExist(err) {
+		t.Error("stale directory should be removed with --force")
+	}
+
+	if !c.FileExists(filepath.Join("worktrees", "healthy", ".wt", "worktree.json")) {
+		t.Error("healthy worktree should be kept")
+	}
+
+	AssertContains(t, c.MustRun("--config", "config.json", "prune"), "Nothing to prune.")
+}
+
+func Test_Prune_Reports_Then_Prunes_Missing_Worktree_Directory(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "gone-wt")
+
+	wtPath := filepath.Join(c.Dir, "worktrees", "gone-wt")
+
+	err := os.RemoveAll(wtPath)
+	if err != nil {
+		t.Fatalf("failed to remove worktree dir: %v", err)
+	}
+
+	adminDir := filepath.Join(".git", "worktrees", "gone-wt")
+
+	stdout := c.MustRun("--config", "config.json", "prune")
+
+	AssertContains(t, stdout, "Would prune git worktree entry: "+wtPath)
+
+	if !c.FileExists(adminDir) {
+		t.Error("git worktree metadata should not be pruned without --force")
+	}
+
+	stdout = c.MustRun("--config", "config.json", "prune", "--force")
+
+	AssertContains(t, stdout, "Pruned git worktree entry: "+wtPath)
+
+	if c.FileExists(adminDir) {
+		t.Error("git worktree metadata should be pruned with --force")
+	}
+}
+
+func Test_Prune_Rejects_Dry_Run_With_Force(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	_, stderr, code := c.Run("prune", "--dry-run", "--force")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --dry-run and --force together")
+}
+
+func Test_Prune_Summarizes_Count_Of_Stale_Worktrees(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	createStaleWorktreeDir(t, c, "stale-wt")
+	c.MustRun("--config", "config.json", "create", "--name", "gone-wt")
+
+	err := os.RemoveAll(filepath.Join(c.Dir, "worktrees", "gone-wt"))
+	if err != nil {
+		t.Fatalf("failed to remove worktree dir: %v", err)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "prune", "--dry-run")
+	AssertContains(t, stdout, "Would prune 2 stale worktrees.")
+
+	stdout = c.MustRun("--config", "config.json", "prune", "--force")
+	AssertContains(t, stdout, "Pruned 2 stale worktrees.")
+
+	AssertContains(t, c.MustRun("--config", "config.json", "prune"), "Nothing to prune.")
+}
+
+func Test_Prune_Reports_Unmanaged_Git_Worktree_But_Keeps_It(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	plainPath := filepath.Join(c.Dir, "plain-wt")
+
+	out, err := testGitCmd("-C", c.Dir, "worktree", "add", "-b", "plain", plainPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git worktree add failed: %v\n%s", err, out)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "prune", "--force")
+
+	AssertContains(t, stdout, "Unmanaged git worktree (no .wt/worktree.json, kept): "+plainPath)
+	AssertContains(t, stdout, "Nothing to prune.")
+
+	if _, statErr := os.Stat(plainPath); statErr != nil {
+		t.Errorf("unmanaged worktree should be kept: %v", statErr)
+	}
+}
+
+func Test_Prune_Force_Keeps_Directories_Without_Metadata(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.WriteFile(filepath.Join("worktrees", "notes", "todo.txt"), "keep me")
+	c.WriteFile(filepath.Join("worktrees", "corrupt", ".wt", "worktree.json"), "{not json")
+
+	stdout := c.MustRun("--config", "config.json", "prune", "--force")
+
+	for _, name := range []string{"notes", "corrupt"} {
+		dir := filepath.Join(c.Dir, "worktrees", name)
+
+		AssertContains(t, stdout, "Not a wt worktree (no .wt/worktree.json, kept): "+dir)
+
+		if _, err := os.Stat(dir); err != nil {
+			t.Errorf("%s should be kept: %v", dir, err)
+		}
+	}
+
+	AssertContains(t, stdout, "Nothing to prune.")
+}
+
+func Test_Prune_From_List_Only_Prunes_Git_Prunable_Entries(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "gone-wt")
+
+	gonePath := filepath.Join(c.Dir, "worktrees", "gone-wt")
+
+	err := os.RemoveAll(gonePath)
+	if err != nil {
+		t.Fatalf("failed to remove worktree dir: %v", err)
+	}
+
+	stalePath := createStaleWorktreeDir(t, c, "stale-wt")
+
+	stdout := c.MustRun("--config", "config.json", "prune", "--from-list")
+
+	AssertContains(t, stdout, "Would prune git worktree entry: "+gonePath)
+	AssertContains(t, stdout, "Would prune 1 stale worktree. Run 'wt prune --from-list --force' to apply.")
+	AssertNotContains(t, stdout, stalePath)
+
+	stdout = c.MustRun("--config", "config.json", "prune", "--from-list", "--force")
+
+	AssertContains(t, stdout, "Pruned git worktree entry: "+gonePath)
+	AssertContains(t, stdout, "Pruned 1 stale worktree.")
+
+	if c.FileExists(filepath.Join(".git", "worktrees", "gone-wt")) {
+		t.Error("git worktree metadata should be pruned")
+	}
+
+	if _, err := os.Stat(stalePath); err != nil {
+		t.Errorf("stale directory should be kept with --from-list: %v", err)
+	}
+
+	AssertContains(t, c.MustRun("--config", "config.json", "prune", "--from-list"), "Nothing to prune.")
+}
@@ -6,7 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/calvinalkan/agent-task/pkg/fs"
@@ -22,6 +22,18 @@ var (
 	errCheckingWorktreeStatus   = errors.New("checking worktree status")
 	errReadingWorktreeInfo      = errors.New("reading worktree info")
 	errPreDeleteHookAbortDelete = errors.New("pre-delete hook aborted deletion (hook exited non-zero)")
+	errBranchKept               = errors.New("worktree was removed but its branch was kept")
+	errBranchNotMerged          = errors.New("branch is not fully merged, nothing was removed")
+	errWorktreeHasChildren      = errors.New("worktree has child worktrees")
+	errRecursiveAndOrphan       = errors.New("cannot use --recursive and --orphan together")
+	errRemovingChildWorktree    = errors.New("removing child worktree")
+	errBranchOnlyConflict       = errors.New("cannot use --branch-only with")
+	errBranchOnlyNoBranch       = errors.New("worktree has no branch checked out (HEAD is already detached)")
+	errDeletingBranch           = errors.New("deleting branch")
+	errRemoveAllWithName        = errors.New("cannot use --all with a worktree name")
+	errRemoveAllConflict        = errors.New("cannot use --all with")
+	errRemoveAllNeedsForce      = errors.New("refusing to remove all worktrees without confirmation (use --force to skip it in non-interactive mode)")
+	errRemoveAllFailed          = errors.New("some worktrees could not be removed")
 )
 
 // RemoveCmd returns the remove command.
@@ -29,24 +41,73 @@ func RemoveCmd(cfg Config, fsys fs.FS, git *Git, env map[string]string) *Command
 	flags := flag.NewFlagSet("remove", flag.ContinueOnError)
 	flags.BoolP("help", "h", false, "Show help")
 	flags.BoolP("force", "f", false, "Remove even if worktree has uncommitted changes")
+	flags.Bool("ignore-untracked", false, "Remove even if untracked files exist (they are deleted), as long as tracked files are clean")
 	flags.BoolP("with-branch", "b", false, "Also delete the git branch (skips interactive prompt)")
+	flags.Bool("force-branch", false, "Delete the branch even if not fully merged (implied by --force)")
+	flags.BoolP("recursive", "r", false, "Also remove child worktrees (created from this one), children first")
+	flags.Bool("orphan", false, "Remove even if child worktrees exist, leaving them in place")
+	flags.Bool("branch-only", false, "Delete only the branch: detach the worktree's HEAD and keep its files")
+	flags.Bool("all", false, "Remove every wt-managed worktree (asks for confirmation)")
+	addPorcelainFlag(flags)
 
 	return &Command{
 		Flags:   flags,
 		Usage:   "remove <name> [flags]",
 		Short:   "Remove a worktree",
 		Aliases: []string{"rm"},
-		Long: `Remove a worktree by name.
+		Long: `Remove a worktree by name, or by the branch checked out in it.
+An identifier that is one worktree's name and another's branch is rejected
+as ambiguous.
 
 Removes the worktree directory and git worktree metadata. If the worktree
-has uncommitted changes, use --force to proceed.
+has uncommitted changes, use --force to proceed. With --ignore-untracked,
+untracked files alone (e.g. build output or scratch files) do not block the
+removal and are deleted with the worktree; staged or modified tracked files
+still need --force.
 
 In an interactive terminal, you will be prompted about branch deletion.
-In non-interactive mode (scripts/pipes), the branch is kept unless
---with-branch is specified.
+In non-interactive mode (scripts/pipes, or the global --non-interactive
+flag), the branch is kept unless --with-branch is specified. The branch
+deleted is the one git has checked out in the worktree, which may differ
+from the worktree's name (e.g. after git switch); a detached worktree has
+none.
+
+Branches that are not fully merged are only deleted with --force-branch
+(or --force). Without it, this is checked before anything is removed, so
+the command fails naming the branch and leaves the worktree in place.
+
+Worktrees created from inside this one (parent_id in their metadata) are
+its children. Removing a worktree that has children fails unless
+--recursive is given, which removes the whole subtree (deepest children
+first, with the same --force and branch choices), or --orphan, which
+removes only this worktree and leaves the children in place (their parent_id
+is cleared, so they become top-level worktrees).
 
 If .wt/hooks/pre-delete exists and is executable, it runs before deletion
-and can abort the operation by exiting non-zero.`,
+and can abort the operation by exiting non-zero.
+
+With --branch-only, the worktree is kept and only its branch is deleted:
+HEAD is detached at the branch's last commit (files, uncommitted changes
+and the index are left as they are), the branch is deleted, and the
+metadata records the worktree as detached. Unmerged branches again need
+--force-branch (or --force); if the delete fails, the branch is checked
+out again. The pre-delete hook does not run. A detached worktree cannot be
+merged with wt merge until a branch is checked out (git switch -c <name>).
+
+With --porcelain, stdout gets only "key<TAB>value" lines: per removed
+worktree (children first with --recursive) "status<TAB>removed", then
+name, path and branch_deleted (true/false); with --branch-only
+"status<TAB>branch_deleted", name, path and branch. The prompt, hook
+output and everything else go to stderr.
+
+With --all (and no name), every wt-managed worktree is removed, children
+before their parents, each like a single remove (pre-delete hook first,
+dirty ones only with --force, branches only with --with-branch). In an
+interactive terminal the worktrees are listed and you are asked to confirm
+once; --force skips the question. In non-interactive mode --all refuses to
+run without --force. A worktree that fails is reported and the others are
+still removed; the command ends with a summary, e.g. "Removed 2 of 3
+worktrees.", and exits 1 if any failed.`,
 		Exec: func(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer, args []string) error {
 			return execRemove(ctx, stdin, stdout, stderr, cfg, fsys, git, env, flags, args)
 		},
@@ -64,13 +125,56 @@ func execRemove(
 	flags *flag.FlagSet,
 	args []string,
 ) error {
-	if len(args) == 0 {
+	all, _ := flags.GetBool("all")
+	if len(args) == 0 && !all {
 		return errWorktreeNameRequired
 	}
 
-	name := args[0]
 	force, _ := flags.GetBool("force")
+	ignoreUntracked, _ := flags.GetBool("ignore-untracked")
 	withBranch, _ := flags.GetBool("with-branch")
+	forceBranch, _ := flags.GetBool("force-branch")
+	recursive, _ := flags.GetBool("recursive")
+	orphan, _ := flags.GetBool("orphan")
+
+	if recursive && orphan {
+		return errRecursiveAndOrphan
+	}
+
+	// --porcelain keeps stdout to its key/value lines, the rest goes to stderr
+	var porcelain io.Writer
+	if usePorcelain, _ := flags.GetBool("porcelain"); usePorcelain {
+		porcelain, stdout = stdout, stderr
+	}
+
+	// The branch prompt stays visible with --quiet
+	prompt := stdout
+	stdout = informational(cfg, stdout)
+
+	if all {
+		if len(args) > 0 {
+			return errRemoveAllWithName
+		}
+
+		for _, conflict := range []string{"recursive", "orphan", "branch-only"} {
+			if flags.Changed(conflict) {
+				return fmt.Errorf("%w --%s", errRemoveAllConflict, conflict)
+			}
+		}
+
+		return removeAll(ctx, stdin, stdout, stderr, prompt, porcelain, cfg, fsys, git, env, withBranch, force, forceBranch, ignoreUntracked)
+	}
+
+	name := args[0]
+
+	branchOnly, _ := flags.GetBool("branch-only")
+	if branchOnly {
+		for _, conflict := range []string{"with-branch", "recursive", "orphan"} {
+			if flags.Changed(conflict) {
+				return fmt.Errorf("%w --%s", errBranchOnlyConflict, conflict)
+			}
+		}
+	}
 
 	// 1. Get main repo root (works from inside worktrees too)
 	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
@@ -78,48 +182,439 @@ func execRemove(
 		return err
 	}
 
-	// 2. Find worktree by name
-	baseDir := resolveWorktreeBaseDir(cfg, mainRepoRoot)
-	wtPath := filepath.Join(baseDir, name)
+	// 2. Find worktree by name or branch
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	wtPath, err := findWorktreeToRemove(ctx, fsys, git, baseDir, mainRepoRoot, name)
+	if err != nil {
+		return err
+	}
 
 	info, err := readWorktreeInfo(fsys, wtPath)
 	if err != nil {
-		if errors.Is(err, ErrNotWtWorktree) {
-			return fmt.Errorf("%w: %s", errWorktreeNotFound, name)
+		return fmt.Errorf("%w: %w", errReadingWorktreeInfo, err)
+	}
+
+	if branchOnly {
+		return removeBranchOnly(ctx, stdout, porcelain, fsys, git, &info, wtPath, mainRepoRoot, force || forceBranch)
+	}
+
+	// 2a. Children must be removed first (--recursive) or left alone (--orphan)
+	var children []WorktreeWithPath
+
+	if !orphan {
+		worktrees, scanErr := findWorktreesWithPaths(fsys, baseDir)
+		if scanErr != nil {
+			return fmt.Errorf("%w: %w", errReadingWorktreeInfo, scanErr)
 		}
 
-		return fmt.Errorf("%w: %w", errReadingWorktreeInfo, err)
+		children = worktreeDescendants(worktrees, info.ID)
+	}
+
+	if len(children) > 0 && !recursive {
+		names := make([]string, 0, len(children))
+		for _, child := range children {
+			names = append(names, child.Name)
+		}
+
+		return fmt.Errorf("%w: %s (use --recursive to remove them too, or --orphan to keep them)",
+			errWorktreeHasChildren, strings.Join(names, ", "))
 	}
 
-	// 3. Check for uncommitted changes
+	// 3. Check for uncommitted changes (in the whole subtree, before removing any)
 	if !force {
-		dirty, err := git.IsDirty(ctx, wtPath)
-		if err != nil {
-			return fmt.Errorf("%w: %w", errCheckingWorktreeStatus, err)
+		for _, child := range children {
+			err = checkRemovable(ctx, git, child.Path, ignoreUntracked)
+			if err != nil {
+				return fmt.Errorf("%w %s: %w", errRemovingChildWorktree, child.Name, err)
+			}
 		}
 
-		if dirty {
-			return errWorktreeHasChanges
+		err = checkRemovable(ctx, git, wtPath, ignoreUntracked)
+		if err != nil {
+			return err
 		}
 	}
 
 	// 4. Determine branch deletion before cleanup
 	deleteBranch := withBranch
 
-	if !withBranch && stdin != nil && IsTerminal() {
-		// Interactive prompt - explain that branch is safe and ask about deletion
-		fprintln(stdout)
-		fprintf(stdout, "Branch '%s' still contains all your commits.\n", name)
-		fprintf(stdout, "Also delete the branch? (y/N) ")
+	// Prompt only if the given stdin is a terminal, so pipes and
+	// --non-interactive never block on an answer
+	if !withBranch && !cfg.NonInteractive && readerIsTerminal(stdin) {
+		branch, branchErr := git.CurrentBranch(ctx, wtPath)
+		if branchErr != nil {
+			return fmt.Errorf("%w: %w", errCheckingWorktreeStatus, branchErr)
+		}
 
-		deleteBranch = readYesNo(stdin)
+		// Interactive prompt - explain that branch is safe and ask about
+		// deletion (a detached worktree has no branch to ask about)
+		if branch != "" {
+			fprintln(prompt)
+			fprintf(prompt, "Branch '%s' still contains all your commits.\n", branch)
+			fprintf(prompt, "Also delete the branch? (y/N) ")
+
+			deleteBranch = readYesNo(stdin)
+		}
 	}
 	// Non-interactive without --with-branch: keep branch (deleteBranch stays false)
 
-	// 5. Perform cleanup (hook, remove, branch delete, prune)
-	hookRunner := NewHookRunner(fsys, mainRepoRoot, env, stdout, stderr)
+	// 5. Perform cleanup (hook, remove, branch delete, prune), children first
+	hooksDir, err := resolveHooksDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	hookRunner := NewHookRunner(fsys, mainRepoRoot, hooksDir, env, stdout, stderr)
+
+	cleanup := func(wtInfo *WorktreeInfo, path string) error {
+		// git worktree remove refuses untracked files without its --force
+		cleanupErr := CleanupWorktree(ctx, stdout, git, hookRunner, wtInfo, path, mainRepoRoot, deleteBranch, force || ignoreUntracked, force || forceBranch)
+
+		// A kept branch still means the worktree itself is gone
+		if porcelain != nil && (cleanupErr == nil || errors.Is(cleanupErr, errBranchKept)) {
+			printPorcelain(porcelain,
+				"status", "removed",
+				"name", wtInfo.Name,
+				"path", path,
+				"branch_deleted", strconv.FormatBool(deleteBranch && cleanupErr == nil),
+			)
+		}
+
+		return cleanupErr
+	}
+
+	for _, child := range children {
+		err = cleanup(&child.WorktreeInfo, child.Path)
+		if err != nil {
+			return fmt.Errorf("%w %s: %w", errRemovingChildWorktree, child.Name, err)
+		}
+	}
+
+	err = cleanup(&info, wtPath)
+
+	// Orphaned children must not point at the removed id, or a later worktree
+	// given that id would become their parent
+	if orphan && (err == nil || errors.Is(err, errBranchKept)) {
+		err = errors.Join(err, unlinkChildren(fsys, baseDir, info.ID))
+	}
+
+	return err
+}
+
+// unlinkChildren clears parent_id in the metadata of the worktrees in
+// baseDir whose parent is parentID, making them top-level worktrees.
+func unlinkChildren(fsys fs.FS, baseDir string, parentID int) error {
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errReadingWorktreeInfo, err)
+	}
+
+	var errs []error
+
+	for _, wt := range worktrees {
+		if wt.ParentID != parentID || wt.ID == parentID {
+			continue
+		}
+
+		wt.ParentID = 0
+
+		writeErr := writeWorktreeInfo(fsys, wt.Path, &wt.WorktreeInfo)
+		if writeErr != nil {
+			errs = append(errs, fmt.Errorf("clearing parent_id of %s: %w", wt.Name, writeErr))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// removeBranchOnly deletes the branch checked out in wtPath but keeps the
+// worktree: HEAD is detached first (git refuses to delete a checked-out
+// branch) and checked out again if the delete fails. The porcelain record
+// goes to porcelain if it is not nil.
+func removeBranchOnly(
+	ctx context.Context,
+	stdout, porcelain io.Writer,
+	fsys fs.FS,
+	git *Git,
+	info *WorktreeInfo,
+	wtPath, mainRepoRoot string,
+	forceBranch bool,
+) error {
+	branch, err := git.CurrentBranch(ctx, wtPath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCheckingWorktreeStatus, err)
+	}
+
+	if branch == "" {
+		return errBranchOnlyNoBranch
+	}
+
+	err = git.DetachHead(ctx, wtPath)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", errDeletingBranch, branch, err)
+	}
+
+	err = git.BranchDelete(ctx, mainRepoRoot, branch, forceBranch)
+	if err != nil {
+		restoreErr := git.SwitchBranch(ctx, wtPath, branch)
+
+		return errors.Join(
+			fmt.Errorf("%w %s (use --force-branch if it is not merged): %w", errDeletingBranch, branch, err),
+			restoreErr,
+		)
+	}
+
+	fprintln(stdout, "Deleted branch:", branch)
+	fprintln(stdout, "Worktree kept (detached HEAD):", wtPath)
+
+	if porcelain != nil {
+		printPorcelain(porcelain, "status", "branch_deleted", "name", info.Name, "path", wtPath, "branch", branch)
+	}
+
+	// The branch and its upstream are gone
+	info.Detached = true
+	info.Upstream = ""
+
+	return writeWorktreeInfo(fsys, wtPath, info)
+}
+
+// removeAll removes every worktree in the base directory for wt remove
+// --all, children before their parents. It asks for confirmation on prompt
+// unless force is set, keeps going past failed worktrees and reports them
+// in a summary at the end.
+func removeAll(
+	ctx context.Context,
+	stdin io.Reader,
+	stdout, stderr, prompt, porcelain io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	env map[string]string,
+	withBranch, force, forceBranch, ignoreUntracked bool,
+) error {
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errReadingWorktreeInfo, err)
+	}
+
+	if len(worktrees) == 0 {
+		fprintln(stdout, "No worktrees to remove.")
+
+		return nil
+	}
+
+	sortWorktrees(worktrees, listSortID, false)
+	worktrees = worktreesChildrenFirst(worktrees)
+
+	if !force {
+		if cfg.NonInteractive || !readerIsTerminal(stdin) {
+			return errRemoveAllNeedsForce
+		}
+
+		fprintln(prompt, "This removes the following worktrees:")
+
+		for _, wt := range worktrees {
+			fprintf(prompt, "  %s (%s)\n", wt.Name, wt.Path)
+		}
+
+		fprintf(prompt, "Remove all %d worktrees? (y/N) ", len(worktrees))
+
+		if !readYesNo(stdin) {
+			fprintln(prompt, "Aborted, no worktrees were removed.")
+
+			return nil
+		}
+	}
+
+	hooksDir, err := resolveHooksDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	hookRunner := NewHookRunner(fsys, mainRepoRoot, hooksDir, env, stdout, stderr)
+
+	var (
+		failures []error
+		failed   []string
+	)
+
+	removed := 0
+
+	for _, wt := range worktrees {
+		var cleanupErr error
 
-	return CleanupWorktree(ctx, stdout, git, hookRunner, &info, wtPath, mainRepoRoot, deleteBranch, force)
+		if !force {
+			cleanupErr = checkRemovable(ctx, git, wt.Path, ignoreUntracked)
+		}
+
+		if cleanupErr == nil {
+			cleanupErr = CleanupWorktree(ctx, stdout, git, hookRunner, &wt.WorktreeInfo, wt.Path, mainRepoRoot, withBranch, force || ignoreUntracked, force || forceBranch)
+		}
+
+		// A kept branch still means the worktree itself is gone
+		gone := cleanupErr == nil || errors.Is(cleanupErr, errBranchKept)
+		if gone {
+			removed++
+
+			if porcelain != nil {
+				printPorcelain(porcelain,
+					"status", "removed",
+					"name", wt.Name,
+					"path", wt.Path,
+					"branch_deleted", strconv.FormatBool(withBranch && cleanupErr == nil),
+				)
+			}
+		} else {
+			failed = append(failed, wt.Name)
+		}
+
+		if cleanupErr != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", wt.Name, cleanupErr))
+			fprintf(stderr, "error: %s: %v\n", wt.Name, cleanupErr)
+		}
+	}
+
+	fprintf(stdout, "Removed %d of %d worktrees.\n", removed, len(worktrees))
+
+	if len(failed) > 0 {
+		fprintf(stdout, "Could not remove: %s\n", strings.Join(failed, ", "))
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%w: %w", errRemoveAllFailed, errors.Join(failures...))
+	}
+
+	return nil
+}
+
+// checkRemovable returns errWorktreeHasChanges if the worktree at path has
+// uncommitted changes, not counting untracked files if ignoreUntracked is
+// set.
+func checkRemovable(ctx context.Context, git *Git, path string, ignoreUntracked bool) error {
+	status, err := git.Status(ctx, path)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCheckingWorktreeStatus, err)
+	}
+
+	if status.HasTrackedChanges() {
+		return errWorktreeHasChanges
+	}
+
+	if status.Untracked > 0 && !ignoreUntracked {
+		return fmt.Errorf("%w; only untracked files, --ignore-untracked deletes them with the worktree", errWorktreeHasChanges)
+	}
+
+	return nil
+}
+
+// worktreesChildrenFirst orders worktrees so every worktree comes after
+// its descendants, keeping the given order otherwise. Worktrees whose parent
+// is not among them count as top-level.
+func worktreesChildrenFirst(worktrees []WorktreeWithPath) []WorktreeWithPath {
+	ids := make(map[int]bool, len(worktrees))
+	for _, wt := range worktrees {
+		ids[wt.ID] = true
+	}
+
+	result := make([]WorktreeWithPath, 0, len(worktrees))
+	seen := make(map[int]bool, len(worktrees))
+
+	add := func(wt WorktreeWithPath) {
+		if !seen[wt.ID] {
+			seen[wt.ID] = true
+
+			result = append(result, wt)
+		}
+	}
+
+	for _, wt := range worktrees {
+		if ids[wt.ParentID] && wt.ParentID != wt.ID {
+			continue
+		}
+
+		for _, child := range worktreeDescendants(worktrees, wt.ID) {
+			add(child)
+		}
+
+		add(wt)
+	}
+
+	// Worktrees only reachable through a parent_id cycle
+	for _, wt := range worktrees {
+		add(wt)
+	}
+
+	return result
+}
+
+// worktreeDescendants returns the worktrees below parentID (children via
+// parent_id, their children, and so on), ordered so every worktree comes
+// before its parent. Each worktree is visited once, so bad metadata with a
+// parent_id cycle cannot loop forever.
+func worktreeDescendants(worktrees []WorktreeWithPath, parentID int) []WorktreeWithPath {
+	var result []WorktreeWithPath
+
+	visited := map[int]bool{parentID: true}
+
+	var walk func(id int)
+
+	walk = func(id int) {
+		for _, wt := range worktrees {
+			if wt.ParentID != id || visited[wt.ID] {
+				continue
+			}
+
+			visited[wt.ID] = true
+
+			walk(wt.ID)
+
+			result = append(result, wt)
+		}
+	}
+
+	walk(parentID)
+
+	return result
+}
+
+// findWorktreeToRemove resolves identifier to a worktree path by name or by
+// the branch checked out in it. IDs and agent_ids are deliberately not
+// accepted, so a destructive command never matches by accident.
+func findWorktreeToRemove(ctx context.Context, fsys fs.FS, git *Git, baseDir, mainRepoRoot, identifier string) (string, error) {
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errReadingWorktreeInfo, err)
+	}
+
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return "", err
+	}
+
+	wt, found, err := findWorktreeByIdentifier(worktrees, newGitWorktreeIndex(entries), identifier, identifierKeyName, identifierKeyBranch)
+	if err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", fmt.Errorf("%w: %s", errWorktreeNotFound, identifier)
+	}
+
+	return wt.Path, nil
 }
 
 // readYesNo reads a yes/no response from stdin.
@@ -135,6 +630,17 @@ func readYesNo(stdin io.Reader) bool {
 	return strings.EqualFold(strings.TrimSpace(response), "y")
 }
 
+// branchFullyMerged reports whether "git branch -d" would delete branch: it
+// is contained in its upstream if it has one, else in dir's HEAD.
+func branchFullyMerged(ctx context.Context, git *Git, dir, branch string) (bool, error) {
+	against := "HEAD"
+	if upstream, _, err := git.Upstream(ctx, dir, branch); err == nil {
+		against = upstream
+	}
+
+	return git.IsAncestor(ctx, dir, "refs/heads/"+branch, against)
+}
+
 // CleanupWorktree performs the core cleanup logic for removing a worktree.
 // This function is shared between 'wt remove' and 'wt merge' commands.
 //
@@ -149,14 +655,18 @@ func readYesNo(stdin io.Reader) bool {
 //   - stdout: Writer for status messages ("Removed worktree:", "Deleted branch:")
 //   - git: Git operations interface
 //   - hookRunner: Hook executor for pre-delete hook
-//   - info: Worktree metadata (used for hook env vars and branch name)
+//   - info: Worktree metadata (used for hook env vars)
 //   - wtPath: Absolute path to the worktree directory (hook runs here)
 //   - mainRepoRoot: Absolute path to the main repository
-//   - deleteBranch: Whether to delete the git branch after removing worktree
+//   - deleteBranch: Whether to delete the branch checked out in the worktree
+//     (as git reports it, not as metadata names it) after removing it
 //   - force: Whether to force removal (ignore uncommitted changes)
+//   - forceBranch: Whether to delete the branch even if it is not fully merged
 //
 // Errors are combined using errors.Join so multiple cleanup failures
-// (e.g., branch deletion and prune) are reported together.
+// (e.g., branch deletion and prune) are reported together. A failed branch
+// deletion is wrapped in errBranchKept, since the worktree is already gone
+// by then.
 func CleanupWorktree(
 	ctx context.Context,
 	stdout io.Writer,
@@ -164,8 +674,34 @@ func CleanupWorktree(
 	hookRunner *HookRunner,
 	info *WorktreeInfo,
 	wtPath, mainRepoRoot string,
-	deleteBranch, force bool,
+	deleteBranch, force, forceBranch bool,
 ) error {
+	// The branch to delete is the one git has checked out there, which may
+	// differ from metadata (a repaired worktree, git switch); detached = none
+	branch := ""
+
+	if deleteBranch {
+		var err error
+
+		branch, err = git.CurrentBranch(ctx, wtPath)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errCheckingWorktreeStatus, err)
+		}
+	}
+
+	// Refuse an unmerged branch before the worktree is gone, so a refusal
+	// changes nothing
+	if branch != "" && !forceBranch {
+		merged, mergedErr := branchFullyMerged(ctx, git, mainRepoRoot, branch)
+		if mergedErr != nil {
+			return fmt.Errorf("%w: %w", errCheckingWorktreeStatus, mergedErr)
+		}
+
+		if !merged {
+			return fmt.Errorf("%w: %s (use --force-branch to delete it anyway, or drop --with-branch to keep it)", errBranchNotMerged, branch)
+		}
+	}
+
 	// 1. Run pre-delete hook (in worktree directory)
 	err := hookRunner.RunPreDelete(ctx, info, wtPath)
 	if err != nil {
@@ -185,10 +721,13 @@ func CleanupWorktree(
 
 	branchDeleted := false
 
-	if deleteBranch {
-		branchErr = git.BranchDelete(ctx, mainRepoRoot, info.Name, force)
+	if branch != "" {
+		branchErr = git.BranchDelete(ctx, mainRepoRoot, branch, forceBranch)
 		if branchErr == nil {
 			branchDeleted = true
+		} else {
+			branchErr = fmt.Errorf("%w: %s (use --force-branch or 'git branch -D %s'): %w",
+				errBranchKept, branch, branch, branchErr)
 		}
 	}
 
@@ -197,7 +736,7 @@ func CleanupWorktree(
 
 	// Output branch deletion status
 	if branchDeleted {
-		fprintln(stdout, "Deleted branch:", info.Name)
+		fprintln(stdout, "Deleted branch:", branch)
 	}
 
 	// Return combined errors if any
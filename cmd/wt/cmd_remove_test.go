@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -422,9 +423,8 @@ func Test_Remove_Help_Shows_Detailed_Description(t *testing.T) {
 func Test_Remove_Interactive_Prompt_Yes_Deletes_Branch(t *testing.T) {
 	t.Parallel()
 
-	// This test verifies the readYesNo function works correctly
-	// Since IsTerminal() returns false in tests, we can't test the full interactive flow
-	// But we can test the readYesNo function directly
+	// This test verifies the readYesNo function works correctly; the full
+	// prompt flow is covered by Test_Remove_Prompts_For_Branch_When_Stdin_Is_Terminal
 
 	stdin := strings.NewReader("y\n")
 
@@ -620,3 +620,596 @@ func Test_GlobalHelp_Shows_Remove_Command(t *testing.T) {
 	// Verify remove command is listed (aliases shown only in command help)
 	AssertContains(t, stdout, "remove <name>")
 }
+
+// createWorktreeWithUnmergedCommit creates a clean worktree whose branch has a
+// commit that is not on master, so "git branch -d" refuses to delete it.
+func createWorktreeWithUnmergedCommit(t *testing.T, c *CLI, name string) string {
+	t.Helper()
+
+	stdout, stderr, code := c.Run("--config", "config.json", "create", "--name", name)
+	if code != 0 {
+		t.Fatalf("create failed: %s", stderr)
+	}
+
+	wtPath := extractPath(stdout)
+	gitCommitInDir(t, wtPath, "unmerged.txt", "work in progress", "Unmerged work")
+
+	status, err := testGitCmd("-C", wtPath, "status", "--porcelain").CombinedOutput()
+	if err != nil || strings.TrimSpace(string(status)) != "" {
+		t.Fatalf("expected clean worktree, got: %v\n%s", err, status)
+	}
+
+	return wtPath
+}
+
+func Test_Remove_WithBranch_Refuses_Unmerged_Branch_Before_Removing(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := createWorktreeWithUnmergedCommit(t, c, "unmerged-wt")
+
+	stdout, stderr, code := c.Run("--config", "config.json", "remove", "unmerged-wt", "--with-branch")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertNotContains(t, stdout, "Removed worktree:")
+	AssertNotContains(t, stdout, "Deleted branch:")
+	AssertContains(t, stderr, "branch is not fully merged, nothing was removed: unmerged-wt")
+	AssertContains(t, stderr, "--force-branch")
+
+	if _, err := os.Stat(wtPath); err != nil {
+		t.Errorf("worktree directory should be kept: %v", err)
+	}
+
+	out, err := testGitCmd("-C", c.Dir, "rev-parse", "--verify", "refs/heads/unmerged-wt").CombinedOutput()
+	if err != nil {
+		t.Errorf("branch should still exist: %v\n%s", err, out)
+	}
+}
+
+func Test_Remove_ForceBranch_Deletes_Unmerged_Branch(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	createWorktreeWithUnmergedCommit(t, c, "force-branch-wt")
+
+	stdout, stderr, code := c.Run("--config", "config.json", "remove", "force-branch-wt", "--with-branch", "--force-branch")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Deleted branch: force-branch-wt")
+
+	_, err := testGitCmd("-C", c.Dir, "rev-parse", "--verify", "refs/heads/force-branch-wt").CombinedOutput()
+	if err == nil {
+		t.Error("branch should be deleted")
+	}
+}
+
+func Test_Remove_WithBranch_Deletes_Checked_Out_Branch_Not_Metadata_Name(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "foo"))
+
+	out, err := testGitCmd("-C", wtPath, "switch", "-c", "feature-x").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git switch failed: %v\n%s", err, out)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "remove", "feature-x", "--with-branch")
+	AssertContains(t, stdout, "Deleted branch: feature-x")
+
+	branches := listBranches(t, c.Dir)
+	if slices.Contains(branches, "feature-x") {
+		t.Error("feature-x, checked out in the worktree, should be deleted")
+	}
+
+	if !slices.Contains(branches, "foo") {
+		t.Errorf("foo is not checked out in the worktree and should be kept, got %v", branches)
+	}
+}
+
+func Test_Remove_By_Branch_Name(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "wt-dir")
+
+	wtPath := filepath.Join(c.Dir, "worktrees", "wt-dir")
+
+	out, err := testGitCmd("-C", wtPath, "checkout", "-b", "feature/remove-me").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git checkout failed: %v\n%s", err, out)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "remove", "feature/remove-me")
+	AssertContains(t, stdout, wtPath)
+
+	if c.FileExists("worktrees/wt-dir") {
+		t.Error("worktree should be removed when looked up by branch")
+	}
+}
+
+func Test_Remove_Does_Not_Match_By_ID(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "keep-me")
+
+	_, stderr, code := c.Run("--config", "config.json", "remove", "1")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "worktree not found: 1")
+
+	if !c.FileExists("worktrees/keep-me") {
+		t.Error("worktree must not be removed by numeric id")
+	}
+}
+
+// createWorktreeTree creates parent, child (created from inside parent) and
+// grandchild (created from inside child) worktrees and returns their paths.
+func createWorktreeTree(t *testing.T, c *CLI) (string, string, string) {
+	t.Helper()
+
+	cfgPath := filepath.Join(c.Dir, "config.json")
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	parent := extractPath(c.MustRun("--config", cfgPath, "create", "--name", "parent"))
+
+	stdout, stderr, code := c.RunInDir(parent, "--config", cfgPath, "create", "--name", "child")
+	if code != 0 {
+		t.Fatalf("create child failed: %s", stderr)
+	}
+
+	child := extractPath(stdout)
+
+	stdout, stderr, code = c.RunInDir(child, "--config", cfgPath, "create", "--name", "grandchild")
+	if code != 0 {
+		t.Fatalf("create grandchild failed: %s", stderr)
+	}
+
+	return parent, child, extractPath(stdout)
+}
+
+func Test_Remove_Refuses_Worktree_With_Children(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	parent, child, _ := createWorktreeTree(t, c)
+
+	AssertContains(t, c.ReadFileAt(child, ".wt/worktree.json"), `"parent_id": 1`)
+
+	_, stderr, code := c.Run("--config", "config.json", "remove", "parent", "--force")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "worktree has child worktrees: grandchild, child")
+	AssertContains(t, stderr, "--recursive")
+
+	if !statTestPath(parent) || !statTestPath(child) {
+		t.Error("nothing should be removed when children exist")
+	}
+
+	_, stderr, code = c.Run("--config", "config.json", "remove", "parent", "--recursive", "--orphan")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --recursive and --orphan together")
+}
+
+func Test_Remove_Recursive_Removes_Children_First(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	parent, child, grandchild := createWorktreeTree(t, c)
+	c.MustRun("--config", "config.json", "create", "--name", "sibling")
+
+	stdout := c.MustRun("--config", "config.json", "remove", "parent", "--recursive", "--force", "--with-branch")
+
+	grandchildAt := strings.Index(stdout, "Removed worktree: "+grandchild)
+	childAt := strings.Index(stdout, "Removed worktree: "+child)
+	parentAt := strings.Index(stdout, "Removed worktree: "+parent)
+
+	if grandchildAt < 0 || childAt < 0 || parentAt < 0 || grandchildAt > childAt || childAt > parentAt {
+		t.Errorf("expected grandchild, child, parent removal order, got:\n%s", stdout)
+	}
+
+	branches := listBranches(t, c.Dir)
+	for _, name := range []string{"parent", "child", "grandchild"} {
+		if slices.Contains(branches, name) {
+			t.Errorf("branch %s should be deleted", name)
+		}
+	}
+
+	if !c.FileExists("worktrees/sibling") {
+		t.Error("unrelated worktrees must be kept")
+	}
+}
+
+func Test_Remove_Orphan_Keeps_Children(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	parent, child, grandchild := createWorktreeTree(t, c)
+
+	c.MustRun("--config", "config.json", "remove", "parent", "--orphan", "--force")
+
+	if statTestPath(parent) {
+		t.Error("parent should be removed")
+	}
+
+	if !statTestPath(child) || !statTestPath(grandchild) {
+		t.Error("children should be kept with --orphan")
+	}
+
+	childInfo, err := readWorktreeInfo(fs.NewReal(), child)
+	if err != nil {
+		t.Fatalf("reading child metadata: %v", err)
+	}
+
+	if childInfo.ParentID != 0 {
+		t.Errorf("orphaned child should have no parent_id, got %d", childInfo.ParentID)
+	}
+
+	grandchildInfo, err := readWorktreeInfo(fs.NewReal(), grandchild)
+	if err != nil {
+		t.Fatalf("reading grandchild metadata: %v", err)
+	}
+
+	if grandchildInfo.ParentID != childInfo.ID {
+		t.Errorf("grandchild should keep its parent %d, got %d", childInfo.ID, grandchildInfo.ParentID)
+	}
+}
+
+func Test_Remove_BranchOnly_Deletes_Branch_And_Keeps_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "keep-files"))
+	writeTestFile(t, filepath.Join(wtPath, "scratch.txt"), "uncommitted")
+
+	stdout := c.MustRun("--config", "config.json", "remove", "keep-files", "--branch-only")
+
+	AssertContains(t, stdout, "Deleted branch: keep-files")
+	AssertContains(t, stdout, "Worktree kept (detached HEAD): "+wtPath)
+
+	if slices.Contains(listBranches(t, c.Dir), "keep-files") {
+		t.Error("branch should be deleted")
+	}
+
+	if !c.FileExists("worktrees/keep-files/scratch.txt") {
+		t.Error("uncommitted files should be kept")
+	}
+
+	out, err := testGitCmd("-C", wtPath, "branch", "--show-current").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "" {
+		t.Errorf("expected detached HEAD, got %q (%v)", out, err)
+	}
+
+	info, err := readWorktreeInfo(fs.NewReal(), wtPath)
+	if err != nil {
+		t.Fatalf("reading metadata: %v", err)
+	}
+
+	if !info.Detached {
+		t.Error("metadata should record the worktree as detached")
+	}
+
+	// A detached worktree has nothing to merge
+	_, stderr, code := NewCLITesterAt(t, wtPath).Run("--config", "../config.json", "merge")
+	if code != 1 {
+		t.Fatalf("expected merge to fail, got exit code %d", code)
+	}
+
+	AssertContains(t, stderr, "HEAD is detached")
+}
+
+func Test_Remove_BranchOnly_Keeps_Unmerged_Branch_Checked_Out(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := createWorktreeWithUnmergedCommit(t, c, "unmerged-wt")
+
+	_, stderr, code := c.Run("--config", "config.json", "remove", "unmerged-wt", "--branch-only")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "deleting branch unmerged-wt")
+	AssertContains(t, stderr, "--force-branch")
+
+	out, err := testGitCmd("-C", wtPath, "branch", "--show-current").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "unmerged-wt" {
+		t.Errorf("expected the branch to be checked out again, got %q (%v)", out, err)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "remove", "unmerged-wt", "--branch-only", "--force-branch")
+
+	AssertContains(t, stdout, "Deleted branch: unmerged-wt")
+
+	if !c.FileExists("worktrees/unmerged-wt/unmerged.txt") {
+		t.Error("worktree files should be kept")
+	}
+}
+
+func Test_Remove_BranchOnly_Conflicts_With_Removal_Flags(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "flags-wt")
+
+	for _, flag := range []string{"--with-branch", "--recursive", "--orphan"} {
+		_, stderr, code := c.Run("--config", "config.json", "remove", "flags-wt", "--branch-only", flag)
+		if code != 1 {
+			t.Errorf("%s: expected exit code 1, got %d", flag, code)
+		}
+
+		AssertContains(t, stderr, "cannot use --branch-only with "+flag)
+	}
+}
+
+func Test_Remove_Prompts_For_Branch_When_Stdin_Is_Terminal(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "prompted")
+
+	stdout, stderr, code := c.RunWithInput(ttyReader{strings.NewReader("y\n")}, "--config", "config.json", "remove", "prompted")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Also delete the branch? (y/N)")
+	AssertContains(t, stdout, "Deleted branch: prompted")
+
+	if slices.Contains(listBranches(t, c.Dir), "prompted") {
+		t.Error("branch should be deleted after answering y")
+	}
+}
+
+func Test_Remove_Does_Not_Prompt_When_Non_Interactive_Or_Piped(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "flagged")
+	c.MustRun("--config", "config.json", "create", "--name", "piped")
+
+	runs := map[string]struct {
+		stdin any
+		args  []string
+	}{
+		"flagged": {ttyReader{strings.NewReader("y\n")}, []string{"--non-interactive", "--config", "config.json", "remove", "flagged"}},
+		"piped":   {strings.NewReader("y\n"), []string{"--config", "config.json", "remove", "piped"}},
+	}
+
+	for name, run := range runs {
+		stdout, stderr, code := c.RunWithInput(run.stdin, run.args...)
+		if code != 0 {
+			t.Fatalf("%s: expected exit code 0, got %d\nstderr: %s", name, code, stderr)
+		}
+
+		AssertNotContains(t, stdout, "Also delete the branch?")
+
+		if !slices.Contains(listBranches(t, c.Dir), name) {
+			t.Errorf("%s: branch should be kept without a prompt", name)
+		}
+	}
+}
+
+func Test_Remove_Porcelain_Prints_One_Record_Per_Removed_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	parentPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "parent"))
+	childPath := extractPath(NewCLITesterAt(t, parentPath).MustRun("--config", filepath.Join(c.Dir, "config.json"), "create", "--name", "child"))
+
+	stdout, stderr, code := c.Run("--config", "config.json", "remove", "parent", "--recursive", "--with-branch", "--porcelain")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	want := "status\tremoved\nname\tchild\npath\t" + childPath + "\nbranch_deleted\ttrue\n" +
+		"status\tremoved\nname\tparent\npath\t" + parentPath + "\nbranch_deleted\ttrue\n"
+	if stdout != want {
+		t.Errorf("stdout =\n%s\nwant\n%s", stdout, want)
+	}
+
+	AssertContains(t, stderr, "Removed worktree: "+parentPath)
+
+	// The branch prompt goes to stderr too
+	c.MustRun("--config", "config.json", "create", "--name", "prompted")
+
+	stdout, stderr, code = c.RunWithInput(ttyReader{strings.NewReader("n\n")}, "--config", "config.json", "remove", "prompted", "--porcelain")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stderr, "Also delete the branch? (y/N)")
+	AssertContains(t, stdout, "name\tprompted\n")
+	AssertContains(t, stdout, "branch_deleted\tfalse\n")
+	AssertNotContains(t, stdout, "Also delete")
+}
+
+func Test_Remove_All_Removes_Every_Worktree_And_Reports_Failures(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	parent, child, grandchild := createWorktreeTree(t, c)
+	c.MustRun("--config", "config.json", "create", "--name", "vetoed")
+
+	c.WriteExecutable(".wt/hooks/pre-delete", "#!/bin/sh\nif [ \"$WT_NAME\" = vetoed ]; then exit 1; fi\necho \"pre-delete $WT_NAME\"\n")
+
+	stdout, stderr, code := c.Run("--config", "config.json", "remove", "--all", "--force", "--with-branch")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+
+	AssertContains(t, stdout, "pre-delete grandchild")
+	AssertContains(t, stdout, "Removed 3 of 4 worktrees.")
+	AssertContains(t, stdout, "Could not remove: vetoed")
+	AssertContains(t, stderr, "some worktrees could not be removed")
+	AssertContains(t, stderr, "vetoed: pre-delete hook aborted deletion")
+
+	grandchildAt := strings.Index(stdout, "Removed worktree: "+grandchild)
+	childAt := strings.Index(stdout, "Removed worktree: "+child)
+	parentAt := strings.Index(stdout, "Removed worktree: "+parent)
+
+	if grandchildAt < 0 || childAt < 0 || parentAt < 0 || grandchildAt > childAt || childAt > parentAt {
+		t.Errorf("expected grandchild, child, parent removal order, got:\n%s", stdout)
+	}
+
+	branches := listBranches(t, c.Dir)
+	for _, name := range []string{"parent", "child", "grandchild"} {
+		if slices.Contains(branches, name) {
+			t.Errorf("branch %s should be deleted", name)
+		}
+	}
+
+	if !c.FileExists("worktrees/vetoed") {
+		t.Error("worktree vetoed by the hook should be kept")
+	}
+}
+
+func Test_Remove_All_Requires_Confirmation(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "one")
+	c.MustRun("--config", "config.json", "create", "--name", "two")
+
+	_, stderr, code := c.Run("--config", "config.json", "remove", "--all")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 without a terminal, got %d", code)
+	}
+
+	AssertContains(t, stderr, "refusing to remove all worktrees without confirmation")
+
+	stdout, stderr, code := c.RunWithInput(ttyReader{strings.NewReader("n\n")}, "--config", "config.json", "remove", "--all")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Remove all 2 worktrees? (y/N)")
+	AssertContains(t, stdout, "Aborted, no worktrees were removed.")
+
+	if !c.FileExists("worktrees/one") || !c.FileExists("worktrees/two") {
+		t.Fatal("declining must keep all worktrees")
+	}
+
+	stdout, stderr, code = c.RunWithInput(ttyReader{strings.NewReader("y\n")}, "--config", "config.json", "remove", "--all")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	AssertContains(t, stdout, "Removed 2 of 2 worktrees.")
+
+	if c.FileExists("worktrees/one") || c.FileExists("worktrees/two") {
+		t.Error("confirming should remove all worktrees")
+	}
+
+	if !slices.Contains(listBranches(t, c.Dir), "one") {
+		t.Error("branches should be kept without --with-branch")
+	}
+
+	_, stderr, code = c.Run("--config", "config.json", "remove", "one", "--all")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --all with a worktree name")
+}
+
+func Test_Remove_Ignore_Untracked_Removes_Worktree_With_Only_Untracked_Files(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "scratch"))
+
+	writeTestFile(t, filepath.Join(wtPath, "notes.txt"), "scratch\n")
+
+	_, stderr, code := c.Run("--config", "config.json", "remove", "scratch")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "uncommitted changes")
+	AssertContains(t, stderr, "--ignore-untracked")
+
+	writeTestFile(t, filepath.Join(wtPath, "README.md"), "# Modified\n")
+
+	_, stderr, code = c.Run("--config", "config.json", "remove", "scratch", "--ignore-untracked")
+	if code != 1 {
+		t.Fatalf("expected exit code 1 with a modified tracked file, got %d", code)
+	}
+
+	AssertContains(t, stderr, "uncommitted changes")
+
+	gitOutput(t, wtPath, "checkout", "README.md")
+
+	c.MustRun("--config", "config.json", "remove", "scratch", "--ignore-untracked")
+
+	if c.FileExists("worktrees/scratch") {
+		t.Error("worktree with only untracked files should be removed")
+	}
+}
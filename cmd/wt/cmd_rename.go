@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// Errors for rename command.
+var (
+	errRenameArgsRequired = errors.New("old and new name are required (usage: wt rename <old> <new>)")
+	errRenameSameName     = errors.New("worktree already has this name")
+	errRenameRolledBack   = errors.New("rename failed, changes were undone")
+)
+
+// RenameCmd returns the rename command.
+func RenameCmd(cfg Config, fsys fs.FS, git *Git) *Command {
+	flags := flag.NewFlagSet("rename", flag.ContinueOnError)
+	flags.BoolP("help", "h", false, "Show help")
+
+	return &Command{
+		Flags: flags,
+		Usage: "rename <old> <new> [flags]",
+		Short: "Rename a worktree and its branch",
+		Long: `Rename a worktree, e.g. one created with a generated name.
+
+<old> is looked up like wt info does: numeric id, name, agent_id or
+checked-out branch. The worktree's branch is renamed (git branch -m) if it
+is named after the worktree (keeping a branch_prefix it was created with,
+e.g. agent/old -> agent/new), the directory is moved to <new> in the base
+directory (git worktree move), and name in .wt/worktree.json is updated.
+The id and agent_id stay the same. The name_slug config applies to <new>
+like it does to create --name, and <new> must be a valid name like there:
+not empty, . or .., and without / or \.
+
+The rename is refused if <new> is already used by another worktree or by
+a branch. It holds the same lock as wt create, so a concurrent create
+cannot take the name meanwhile. If a step fails, the earlier ones are
+undone.`,
+		Exec: func(ctx context.Context, _ io.Reader, stdout, stderr io.Writer, args []string) error {
+			if len(args) != 2 {
+				return errRenameArgsRequired
+			}
+
+			return execRename(ctx, informational(cfg, stdout), stderr, cfg, fsys, git, args[0], args[1])
+		},
+	}
+}
+
+func execRename(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	identifier, newName string,
+) error {
+	if cfg.NameSlug != nil {
+		slug, err := slugifyName(newName, *cfg.NameSlug)
+		if err != nil {
+			return err
+		}
+
+		newName = slug
+	}
+
+	err := validateWorktreeName(newName)
+	if err != nil {
+		return err
+	}
+
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	gitCommonDir, err := git.GitCommonDir(ctx, mainRepoRoot)
+	if err != nil {
+		return fmt.Errorf("cannot determine git directory: %w", err)
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	// Hold the create lock so no worktree takes the new name meanwhile
+	lock, err := acquireCreateLock(ctx, fsys, gitCommonDir, cfg.LockTimeout)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = lock.Close() }()
+
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("scanning worktrees: %w", err)
+	}
+
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	gitIndex := newGitWorktreeIndex(entries)
+
+	wt, found, err := findWorktreeByIdentifier(worktrees, gitIndex, identifier, identifierKeysAll...)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("%w: %s", errWorktreeNotFound, identifier)
+	}
+
+	if newName == wt.Name {
+		return fmt.Errorf("%w: %s", errRenameSameName, newName)
+	}
+
+	newBranch := renamedBranch(&wt.WorktreeInfo, newName)
+
+	err = checkRenameTarget(ctx, fsys, git, mainRepoRoot, worktrees, wt, filepath.Join(baseDir, newName), newName, newBranch)
+	if err != nil {
+		return err
+	}
+
+	entry, _ := gitIndex.lookup(wt.Path)
+
+	err = runRename(ctx, fsys, git, mainRepoRoot, wt, entry, filepath.Join(baseDir, newName), newName, newBranch)
+	if err != nil {
+		return err
+	}
+
+	fprintf(stdout, "Renamed worktree: %s -> %s\n", wt.Name, newName)
+	fprintf(stdout, "  path:        %s\n", filepath.Join(baseDir, newName))
+
+	if entry.Branch == wt.BranchName() {
+		fprintf(stdout, "  branch:      %s\n", newBranch)
+	} else if entry.Branch != "" {
+		fprintf(stdout, "  branch:      %s (kept, not named after the worktree)\n", entry.Branch)
+	}
+
+	if currentRoot, findErr := findWorktreeRoot(fsys, cfg.EffectiveCwd); findErr == nil && isSamePath(currentRoot, wt.Path) {
+		fprintf(stderr, "note: the current directory was moved, run: cd %s\n", filepath.Join(baseDir, newName))
+	}
+
+	return nil
+}
+
+// renamedBranch returns the branch of a worktree renamed to newName: its
+// branch with the worktree name replaced, so a branch_prefix it was created
+// with is kept.
+func renamedBranch(info *WorktreeInfo, newName string) string {
+	prefix, ok := strings.CutSuffix(info.BranchName(), info.Name)
+	if !ok {
+		return newName
+	}
+
+	return prefix + newName
+}
+
+// checkRenameTarget fails if newName is used by another worktree (name or
+// agent_id), newBranch by a branch, or newPath by a directory.
+func checkRenameTarget(
+	ctx context.Context,
+	fsys fs.FS,
+	git *Git,
+	mainRepoRoot string,
+	worktrees []WorktreeWithPath,
+	wt WorktreeWithPath,
+	newPath, newName, newBranch string,
+) error {
+	others := make([]WorktreeInfo, 0, len(worktrees))
+
+	for _, other := range worktrees {
+		if other.ID != wt.ID {
+			others = append(others, other.WorktreeInfo)
+		}
+	}
+
+	if slices.Contains(getExistingNames(others), newName) {
+		return fmt.Errorf("%w: %s", ErrNameAlreadyInUse, newName)
+	}
+
+	exists, err := git.BranchExists(ctx, mainRepoRoot, newBranch)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return fmt.Errorf("%w: %s (a branch has this name)", ErrNameAlreadyInUse, newBranch)
+	}
+
+	if _, statErr := fsys.Stat(newPath); statErr == nil {
+		return fmt.Errorf("%w: %s (directory exists)", ErrNameAlreadyInUse, newPath)
+	}
+
+	return nil
+}
+
+// runRename renames the branch (if named after the worktree), moves the
+// worktree and rewrites its metadata. A failed step undoes the earlier ones.
+func runRename(
+	ctx context.Context,
+	fsys fs.FS,
+	git *Git,
+	mainRepoRoot string,
+	wt WorktreeWithPath,
+	entry WorktreeEntry,
+	newPath, newName, newBranch string,
+) error {
+	// Undo with a fresh context so an interrupt still rolls back
+	undoCtx := context.WithoutCancel(ctx)
+
+	renameBranch := entry.Branch == wt.BranchName()
+	if renameBranch {
+		err := git.BranchRename(ctx, mainRepoRoot, wt.BranchName(), newBranch)
+		if err != nil {
+			return err
+		}
+	}
+
+	undoBranch := func() error {
+		if !renameBranch {
+			return nil
+		}
+
+		return git.BranchRename(undoCtx, mainRepoRoot, newBranch, wt.BranchName())
+	}
+
+	err := git.WorktreeMove(ctx, mainRepoRoot, wt.Path, newPath, entry.Locked)
+	if err != nil {
+		return errors.Join(fmt.Errorf("%w: %w", errRenameRolledBack, err), undoBranch())
+	}
+
+	info := wt.WorktreeInfo
+	info.Name = newName
+
+	if renameBranch {
+		info.Branch = ""
+		if newBranch != newName {
+			info.Branch = newBranch
+		}
+	}
+
+	err = writeWorktreeInfo(fsys, newPath, &info)
+	if err != nil {
+		moveErr := git.WorktreeMove(undoCtx, mainRepoRoot, newPath, wt.Path, entry.Locked)
+
+		return errors.Join(fmt.Errorf("%w: %w", errRenameRolledBack, err), moveErr, undoBranch())
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+)
+
+func Test_Rename_Renames_Worktree_Branch_And_Metadata(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	oldPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "swift-fox"))
+
+	before, err := readWorktreeInfo(fs.NewReal(), oldPath)
+	if err != nil {
+		t.Fatalf("reading metadata: %v", err)
+	}
+
+	stdout := c.MustRun("--config", "config.json", "rename", "swift-fox", "fix-login")
+
+	newPath := filepath.Join(c.Dir, "worktrees", "fix-login")
+
+	AssertContains(t, stdout, "Renamed worktree: swift-fox -> fix-login")
+	AssertContains(t, stdout, "path:        "+newPath)
+	AssertContains(t, stdout, "branch:      fix-login")
+
+	if c.FileExists("worktrees/swift-fox") {
+		t.Error("old directory should be gone")
+	}
+
+	after, err := readWorktreeInfo(fs.NewReal(), newPath)
+	if err != nil {
+		t.Fatalf("reading metadata: %v", err)
+	}
+
+	if after.Name != "fix-login" || after.ID != before.ID || after.AgentID != before.AgentID {
+		t.Errorf("expected name fix-login with id %d and agent_id %s, got %+v", before.ID, before.AgentID, after)
+	}
+
+	branches := listBranches(t, c.Dir)
+	if !slices.Contains(branches, "fix-login") || slices.Contains(branches, "swift-fox") {
+		t.Errorf("expected branch renamed to fix-login, got %v", branches)
+	}
+
+	out, err := testGitCmd("-C", newPath, "branch", "--show-current").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "fix-login" {
+		t.Errorf("expected fix-login checked out at new path, got %q (%v)", out, err)
+	}
+
+	// git tracks the worktree at its new path, and wt finds it by the new name
+	AssertContains(t, c.MustRun("--config", "config.json", "switch", "fix-login"), newPath)
+}
+
+func Test_Rename_Rejects_Names_In_Use(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	c.MustRun("--config", "config.json", "create", "--name", "first")
+	c.MustRun("--config", "config.json", "create", "--name", "second")
+	createBranch(t, c.Dir, "leftover")
+
+	for _, newName := range []string{"second", "leftover"} {
+		_, stderr, code := c.Run("--config", "config.json", "rename", "first", newName)
+		if code != 1 {
+			t.Errorf("rename to %s: expected exit code 1, got %d", newName, code)
+		}
+
+		AssertContains(t, stderr, "already in use")
+	}
+
+	if !c.FileExists("worktrees/first/.wt/worktree.json") {
+		t.Error("worktree should be unchanged")
+	}
+
+	if !slices.Contains(listBranches(t, c.Dir), "first") {
+		t.Error("branch should be unchanged")
+	}
+}
+
+func Test_Rename_Rejects_Invalid_Names(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	c.MustRun("--config", "config.json", "create", "--name", "first")
+
+	for _, newName := range []string{"", " ", "..", "../escaped", "nested/name", `back\slash`} {
+		_, stderr, code := c.Run("--config", "config.json", "rename", "first", newName)
+		if code != 1 {
+			t.Errorf("rename to %q: expected exit code 1, got %d", newName, code)
+		}
+
+		AssertContains(t, stderr, "invalid name")
+	}
+
+	if !c.FileExists("worktrees/first/.wt/worktree.json") {
+		t.Error("worktree should be unchanged")
+	}
+
+	if c.FileExists("escaped") {
+		t.Error("no directory should be created outside the base")
+	}
+}
+
+func Test_Rename_Requires_Two_Arguments(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	_, stderr, code := c.Run("rename", "only-one")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "usage: wt rename <old> <new>")
+}
+
+func Test_Rename_Returns_Error_When_Worktree_Not_Found(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := c.Run("--config", "config.json", "rename", "missing", "new-name")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "worktree not found: missing")
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -29,6 +30,11 @@ func Run(stdin io.Reader, stdout, stderr io.Writer, args []string, env map[strin
 	flagVersion := globalFlags.BoolP("version", "v", false, "Show version and exit")
 	flagCwd := globalFlags.StringP("cwd", "C", "", "Run as if started in `dir`")
 	flagConfig := globalFlags.StringP("config", "c", "", "Use specified config `file`")
+	flagNonInteractive := globalFlags.Bool("non-interactive", false, "Never prompt, even on a terminal")
+	flagQuiet := globalFlags.BoolP("quiet", "q", false, "Suppress informational output (errors and requested output are kept)")
+	flagNoHooks := globalFlags.Bool("no-hooks", false, "Do not run any hooks")
+	flagCleanupTimeout := globalFlags.String("cleanup-timeout", "", "Wait up to `duration` for cleanup after an interrupt")
+	flagLockTimeout := globalFlags.String("lock-timeout", "", "Wait up to `duration` for the create lock (0 = fail at once)")
 
 	err := globalFlags.Parse(args[1:])
 	if err != nil {
@@ -39,6 +45,20 @@ func Run(stdin io.Reader, stdout, stderr io.Writer, args []string, env map[strin
 		return 1
 	}
 
+	cleanupTimeout, err := resolveCleanupTimeout(*flagCleanupTimeout, env)
+	if err != nil {
+		fprintError(stderr, err)
+
+		return 1
+	}
+
+	lockTimeout, err := resolveLockTimeout(*flagLockTimeout, env)
+	if err != nil {
+		fprintError(stderr, err)
+
+		return 1
+	}
+
 	// Handle --version early, before loading config
 	if *flagVersion {
 		if commit == "none" && date == "unknown" {
@@ -77,14 +97,31 @@ func Run(stdin io.Reader, stdout, stderr io.Writer, args []string, env map[strin
 		return 1
 	}
 
+	cfg.NonInteractive = *flagNonInteractive
+	cfg.Quiet = *flagQuiet
+	cfg.NoHooks = *flagNoHooks
+	cfg.LockTimeout = lockTimeout
+
 	// Create all commands
 	commands := []*Command{
 		CreateCmd(cfg, fsys, git, env),
 		LsCmd(cfg, fsys, git),
 		InfoCmd(cfg, fsys, git),
+		StatusCmd(cfg, fsys, git),
+		SwitchCmd(cfg, fsys, git),
+		OpenCmd(cfg, fsys, git, env),
+		RenameCmd(cfg, fsys, git),
+		LabelCmd(cfg, fsys, git),
 		RemoveCmd(cfg, fsys, git, env),
 		MergeCmd(cfg, fsys, git, env),
+		PruneCmd(cfg, fsys, git),
+		CleanCmd(cfg, fsys, git, env),
+		MigrateCmd(cfg, fsys, git),
+		ConfigCmd(cfg, fsys, git),
+		DoctorCmd(cfg, fsys, git, env),
+		ExecCmd(cfg, fsys, git, env),
 		InitCmd(),
+		ShellInitCmd(),
 	}
 
 	commandMap := make(map[string]*Command, len(commands)*2)
@@ -104,11 +141,26 @@ func Run(stdin io.Reader, stdout, stderr io.Writer, args []string, env map[strin
 		return 0
 	}
 
-	// Dispatch to command
+	// Dispatch to command; built-ins take precedence over wt-<name> plugins
 	cmdName := commandAndArgs[0]
 
-	cmd, ok := commandMap[cmdName]
-	if !ok {
+	cmd, isBuiltin := commandMap[cmdName]
+
+	pluginPath, isPlugin := "", false
+	if !isBuiltin {
+		pluginPath, isPlugin = findPlugin(fsys, env, cmdName)
+	}
+
+	var run func() int
+
+	switch {
+	case isBuiltin:
+		run = func() int { return cmd.Run(ctx, stdin, stdout, stderr, commandAndArgs[1:]) }
+	case isPlugin:
+		run = func() int {
+			return runPlugin(ctx, stdin, stdout, stderr, cfg, git, env, pluginPath, commandAndArgs[1:])
+		}
+	default:
 		fprintErrorMsg(stderr, "unknown command: %s", cmdName)
 		fprintln(stderr)
 		printUsage(stderr, commands)
@@ -120,7 +172,7 @@ func Run(stdin io.Reader, stdout, stderr io.Writer, args []string, env map[strin
 	done := make(chan int, 1)
 
 	go func() {
-		done <- cmd.Run(ctx, stdin, stdout, stderr, commandAndArgs[1:])
+		done <- run()
 	}()
 
 	// Handle nil sigCh for tests
@@ -133,7 +185,7 @@ func Run(stdin io.Reader, stdout, stderr io.Writer, args []string, env map[strin
 	case exitCode := <-done:
 		return exitCode
 	case <-sigCh:
-		fprintln(stderr, "Interrupted, waiting up to 10s for cleanup... (Ctrl+C again to force exit)")
+		fprintf(stderr, "Interrupted, waiting up to %s for cleanup... (Ctrl+C again to force exit)\n", cleanupTimeout)
 		cancel()
 	}
 
@@ -143,7 +195,7 @@ func Run(stdin io.Reader, stdout, stderr io.Writer, args []string, env map[strin
 		fprintln(stderr, "Cleanup complete.")
 
 		return 130
-	case <-time.After(10 * time.Second):
+	case <-time.After(cleanupTimeout):
 		fprintln(stderr, "Cleanup timed out, forced exit.")
 
 		return 130
@@ -154,6 +206,55 @@ func Run(stdin io.Reader, stdout, stderr io.Writer, args []string, env map[strin
 	}
 }
 
+// defaultCleanupTimeout is how long Run waits for the command to clean up
+// after the first interrupt.
+const defaultCleanupTimeout = 10 * time.Second
+
+var errInvalidCleanupTimeout = errors.New("invalid cleanup timeout (use a Go duration like 30s or 2m)")
+
+// resolveCleanupTimeout returns the --cleanup-timeout value, else
+// WT_CLEANUP_TIMEOUT from env, else defaultCleanupTimeout.
+func resolveCleanupTimeout(flagValue string, env map[string]string) (time.Duration, error) {
+	value, source := flagValue, "--cleanup-timeout"
+	if value == "" {
+		value, source = env["WT_CLEANUP_TIMEOUT"], "WT_CLEANUP_TIMEOUT"
+	}
+
+	if value == "" {
+		return defaultCleanupTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("%w: %s=%q", errInvalidCleanupTimeout, source, value)
+	}
+
+	return timeout, nil
+}
+
+var errInvalidLockTimeout = errors.New("invalid lock timeout (use a Go duration like 500ms or 30s, or 0)")
+
+// resolveLockTimeout returns the --lock-timeout value, else
+// WT_LOCK_TIMEOUT from env, else createLockTimeout. 0 is allowed and
+// means the lock is tried only once.
+func resolveLockTimeout(flagValue string, env map[string]string) (time.Duration, error) {
+	value, source := flagValue, "--lock-timeout"
+	if value == "" {
+		value, source = env["WT_LOCK_TIMEOUT"], "WT_LOCK_TIMEOUT"
+	}
+
+	if value == "" {
+		return createLockTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("%w: %s=%q", errInvalidLockTimeout, source, value)
+	}
+
+	return timeout, nil
+}
+
 func fprintln(output io.Writer, a ...any) {
 	_, _ = fmt.Fprintln(output, a...)
 }
@@ -164,8 +265,10 @@ func fprintf(output io.Writer, format string, a ...any) {
 
 // ANSI color codes for terminal output.
 const (
-	colorRed   = "\033[31m"
-	colorReset = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorReset  = "\033[0m"
 )
 
 // fprintError prints an error message with optional red coloring for TTY.
@@ -189,7 +292,19 @@ func fprintErrorMsg(output io.Writer, msg string, args ...any) {
 const globalOptionsHelp = `  -h, --help             Show help
   -v, --version          Show version and exit
   -C, --cwd <dir>        Run as if started in <dir>
-  -c, --config <file>    Use specified config file`
+  -c, --config <file>    Use specified config file
+      --non-interactive  Never prompt, even on a terminal
+      --no-hooks         Do not run any hooks (pre-create, post-create,
+                         pre-delete, post_create_cmd)
+  -q, --quiet            Suppress informational output such as
+                         "Created worktree:" and hook output
+      --cleanup-timeout <duration>
+                         Wait up to <duration> for cleanup after Ctrl+C
+                         (default 10s, or $WT_CLEANUP_TIMEOUT)
+      --lock-timeout <duration>
+                         Wait up to <duration> for the create lock held
+                         by another wt process, 0 to fail at once
+                         (default 5s, or $WT_LOCK_TIMEOUT)`
 
 func printGlobalOptions(output io.Writer) {
 	fprintln(output, "Usage: wt [flags] <command> [args]")
@@ -216,20 +331,82 @@ func printUsage(output io.Writer, commands []*Command) {
 
 	fprintln(output)
 	fprintln(output, "Run 'wt <command> --help' for more information on a command.")
+	fprintln(output, "Other commands run a wt-<command> executable from PATH, if one exists.")
 }
 
 // Config holds the application configuration.
 type Config struct {
-	Base string `json:"base"`
+	Base       string `json:"base"`
+	ReadmeFile string `json:"readme_file"` // File name written by create --readme
+
+	// Directories new worktrees are restricted to via sparse-checkout (empty = full checkout)
+	SparseCheckout []string `json:"sparse_checkout"`
+
+	// Globs of gitignored files create --with-changes copies too
+	CopyIgnored []string `json:"copy_ignored"`
+
+	// Directory whose contents are copied into every new worktree
+	TemplateDir string `json:"template_dir"`
+
+	// Directory hooks are run from instead of .wt/hooks
+	HooksDir string `json:"hooks_dir"`
+
+	// Prepended to the worktree name to get the branch create makes (e.g. "agent/")
+	BranchPrefix string `json:"branch_prefix"`
+
+	// Shell command create runs in every new worktree after the post-create hook
+	PostCreateCmd string `json:"post_create_cmd"`
+
+	// How --name is slugified into a directory and branch name (nil = use as given)
+	NameSlug *NameSlugConfig `json:"name_slug"`
+
+	// Branch merge targets by default instead of each worktree's base_branch
+	DefaultMergeTarget string `json:"default_merge_target"`
+
+	// Older name of default_merge_target, folded into it on load
+	MergeInto string `json:"merge_into"`
+
+	// Add a Squashed-from/Merged-from trailer per commit to merge --squash and
+	// --merge-commit messages
+	MergeMessageTrailer bool `json:"merge_message_trailer"`
+
+	// Scheme for auto-generated names: adjective-animal (default), uuid,
+	// numeric or a template like agent-<n>
+	Naming string `json:"naming"`
 
 	// Resolved paths (computed, not serialized)
 	EffectiveCwd string `json:"-"` // Absolute working directory (from -C flag or os.Getwd)
+
+	// Set by --non-interactive: never prompt, even on a terminal
+	NonInteractive bool `json:"-"`
+
+	// Set by --quiet: drop informational stdout (see informational)
+	Quiet bool `json:"-"`
+
+	// Set by the global --no-hooks: run no hook files (see resolveHooksDir)
+	NoHooks bool `json:"-"`
+
+	// Set by --lock-timeout: how long to wait for the create lock
+	LockTimeout time.Duration `json:"-"`
+}
+
+// informational returns where a command writes its human progress and
+// success messages ("Created worktree:", hook output, ...): w, or
+// io.Discard with --quiet. Output the user explicitly asked for (--json,
+// --porcelain, --switch, --dry-run, wt ls, ...) does not go through it.
+func informational(cfg Config, w io.Writer) io.Writer {
+	if cfg.Quiet {
+		return io.Discard
+	}
+
+	return w
 }
 
 // DefaultConfig returns the default configuration.
 func DefaultConfig() Config {
 	return Config{
-		Base: "~/code/worktrees",
+		Base:       "~/code/worktrees",
+		ReadmeFile: "TASK.md",
 	}
 }
 
@@ -342,9 +519,37 @@ func loadConfigFile(fsys fs.FS, path string) (Config, error) {
 		return Config{}, fmt.Errorf("parsing config %s: %w", path, err)
 	}
 
+	if cfg.DefaultMergeTarget == "" {
+		cfg.DefaultMergeTarget = cfg.MergeInto
+	}
+
+	err = validateNamingScheme(cfg.Naming)
+	if err != nil {
+		return Config{}, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if cfg.ReadmeFile != "" {
+		err = validateReadmeFile(cfg.ReadmeFile)
+		if err != nil {
+			return Config{}, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+
 	return cfg, nil
 }
 
+var errInvalidReadmeFile = errors.New("readme_file must be a file name without directories")
+
+// validateReadmeFile checks a readme_file config value. It is joined to the
+// worktree path, so an absolute path or .. would write outside the worktree.
+func validateReadmeFile(name string) error {
+	if name == "" || name == "." || name == ".." || filepath.IsAbs(name) || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%w, got %q", errInvalidReadmeFile, name)
+	}
+
+	return nil
+}
+
 // mergeConfigs merges override into base, with override taking precedence.
 // Empty/zero values in override do not override base values.
 func mergeConfigs(base, override Config) Config {
@@ -354,6 +559,50 @@ func mergeConfigs(base, override Config) Config {
 		result.Base = override.Base
 	}
 
+	if override.ReadmeFile != "" {
+		result.ReadmeFile = override.ReadmeFile
+	}
+
+	if len(override.SparseCheckout) > 0 {
+		result.SparseCheckout = override.SparseCheckout
+	}
+
+	if len(override.CopyIgnored) > 0 {
+		result.CopyIgnored = override.CopyIgnored
+	}
+
+	if override.TemplateDir != "" {
+		result.TemplateDir = override.TemplateDir
+	}
+
+	if override.HooksDir != "" {
+		result.HooksDir = override.HooksDir
+	}
+
+	if override.BranchPrefix != "" {
+		result.BranchPrefix = override.BranchPrefix
+	}
+
+	if override.PostCreateCmd != "" {
+		result.PostCreateCmd = override.PostCreateCmd
+	}
+
+	if override.NameSlug != nil {
+		result.NameSlug = override.NameSlug
+	}
+
+	if override.DefaultMergeTarget != "" {
+		result.DefaultMergeTarget = override.DefaultMergeTarget
+	}
+
+	if override.MergeMessageTrailer {
+		result.MergeMessageTrailer = true
+	}
+
+	if override.Naming != "" {
+		result.Naming = override.Naming
+	}
+
 	return result
 }
 
@@ -363,6 +612,10 @@ func applyConfigDefaults(cfg Config) Config {
 		cfg.Base = DefaultConfig().Base
 	}
 
+	if cfg.ReadmeFile == "" {
+		cfg.ReadmeFile = DefaultConfig().ReadmeFile
+	}
+
 	return cfg
 }
 
@@ -381,18 +634,24 @@ func getUserConfigPath(env map[string]string) string {
 	return filepath.Join(home, ".config", "wt", "config.json")
 }
 
+// ErrHomeNotSet is returned when a ~-based path is used but the home
+// directory cannot be determined.
+var ErrHomeNotSet = errors.New("cannot expand '~': HOME not set; use an absolute base")
+
 // ExpandPath expands ~ to home directory.
-func ExpandPath(path string) string {
+// Returns ErrHomeNotSet instead of the unexpanded path when HOME is missing,
+// so callers never create a literal "~" directory.
+func ExpandPath(path string) (string, error) {
 	if strings.HasPrefix(path, "~/") {
 		home, err := os.UserHomeDir()
 		if err != nil {
-			return path
+			return "", fmt.Errorf("%w: %s", ErrHomeNotSet, path)
 		}
 
-		return filepath.Join(home, path[2:])
+		return filepath.Join(home, path[2:]), nil
 	}
 
-	return path
+	return path, nil
 }
 
 // IsAbsolutePath returns true if path is absolute (starts with / or ~).
@@ -416,6 +675,32 @@ func IsTerminal() bool {
 	return isTerminal()
 }
 
+// readerIsTerminal reports whether r is a terminal. Unlike IsTerminal it
+// checks the reader a command was given, not os.Stdin, so any reader with a
+// Stat method reporting a character device counts.
+func readerIsTerminal(r io.Reader) bool {
+	return statIsTerminal(r)
+}
+
+// writerIsTerminal reports whether w is a terminal, like readerIsTerminal.
+func writerIsTerminal(w io.Writer) bool {
+	return statIsTerminal(w)
+}
+
+func statIsTerminal(f any) bool {
+	statter, ok := f.(interface{ Stat() (os.FileInfo, error) })
+	if !ok {
+		return false
+	}
+
+	stat, err := statter.Stat()
+	if err != nil {
+		return false
+	}
+
+	return (stat.Mode() & os.ModeCharDevice) != 0
+}
+
 // getRepoName extracts the repository name from the root path.
 // Returns the last path component (directory name).
 func getRepoName(repoRoot string) string {
@@ -439,50 +724,123 @@ func getRepoName(repoRoot string) string {
 //
 //	base=../worktrees, main-repo=/code/myapp, name=swift-fox
 //	  => /code/worktrees/swift-fox
-func resolveWorktreePath(cfg Config, mainRepoRoot, worktreeName string) string {
-	base := ExpandPath(cfg.Base)
-
-	if IsAbsolutePath(cfg.Base) {
-		// Absolute: include repo name in path
-		repoName := getRepoName(mainRepoRoot)
-
-		return filepath.Join(base, repoName, worktreeName)
+//
+// Relative bases are never resolved from EffectiveCwd, so a worktree created
+// from inside another worktree lands next to it rather than nested within it.
+func resolveWorktreePath(cfg Config, mainRepoRoot, worktreeName string) (string, error) {
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return "", err
 	}
 
-	// Relative: resolve from main repo root, no repo name.
-	// This ensures consistency when creating from inside worktrees.
-	return filepath.Join(mainRepoRoot, base, worktreeName)
+	return filepath.Join(baseDir, worktreeName), nil
 }
 
 // resolveWorktreeBaseDir returns the directory containing worktrees for a repo.
-// Used by list/delete to find existing worktrees.
-func resolveWorktreeBaseDir(cfg Config, mainRepoRoot string) string {
-	base := ExpandPath(cfg.Base)
+// Used by create to place new worktrees and by list/delete to find existing ones.
+func resolveWorktreeBaseDir(cfg Config, mainRepoRoot string) (string, error) {
+	base, err := ExpandPath(cfg.Base)
+	if err != nil {
+		return "", err
+	}
 
 	if IsAbsolutePath(cfg.Base) {
 		repoName := getRepoName(mainRepoRoot)
 
-		return filepath.Join(base, repoName)
+		return filepath.Join(base, repoName), nil
 	}
 
 	// For relative paths, resolve relative to main repo root (not cwd).
 	// This ensures worktrees created from inside other worktrees use
 	// the same base directory as the main repo.
-	return filepath.Join(mainRepoRoot, base)
+	return filepath.Join(mainRepoRoot, base), nil
+}
+
+// resolveHooksDir returns the directory hooks are run from: hooks_dir (~
+// expanded, relative to mainRepoRoot) if configured, else .wt/hooks in
+// mainRepoRoot. A configured hooks_dir replaces .wt/hooks entirely, so
+// hooks there are not run even if hooks_dir lacks them. With the global
+// --no-hooks it returns "", for which no hook is found.
+func resolveHooksDir(cfg Config, mainRepoRoot string) (string, error) {
+	if cfg.NoHooks {
+		return "", nil
+	}
+
+	if cfg.HooksDir == "" {
+		return filepath.Join(mainRepoRoot, ".wt", "hooks"), nil
+	}
+
+	dir, err := ExpandPath(cfg.HooksDir)
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(mainRepoRoot, dir)
+	}
+
+	return dir, nil
 }
 
+// worktreeSchemaVersion is the schema_version writeWorktreeInfo writes.
+// Bump it, and add the upgrade to upgradeWorktreeInfo, when the meaning of
+// a stored field changes.
+const worktreeSchemaVersion = 1
+
 // WorktreeInfo holds metadata for a wt-managed worktree.
 // Stored in .wt/worktree.json within each worktree.
 type WorktreeInfo struct {
-	Name       string    `json:"name"`
-	AgentID    string    `json:"agent_id"`
-	ID         int       `json:"id"`
-	BaseBranch string    `json:"base_branch"`
-	Created    time.Time `json:"created"`
+	// 0 for files written before schema_version existed
+	SchemaVersion int `json:"schema_version"`
+
+	Name        string    `json:"name"`
+	Branch      string    `json:"branch,omitempty"` // Branch create made, if branch_prefix made it differ from Name
+	AgentID     string    `json:"agent_id"`
+	ID          int       `json:"id"`
+	BaseBranch  string    `json:"base_branch"`
+	StartCommit string    `json:"start_commit,omitempty"`
+	Upstream    string    `json:"upstream,omitempty"`
+	Locked      bool      `json:"locked,omitempty"`     // Locked with git worktree lock at creation
+	ParentID    int       `json:"parent_id,omitempty"`  // ID of the worktree create ran from (0 = none)
+	Source      string    `json:"source,omitempty"`     // Absolute directory create ran from (WT_SOURCE)
+	MergeInto   string    `json:"merge_into,omitempty"` // Default merge target for this worktree (create --merge-into)
+	Detached    bool      `json:"detached,omitempty"`   // Branch deleted by remove --branch-only, HEAD detached
+	Created     time.Time `json:"created"`
+
+	// Key/value labels from create --label and wt label
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
-// writeWorktreeInfo writes metadata to .wt/worktree.json in the worktree.
+// BranchName returns the branch create made for the worktree: Branch, or
+// Name for worktrees created without branch_prefix.
+func (info *WorktreeInfo) BranchName() string {
+	if info.Branch != "" {
+		return info.Branch
+	}
+
+	return info.Name
+}
+
+// branchForName returns the branch create makes for a worktree named name.
+func branchForName(cfg Config, name string) string {
+	return cfg.BranchPrefix + name
+}
+
+// writeWorktreeInfo writes metadata to .wt/worktree.json in the worktree,
+// always as worktreeSchemaVersion. Metadata a newer wt wrote is not
+// overwritten (ErrNewerWorktreeSchema), since its fields this version does
+// not know would be lost.
 func writeWorktreeInfo(fsys fs.FS, wtPath string, info *WorktreeInfo) error {
+	stored := info.SchemaVersion
+	if version, err := storedSchemaVersion(fsys, wtPath); err == nil {
+		stored = max(stored, version)
+	}
+
+	if stored > worktreeSchemaVersion {
+		return fmt.Errorf("%w: %s has schema_version %d, newer than the supported %d (upgrade wt)",
+			ErrNewerWorktreeSchema, filepath.Join(wtPath, ".wt", "worktree.json"), stored, worktreeSchemaVersion)
+	}
+
 	wtDir := filepath.Join(wtPath, ".wt")
 
 	mkdirErr := fsys.MkdirAll(wtDir, 0o750)
@@ -490,7 +848,10 @@ func writeWorktreeInfo(fsys fs.FS, wtPath string, info *WorktreeInfo) error {
 		return fmt.Errorf("creating .wt directory: %w", mkdirErr)
 	}
 
-	data, marshalErr := json.MarshalIndent(info, "", "  ")
+	current := *info
+	current.SchemaVersion = worktreeSchemaVersion
+
+	data, marshalErr := json.MarshalIndent(&current, "", "  ")
 	if marshalErr != nil {
 		return fmt.Errorf("marshaling worktree info: %w", marshalErr)
 	}
@@ -527,8 +888,21 @@ func writeWorktreeInfo(fsys fs.FS, wtPath string, info *WorktreeInfo) error {
 // ErrNotWtWorktree indicates the directory is not a wt-managed worktree.
 var ErrNotWtWorktree = errors.New("not a wt-managed worktree (run from a worktree created with 'wt create')")
 
+// ErrInvalidWorktreeInfo indicates .wt/worktree.json exists but is not valid metadata.
+var ErrInvalidWorktreeInfo = errors.New("invalid worktree metadata")
+
+// ErrNewerWorktreeSchema indicates .wt/worktree.json was written by a newer wt.
+var ErrNewerWorktreeSchema = errors.New("refusing to rewrite worktree metadata")
+
+// utf8BOM is the byte order mark some editors prepend to UTF-8 files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
 // readWorktreeInfo reads metadata from .wt/worktree.json in the worktree.
-// Returns ErrNotWtWorktree if the file doesn't exist.
+// Returns ErrNotWtWorktree if the file doesn't exist. A leading UTF-8 BOM
+// and surrounding whitespace (e.g. from hand edits) are ignored; anything
+// else that is not valid JSON fails with ErrInvalidWorktreeInfo. Metadata of
+// an older schema_version is upgraded in memory; a newer one is returned as
+// read, best-effort (see schemaWarning).
 func readWorktreeInfo(fsys fs.FS, wtPath string) (WorktreeInfo, error) {
 	infoPath := filepath.Join(wtPath, ".wt", "worktree.json")
 
@@ -541,44 +915,75 @@ func readWorktreeInfo(fsys fs.FS, wtPath string) (WorktreeInfo, error) {
 		return WorktreeInfo{}, fmt.Errorf("reading worktree.json: %w", readErr)
 	}
 
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM))
+
 	var info WorktreeInfo
 
 	unmarshalErr := json.Unmarshal(data, &info)
 	if unmarshalErr != nil {
-		return WorktreeInfo{}, fmt.Errorf("parsing worktree.json: %w", unmarshalErr)
+		return WorktreeInfo{}, fmt.Errorf("%w: parsing %s: %w", ErrInvalidWorktreeInfo, infoPath, unmarshalErr)
+	}
+
+	if info.SchemaVersion < worktreeSchemaVersion {
+		upgradeWorktreeInfo(&info)
 	}
 
 	return info, nil
 }
 
-// findWorktrees scans the given directory for wt-managed worktrees.
-// searchDir should be the directory containing worktree subdirectories.
-// Returns worktrees that have .wt/worktree.json files.
-func findWorktrees(fsys fs.FS, searchDir string) ([]WorktreeInfo, error) {
-	entries, readDirErr := fsys.ReadDir(searchDir)
-	if readDirErr != nil {
-		if errors.Is(readDirErr, os.ErrNotExist) {
-			return nil, nil // No worktrees yet
-		}
+// upgradeWorktreeInfo converts metadata of an older schema_version to
+// worktreeSchemaVersion. Version 0 (no schema_version) has the same fields
+// as version 1, so there is nothing to convert yet.
+func upgradeWorktreeInfo(info *WorktreeInfo) {
+	info.SchemaVersion = worktreeSchemaVersion
+}
 
-		return nil, fmt.Errorf("reading worktree directory: %w", readDirErr)
+// schemaWarning returns a warning for metadata written by a newer wt, which
+// this version may misread, or "" if info's schema_version is supported.
+func schemaWarning(info *WorktreeInfo, wtPath string) string {
+	if info.SchemaVersion <= worktreeSchemaVersion {
+		return ""
 	}
 
-	worktrees := make([]WorktreeInfo, 0, len(entries))
+	return fmt.Sprintf("warning: %s has schema_version %d, newer than the supported %d (upgrade wt); reading it best-effort",
+		filepath.Join(wtPath, ".wt", "worktree.json"), info.SchemaVersion, worktreeSchemaVersion)
+}
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
+// storedSchemaVersion returns the schema_version in wtPath's
+// .wt/worktree.json as written, before readWorktreeInfo upgrades it.
+func storedSchemaVersion(fsys fs.FS, wtPath string) (int, error) {
+	infoPath := filepath.Join(wtPath, ".wt", "worktree.json")
 
-		wtPath := filepath.Join(searchDir, entry.Name())
+	data, err := fsys.ReadFile(infoPath)
+	if err != nil {
+		return 0, fmt.Errorf("reading worktree.json: %w", err)
+	}
 
-		info, readInfoErr := readWorktreeInfo(fsys, wtPath)
-		if readInfoErr != nil {
-			continue // Skip non-wt directories
-		}
+	var stored struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+
+	err = json.Unmarshal(bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM)), &stored)
+	if err != nil {
+		return 0, fmt.Errorf("%w: parsing %s: %w", ErrInvalidWorktreeInfo, infoPath, err)
+	}
+
+	return stored.SchemaVersion, nil
+}
+
+// findWorktrees scans the given directory for wt-managed worktrees.
+// searchDir should be the directory containing worktree subdirectories.
+// Returns worktrees that have .wt/worktree.json files.
+func findWorktrees(fsys fs.FS, searchDir string) ([]WorktreeInfo, error) {
+	found, err := findWorktreesWithPaths(fsys, searchDir)
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees := make([]WorktreeInfo, 0, len(found))
 
-		worktrees = append(worktrees, info)
+	for _, wt := range found {
+		worktrees = append(worktrees, wt.WorktreeInfo)
 	}
 
 	return worktrees, nil
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"os"
 	"path/filepath"
@@ -156,6 +157,90 @@ func Test_Run_Error_Output_Contains_Error_Prefix(t *testing.T) {
 	}
 }
 
+func Test_Run_Rejects_Invalid_Cleanup_Timeout_Before_Running_Command(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := c.Run("--cleanup-timeout", "soon", "--config", "config.json", "create", "--name", "never")
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+
+	AssertContains(t, stderr, "invalid cleanup timeout")
+	AssertContains(t, stderr, `--cleanup-timeout="soon"`)
+
+	c.Env["WT_CLEANUP_TIMEOUT"] = "-5s"
+
+	_, stderr, code = c.Run("--config", "config.json", "create", "--name", "never")
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+
+	AssertContains(t, stderr, `WT_CLEANUP_TIMEOUT="-5s"`)
+
+	if c.FileExists("worktrees/never") {
+		t.Error("create should not run with an invalid cleanup timeout")
+	}
+
+	// The flag takes precedence over the environment
+	c.MustRun("--cleanup-timeout", "30s", "--config", "config.json", "create", "--name", "flag-wins")
+}
+
+func Test_Run_Quiet_Suppresses_Informational_Output(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.WriteExecutable(".wt/hooks/post-create", "#!/bin/bash\necho \"hook says hi\"\n")
+
+	stdout, stderr, code := c.Run("-q", "--config", "config.json", "create", "--name", "silent")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	if stdout != "" {
+		t.Errorf("expected no stdout with --quiet, got %q", stdout)
+	}
+
+	if !c.FileExists("worktrees/silent/.wt/worktree.json") {
+		t.Error("worktree was not created")
+	}
+
+	// Explicitly requested output is kept
+	stdout = c.MustRun("--quiet", "--config", "config.json", "create", "--name", "json", "--json", "--no-hooks")
+	AssertContains(t, stdout, `"name": "json"`)
+
+	stdout = c.MustRun("--quiet", "--config", "config.json", "create", "--name", "switched", "--switch", "--no-hooks")
+	if stdout != filepath.Join(c.Dir, "worktrees", "switched") {
+		t.Errorf("expected --switch path, got %q", stdout)
+	}
+
+	AssertContains(t, c.MustRun("-q", "--config", "config.json", "ls"), "silent")
+
+	stdout, _, code = c.Run("-q", "--config", "config.json", "remove", "silent")
+	if code != 0 || stdout != "" {
+		t.Errorf("expected silent delete with exit code 0, got %d and %q", code, stdout)
+	}
+
+	// Errors still reach stderr with the usual exit code
+	stdout, stderr, code = c.Run("-q", "--config", "config.json", "remove", "missing")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	if stdout != "" {
+		t.Errorf("expected no stdout, got %q", stdout)
+	}
+
+	AssertContains(t, stderr, "missing")
+}
+
 func Test_Create_Shows_Help_When_Help_Flag(t *testing.T) {
 	t.Parallel()
 
@@ -443,7 +528,11 @@ func Test_resolveWorktreePath_Absolute_Base_Includes_Repo_Name(t *testing.T) {
 		EffectiveCwd: "/some/other/path",
 	}
 
-	got := resolveWorktreePath(cfg, "/home/user/repos/my-app", "swift-fox")
+	got, err := resolveWorktreePath(cfg, "/home/user/repos/my-app", "swift-fox")
+	if err != nil {
+		t.Fatalf("resolveWorktreePath() error: %v", err)
+	}
+
 	want := filepath.Join(home, "code", "worktrees", "my-app", "swift-fox")
 
 	if got != want {
@@ -459,7 +548,11 @@ func Test_resolveWorktreePath_Absolute_Base_With_Slash(t *testing.T) {
 		EffectiveCwd: "/some/other/path",
 	}
 
-	got := resolveWorktreePath(cfg, "/home/user/repos/project", "brave-owl")
+	got, err := resolveWorktreePath(cfg, "/home/user/repos/project", "brave-owl")
+	if err != nil {
+		t.Fatalf("resolveWorktreePath() error: %v", err)
+	}
+
 	want := "/var/worktrees/project/brave-owl"
 
 	if got != want {
@@ -467,7 +560,7 @@ func Test_resolveWorktreePath_Absolute_Base_With_Slash(t *testing.T) {
 	}
 }
 
-func Test_resolveWorktreePath_Relative_Base_Uses_EffectiveCwd(t *testing.T) {
+func Test_resolveWorktreePath_Relative_Base_Uses_Main_Repo_Root(t *testing.T) {
 	t.Parallel()
 
 	cfg := Config{
@@ -475,7 +568,11 @@ func Test_resolveWorktreePath_Relative_Base_Uses_EffectiveCwd(t *testing.T) {
 		EffectiveCwd: "/home/user/code/my-repo",
 	}
 
-	got := resolveWorktreePath(cfg, "/home/user/code/my-repo", "calm-deer")
+	got, err := resolveWorktreePath(cfg, "/home/user/code/my-repo", "calm-deer")
+	if err != nil {
+		t.Fatalf("resolveWorktreePath() error: %v", err)
+	}
+
 	want := "/home/user/code/my-repo/../worktrees/calm-deer"
 
 	// Clean for comparison
@@ -487,6 +584,35 @@ func Test_resolveWorktreePath_Relative_Base_Uses_EffectiveCwd(t *testing.T) {
 	}
 }
 
+func Test_resolveWorktreePath_Relative_Base_Ignores_EffectiveCwd(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		Base:         "worktrees",
+		EffectiveCwd: "/code/project/worktrees/swift-fox",
+	}
+
+	got, err := resolveWorktreePath(cfg, "/code/project", "brave-owl")
+	if err != nil {
+		t.Fatalf("resolveWorktreePath() error: %v", err)
+	}
+
+	want := "/code/project/worktrees/brave-owl"
+
+	if got != want {
+		t.Errorf("resolveWorktreePath() = %q, want %q", got, want)
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, "/code/project")
+	if err != nil {
+		t.Fatalf("resolveWorktreeBaseDir() error: %v", err)
+	}
+
+	if filepath.Dir(got) != baseDir {
+		t.Errorf("worktree path %q not inside base dir %q", got, baseDir)
+	}
+}
+
 func Test_resolveWorktreePath_Relative_Base_No_Repo_Name(t *testing.T) {
 	t.Parallel()
 
@@ -495,7 +621,11 @@ func Test_resolveWorktreePath_Relative_Base_No_Repo_Name(t *testing.T) {
 		EffectiveCwd: "/code/project",
 	}
 
-	got := resolveWorktreePath(cfg, "/code/project", "swift-fox")
+	got, err := resolveWorktreePath(cfg, "/code/project", "swift-fox")
+	if err != nil {
+		t.Fatalf("resolveWorktreePath() error: %v", err)
+	}
+
 	want := "/code/project/worktrees/swift-fox"
 
 	if got != want {
@@ -516,7 +646,11 @@ func Test_resolveWorktreeBaseDir_Absolute_Includes_Repo_Name(t *testing.T) {
 		EffectiveCwd: "/other/path",
 	}
 
-	got := resolveWorktreeBaseDir(cfg, "/home/user/repos/my-project")
+	got, err := resolveWorktreeBaseDir(cfg, "/home/user/repos/my-project")
+	if err != nil {
+		t.Fatalf("resolveWorktreeBaseDir() error: %v", err)
+	}
+
 	want := filepath.Join(home, "code", "worktrees", "my-project")
 
 	if got != want {
@@ -532,7 +666,11 @@ func Test_resolveWorktreeBaseDir_Relative_Uses_EffectiveCwd(t *testing.T) {
 		EffectiveCwd: "/code/my-repo",
 	}
 
-	got := resolveWorktreeBaseDir(cfg, "/code/my-repo")
+	got, err := resolveWorktreeBaseDir(cfg, "/code/my-repo")
+	if err != nil {
+		t.Fatalf("resolveWorktreeBaseDir() error: %v", err)
+	}
+
 	want := "/code/my-repo/../worktrees"
 
 	got = filepath.Clean(got)
@@ -551,7 +689,11 @@ func Test_resolveWorktreeBaseDir_Relative_No_Repo_Name(t *testing.T) {
 		EffectiveCwd: "/code/project",
 	}
 
-	got := resolveWorktreeBaseDir(cfg, "/code/project")
+	got, err := resolveWorktreeBaseDir(cfg, "/code/project")
+	if err != nil {
+		t.Fatalf("resolveWorktreeBaseDir() error: %v", err)
+	}
+
 	want := "/code/project/worktrees"
 
 	if got != want {
@@ -1022,3 +1164,172 @@ func Test_Run_Create_Fails_When_From_Branch_Does_Not_Exist(t *testing.T) {
 
 	AssertContains(t, stderr, "error:")
 }
+
+func Test_ExpandPath_Returns_Error_When_HOME_Unset(t *testing.T) {
+	t.Setenv("HOME", "")
+
+	_, err := ExpandPath("~/code/worktrees")
+	if !errors.Is(err, ErrHomeNotSet) {
+		t.Fatalf("expected ErrHomeNotSet, got %v", err)
+	}
+
+	// Paths without ~ don't need HOME
+	got, err := ExpandPath("/var/worktrees")
+	if err != nil || got != "/var/worktrees" {
+		t.Errorf("ExpandPath(/var/worktrees) = %q, %v", got, err)
+	}
+}
+
+func Test_Config_Tilde_Base_Fails_Clearly_When_HOME_Unset(t *testing.T) {
+	t.Setenv("HOME", "")
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile(".wt/config.json", `{"base": "~/worktrees"}`)
+
+	_, stderr, code := c.Run("create", "--name", "no-home")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "HOME not set")
+	AssertContains(t, stderr, "use an absolute base")
+
+	if c.FileExists("~") {
+		t.Error("a literal ~ directory was created")
+	}
+
+	_, stderr, code = c.Run("ls")
+	if code != 1 {
+		t.Fatalf("expected ls exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "HOME not set")
+}
+
+func Test_Run_Dispatches_Unknown_Command_To_Plugin_On_Path(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	binDir := t.TempDir()
+	c.Env["PATH"] = binDir
+
+	writeTestFile(t, filepath.Join(binDir, "wt-foo"), `#!/bin/sh
+echo "args: $*"
+echo "repo: $WT_REPO_ROOT"
+echo "pwd: $(pwd -P)"
+echo "to stderr" >&2
+exit 3
+`)
+
+	err := os.Chmod(filepath.Join(binDir, "wt-foo"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, code := c.Run("foo", "bar", "--baz")
+	if code != 3 {
+		t.Fatalf("exit code = %d, want plugin's 3\nstderr: %s", code, stderr)
+	}
+
+	repoRoot, err := filepath.EvalSymlinks(c.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AssertContains(t, stdout, "args: bar --baz")
+	AssertContains(t, stdout, "repo: "+repoRoot)
+	AssertContains(t, stdout, "pwd: "+repoRoot)
+	AssertContains(t, stderr, "to stderr")
+	AssertNotContains(t, stderr, "unknown command")
+}
+
+func Test_Run_Prefers_Builtin_Command_Over_Plugin(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	binDir := t.TempDir()
+	c.Env["PATH"] = binDir
+
+	for _, name := range []string{"wt-ls", "wt-nonexec"} {
+		writeTestFile(t, filepath.Join(binDir, name), "#!/bin/sh\necho plugin ran\n")
+	}
+
+	err := os.Chmod(filepath.Join(binDir, "wt-ls"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout, _, code := c.Run("--config", "config.json", "ls")
+	if code != 0 {
+		t.Fatalf("exit code = %d, want 0", code)
+	}
+
+	AssertNotContains(t, stdout, "plugin ran")
+
+	// Files that are not executable are not plugins
+	_, stderr, code := c.Run("nonexec")
+	if code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+
+	AssertContains(t, stderr, "error: unknown command: nonexec")
+}
+
+func Test_Run_JSON_Flag_Prints_Errors_As_JSON_Object(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "taken")
+
+	for _, args := range [][]string{
+		{"create", "--name", "taken", "--json"},
+		{"info", "missing", "--format", "json"},
+		{"ls", "--jsonl", "--bogus"},
+	} {
+		stdout, stderr, code := c.Run(append([]string{"--config", "config.json"}, args...)...)
+		if code != 1 {
+			t.Fatalf("%v: expected exit code 1, got %d", args, code)
+		}
+
+		if stdout != "" {
+			t.Errorf("%v: stdout should be empty, got %q", args, stdout)
+		}
+
+		lines := strings.Split(strings.TrimSpace(stderr), "\n")
+
+		var got struct {
+			Error string `json:"error"`
+			Code  int    `json:"code"`
+		}
+
+		err := json.Unmarshal([]byte(lines[len(lines)-1]), &got)
+		if err != nil {
+			t.Fatalf("%v: last stderr line is not JSON: %v\nstderr: %s", args, err, stderr)
+		}
+
+		if got.Error == "" || got.Code != 1 {
+			t.Errorf("%v: unexpected error object %+v", args, got)
+		}
+
+		AssertNotContains(t, stderr, "error: ")
+	}
+
+	_, stderr, code := c.Run("--config", "config.json", "create", "--name", "taken")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "error: name already in use")
+	AssertNotContains(t, stderr, `"code"`)
+}
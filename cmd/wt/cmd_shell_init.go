@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	flag "github.com/spf13/pflag"
+)
+
+// Errors for shell-init command.
+var (
+	errMissingShellInitShell = errors.New("missing shell argument (usage: wt shell-init <bash|zsh|fish>)")
+	errUnsupportedShellInit  = errors.New("unsupported shell (supported: bash, zsh, fish)")
+	errTooManyShellInitArgs  = errors.New("too many arguments (usage: wt shell-init <bash|zsh|fish>)")
+)
+
+// ShellInitCmd returns the shell-init command.
+func ShellInitCmd() *Command {
+	flags := flag.NewFlagSet("shell-init", flag.ContinueOnError)
+	flags.BoolP("help", "h", false, "Show help")
+
+	return &Command{
+		Flags: flags,
+		Usage: "shell-init <shell>",
+		Short: "Output a wtcd shell function",
+		Long: `Output a wtcd shell function for the specified shell.
+
+A program cannot change its parent shell's directory, so wtcd runs
+wt switch and cds to the path it prints. Add the output to your shell's
+config file:
+  eval "$(wt shell-init bash)"    # ~/.bashrc
+  eval "$(wt shell-init zsh)"     # ~/.zshrc
+  wt shell-init fish | source     # ~/.config/fish/config.fish
+
+Then:
+  wtcd <identifier>   cd to a worktree (id, name, agent_id or branch)
+  wtcd -              cd to the most recently created worktree
+
+Unlike wt init, this only adds wtcd and leaves the wt command itself alone.
+
+Supported shells: bash, zsh, fish`,
+		Exec: func(_ context.Context, _ io.Reader, stdout, _ io.Writer, args []string) error {
+			return execShellInit(stdout, args)
+		},
+	}
+}
+
+func execShellInit(stdout io.Writer, args []string) error {
+	if len(args) == 0 {
+		return errMissingShellInitShell
+	}
+
+	if len(args) > 1 {
+		return errTooManyShellInitArgs
+	}
+
+	var script string
+
+	switch args[0] {
+	case "bash", "zsh":
+		script = posixShellInitScript
+	case evalShellFish:
+		script = fishShellInitScript
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedShellInit, args[0])
+	}
+
+	_, err := fmt.Fprint(stdout, script)
+	if err != nil {
+		return fmt.Errorf("writing %s shell-init script: %w", args[0], err)
+	}
+
+	return nil
+}
+
+// posixShellInitScript defines wtcd for bash and zsh. wt switch reports its
+// own errors on stderr; on failure the directory is left unchanged.
+const posixShellInitScript = `wtcd() {
+  local dir
+  dir="$(command wt switch "$@")" || return 1
+  cd "$dir" || return 1
+}
+`
+
+// fishShellInitScript defines wtcd for fish.
+const fishShellInitScript = `function wtcd --description 'cd to a wt worktree'
+    set -l dir (command wt switch $argv); or return 1
+    cd $dir
+end
+`
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_ShellInit_Rejects_Missing_Or_Unknown_Shell(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+
+	_, stderr, code := c.Run("shell-init")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "missing shell argument")
+
+	_, stderr, code = c.Run("shell-init", "tcsh")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "unsupported shell (supported: bash, zsh, fish): tcsh")
+}
+
+func Test_ShellInit_Prints_Wtcd_Function_Per_Shell(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+
+	for _, shell := range []string{"bash", "zsh"} {
+		stdout := c.MustRun("shell-init", shell)
+		AssertContains(t, stdout, "wtcd() {")
+		AssertContains(t, stdout, `command wt switch "$@"`)
+	}
+
+	stdout := c.MustRun("shell-init", "fish")
+	AssertContains(t, stdout, "function wtcd")
+	AssertContains(t, stdout, "command wt switch $argv")
+}
+
+func Test_ShellInit_Bash_Wtcd_Changes_To_Switch_Path(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	script := c.MustRun("shell-init", "bash")
+
+	// A stub wt on PATH stands in for the binary
+	binDir := t.TempDir()
+	target := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(binDir, "wt"),
+		[]byte("#!/bin/sh\n[ \"$1\" = switch ] && [ \"$2\" = good ] && echo '"+target+"' && exit 0\necho 'error: worktree not found' >&2\nexit 1\n"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(identifier string) (string, error) {
+		cmd := exec.Command("bash", "-c", script+"\nwtcd "+identifier+" && pwd")
+		cmd.Env = append(os.Environ(), "PATH="+binDir+":"+os.Getenv("PATH"))
+		cmd.Dir = c.Dir
+
+		out, runErr := cmd.CombinedOutput()
+
+		return strings.TrimSpace(string(out)), runErr
+	}
+
+	out, err := run("good")
+	if err != nil {
+		t.Fatalf("wtcd good failed: %v\n%s", err, out)
+	}
+
+	if out != target {
+		t.Errorf("expected pwd %s, got %q", target, out)
+	}
+
+	out, err = run("bad")
+	if err == nil {
+		t.Errorf("wtcd bad should fail, got %q", out)
+	}
+
+	AssertContains(t, out, "worktree not found")
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// StatusCmd returns the status command.
+func StatusCmd(cfg Config, fsys fs.FS, git *Git) *Command {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	flags.BoolP("help", "h", false, "Show help")
+	flags.Bool("json", false, "Output as JSON")
+	addFormatFlag(flags)
+
+	return &Command{
+		Flags: flags,
+		Usage: "status [flags]",
+		Short: "Summarize the git state of all worktrees",
+		Long: `Show the git state of every wt-managed worktree at a glance.
+
+Each worktree gets one line: NAME, BRANCH, AHEAD and BEHIND (commits its
+HEAD has that its base_branch does not, and the other way round), DIRTY
+(uncommitted changes, including untracked files) and the subject of its
+last commit. The current worktree is marked with '*'. On a terminal, ahead
+counts are green, behind counts red and dirty worktrees yellow.
+
+A value that cannot be computed (e.g. the base branch was deleted or the
+worktree directory is gone) is shown as ? (null in JSON), and the line
+gets a note saying why; the other worktrees are still listed.
+
+Use --json (or --format json) for an array with name, id, path, branch,
+base_branch, ahead, behind, dirty, subject, is_current and note; --format
+yaml prints the same fields as YAML.`,
+		Exec: func(ctx context.Context, _ io.Reader, stdout, stderr io.Writer, _ []string) error {
+			format, err := outputFormat(flags)
+			if err != nil {
+				return err
+			}
+
+			return execStatus(ctx, stdout, stderr, cfg, fsys, git, format)
+		},
+	}
+}
+
+// jsonStatus is one worktree in wt status --json. Counts and dirty are null
+// when they could not be computed; note says why.
+type jsonStatus struct {
+	Name       string `json:"name"`
+	ID         int    `json:"id"`
+	Path       string `json:"path"`
+	Branch     string `json:"branch"`
+	BaseBranch string `json:"base_branch"`
+	Ahead      *int   `json:"ahead"`
+	Behind     *int   `json:"behind"`
+	Dirty      *bool  `json:"dirty"`
+	Subject    string `json:"subject"`
+	IsCurrent  bool   `json:"is_current"`
+	Note       string `json:"note,omitempty"`
+}
+
+func execStatus(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	format string,
+) error {
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("scanning worktrees: %w", err)
+	}
+
+	sortWorktrees(worktrees, listSortCreated, false)
+
+	gitIndex := newGitWorktreeIndex(entries)
+	commits := headCommits(ctx, git, mainRepoRoot, entries)
+
+	currentPath, findErr := findWorktreeRoot(fsys, cfg.EffectiveCwd)
+	if findErr != nil {
+		currentPath = ""
+	}
+
+	rows := make([]jsonStatus, 0, len(worktrees))
+
+	for _, wt := range worktrees {
+		entry, _ := gitIndex.lookup(wt.Path)
+
+		row := jsonStatus{
+			Name:       wt.Name,
+			ID:         wt.ID,
+			Path:       wt.Path,
+			Branch:     entry.Branch,
+			BaseBranch: wt.BaseBranch,
+			Subject:    commits[entry.HEAD].Subject,
+			IsCurrent:  isSamePath(wt.Path, currentPath),
+		}
+
+		var notes []string
+
+		ahead, behind, countErr := git.AheadBehind(ctx, wt.Path, wt.BaseBranch)
+		if countErr == nil {
+			row.Ahead, row.Behind = &ahead, &behind
+		} else {
+			notes = append(notes, "cannot compare with "+wt.BaseBranch)
+		}
+
+		dirty, dirtyErr := git.IsDirty(ctx, wt.Path)
+		if dirtyErr == nil {
+			row.Dirty = &dirty
+		} else {
+			notes = append(notes, "cannot read working tree status")
+		}
+
+		row.Note = strings.Join(notes, "; ")
+		rows = append(rows, row)
+	}
+
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		encodeErr := enc.Encode(rows)
+		if encodeErr != nil {
+			return fmt.Errorf("encoding JSON: %w", encodeErr)
+		}
+
+		return nil
+	case formatYAML:
+		return encodeYAML(stdout, rows)
+	}
+
+	if len(rows) == 0 {
+		fprintln(stderr, "No worktrees found. Create one with: wt create")
+
+		return nil
+	}
+
+	outputStatusTable(stdout, rows, writerIsTerminal(stdout))
+
+	return nil
+}
+
+// outputStatusTable prints the wt status table, colorizing counts and the
+// dirty flag if color is set. Cells are padded before they are colored so
+// the escape codes do not shift the columns.
+func outputStatusTable(stdout io.Writer, rows []jsonStatus, color bool) {
+	paint := func(cell, code string) string {
+		if !color || code == "" {
+			return cell
+		}
+
+		return code + cell + colorReset
+	}
+
+	fprintf(stdout, "  %-15s %-20s %-5s %-6s %-5s %s\n", "NAME", "BRANCH", "AHEAD", "BEHIND", "DIRTY", "LAST COMMIT")
+
+	for _, row := range rows {
+		marker := " "
+		if row.IsCurrent {
+			marker = "*"
+		}
+
+		branch := row.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+
+		ahead, aheadColor := "?", ""
+		if row.Ahead != nil {
+			ahead = strconv.Itoa(*row.Ahead)
+			if *row.Ahead > 0 {
+				aheadColor = colorGreen
+			}
+		}
+
+		behind, behindColor := "?", ""
+		if row.Behind != nil {
+			behind = strconv.Itoa(*row.Behind)
+			if *row.Behind > 0 {
+				behindColor = colorRed
+			}
+		}
+
+		dirty, dirtyColor := "?", ""
+		if row.Dirty != nil {
+			dirty = "no"
+			if *row.Dirty {
+				dirty, dirtyColor = "yes", colorYellow
+			}
+		}
+
+		line := fmt.Sprintf("%s %-15s %-20s %s %s %s %s", marker, row.Name, branch,
+			paint(fmt.Sprintf("%-5s", ahead), aheadColor),
+			paint(fmt.Sprintf("%-6s", behind), behindColor),
+			paint(fmt.Sprintf("%-5s", dirty), dirtyColor),
+			row.Subject)
+
+		if row.Note != "" {
+			line += " (" + row.Note + ")"
+		}
+
+		fprintln(stdout, strings.TrimRight(line, " "))
+	}
+}
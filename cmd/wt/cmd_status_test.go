@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+)
+
+func Test_Status_Shows_Ahead_Behind_Dirty_And_Subject(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	aheadPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "ahead"))
+	dirtyPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "dirty"))
+	brokenPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "broken"))
+
+	gitCommitInDir(t, aheadPath, "one.txt", "one", "Add one")
+	gitCommitInDir(t, aheadPath, "two.txt", "two", "Add two")
+	gitCommitInDir(t, c.Dir, "main.txt", "main", "Advance master")
+	writeTestFile(t, filepath.Join(dirtyPath, "untracked.txt"), "wip")
+
+	// A base branch that no longer exists cannot be compared
+	info, err := readWorktreeInfo(fs.NewReal(), brokenPath)
+	if err != nil {
+		t.Fatalf("reading metadata: %v", err)
+	}
+
+	info.BaseBranch = "deleted-branch"
+
+	err = writeWorktreeInfo(fs.NewReal(), brokenPath, &info)
+	if err != nil {
+		t.Fatalf("writing metadata: %v", err)
+	}
+
+	var rows []jsonStatus
+
+	err = json.Unmarshal([]byte(c.MustRun("--config", "config.json", "status", "--json")), &rows)
+	if err != nil {
+		t.Fatalf("parsing status JSON: %v", err)
+	}
+
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 worktrees, got %d", len(rows))
+	}
+
+	ahead, dirty, broken := rows[0], rows[1], rows[2]
+
+	if ahead.Name != "ahead" || ahead.Ahead == nil || *ahead.Ahead != 2 || *ahead.Behind != 1 || *ahead.Dirty {
+		t.Errorf("unexpected status for ahead: %+v", ahead)
+	}
+
+	if ahead.Subject != "Add two" || ahead.Branch != "ahead" || ahead.BaseBranch != testBaseBranchMain {
+		t.Errorf("expected branch ahead, base master and subject 'Add two', got %+v", ahead)
+	}
+
+	if dirty.Dirty == nil || !*dirty.Dirty || *dirty.Ahead != 0 || *dirty.Behind != 1 {
+		t.Errorf("unexpected status for dirty: %+v", dirty)
+	}
+
+	if broken.Ahead != nil || broken.Behind != nil || broken.Dirty == nil || *broken.Dirty {
+		t.Errorf("expected broken to have no counts but a dirty flag, got %+v", broken)
+	}
+
+	AssertContains(t, broken.Note, "cannot compare with deleted-branch")
+
+	stdout := c.MustRun("--config", "config.json", "status")
+	AssertContains(t, stdout, "NAME            BRANCH               AHEAD BEHIND DIRTY LAST COMMIT")
+	AssertContains(t, stdout, "  ahead           ahead                2     1      no    Add two")
+	AssertContains(t, stdout, "  dirty           dirty                0     1      yes")
+	AssertContains(t, stdout, "  broken          broken               ?     ?      no")
+	AssertContains(t, stdout, "(cannot compare with deleted-branch)")
+	AssertNotContains(t, stdout, "\033[")
+}
+
+func Test_Status_Reports_No_Worktrees(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout, stderr, code := c.Run("--config", "config.json", "status")
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	if stdout != "" {
+		t.Errorf("expected no stdout, got %q", stdout)
+	}
+
+	AssertContains(t, stderr, "No worktrees found")
+
+	if got := c.MustRun("--config", "config.json", "status", "--json"); got != "[]" {
+		t.Errorf("expected empty JSON array, got %q", got)
+	}
+}
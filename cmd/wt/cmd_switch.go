@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// Errors for switch command.
+var (
+	errSwitchIdentifierRequired = errors.New("worktree identifier is required (usage: wt switch <name|id|agent_id|->)")
+	errNoWorktreesToSwitch      = errors.New("no worktrees to switch to (use wt create)")
+)
+
+// switchLatest is the identifier for the most recently created worktree.
+const switchLatest = "-"
+
+// SwitchCmd returns the switch command.
+func SwitchCmd(cfg Config, fsys fs.FS, git *Git) *Command {
+	flags := flag.NewFlagSet("switch", flag.ContinueOnError)
+	flags.BoolP("help", "h", false, "Show help")
+	flags.Bool("json", false, "Output the worktree's metadata and path as JSON")
+
+	return &Command{
+		Flags: flags,
+		Usage: "switch <identifier> [flags]",
+		Short: "Print a worktree's path",
+		Long: `Print the absolute path of a worktree, for cd "$(wt switch foo)".
+
+The identifier is looked up like wt info does: numeric id, name, agent_id
+or checked-out branch. Use - for the most recently created worktree.
+
+With shell integration (eval "$(wt init bash)"), wt switch changes into
+the worktree instead of printing its path, except with --json.`,
+		Exec: func(ctx context.Context, _ io.Reader, stdout, _ io.Writer, args []string) error {
+			return execSwitch(ctx, stdout, cfg, fsys, git, flags, args)
+		},
+	}
+}
+
+func execSwitch(
+	ctx context.Context,
+	stdout io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	flags *flag.FlagSet,
+	args []string,
+) error {
+	if len(args) == 0 {
+		return errSwitchIdentifierRequired
+	}
+
+	jsonOutput, _ := flags.GetBool("json")
+	identifier := args[0]
+
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("scanning worktrees: %w", err)
+	}
+
+	wt, err := findSwitchTarget(ctx, git, mainRepoRoot, worktrees, identifier)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		err = enc.Encode(wt)
+		if err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+
+		return nil
+	}
+
+	fprintln(stdout, wt.Path)
+
+	return nil
+}
+
+// findSwitchTarget resolves identifier to a worktree: - picks the most
+// recently created one, anything else goes through info's lookup.
+func findSwitchTarget(
+	ctx context.Context,
+	git *Git,
+	mainRepoRoot string,
+	worktrees []WorktreeWithPath,
+	identifier string,
+) (WorktreeWithPath, error) {
+	if identifier == switchLatest {
+		if len(worktrees) == 0 {
+			return WorktreeWithPath{}, errNoWorktreesToSwitch
+		}
+
+		latest := worktrees[0]
+		for _, wt := range worktrees[1:] {
+			if wt.Created.After(latest.Created) {
+				latest = wt
+			}
+		}
+
+		return latest, nil
+	}
+
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return WorktreeWithPath{}, err
+	}
+
+	wt, found, err := findWorktreeByIdentifier(worktrees, newGitWorktreeIndex(entries), identifier, identifierKeysAll...)
+	if err != nil {
+		return WorktreeWithPath{}, err
+	}
+
+	if !found {
+		return WorktreeWithPath{}, fmt.Errorf("%w: %s", errWorktreeNotFound, identifier)
+	}
+
+	return wt, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func Test_Switch_Prints_Path_Of_Worktree_By_Name(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	createOut := c.MustRun("--config", "config.json", "create", "--name", "switch-me")
+	wtPath := extractPath(createOut)
+
+	stdout, stderr, code := c.Run("--config", "config.json", "switch", "switch-me")
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d\nstderr: %s", code, stderr)
+	}
+
+	if stdout != wtPath+"\n" {
+		t.Errorf("expected only the path %q, got %q", wtPath, stdout)
+	}
+
+	if !filepath.IsAbs(wtPath) {
+		t.Errorf("expected absolute path, got %q", wtPath)
+	}
+}
+
+func Test_Switch_Looks_Up_By_ID_And_AgentID(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	createOut := c.MustRun("--config", "config.json", "create", "--name", "by-key")
+	wtPath := extractPath(createOut)
+	agentID := extractField(createOut, "agent_id")
+
+	for _, identifier := range []string{"1", agentID} {
+		stdout := c.MustRun("--config", "config.json", "switch", identifier)
+		if stdout != wtPath {
+			t.Errorf("switch %s: expected %q, got %q", identifier, wtPath, stdout)
+		}
+	}
+}
+
+func Test_Switch_Dash_Picks_Most_Recently_Created_Worktree(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	// Created second but sorted after a-old in the directory listing
+	c.MustRun("--config", "config.json", "create", "--name", "b-new")
+	c.MustRun("--config", "config.json", "create", "--name", "a-old")
+
+	// Backdate b-new so a-old is the newest despite directory order
+	c.WriteFile("worktrees/b-new/.wt/worktree.json",
+		`{"name":"b-new","agent_id":"b-new","id":1,"base_branch":"master","created":"2020-01-01T00:00:00Z"}`)
+
+	stdout := c.MustRun("--config", "config.json", "switch", "-")
+
+	if filepath.Base(stdout) != "a-old" {
+		t.Errorf("expected newest worktree a-old, got %q", stdout)
+	}
+}
+
+func Test_Switch_Dash_Fails_When_No_Worktrees(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	_, stderr, code := c.Run("--config", "config.json", "switch", "-")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "no worktrees to switch to")
+}
+
+func Test_Switch_Returns_Error_When_Not_Found(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	stdout, stderr, code := c.Run("--config", "config.json", "switch", "nope-wt")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	if stdout != "" {
+		t.Errorf("expected no stdout, got %q", stdout)
+	}
+
+	AssertContains(t, stderr, "worktree not found: nope-wt")
+}
+
+func Test_Switch_Requires_Identifier(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	_, stderr, code := c.Run("switch")
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "worktree identifier is required")
+}
+
+func Test_Switch_JSON_Outputs_Worktree_Info(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	createOut := c.MustRun("--config", "config.json", "create", "--name", "json-switch")
+	wtPath := extractPath(createOut)
+
+	stdout := c.MustRun("--config", "config.json", "switch", "json-switch", "--json")
+
+	var wt WorktreeWithPath
+
+	err := json.Unmarshal([]byte(stdout), &wt)
+	if err != nil {
+		t.Fatalf("failed to parse JSON: %v\noutput: %s", err, stdout)
+	}
+
+	if wt.Name != "json-switch" || wt.ID != 1 || wt.BaseBranch != "master" || wt.Path != wtPath {
+		t.Errorf("unexpected JSON: %+v", wt)
+	}
+
+	if wt.AgentID == "" || wt.Created.IsZero() {
+		t.Errorf("expected agent_id and created to be set: %+v", wt)
+	}
+}
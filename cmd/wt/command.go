@@ -10,6 +10,10 @@ import (
 	flag "github.com/spf13/pflag"
 )
 
+// errReported is wrapped by Exec errors the command already reported (e.g.
+// as data on stdout), so Run exits 1 without printing them again.
+var errReported = errors.New("error already reported")
+
 // Command defines a CLI command with unified help generation.
 type Command struct {
 	// Flags defines command-specific flags.
@@ -89,6 +93,13 @@ func (c *Command) Run(ctx context.Context, stdin io.Reader, stdout, stderr io.Wr
 			return 0
 		}
 
+		// Flags before the bad one are parsed, so a leading --json still counts
+		if jsonRequested(c.Flags) {
+			printJSONError(stderr, err)
+
+			return 1
+		}
+
 		fprintError(stderr, err)
 		fprintln(stderr)
 		c.PrintHelp(stderr)
@@ -106,7 +117,14 @@ func (c *Command) Run(ctx context.Context, stdin io.Reader, stdout, stderr io.Wr
 
 	err = c.Exec(ctx, stdin, stdout, stderr, c.Flags.Args())
 	if err != nil {
-		fprintError(stderr, err)
+		switch {
+		case errors.Is(err, errReported):
+			// Already on stdout
+		case jsonRequested(c.Flags):
+			printJSONError(stderr, err)
+		default:
+			fprintError(stderr, err)
+		}
 
 		return 1
 	}
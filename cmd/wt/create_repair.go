@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+	flag "github.com/spf13/pflag"
+)
+
+// Errors for create --overwrite-metadata.
+var (
+	errRepairConflict      = errors.New("cannot use --overwrite-metadata with")
+	errRepairNotWorktree   = errors.New("not a linked git worktree (see git worktree list)")
+	errRepairMetadataValid = errors.New("worktree already has valid metadata (use --force to overwrite it)")
+	errForceRequiresRepair = errors.New("--force can only be used with --overwrite-metadata")
+)
+
+// repairFlags are the create flags that apply to --overwrite-metadata.
+var repairFlags = []string{"overwrite-metadata", "force", "name", "from-branch", "from", "json", "porcelain"}
+
+// checkRepairFlags fails if a create flag that does not apply to
+// --overwrite-metadata was given.
+func checkRepairFlags(flags *flag.FlagSet) error {
+	var err error
+
+	flags.Visit(func(f *flag.Flag) {
+		if err == nil && !slices.Contains(repairFlags, f.Name) {
+			err = fmt.Errorf("%w --%s", errRepairConflict, f.Name)
+		}
+	})
+
+	return err
+}
+
+// execRepairMetadata writes fresh .wt/worktree.json metadata into the
+// existing git worktree at target, e.g. after it was deleted or corrupted.
+// The ID and agent_id are allocated under the create lock like for a new
+// worktree. Valid metadata is only replaced with force.
+func execRepairMetadata(
+	ctx context.Context,
+	stdout, stderr io.Writer,
+	cfg Config,
+	fsys fs.FS,
+	git *Git,
+	opts createOptions,
+	target string,
+	force bool,
+) error {
+	wtPath := target
+	if !filepath.IsAbs(wtPath) {
+		wtPath = filepath.Join(cfg.EffectiveCwd, wtPath)
+	}
+
+	wtPath = filepath.Clean(wtPath)
+
+	mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd)
+	if err != nil {
+		return err
+	}
+
+	entries, err := git.WorktreeListDetailed(ctx, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	entry, ok := newGitWorktreeIndex(entries).lookup(wtPath)
+	if !ok || entry.Bare || entry.Prunable || isSamePath(entry.Path, mainRepoRoot) {
+		return fmt.Errorf("%w: %s", errRepairNotWorktree, target)
+	}
+
+	_, readErr := readWorktreeInfo(fsys, wtPath)
+	if readErr == nil && !force {
+		return fmt.Errorf("%w: %s", errRepairMetadataValid, wtPath)
+	}
+
+	baseBranch := opts.fromBranch
+	if baseBranch == "" {
+		baseBranch, err = git.CurrentBranch(ctx, cfg.EffectiveCwd)
+		if err != nil {
+			return fmt.Errorf("getting current branch (use --from-branch if in detached HEAD): %w", err)
+		}
+	} else {
+		validateErr := validateBaseBranch(ctx, git, mainRepoRoot, baseBranch)
+		if validateErr != nil {
+			return validateErr
+		}
+
+		baseBranch, err = recordedBaseName(ctx, git, mainRepoRoot, baseBranch)
+		if err != nil {
+			return err
+		}
+	}
+
+	gitCommonDir, err := git.GitCommonDir(ctx, mainRepoRoot)
+	if err != nil {
+		return fmt.Errorf("cannot determine git directory: %w", err)
+	}
+
+	baseDir, err := resolveWorktreeBaseDir(cfg, mainRepoRoot)
+	if err != nil {
+		return err
+	}
+
+	if !isSamePath(filepath.Dir(wtPath), baseDir) {
+		fprintf(stderr, "warning: %s is not in the base directory %s, so wt ls and other commands will not find it\n", wtPath, baseDir)
+	}
+
+	lock, err := acquireCreateLock(ctx, fsys, gitCommonDir, cfg.LockTimeout)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = lock.Close() }()
+
+	_, warning := ensureWorktreeExcluded(fsys, gitCommonDir)
+	if warning != "" {
+		fprintln(stderr, warning)
+	}
+
+	// The metadata being replaced does not count against the new one
+	worktrees, err := findWorktreesWithPaths(fsys, baseDir)
+	if err != nil {
+		return fmt.Errorf("scanning existing worktrees: %w", err)
+	}
+
+	existing := make([]WorktreeInfo, 0, len(worktrees))
+
+	for _, wt := range worktrees {
+		if !isSamePath(wt.Path, wtPath) {
+			existing = append(existing, wt.WorktreeInfo)
+		}
+	}
+
+	customName := opts.customName
+	if customName == "" {
+		customName = filepath.Base(wtPath)
+	}
+
+	branchExists := func(name string) bool {
+		exists, _ := git.BranchExists(ctx, mainRepoRoot, branchForName(cfg, name))
+
+		return exists
+	}
+
+	name, agentID, nextID, err := allocateAmong(existing, customName, cfg.Naming, branchExists)
+	if err != nil {
+		return err
+	}
+
+	info := &WorktreeInfo{
+		Name:       name,
+		AgentID:    agentID,
+		ID:         nextID,
+		BaseBranch: baseBranch,
+		Locked:     entry.Locked,
+		Detached:   entry.Detached,
+		Created:    time.Now().UTC(),
+	}
+
+	// Record whatever branch is checked out so merge and remove act on it
+	if entry.Branch != "" && entry.Branch != name {
+		info.Branch = entry.Branch
+	}
+
+	err = writeWorktreeInfo(fsys, wtPath, info)
+	if err != nil {
+		return err
+	}
+
+	_ = lock.Close()
+
+	repaired := &createdWorktree{
+		info:              info,
+		path:              wtPath,
+		repoRoot:          mainRepoRoot,
+		branch:            entry.Branch,
+		hookSkippedReason: hookSkippedRepair,
+	}
+
+	switch {
+	case opts.porcelain:
+		printWorktreePorcelain(stdout, "repaired", repaired)
+	case opts.jsonOutput:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		encodeErr := enc.Encode(repaired.jsonOutput())
+		if encodeErr != nil {
+			return fmt.Errorf("encoding JSON: %w", encodeErr)
+		}
+	default:
+		printWorktreeSummary(informational(cfg, stdout), "Repaired worktree metadata:", repaired)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+)
+
+func Test_Create_Overwrite_Metadata_Repairs_Missing_Metadata(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "first")
+	brokenPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "broken"))
+
+	err := os.Remove(filepath.Join(brokenPath, ".wt", "worktree.json"))
+	if err != nil {
+		t.Fatalf("removing metadata: %v", err)
+	}
+
+	AssertNotContains(t, c.MustRun("--config", "config.json", "ls"), "broken")
+
+	stdout := c.MustRun("--config", "config.json", "create", "--overwrite-metadata", "worktrees/broken")
+
+	AssertContains(t, stdout, "Repaired worktree metadata:")
+	AssertContains(t, stdout, "path:        "+brokenPath)
+
+	info, err := readWorktreeInfo(fs.NewReal(), brokenPath)
+	if err != nil {
+		t.Fatalf("reading repaired metadata: %v", err)
+	}
+
+	if info.Name != "broken" || info.ID != 2 || info.AgentID == "" || info.BaseBranch != "master" {
+		t.Errorf("expected name broken, id 2, an agent_id and base_branch master, got %+v", info)
+	}
+
+	AssertContains(t, c.MustRun("--config", "config.json", "ls"), "broken")
+
+	// The branch checked out there is untouched
+	if branch := gitOutput(t, brokenPath, "branch", "--show-current"); branch != "broken" {
+		t.Errorf("expected branch broken, got %q", branch)
+	}
+}
+
+func Test_Create_Overwrite_Metadata_Records_Checked_Out_Branch(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	wtPath := filepath.Join(c.Dir, "worktrees", "foo")
+
+	out, err := testGitCmd("-C", c.Dir, "worktree", "add", "-b", "feature-x", wtPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git worktree add: %v\n%s", err, out)
+	}
+
+	c.MustRun("--config", "config.json", "create", "--overwrite-metadata", wtPath)
+
+	info, err := readWorktreeInfo(fs.NewReal(), wtPath)
+	if err != nil {
+		t.Fatalf("reading repaired metadata: %v", err)
+	}
+
+	if info.Name != "foo" || info.Branch != "feature-x" {
+		t.Fatalf("expected name foo and branch feature-x, got %+v", info)
+	}
+
+	c.MustRun("--config", "config.json", "remove", "foo", "--with-branch", "--force")
+
+	branches := listBranches(t, c.Dir)
+	if slices.Contains(branches, "feature-x") {
+		t.Errorf("expected remove --with-branch to delete feature-x, got %v", branches)
+	}
+}
+
+func Test_Create_Overwrite_Metadata_Replaces_Valid_Metadata_Only_With_Force(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	wtPath := extractPath(c.MustRun("--config", "config.json", "create", "--name", "valid"))
+
+	_, stderr, code := c.Run("--config", "config.json", "create", "--overwrite-metadata", wtPath)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "already has valid metadata (use --force")
+
+	stdout := c.MustRun("--config", "config.json", "create", "--overwrite-metadata", wtPath, "--force", "--name", "renamed", "--porcelain")
+
+	AssertContains(t, stdout, "status\trepaired\nname\trenamed\n")
+	AssertContains(t, stdout, "id\t1\n")
+
+	// Corrupt metadata needs no --force
+	c.WriteFile(filepath.Join("worktrees", "valid", ".wt", "worktree.json"), "{not json")
+	c.MustRun("--config", "config.json", "create", "--overwrite-metadata", wtPath)
+
+	if _, err := readWorktreeInfo(fs.NewReal(), wtPath); err != nil {
+		t.Errorf("metadata should be valid after repair: %v", err)
+	}
+}
+
+func Test_Create_Overwrite_Metadata_Rejects_Non_Worktrees_And_Other_Flags(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.WriteFile("worktrees/plain/file.txt", "not a worktree")
+
+	for _, target := range []string{"worktrees/plain", "."} {
+		_, stderr, code := c.Run("--config", "config.json", "create", "--overwrite-metadata", target)
+		if code != 1 {
+			t.Errorf("%s: expected exit code 1, got %d", target, code)
+		}
+
+		AssertContains(t, stderr, "not a linked git worktree")
+	}
+
+	_, stderr, code := c.Run("--config", "config.json", "create", "--overwrite-metadata", "worktrees/plain", "--lock")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "cannot use --overwrite-metadata with --lock")
+
+	_, stderr, code = c.Run("--config", "config.json", "create", "--force")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "--force can only be used with --overwrite-metadata")
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -27,6 +28,29 @@ var (
 	ErrGitBranchCheck    = errors.New("checking branch")
 	ErrGitConflictCheck  = errors.New("checking conflicts")
 	ErrGitCommitCount    = errors.New("counting commits")
+	ErrGitNoUpstream     = errors.New("branch has no upstream")
+	ErrGitFetch          = errors.New("fetching from remote")
+	ErrGitPush           = errors.New("pushing to remote")
+	ErrGitRevParse       = errors.New("resolving revision")
+	ErrGitDefaultBranch  = errors.New("could not determine default branch (no origin/HEAD, main, or master)")
+	ErrGitSparseCheckout = errors.New("configuring sparse-checkout")
+	ErrGitDir            = errors.New("resolving git directory")
+	ErrGitRemoteList     = errors.New("listing remotes")
+	ErrGitSetUpstream    = errors.New("configuring upstream")
+	ErrGitWorktreeLock   = errors.New("locking worktree")
+	ErrGitBranchDesc     = errors.New("setting branch description")
+	ErrGitBranchList     = errors.New("listing branches")
+	ErrGitWorktreeMove   = errors.New("moving worktree")
+	ErrGitBranchCreate   = errors.New("creating branch")
+	ErrGitLog            = errors.New("reading commit log")
+	ErrGitSwitch         = errors.New("switching HEAD")
+	ErrGitBranchRename   = errors.New("renaming branch")
+	ErrGitNotFastForward = errors.New("not a fast-forward")
+	ErrGitLsFiles        = errors.New("listing ignored files")
+	ErrGitMergeBase      = errors.New("checking ancestry")
+	ErrGitMergeAbort     = errors.New("aborting merge")
+	ErrGitMergeTree      = errors.New("computing merge")
+	ErrGitCommitTree     = errors.New("creating commit")
 )
 
 // Git provides git operations with explicit environment control.
@@ -55,6 +79,20 @@ func (g *Git) RepoRoot(ctx context.Context, cwd string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// GitDir returns the absolute git directory for the worktree containing dir.
+// For linked worktrees this is .git/worktrees/<id> in the main repository,
+// where per-worktree state such as rebase-merge lives.
+func (g *Git) GitDir(ctx context.Context, dir string) (string, error) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "rev-parse", "--absolute-git-dir")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrGitDir, err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
 // GitCommonDir returns the absolute path to the shared .git directory.
 // For a regular repo, this is .git/. For a worktree, this returns
 // the main repository's .git directory, ensuring all worktrees
@@ -96,18 +134,96 @@ func (g *Git) CurrentBranch(ctx context.Context, cwd string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// DetachHead detaches HEAD in dir at its current commit, keeping the working
+// tree and index as they are.
+func (g *Git) DetachHead(ctx context.Context, dir string) error {
+	cmd := g.newCmdContext(ctx, "-C", dir, "switch", "--detach")
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrGitSwitch, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// SwitchBranch checks out branch in dir.
+func (g *Git) SwitchBranch(ctx context.Context, dir, branch string) error {
+	cmd := g.newCmdContext(ctx, "-C", dir, "switch", branch)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w to %s: %w: %s", ErrGitSwitch, branch, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
 // IsDirty returns true if the worktree has any uncommitted changes,
 // including modified tracked files and untracked files.
 // Use this for checking before deleting a worktree (user might lose work).
 func (g *Git) IsDirty(ctx context.Context, path string) (bool, error) {
+	status, err := g.Status(ctx, path)
+	if err != nil {
+		return false, err
+	}
+
+	return status.Dirty(), nil
+}
+
+// WorktreeStatus counts the uncommitted changes in a worktree, by kind.
+// A file that is staged and modified again counts as both.
+type WorktreeStatus struct {
+	Staged    int // Files with changes in the index
+	Unstaged  int // Tracked files with changes not in the index
+	Untracked int // Files git does not track (ignored files not included)
+}
+
+// Dirty reports whether there is any uncommitted change, untracked files
+// included.
+func (s WorktreeStatus) Dirty() bool {
+	return s.HasTrackedChanges() || s.Untracked > 0
+}
+
+// HasTrackedChanges reports whether tracked files are staged or modified.
+func (s WorktreeStatus) HasTrackedChanges() bool {
+	return s.Staged > 0 || s.Unstaged > 0
+}
+
+// Status returns the uncommitted changes in the worktree at path, parsed
+// from git status --porcelain.
+func (g *Git) Status(ctx context.Context, path string) (WorktreeStatus, error) {
 	cmd := g.newCmdContext(ctx, "-C", path, "status", "--porcelain")
 
 	out, err := cmd.Output()
 	if err != nil {
-		return false, fmt.Errorf("%w: %w", ErrGitStatusCheck, err)
+		return WorktreeStatus{}, fmt.Errorf("%w: %w", ErrGitStatusCheck, err)
 	}
 
-	return len(out) > 0, nil
+	var status WorktreeStatus
+
+	for line := range strings.Lines(string(out)) {
+		// "XY path": X is the index, Y the working tree
+		if len(line) < 2 {
+			continue
+		}
+
+		if line[:2] == "??" {
+			status.Untracked++
+
+			continue
+		}
+
+		if line[0] != ' ' {
+			status.Staged++
+		}
+
+		if line[1] != ' ' {
+			status.Unstaged++
+		}
+	}
+
+	return status, nil
 }
 
 // HasUncommittedTrackedChanges returns true if the worktree has uncommitted
@@ -126,9 +242,16 @@ func (g *Git) HasUncommittedTrackedChanges(ctx context.Context, path string) (bo
 	return len(out) > 0, nil
 }
 
-// WorktreeAdd creates a new worktree with a new branch.
-func (g *Git) WorktreeAdd(ctx context.Context, repoRoot, wtPath, branch, baseBranch string) error {
-	cmd := g.newCmdContext(ctx, "-C", repoRoot, "worktree", "add", "-b", branch, wtPath, baseBranch)
+// WorktreeAdd creates a new worktree with a new branch starting at
+// startPoint, which can be any commit-ish (branch, tag, SHA, HEAD~3, ...).
+// With noCheckout, no files are checked out (git worktree add --no-checkout).
+func (g *Git) WorktreeAdd(ctx context.Context, repoRoot, wtPath, branch, startPoint string, noCheckout bool) error {
+	args := []string{"-C", repoRoot, "worktree", "add"}
+	if noCheckout {
+		args = append(args, "--no-checkout")
+	}
+
+	cmd := g.newCmdContext(ctx, append(args, "-b", branch, wtPath, startPoint)...)
 
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -138,6 +261,51 @@ func (g *Git) WorktreeAdd(ctx context.Context, repoRoot, wtPath, branch, baseBra
 	return nil
 }
 
+// WorktreeAddExisting creates a worktree that checks out an existing branch.
+func (g *Git) WorktreeAddExisting(ctx context.Context, repoRoot, wtPath, branch string) error {
+	cmd := g.newCmdContext(ctx, "-C", repoRoot, "worktree", "add", wtPath, branch)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrGitWorktreeAdd, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// WorktreeLock locks the worktree at wtPath, so "git worktree prune" and
+// "git worktree remove" leave it alone. An empty reason is omitted.
+func (g *Git) WorktreeLock(ctx context.Context, repoRoot, wtPath, reason string) error {
+	args := []string{"-C", repoRoot, "worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+
+	cmd := g.newCmdContext(ctx, append(args, wtPath)...)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrGitWorktreeLock, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// SparseCheckoutSet restricts the worktree at wtPath to the given directories
+// using cone-mode sparse-checkout. The setting is per-worktree, so other
+// worktrees of the repo keep their full checkout.
+func (g *Git) SparseCheckoutSet(ctx context.Context, wtPath string, patterns []string) error {
+	args := append([]string{"-C", wtPath, "sparse-checkout", "set", "--"}, patterns...)
+	cmd := g.newCmdContext(ctx, args...)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrGitSparseCheckout, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
 // WorktreeRemove removes a worktree.
 func (g *Git) WorktreeRemove(ctx context.Context, repoRoot, wtPath string, force bool) error {
 	args := []string{"-C", repoRoot, "worktree", "remove", wtPath}
@@ -155,6 +323,24 @@ func (g *Git) WorktreeRemove(ctx context.Context, repoRoot, wtPath string, force
 	return nil
 }
 
+// WorktreeMove moves a worktree to newPath. Locked worktrees are only moved
+// with force (git requires --force twice for them).
+func (g *Git) WorktreeMove(ctx context.Context, repoRoot, wtPath, newPath string, force bool) error {
+	args := []string{"-C", repoRoot, "worktree", "move"}
+	if force {
+		args = append(args, "--force", "--force")
+	}
+
+	cmd := g.newCmdContext(ctx, append(args, wtPath, newPath)...)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrGitWorktreeMove, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
 // WorktreePrune prunes stale worktree metadata.
 func (g *Git) WorktreePrune(ctx context.Context, repoRoot string) error {
 	cmd := g.newCmdContext(ctx, "-C", repoRoot, "worktree", "prune")
@@ -184,8 +370,50 @@ func (g *Git) BranchDelete(ctx context.Context, repoRoot, branch string, force b
 	return nil
 }
 
+// BranchRename renames branch oldName to newName (git branch -m), also when
+// it is checked out in a worktree.
+func (g *Git) BranchRename(ctx context.Context, repoRoot, oldName, newName string) error {
+	cmd := g.newCmdContext(ctx, "-C", repoRoot, "branch", "-m", oldName, newName)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w %s: %w: %s", ErrGitBranchRename, oldName, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
 // WorktreeList returns paths of all worktrees for the repo.
 func (g *Git) WorktreeList(ctx context.Context, repoRoot string) ([]string, error) {
+	entries, err := g.WorktreeListDetailed(ctx, repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+
+	return paths, nil
+}
+
+// WorktreeEntry is a single worktree as reported by "git worktree list --porcelain".
+type WorktreeEntry struct {
+	Path           string
+	HEAD           string
+	Branch         string // Short branch name; empty when detached or bare
+	Bare           bool
+	Detached       bool
+	Locked         bool
+	LockReason     string
+	Prunable       bool
+	PrunableReason string
+}
+
+// WorktreeListDetailed returns all worktrees for the repo with their git state.
+// The main worktree is always the first entry.
+func (g *Git) WorktreeListDetailed(ctx context.Context, repoRoot string) ([]WorktreeEntry, error) {
 	cmd := g.newCmdContext(ctx, "-C", repoRoot, "worktree", "list", "--porcelain")
 
 	out, err := cmd.Output()
@@ -193,16 +421,64 @@ func (g *Git) WorktreeList(ctx context.Context, repoRoot string) ([]string, erro
 		return nil, fmt.Errorf("%w: %w", ErrGitWorktreeList, err)
 	}
 
-	// Parse "worktree <path>" lines
-	var paths []string
+	return parseWorktreePorcelain(string(out)), nil
+}
 
-	for line := range strings.SplitSeq(string(out), "\n") {
-		if after, ok := strings.CutPrefix(line, "worktree "); ok {
-			paths = append(paths, after)
+// parseWorktreePorcelain parses "git worktree list --porcelain" output.
+// Blocks are separated by blank lines and start with a "worktree <path>" line:
+//
+//	worktree /path/to/wt
+//	HEAD <sha>
+//	branch refs/heads/<branch>   (or "detached", or "bare")
+//	locked [<reason>]
+//	prunable [<reason>]
+//
+// Unknown attributes are ignored so newer git versions don't break parsing.
+func parseWorktreePorcelain(out string) []WorktreeEntry {
+	var entries []WorktreeEntry
+
+	var current *WorktreeEntry
+
+	for line := range strings.SplitSeq(out, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			current = nil
+
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+
+		if key == "worktree" {
+			entries = append(entries, WorktreeEntry{Path: value})
+			current = &entries[len(entries)-1]
+
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		switch key {
+		case "HEAD":
+			current.HEAD = value
+		case "branch":
+			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "bare":
+			current.Bare = true
+		case "detached":
+			current.Detached = true
+		case "locked":
+			current.Locked = true
+			current.LockReason = value
+		case "prunable":
+			current.Prunable = true
+			current.PrunableReason = value
 		}
 	}
 
-	return paths, nil
+	return entries
 }
 
 // ChangedFiles returns all uncommitted files: staged, unstaged, and untracked.
@@ -285,30 +561,134 @@ func (g *Git) BranchExists(ctx context.Context, dir, branch string) (bool, error
 	return true, nil
 }
 
-// FindWorktreeForBranch returns the worktree path that has the given branch checked out.
-// Returns empty string if the branch is not checked out in any worktree.
-func (g *Git) FindWorktreeForBranch(ctx context.Context, dir, branch string) (string, error) {
-	cmd := g.newCmdContext(ctx, "-C", dir, "worktree", "list", "--porcelain")
+// IsAncestor reports whether commit ancestor is reachable from descendant
+// (git merge-base --is-ancestor). A commit is its own ancestor.
+func (g *Git) IsAncestor(ctx context.Context, dir, ancestor, descendant string) (bool, error) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "merge-base", "--is-ancestor", ancestor, descendant)
+
+	err := cmd.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("%w %s %s: %w", ErrGitMergeBase, ancestor, descendant, err)
+	}
+
+	return true, nil
+}
+
+// ListBranches returns the names of all local branches.
+func (g *Git) ListBranches(ctx context.Context, dir string) ([]string, error) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "branch", "--list", "--format=%(refname:short)")
 
 	out, err := cmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("%w: %w", ErrGitWorktreeList, err)
+		return nil, fmt.Errorf("%w: %w", ErrGitBranchList, err)
 	}
 
-	// Parse porcelain output: blocks separated by blank lines
-	// Each block has:
-	//   worktree <path>
-	//   HEAD <sha>
-	//   branch refs/heads/<branch>
-	var currentPath string
+	return strings.Fields(string(out)), nil
+}
 
-	for line := range strings.SplitSeq(string(out), "\n") {
-		if after, ok := strings.CutPrefix(line, "worktree "); ok {
-			currentPath = after
-		} else if after, ok := strings.CutPrefix(line, "branch refs/heads/"); ok {
-			if after == branch {
-				return currentPath, nil
-			}
+// ResolveBranchName resolves name to a local branch name if it refers to one
+// via another spelling (HEAD, @, refs/heads/x, @{upstream} tracking a local
+// branch). Returns name unchanged if it does not resolve to a local branch.
+func (g *Git) ResolveBranchName(ctx context.Context, dir, name string) string {
+	cmd := g.newCmdContext(ctx, "-C", dir, "rev-parse", "--symbolic-full-name", name)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return name
+	}
+
+	branch, ok := strings.CutPrefix(strings.TrimSpace(string(out)), "refs/heads/")
+	if !ok || branch == "" {
+		return name
+	}
+
+	return branch
+}
+
+// RemoteTrackingBranch reports whether name refers to a remote-tracking
+// branch (origin/main, refs/remotes/origin/main). It returns the full ref and
+// the branch name without the remote, e.g. "refs/remotes/origin/main" and
+// "main".
+func (g *Git) RemoteTrackingBranch(ctx context.Context, dir, name string) (string, string, bool) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "rev-parse", "--verify", "--quiet", "--symbolic-full-name", name)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", false
+	}
+
+	ref := strings.TrimSpace(string(out))
+
+	remoteAndBranch, ok := strings.CutPrefix(ref, "refs/remotes/")
+	if !ok {
+		return "", "", false
+	}
+
+	_, branch, ok := strings.Cut(remoteAndBranch, "/")
+	if !ok || branch == "" || branch == "HEAD" {
+		return "", "", false
+	}
+
+	return ref, branch, true
+}
+
+// BranchCreate creates branch at startPoint. A remote-tracking startPoint
+// becomes the branch's upstream.
+func (g *Git) BranchCreate(ctx context.Context, dir, branch, startPoint string) error {
+	cmd := g.newCmdContext(ctx, "-C", dir, "branch", "--track", branch, startPoint)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w %s: %w: %s", ErrGitBranchCreate, branch, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// IgnoredFiles returns the untracked, gitignored files in the worktree at
+// dir that match any of patterns, as paths relative to dir. Patterns are git
+// glob pathspecs: * does not match /, ** does, and a directory matches
+// everything below it.
+func (g *Git) IgnoredFiles(ctx context.Context, dir string, patterns []string) ([]string, error) {
+	args := []string{"-C", dir, "ls-files", "-z", "--others", "--ignored", "--exclude-standard", "--"}
+	for _, pattern := range patterns {
+		args = append(args, ":(glob)"+pattern)
+	}
+
+	cmd := g.newCmdContext(ctx, args...)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGitLsFiles, err)
+	}
+
+	var files []string
+
+	for file := range strings.SplitSeq(string(out), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+// FindWorktreeForBranch returns the worktree path that has the given branch checked out.
+// Returns empty string if the branch is not checked out in any worktree.
+func (g *Git) FindWorktreeForBranch(ctx context.Context, dir, branch string) (string, error) {
+	entries, err := g.WorktreeListDetailed(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if entry.Branch == branch {
+			return entry.Path, nil
 		}
 	}
 
@@ -377,6 +757,79 @@ func (g *Git) Merge(ctx context.Context, dir, branch string, ffOnly bool) error
 	return nil
 }
 
+// MergeNoFF merges branch into the branch checked out in dir with a merge
+// commit (git merge --no-ff), using message as its commit message.
+func (g *Git) MergeNoFF(ctx context.Context, dir, branch, message string) error {
+	cmd := g.newCmdContext(ctx, "-C", dir, "merge", "--no-ff", "--no-edit", "-m", message, branch)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrGitMerge, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// MergeAbort aborts an in-progress merge, restoring the pre-merge state.
+func (g *Git) MergeAbort(ctx context.Context, dir string) error {
+	cmd := g.newCmdContext(ctx, "-C", dir, "merge", "--abort")
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrGitMergeAbort, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// MergeTree merges commits ours and theirs without touching any working
+// tree or ref (git merge-tree --write-tree). It returns the resulting tree,
+// or the conflicting paths if the merge does not apply cleanly.
+func (g *Git) MergeTree(ctx context.Context, dir, ours, theirs string) (string, []string, error) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "merge-tree", "--write-tree", "--name-only", "--no-messages", ours, theirs)
+
+	out, err := cmd.Output()
+
+	// Exit 1 means conflicts: the tree is followed by the conflicting paths
+	var exitErr *exec.ExitError
+	if err != nil && (!errors.As(err, &exitErr) || exitErr.ExitCode() != 1) {
+		return "", nil, fmt.Errorf("%w: %w", ErrGitMergeTree, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if err == nil {
+		return lines[0], nil, nil
+	}
+
+	var conflicts []string
+
+	for _, line := range lines[1:] {
+		if line = strings.TrimSpace(line); line != "" && !slices.Contains(conflicts, line) {
+			conflicts = append(conflicts, line)
+		}
+	}
+
+	return "", conflicts, nil
+}
+
+// CommitTree creates a commit of tree with the given parents and message
+// (git commit-tree), without moving any ref, and returns its SHA.
+func (g *Git) CommitTree(ctx context.Context, dir, tree, message string, parents ...string) (string, error) {
+	args := []string{"-C", dir, "commit-tree", tree, "-m", message}
+	for _, parent := range parents {
+		args = append(args, "-p", parent)
+	}
+
+	cmd := g.newCmdContext(ctx, args...)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrGitCommitTree, err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
 // PushLocal updates a local branch to match another branch using "git push . src:dst".
 // This is a safe, atomic way to fast-forward a branch that isn't checked out.
 // Fails if not fast-forward (target moved), which triggers retry logic.
@@ -393,6 +846,126 @@ func (g *Git) PushLocal(ctx context.Context, dir, sourceBranch, targetBranch str
 	return nil
 }
 
+// FastForwardBranch moves branch (not checked out anywhere) forward to rev,
+// like PushLocal, but writes message to the branch's reflog. It fails if
+// rev does not contain the branch tip, or if the branch moves meanwhile
+// (update-ref only updates from the tip that was checked).
+func (g *Git) FastForwardBranch(ctx context.Context, dir, branch, rev, message string) error {
+	oldSHA, err := g.RevParse(ctx, dir, "refs/heads/"+branch)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrGitPushLocal, err)
+	}
+
+	newSHA, err := g.RevParse(ctx, dir, rev)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrGitPushLocal, err)
+	}
+
+	fastForward, err := g.IsAncestor(ctx, dir, oldSHA, newSHA)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrGitPushLocal, err)
+	}
+
+	if !fastForward {
+		return fmt.Errorf("%w: %w: %s does not contain %s", ErrGitPushLocal, ErrGitNotFastForward, rev, branch)
+	}
+
+	cmd := g.newCmdContext(ctx, "-C", dir, "update-ref", "-m", message, "refs/heads/"+branch, newSHA, oldSHA)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrGitPushLocal, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// WithReflogAction returns a copy of g whose commands run with
+// GIT_REFLOG_ACTION=action, so rebase and merge label their reflog entries
+// with it instead of their own name.
+func (g *Git) WithReflogAction(action string) *Git {
+	env := slices.Clone(g.env)
+	env = slices.DeleteFunc(env, func(kv string) bool {
+		return strings.HasPrefix(kv, "GIT_REFLOG_ACTION=")
+	})
+
+	return &Git{env: append(env, "GIT_REFLOG_ACTION="+action)}
+}
+
+// WithAuthor returns a copy of g whose commits are authored by name and
+// email (GIT_AUTHOR_NAME and GIT_AUTHOR_EMAIL) instead of the configured
+// identity. The committer is unchanged.
+func (g *Git) WithAuthor(name, email string) *Git {
+	env := slices.Clone(g.env)
+	env = slices.DeleteFunc(env, func(kv string) bool {
+		return strings.HasPrefix(kv, "GIT_AUTHOR_NAME=") || strings.HasPrefix(kv, "GIT_AUTHOR_EMAIL=")
+	})
+
+	return &Git{env: append(env, "GIT_AUTHOR_NAME="+name, "GIT_AUTHOR_EMAIL="+email)}
+}
+
+// CommitSummary is a commit's abbreviated SHA and subject line.
+type CommitSummary struct {
+	ShortSHA string
+	Subject  string
+}
+
+// CommitSummaries returns the summary of each commit in shas (full SHAs),
+// keyed by full SHA, using a single git log call.
+func (g *Git) CommitSummaries(ctx context.Context, dir string, shas []string) (map[string]CommitSummary, error) {
+	summaries := make(map[string]CommitSummary, len(shas))
+	if len(shas) == 0 {
+		return summaries, nil
+	}
+
+	args := append([]string{"-C", dir, "log", "--no-walk=unsorted", "--format=%H%x00%h%x00%s"}, shas...)
+	cmd := g.newCmdContext(ctx, args...)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGitLog, err)
+	}
+
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		parts := strings.SplitN(line, "\x00", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		summaries[parts[0]] = CommitSummary{ShortSHA: parts[1], Subject: parts[2]}
+	}
+
+	return summaries, nil
+}
+
+// BranchCommit is a commit's full SHA and subject line.
+type BranchCommit struct {
+	SHA     string
+	Subject string
+}
+
+// BranchCommits returns the commits in branch but not in target, oldest
+// first.
+func (g *Git) BranchCommits(ctx context.Context, dir, target, branch string) ([]BranchCommit, error) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "log", "--reverse", "--format=%H%x00%s", target+".."+branch)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrGitLog, err)
+	}
+
+	var commits []BranchCommit
+
+	for line := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n") {
+		sha, subject, ok := strings.Cut(line, "\x00")
+		if ok {
+			commits = append(commits, BranchCommit{SHA: sha, Subject: subject})
+		}
+	}
+
+	return commits, nil
+}
+
 // CommitsBetween returns the number of commits on branch that are not on target.
 func (g *Git) CommitsBetween(ctx context.Context, dir, target, branch string) (int, error) {
 	cmd := g.newCmdContext(ctx, "-C", dir, "rev-list", "--count", target+".."+branch)
@@ -412,6 +985,222 @@ func (g *Git) CommitsBetween(ctx context.Context, dir, target, branch string) (i
 	return count, nil
 }
 
+// AheadBehind returns how many commits HEAD in dir has that base does not
+// (ahead) and base has that HEAD does not (behind).
+func (g *Git) AheadBehind(ctx context.Context, dir, base string) (int, int, error) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "rev-list", "--left-right", "--count", base+"...HEAD")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %w", ErrGitCommitCount, err)
+	}
+
+	var behind, ahead int
+
+	_, err = fmt.Sscanf(strings.TrimSpace(string(out)), "%d %d", &behind, &ahead)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %w", ErrGitCommitCount, err)
+	}
+
+	return ahead, behind, nil
+}
+
+// Upstream returns the upstream of branch as a ref usable for merges
+// (e.g. "origin/main") and the name of its remote.
+// Returns ErrGitNoUpstream if the branch has no upstream configured.
+func (g *Git) Upstream(ctx context.Context, dir, branch string) (string, string, error) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "rev-parse", "--abbrev-ref", "--symbolic-full-name", branch+"@{upstream}")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s", ErrGitNoUpstream, branch)
+	}
+
+	cmd = g.newCmdContext(ctx, "-C", dir, "config", "--get", "branch."+branch+".remote")
+
+	remoteOut, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s", ErrGitNoUpstream, branch)
+	}
+
+	return strings.TrimSpace(string(out)), strings.TrimSpace(string(remoteOut)), nil
+}
+
+// RemoteExists reports whether a remote with the given name is configured.
+func (g *Git) RemoteExists(ctx context.Context, dir, remote string) (bool, error) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "remote")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrGitRemoteList, err)
+	}
+
+	for line := range strings.SplitSeq(string(out), "\n") {
+		if strings.TrimSpace(line) == remote {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// SetBranchUpstream sets branch.<branch>.remote and branch.<branch>.merge so
+// the branch pushes to and pulls from a branch of the same name on remote.
+// Unlike "git branch --set-upstream-to", the remote branch need not exist yet.
+func (g *Git) SetBranchUpstream(ctx context.Context, dir, branch, remote string) error {
+	settings := [][2]string{
+		{"branch." + branch + ".remote", remote},
+		{"branch." + branch + ".merge", "refs/heads/" + branch},
+	}
+
+	for _, kv := range settings {
+		cmd := g.newCmdContext(ctx, "-C", dir, "config", kv[0], kv[1])
+
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%w: %w: %s", ErrGitSetUpstream, err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	return nil
+}
+
+// SetBranchDescription sets branch.<branch>.description, the note shown by
+// 'git branch --edit-description' and tools that read it.
+func (g *Git) SetBranchDescription(ctx context.Context, dir, branch, description string) error {
+	cmd := g.newCmdContext(ctx, "-C", dir, "config", "branch."+branch+".description", description)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrGitBranchDesc, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// BranchDescription returns branch.<branch>.description, or "" if the
+// branch has none.
+func (g *Git) BranchDescription(ctx context.Context, dir, branch string) string {
+	cmd := g.newCmdContext(ctx, "-C", dir, "config", "--get", "branch."+branch+".description")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// Fetch fetches from the given remote.
+func (g *Git) Fetch(ctx context.Context, dir, remote string) error {
+	cmd := g.newCmdContext(ctx, "-C", dir, "fetch", remote)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrGitFetch, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// Push pushes branch to the branch of the same name on remote.
+func (g *Git) Push(ctx context.Context, dir, remote, branch string) error {
+	refspec := "refs/heads/" + branch + ":refs/heads/" + branch
+
+	cmd := g.newCmdContext(ctx, "-C", dir, "push", remote, refspec)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %w: %s", ErrGitPush, err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// RevParse resolves a revision to its full commit SHA.
+func (g *Git) RevParse(ctx context.Context, dir, rev string) (string, error) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w '%s': %w", ErrGitRevParse, rev, err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// RevName returns the short symbolic name of the ref rev points at: a
+// branch, tag or remote-tracking branch name, with HEAD resolved to the
+// checked-out branch. It returns "" if rev is not a ref (e.g. a SHA or
+// HEAD~3) or HEAD is detached.
+func (g *Git) RevName(ctx context.Context, dir, rev string) (string, error) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "rev-parse", "--verify", "--quiet", "--abbrev-ref", rev)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w '%s': %w", ErrGitRevParse, rev, err)
+	}
+
+	name := strings.TrimSpace(string(out))
+	if name == "HEAD" {
+		return "", nil
+	}
+
+	return name, nil
+}
+
+// ShortSHA returns the abbreviated SHA of commit.
+func (g *Git) ShortSHA(ctx context.Context, dir, commit string) (string, error) {
+	cmd := g.newCmdContext(ctx, "-C", dir, "rev-parse", "--short", commit)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w '%s': %w", ErrGitRevParse, commit, err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// DefaultBranch returns the repository's default branch.
+// Resolution order: the branch origin/HEAD points to, init.defaultBranch,
+// then "main" or "master". Only branches that exist locally are returned.
+func (g *Git) DefaultBranch(ctx context.Context, dir string) (string, error) {
+	candidates := make([]string, 0, 4)
+
+	cmd := g.newCmdContext(ctx, "-C", dir, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD")
+
+	out, err := cmd.Output()
+	if err == nil {
+		candidates = append(candidates, strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/"))
+	}
+
+	cmd = g.newCmdContext(ctx, "-C", dir, "config", "--get", "init.defaultBranch")
+
+	out, err = cmd.Output()
+	if err == nil {
+		candidates = append(candidates, strings.TrimSpace(string(out)))
+	}
+
+	candidates = append(candidates, "main", "master")
+
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+
+		exists, existsErr := g.BranchExists(ctx, dir, candidate)
+		if existsErr != nil {
+			return "", existsErr
+		}
+
+		if exists {
+			return candidate, nil
+		}
+	}
+
+	return "", ErrGitDefaultBranch
+}
+
 // newCmdContext creates an exec.Cmd for git with the configured environment and context.
 func (g *Git) newCmdContext(ctx context.Context, args ...string) *exec.Cmd {
 	cmd := exec.CommandContext(ctx, "git", args...)
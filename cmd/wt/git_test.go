@@ -317,6 +317,44 @@ func Test_gitIsDirty_Returns_True_When_Untracked(t *testing.T) {
 	}
 }
 
+func Test_gitStatus_Counts_Staged_Unstaged_And_Untracked(t *testing.T) {
+	t.Parallel()
+
+	git := newTestGit()
+
+	dir := t.TempDir()
+	initRealGitRepo(t, dir)
+
+	writeTestFile(t, filepath.Join(dir, "staged.txt"), "staged\n")
+	gitOutput(t, dir, "add", "staged.txt")
+	writeTestFile(t, filepath.Join(dir, "README.md"), "# Modified\n")
+	writeTestFile(t, filepath.Join(dir, "a.txt"), "untracked\n")
+	writeTestFile(t, filepath.Join(dir, "b.txt"), "untracked\n")
+
+	status, err := git.Status(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	want := WorktreeStatus{Staged: 1, Unstaged: 1, Untracked: 2}
+	if status != want {
+		t.Errorf("Status() = %+v, want %+v", status, want)
+	}
+
+	gitOutput(t, dir, "add", "-A")
+	gitOutput(t, dir, "commit", "-m", "everything")
+	writeTestFile(t, filepath.Join(dir, "c.txt"), "untracked\n")
+
+	status, err = git.Status(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if status.HasTrackedChanges() || status.Untracked != 1 || !status.Dirty() {
+		t.Errorf("expected only one untracked file, got %+v", status)
+	}
+}
+
 func Test_gitWorktreeAdd_Creates_Worktree(t *testing.T) {
 	t.Parallel()
 
@@ -326,7 +364,7 @@ func Test_gitWorktreeAdd_Creates_Worktree(t *testing.T) {
 	repoPath := initRealGitRepo(t, dir)
 	wtPath := filepath.Join(dir, "worktree-test")
 
-	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-branch", "master")
+	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-branch", "master", false)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -357,7 +395,7 @@ func Test_gitWorktreeAdd_Returns_Error_When_Branch_Exists(t *testing.T) {
 	wtPath := filepath.Join(dir, "worktree-test")
 
 	// First create should succeed
-	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-branch", "master")
+	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-branch", "master", false)
 	if err != nil {
 		t.Fatalf("first worktree add failed: %v", err)
 	}
@@ -365,7 +403,7 @@ func Test_gitWorktreeAdd_Returns_Error_When_Branch_Exists(t *testing.T) {
 	// Second create with same branch should fail
 	wtPath2 := filepath.Join(dir, "worktree-test-2")
 
-	err = git.WorktreeAdd(context.Background(), repoPath, wtPath2, "feature-branch", "master")
+	err = git.WorktreeAdd(context.Background(), repoPath, wtPath2, "feature-branch", "master", false)
 	if err == nil {
 		t.Error("expected error for duplicate branch, got nil")
 	}
@@ -391,7 +429,7 @@ func Test_gitWorktreeAdd_Creates_Worktree_From_Different_Branch(t *testing.T) {
 	wtPath := filepath.Join(dir, "worktree-test")
 
 	// Create worktree from develop branch
-	err = git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-from-develop", "develop")
+	err = git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-from-develop", "develop", false)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -412,7 +450,7 @@ func Test_gitWorktreeRemove_Removes_Worktree(t *testing.T) {
 	wtPath := filepath.Join(dir, "worktree-test")
 
 	// Create worktree first
-	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-branch", "master")
+	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-branch", "master", false)
 	if err != nil {
 		t.Fatalf("worktree add failed: %v", err)
 	}
@@ -439,7 +477,7 @@ func Test_gitWorktreeRemove_Returns_Error_When_Dirty_Without_Force(t *testing.T)
 	wtPath := filepath.Join(dir, "worktree-test")
 
 	// Create worktree
-	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-branch", "master")
+	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-branch", "master", false)
 	if err != nil {
 		t.Fatalf("worktree add failed: %v", err)
 	}
@@ -465,7 +503,7 @@ func Test_gitWorktreeRemove_Removes_Dirty_Worktree_With_Force(t *testing.T) {
 	wtPath := filepath.Join(dir, "worktree-test")
 
 	// Create worktree
-	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-branch", "master")
+	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "feature-branch", "master", false)
 	if err != nil {
 		t.Fatalf("worktree add failed: %v", err)
 	}
@@ -655,7 +693,7 @@ func Test_gitWorktreeList_Returns_Worktree_Paths(t *testing.T) {
 	// Add a worktree
 	wtPath := filepath.Join(dir, "worktree-1")
 
-	err = git.WorktreeAdd(context.Background(), repoPath, wtPath, "branch-1", "master")
+	err = git.WorktreeAdd(context.Background(), repoPath, wtPath, "branch-1", "master", false)
 	if err != nil {
 		t.Fatalf("worktree add failed: %v", err)
 	}
@@ -680,7 +718,7 @@ func Test_gitWorktreeList_Includes_Worktree_Path(t *testing.T) {
 	repoPath := initRealGitRepo(t, dir)
 	wtPath := filepath.Join(dir, "my-worktree")
 
-	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "my-branch", "master")
+	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "my-branch", "master", false)
 	if err != nil {
 		t.Fatalf("worktree add failed: %v", err)
 	}
@@ -707,3 +745,205 @@ func Test_gitWorktreeList_Includes_Worktree_Path(t *testing.T) {
 		t.Errorf("worktree path %q not found in list: %v", wtPath, paths)
 	}
 }
+
+func Test_parseWorktreePorcelain_Handles_Format_Edge_Cases(t *testing.T) {
+	t.Parallel()
+
+	out := `worktree /repo
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/master
+
+worktree /wt/detached
+HEAD 2222222222222222222222222222222222222222
+detached
+
+worktree /wt/locked
+HEAD 3333333333333333333333333333333333333333
+branch refs/heads/feature/nested
+locked reason with spaces
+
+worktree /wt/locked-no-reason
+HEAD 4444444444444444444444444444444444444444
+branch refs/heads/plain
+locked
+
+worktree /wt/gone
+HEAD 5555555555555555555555555555555555555555
+branch refs/heads/gone
+prunable gitdir file points to non-existent location
+
+`
+
+	got := parseWorktreePorcelain(out)
+
+	want := []WorktreeEntry{
+		{Path: "/repo", HEAD: "1111111111111111111111111111111111111111", Branch: "master"},
+		{Path: "/wt/detached", HEAD: "2222222222222222222222222222222222222222", Detached: true},
+		{
+			Path: "/wt/locked", HEAD: "3333333333333333333333333333333333333333", Branch: "feature/nested",
+			Locked: true, LockReason: "reason with spaces",
+		},
+		{Path: "/wt/locked-no-reason", HEAD: "4444444444444444444444444444444444444444", Branch: "plain", Locked: true},
+		{
+			Path: "/wt/gone", HEAD: "5555555555555555555555555555555555555555", Branch: "gone",
+			Prunable: true, PrunableReason: "gitdir file points to non-existent location",
+		},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(got), got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d:\ngot  %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func Test_parseWorktreePorcelain_Handles_Bare_Repository(t *testing.T) {
+	t.Parallel()
+
+	out := "worktree /repo.git\nbare\n\nworktree /wt/one\nHEAD abc\nbranch refs/heads/one\n"
+
+	got := parseWorktreePorcelain(out)
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(got), got)
+	}
+
+	if !got[0].Bare || got[0].Branch != "" || got[0].HEAD != "" {
+		t.Errorf("expected bare entry without HEAD/branch, got %+v", got[0])
+	}
+
+	if got[1].Bare || got[1].Branch != "one" {
+		t.Errorf("expected non-bare entry on branch one, got %+v", got[1])
+	}
+}
+
+func Test_parseWorktreePorcelain_Returns_Empty_For_Empty_Output(t *testing.T) {
+	t.Parallel()
+
+	got := parseWorktreePorcelain("")
+
+	if len(got) != 0 {
+		t.Errorf("expected no entries, got %+v", got)
+	}
+}
+
+func Test_gitWorktreeListDetailed_Reports_Branch_And_Lock(t *testing.T) {
+	t.Parallel()
+
+	git := newTestGit()
+
+	dir := t.TempDir()
+	repoPath := initRealGitRepo(t, dir)
+	wtPath := filepath.Join(dir, "locked-wt")
+
+	err := git.WorktreeAdd(context.Background(), repoPath, wtPath, "locked-branch", "master", false)
+	if err != nil {
+		t.Fatalf("worktree add failed: %v", err)
+	}
+
+	out, err := testGitCmd("-C", repoPath, "worktree", "lock", "--reason", "keep me", wtPath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("worktree lock failed: %v\n%s", err, out)
+	}
+
+	entries, err := git.WorktreeListDetailed(context.Background(), repoPath)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+
+	if entries[0].Branch != "master" {
+		t.Errorf("expected main worktree on master, got %q", entries[0].Branch)
+	}
+
+	locked := entries[1]
+	if locked.Branch != "locked-branch" || !locked.Locked || locked.LockReason != "keep me" {
+		t.Errorf("unexpected locked entry: %+v", locked)
+	}
+
+	if len(locked.HEAD) != 40 {
+		t.Errorf("expected full HEAD sha, got %q", locked.HEAD)
+	}
+}
+
+func Test_gitDefaultBranch_Prefers_Origin_HEAD(t *testing.T) {
+	t.Parallel()
+
+	git := newTestGit()
+	repoPath := initRealGitRepo(t, t.TempDir())
+
+	for _, args := range [][]string{
+		{"branch", "trunk"},
+		{"symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/trunk"},
+	} {
+		out, err := testGitCmd(append([]string{"-C", repoPath}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	branch, err := git.DefaultBranch(context.Background(), repoPath)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if branch != "trunk" {
+		t.Errorf("expected trunk, got %q", branch)
+	}
+}
+
+func Test_gitDefaultBranch_Returns_Error_When_No_Candidate_Exists(t *testing.T) {
+	t.Parallel()
+
+	git := newTestGit()
+	repoPath := initRealGitRepo(t, t.TempDir())
+
+	out, err := testGitCmd("-C", repoPath, "branch", "-m", "master", "work").CombinedOutput()
+	if err != nil {
+		t.Fatalf("git branch -m failed: %v\n%s", err, out)
+	}
+
+	_, err = git.DefaultBranch(context.Background(), repoPath)
+	if !errors.Is(err, ErrGitDefaultBranch) {
+		t.Errorf("expected ErrGitDefaultBranch, got: %v", err)
+	}
+}
+
+func Test_gitPush_Updates_Branch_On_Remote(t *testing.T) {
+	t.Parallel()
+
+	git := newTestGit()
+	repoPath := initRealGitRepo(t, t.TempDir())
+	remotePath := t.TempDir()
+
+	for _, cmd := range [][]string{
+		{"init", "--bare", "--quiet", remotePath},
+		{"-C", repoPath, "remote", "add", "origin", remotePath},
+	} {
+		out, err := testGitCmd(cmd...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", cmd, err, out)
+		}
+	}
+
+	err := git.Push(context.Background(), repoPath, "origin", "master")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if got, want := gitOutput(t, remotePath, "rev-parse", "master"), gitOutput(t, repoPath, "rev-parse", "master"); got != want {
+		t.Errorf("remote master = %s, want %s", got, want)
+	}
+
+	err = git.Push(context.Background(), repoPath, "missing", "master")
+	if !errors.Is(err, ErrGitPush) {
+		t.Errorf("expected ErrGitPush for a missing remote, got: %v", err)
+	}
+}
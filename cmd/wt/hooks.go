@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -76,9 +78,17 @@ func (p *prefixWriter) Write(data []byte) (int, error) {
 	return totalWritten, nil
 }
 
+// hookNames are the hooks wt runs from the hooks directory (.wt/hooks, or
+// hooks_dir; see resolveHooksDir).
+var hookNames = []string{"pre-create", "post-create", "pre-delete"}
+
 // hookTimeout is the maximum time a hook can run before being killed.
 const hookTimeout = 5 * time.Minute
 
+// childKillDelay is how long a child process gets to exit after SIGTERM
+// before it is killed.
+const childKillDelay = 7 * time.Second
+
 // Hook errors.
 var (
 	ErrHookNotExecutable = errors.New("hook not executable")
@@ -90,29 +100,63 @@ var (
 type HookRunner struct {
 	fsys     fs.FS
 	repoRoot string
+	hooksDir string
 	baseEnv  map[string]string // inherited environment from Run()
 	stdout   io.Writer
 	stderr   io.Writer
 }
 
-// NewHookRunner creates a hook runner.
+// NewHookRunner creates a hook runner for the hooks in hooksDir. With an
+// empty hooksDir no hook files run (inline --post-create-cmd still does).
 // baseEnv should be the env map passed to Run() - we don't call os.Environ().
-func NewHookRunner(fsys fs.FS, repoRoot string, baseEnv map[string]string, stdout, stderr io.Writer) *HookRunner {
+func NewHookRunner(fsys fs.FS, repoRoot, hooksDir string, baseEnv map[string]string, stdout, stderr io.Writer) *HookRunner {
 	return &HookRunner{
 		fsys:     fsys,
 		repoRoot: repoRoot,
+		hooksDir: hooksDir,
 		baseEnv:  baseEnv,
 		stdout:   stdout,
 		stderr:   stderr,
 	}
 }
 
+// RunPreCreate executes the pre-create hook if it exists, before the
+// worktree is added. The worktree does not exist yet, so the hook runs in
+// the repository root and only gets WT_NAME, WT_BASE_BRANCH, WT_REPO_ROOT
+// and WT_SOURCE (the directory create runs from).
+func (h *HookRunner) RunPreCreate(ctx context.Context, name, baseBranch, sourceDir string) error {
+	preEnv := map[string]string{
+		"WT_NAME":        name,
+		"WT_BASE_BRANCH": baseBranch,
+		"WT_REPO_ROOT":   h.repoRoot,
+		"WT_SOURCE":      sourceDir,
+	}
+
+	return runHook(ctx, h.fsys, h.hooksDir, "pre-create", h.baseEnv, preEnv, h.repoRoot, h.stdout, h.stderr)
+}
+
 // RunPostCreate executes the post-create hook if it exists.
 // The hook runs with working directory set to wtPath.
-func (h *HookRunner) RunPostCreate(ctx context.Context, info *WorktreeInfo, wtPath string) error {
+// Returns whether a hook ran, so callers can tell "no hook" from "hook ran".
+func (h *HookRunner) RunPostCreate(ctx context.Context, info *WorktreeInfo, wtPath string) (bool, error) {
 	wtEnv := hookEnv(info, wtPath, h.repoRoot)
+	exists := hookExists(h.fsys, h.hooksDir, "post-create")
+
+	err := runHook(ctx, h.fsys, h.hooksDir, "post-create", h.baseEnv, wtEnv, wtPath, h.stdout, h.stderr)
+	if err != nil {
+		return false, err
+	}
 
-	return runHook(ctx, h.fsys, h.repoRoot, "post-create", h.baseEnv, wtEnv, wtPath, h.stdout, h.stderr)
+	return exists, nil
+}
+
+// RunPostCreateCmd runs command through /bin/sh as an inline post-create
+// hook. It gets the same working directory, environment and timeout as
+// hook files; output is prefixed with "hook(post-create-cmd): ".
+func (h *HookRunner) RunPostCreateCmd(ctx context.Context, info *WorktreeInfo, wtPath, command string) error {
+	wtEnv := hookEnv(info, wtPath, h.repoRoot)
+
+	return execHook(ctx, "post-create-cmd", []string{"/bin/sh", "-c", command}, h.baseEnv, wtEnv, wtPath, h.stdout, h.stderr)
 }
 
 // RunPreDelete executes the pre-delete hook if it exists.
@@ -120,7 +164,7 @@ func (h *HookRunner) RunPostCreate(ctx context.Context, info *WorktreeInfo, wtPa
 func (h *HookRunner) RunPreDelete(ctx context.Context, info *WorktreeInfo, wtPath string) error {
 	wtEnv := hookEnv(info, wtPath, h.repoRoot)
 
-	return runHook(ctx, h.fsys, h.repoRoot, "pre-delete", h.baseEnv, wtEnv, wtPath, h.stdout, h.stderr)
+	return runHook(ctx, h.fsys, h.hooksDir, "pre-delete", h.baseEnv, wtEnv, wtPath, h.stdout, h.stderr)
 }
 
 // hookEnv creates the WT_* environment variables available to hooks.
@@ -136,23 +180,43 @@ func hookEnv(info *WorktreeInfo, wtPath, repoRoot string) map[string]string {
 	}
 }
 
-// runHook executes a hook script if it exists.
-// hookName is "post-create" or "pre-delete".
+// hookExists reports whether <hooksDir>/<hookName> exists and is executable,
+// i.e. whether runHook would execute it.
+func hookExists(fsys fs.FS, hooksDir, hookName string) bool {
+	if hooksDir == "" {
+		return false
+	}
+
+	info, err := fsys.Stat(filepath.Join(hooksDir, hookName))
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&0o111 != 0
+}
+
+// runHook executes <hooksDir>/<hookName> if it exists.
+// hookName is one of hookNames.
 // baseEnv is the inherited environment (passed from Run()'s env parameter).
 // wtEnv contains the WT_* variables to add.
-// wtPath is the worktree path, used as the hook's working directory.
-// Returns nil if hook doesn't exist.
+// wtPath is the hook's working directory (the worktree, or the repository
+// root for pre-create).
+// Returns nil if hook doesn't exist or hooksDir is "" (--no-hooks).
 // Returns error if hook exists but is not executable, or if execution fails.
 func runHook(
 	ctx context.Context,
 	fsys fs.FS,
-	repoRoot string,
+	hooksDir string,
 	hookName string,
 	baseEnv, wtEnv map[string]string,
 	wtPath string,
 	stdout, stderr io.Writer,
 ) error {
-	hookPath := filepath.Join(repoRoot, ".wt", "hooks", hookName)
+	if hooksDir == "" {
+		return nil
+	}
+
+	hookPath := filepath.Join(hooksDir, hookName)
 
 	// Check if hook exists
 	info, statErr := fsys.Stat(hookPath)
@@ -169,45 +233,90 @@ func runHook(
 		return fmt.Errorf("%w: %s (fix with: chmod +x %s)", ErrHookNotExecutable, hookPath, hookPath)
 	}
 
+	return execHook(ctx, hookName, []string{hookPath}, baseEnv, wtEnv, wtPath, stdout, stderr)
+}
+
+// execHook runs argv in wtPath with the hook environment, timeout and
+// signal handling shared by hook files and inline hook commands.
+func execHook(
+	ctx context.Context,
+	hookName string,
+	argv []string,
+	baseEnv, wtEnv map[string]string,
+	wtPath string,
+	stdout, stderr io.Writer,
+) error {
 	// Build command with timeout
 	timeoutCtx, cancel := context.WithTimeout(ctx, hookTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(timeoutCtx, hookPath)
-	cmd.Dir = wtPath
+	cmd := newChildCmd(timeoutCtx, wtPath, argv, baseEnv, wtEnv)
 
 	// Prefix hook output so it's clear where it comes from
 	prefix := fmt.Sprintf("hook(%s): ", hookName)
 	cmd.Stdout = newPrefixWriter(stdout, prefix)
 	cmd.Stderr = newPrefixWriter(stderr, prefix)
 
-	// Send SIGTERM instead of SIGKILL on context cancellation.
-	// This gives hooks a chance to clean up gracefully.
-	// WaitDelay ensures we SIGKILL after 3s if the hook ignores the signal.
+	runErr := cmd.Run()
+	if runErr != nil {
+		if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w: %s after 5 minutes", ErrHookTimeout, hookName)
+		}
+
+		return fmt.Errorf("%w: %s: %w", ErrHookFailed, hookName, runErr)
+	}
+
+	return nil
+}
+
+// newChildCmd returns a command running argv in dir with the variables of
+// envs (later maps win), as hooks, exec, plugins and open run their children.
+// A bare command name is looked up in the PATH of envs, not of this process.
+// On ctx cancellation the child gets SIGTERM, so it can clean up, and is
+// killed if it is still running childKillDelay later.
+func newChildCmd(ctx context.Context, dir string, argv []string, envs ...map[string]string) *exec.Cmd {
+	merged := map[string]string{}
+
+	for _, env := range envs {
+		maps.Copy(merged, env)
+	}
+
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = dir
+
+	if path, ok := merged["PATH"]; ok && !strings.Contains(argv[0], "/") {
+		cmd.Path, cmd.Err = lookPathIn(argv[0], path)
+	}
+
 	cmd.Cancel = func() error {
 		return cmd.Process.Signal(syscall.SIGTERM)
 	}
-	cmd.WaitDelay = 7 * time.Second
+	cmd.WaitDelay = childKillDelay
 
-	// Build environment from baseEnv + wtEnv
-	cmd.Env = make([]string, 0, len(baseEnv)+len(wtEnv))
+	cmd.Env = make([]string, 0, len(merged))
 
-	for k, v := range baseEnv {
+	for k, v := range merged {
 		cmd.Env = append(cmd.Env, k+"="+v)
 	}
 
-	for k, v := range wtEnv {
-		cmd.Env = append(cmd.Env, k+"="+v)
-	}
+	return cmd
+}
 
-	runErr := cmd.Run()
-	if runErr != nil {
-		if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
-			return fmt.Errorf("%w: %s after 5 minutes", ErrHookTimeout, hookName)
+// lookPathIn finds the executable file name in the directories of path (a
+// PATH value), like exec.LookPath does with this process's PATH.
+func lookPathIn(name, path string) (string, error) {
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			dir = "."
 		}
 
-		return fmt.Errorf("%w: %s: %w", ErrHookFailed, hookName, runErr)
+		candidate := filepath.Join(dir, name)
+
+		info, err := os.Stat(candidate)
+		if err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0 {
+			return candidate, nil
+		}
 	}
 
-	return nil
+	return name, &exec.Error{Name: name, Err: exec.ErrNotFound}
 }
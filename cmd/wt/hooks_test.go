@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -60,7 +61,7 @@ func Test_runHook_Skips_When_Hook_Not_Present(t *testing.T) {
 	err := runHook(
 		context.Background(),
 		fsys,
-		dir, // repoRoot with no .wt/hooks
+		filepath.Join(dir, ".wt", "hooks"), // does not exist
 		"post-create",
 		map[string]string{},
 		map[string]string{},
@@ -103,7 +104,7 @@ func Test_runHook_Returns_Error_When_Hook_Not_Executable(t *testing.T) {
 	err = runHook(
 		context.Background(),
 		fsys,
-		dir,
+		filepath.Join(dir, ".wt", "hooks"),
 		"post-create",
 		map[string]string{},
 		map[string]string{},
@@ -151,7 +152,7 @@ func Test_runHook_Executes_Hook_Successfully(t *testing.T) {
 	err = runHook(
 		context.Background(),
 		fsys,
-		dir,
+		filepath.Join(dir, ".wt", "hooks"),
 		"post-create",
 		map[string]string{"PATH": os.Getenv("PATH")},
 		map[string]string{},
@@ -199,7 +200,7 @@ func Test_runHook_Returns_Error_When_Hook_Fails(t *testing.T) {
 	err = runHook(
 		context.Background(),
 		fsys,
-		dir,
+		filepath.Join(dir, ".wt", "hooks"),
 		"post-create",
 		map[string]string{"PATH": os.Getenv("PATH")},
 		map[string]string{},
@@ -264,7 +265,7 @@ echo "REPO_ROOT=$WT_REPO_ROOT"
 	err = runHook(
 		context.Background(),
 		fsys,
-		dir,
+		filepath.Join(dir, ".wt", "hooks"),
 		"post-create",
 		map[string]string{"PATH": os.Getenv("PATH")},
 		wtEnv,
@@ -326,7 +327,7 @@ func Test_runHook_Uses_WtPath_As_Working_Directory(t *testing.T) {
 	err = runHook(
 		context.Background(),
 		fsys,
-		repoDir,
+		filepath.Join(repoDir, ".wt", "hooks"),
 		"post-create",
 		map[string]string{"PATH": os.Getenv("PATH")},
 		map[string]string{},
@@ -419,15 +420,19 @@ func Test_HookRunner_RunPostCreate_Calls_Hook(t *testing.T) {
 
 	var stdout, stderr bytes.Buffer
 
-	runner := NewHookRunner(fsys, dir, map[string]string{"PATH": os.Getenv("PATH")}, &stdout, &stderr)
+	runner := NewHookRunner(fsys, dir, filepath.Join(dir, ".wt", "hooks"), map[string]string{"PATH": os.Getenv("PATH")}, &stdout, &stderr)
 
 	info := &WorktreeInfo{Name: "test", AgentID: "test-id", ID: 1, BaseBranch: "master"}
 
-	err = runner.RunPostCreate(context.Background(), info, dir)
+	ran, err := runner.RunPostCreate(context.Background(), info, dir)
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
 
+	if !ran {
+		t.Error("expected RunPostCreate to report that the hook ran")
+	}
+
 	if !strings.Contains(stdout.String(), "hook(post-create): post-create-ran") {
 		t.Errorf("expected stdout to contain 'hook(post-create): post-create-ran', got: %q", stdout.String())
 	}
@@ -461,7 +466,7 @@ func Test_HookRunner_RunPreDelete_Calls_Hook(t *testing.T) {
 
 	var stdout, stderr bytes.Buffer
 
-	runner := NewHookRunner(fsys, dir, map[string]string{"PATH": os.Getenv("PATH")}, &stdout, &stderr)
+	runner := NewHookRunner(fsys, dir, filepath.Join(dir, ".wt", "hooks"), map[string]string{"PATH": os.Getenv("PATH")}, &stdout, &stderr)
 
 	info := &WorktreeInfo{Name: "test", AgentID: "test-id", ID: 1, BaseBranch: "master"}
 
@@ -475,6 +480,42 @@ func Test_HookRunner_RunPreDelete_Calls_Hook(t *testing.T) {
 	}
 }
 
+func Test_HookRunner_RunPreCreate_Runs_In_Repo_Root(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("skipping shell script test on Windows")
+	}
+
+	dir := t.TempDir()
+	fsys := fs.NewReal()
+
+	hookDir := filepath.Join(dir, ".wt", "hooks")
+
+	err := os.MkdirAll(hookDir, 0o750)
+	if err != nil {
+		t.Fatalf("failed to create hook dir: %v", err)
+	}
+
+	writeExecutableFile(t, filepath.Join(hookDir, "pre-create"), []byte("#!/bin/bash\necho \"$PWD $WT_NAME $WT_BASE_BRANCH $WT_SOURCE ${WT_PATH:-unset}\""))
+
+	var stdout, stderr bytes.Buffer
+
+	runner := NewHookRunner(fsys, dir, filepath.Join(dir, ".wt", "hooks"), map[string]string{"PATH": os.Getenv("PATH")}, &stdout, &stderr)
+
+	err = runner.RunPreCreate(context.Background(), "test", "master", "/src")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expectedDir, _ := filepath.EvalSymlinks(dir)
+	want := "hook(pre-create): " + expectedDir + " test master /src unset"
+
+	if !strings.Contains(stdout.String(), want) {
+		t.Errorf("expected stdout to contain %q, got: %q", want, stdout.String())
+	}
+}
+
 // E2E tests for hooks with actual CLI commands
 
 func Test_E2E_PostCreate_Hook_Receives_All_Environment_Variables(t *testing.T) {
@@ -543,6 +584,64 @@ EOF
 	}
 }
 
+func Test_E2E_PreCreate_Hook_Receives_Environment_And_Can_Abort(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("skipping shell script test on Windows")
+	}
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	// The worktree does not exist yet, so write the env to the repo root
+	hookScript := `#!/bin/bash
+cat > "$WT_REPO_ROOT/pre-create-env.txt" << EOF
+WT_NAME=$WT_NAME
+WT_BASE_BRANCH=$WT_BASE_BRANCH
+WT_REPO_ROOT=$WT_REPO_ROOT
+WT_SOURCE=$WT_SOURCE
+WT_PATH=$WT_PATH
+PWD=$PWD
+EOF
+[ "$WT_NAME" != "vetoed" ] || { echo "name not allowed" >&2; exit 1; }
+`
+	c.WriteExecutable(".wt/hooks/pre-create", hookScript)
+
+	c.MustRun("--config", "config.json", "create", "--name", "allowed")
+
+	envContent := c.ReadFile("pre-create-env.txt")
+	expectedRepoRoot, _ := filepath.EvalSymlinks(c.Dir)
+
+	AssertContains(t, envContent, "WT_NAME=allowed")
+	AssertContains(t, envContent, "WT_BASE_BRANCH=master")
+	AssertContains(t, envContent, "WT_REPO_ROOT="+expectedRepoRoot)
+	AssertContains(t, envContent, "WT_SOURCE="+c.Dir)
+	AssertContains(t, envContent, "WT_PATH=\n")
+	AssertContains(t, envContent, "PWD="+expectedRepoRoot)
+
+	_, stderr, code := c.Run("--config", "config.json", "create", "--name", "vetoed")
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	AssertContains(t, stderr, "hook(pre-create): name not allowed")
+	AssertContains(t, stderr, "pre-create hook aborted creation")
+
+	if c.FileExists("worktrees/vetoed") {
+		t.Error("worktree directory should not exist")
+	}
+
+	if slices.Contains(listBranches(t, c.Dir), "vetoed") {
+		t.Error("branch should not exist")
+	}
+
+	// --no-hooks skips it
+	c.MustRun("--config", "config.json", "create", "--name", "vetoed", "--no-hooks")
+}
+
 func Test_E2E_PreDelete_Hook_Receives_All_Environment_Variables(t *testing.T) {
 	t.Parallel()
 
@@ -978,3 +1077,243 @@ echo "survived" > "$WT_REPO_ROOT/hook-survived.txt"
 		t.Error("hook should have been killed before writing hook-survived.txt")
 	}
 }
+
+func Test_E2E_Interrupted_Create_Rolls_Back_Worktree_And_Branch(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("skipping shell script test on Windows")
+	}
+
+	hooks := map[string]string{
+		// Killed by SIGTERM, so the hook fails
+		"killed": `#!/bin/bash
+echo "started" > "$WT_REPO_ROOT/hook-started.txt"
+exec sleep 30
+`,
+		// Exits cleanly on SIGTERM, so the hook itself succeeds
+		"clean-exit": `#!/bin/bash
+echo "started" > "$WT_REPO_ROOT/hook-started.txt"
+trap 'exit 0' TERM INT
+while true; do
+    sleep 0.1
+done
+`,
+	}
+
+	for name, hookScript := range hooks {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := NewCLITester(t)
+			initRealGitRepo(t, c.Dir)
+
+			c.WriteFile("config.json", `{"base": "worktrees"}`)
+			c.WriteExecutable(".wt/hooks/post-create", hookScript)
+
+			deadline := time.Now().Add(5 * time.Second)
+
+			sigCh := make(chan os.Signal, 1)
+			done := c.RunWithSignal(sigCh, "--config", "config.json", "create", "--name", "interrupted")
+
+			for !c.FileExists("hook-started.txt") {
+				if time.Now().After(deadline) {
+					t.Fatal("timeout waiting for hook to start")
+				}
+
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			sigCh <- os.Interrupt
+
+			select {
+			case code := <-done:
+				if code != 130 {
+					t.Errorf("expected exit code 130, got %d", code)
+				}
+			case <-time.After(time.Until(deadline)):
+				t.Fatal("timeout waiting for command to finish after signal")
+			}
+
+			if c.FileExists("worktrees/interrupted") {
+				t.Error("worktree directory should be rolled back")
+			}
+
+			if slices.Contains(listBranches(t, c.Dir), "interrupted") {
+				t.Error("branch should be rolled back")
+			}
+
+			out, err := testGitCmd("-C", c.Dir, "worktree", "list", "--porcelain").CombinedOutput()
+			if err != nil {
+				t.Fatalf("git worktree list failed: %v\n%s", err, out)
+			}
+
+			AssertNotContains(t, string(out), "interrupted")
+		})
+	}
+}
+
+func Test_E2E_Cleanup_Timeout_Flag_Shortens_Wait_After_Interrupt(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == windowsOS {
+		t.Skip("skipping shell script test on Windows")
+	}
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+	c.MustRun("--config", "config.json", "create", "--name", "to-delete")
+
+	// Hook that takes 2s to clean up after the signal
+	hookScript := `#!/bin/bash
+echo "started" > "$WT_REPO_ROOT/hook-started.txt"
+
+cleanup() {
+    sleep 2
+    echo "done" > "$WT_REPO_ROOT/hook-done.txt"
+    exit 1
+}
+
+trap cleanup TERM INT
+
+while true; do
+    sleep 0.1
+done
+`
+	c.WriteExecutable(".wt/hooks/pre-delete", hookScript)
+
+	deadline := time.Now().Add(10 * time.Second)
+
+	sigCh := make(chan os.Signal, 1)
+	done := c.RunWithSignal(sigCh, "--cleanup-timeout", "200ms", "--config", "config.json", "remove", "to-delete", "--force")
+
+	for !c.FileExists("hook-started.txt") {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for hook to start")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	interrupted := time.Now()
+	sigCh <- os.Interrupt
+
+	select {
+	case code := <-done:
+		if code != 130 {
+			t.Errorf("expected exit code 130, got %d", code)
+		}
+
+		if waited := time.Since(interrupted); waited > time.Second {
+			t.Errorf("expected exit after about 200ms, waited %v", waited)
+		}
+	case <-time.After(time.Until(deadline)):
+		t.Fatal("timeout waiting for command to exit after signal")
+	}
+
+	// Let the hook finish before the temp dir is removed
+	for !c.FileExists("hook-done.txt") {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for hook to finish")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+}
+
+func Test_Hooks_Run_From_Configured_Hooks_Dir(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees", "hooks_dir": "tools/wt-hooks"}`)
+	c.WriteExecutable("tools/wt-hooks/post-create", "#!/bin/bash\necho shared > \"$WT_PATH/shared-hook.txt\"\n")
+	c.WriteExecutable("tools/wt-hooks/pre-delete", "#!/bin/bash\necho \"$WT_NAME\" > \"$WT_REPO_ROOT/pre-delete.txt\"\n")
+
+	// The configured directory wins; .wt/hooks is not consulted at all
+	c.WriteExecutable(".wt/hooks/post-create", "#!/bin/bash\necho default > \"$WT_PATH/default-hook.txt\"\n")
+
+	c.MustRun("--config", "config.json", "create", "--name", "shared")
+
+	if !c.FileExists("worktrees/shared/shared-hook.txt") {
+		t.Error("post-create hook from hooks_dir did not run")
+	}
+
+	if c.FileExists("worktrees/shared/default-hook.txt") {
+		t.Error("post-create hook from .wt/hooks should not run when hooks_dir is set")
+	}
+
+	c.MustRun("--config", "config.json", "remove", "shared", "--force")
+
+	if got := strings.TrimSpace(c.ReadFile("pre-delete.txt")); got != "shared" {
+		t.Errorf("expected pre-delete hook from hooks_dir to run, got %q", got)
+	}
+}
+
+func Test_Hooks_Skipped_When_Absolute_Hooks_Dir_Is_Missing(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	missing := filepath.Join(t.TempDir(), "no-such-dir")
+
+	c.WriteFile("config.json", `{"base": "worktrees", "hooks_dir": "`+missing+`"}`)
+	c.WriteExecutable(".wt/hooks/post-create", "#!/bin/bash\nexit 1\n")
+
+	stdout := c.MustRun("--config", "config.json", "create", "--name", "quiet-hooks")
+	AssertContains(t, stdout, "Created worktree:")
+
+	stdout = c.MustRun("--config", "config.json", "create", "--name", "planned", "--dry-run")
+	AssertContains(t, stdout, "hook:        no")
+}
+
+func Test_Global_No_Hooks_Skips_Failing_Hooks(t *testing.T) {
+	t.Parallel()
+
+	c := NewCLITester(t)
+	initRealGitRepo(t, c.Dir)
+
+	c.WriteFile("config.json", `{"base": "worktrees"}`)
+
+	for _, hook := range hookNames {
+		c.WriteExecutable(".wt/hooks/"+hook, "#!/bin/bash\ntouch \"$WT_REPO_ROOT/"+hook+"-ran\"\nexit 1\n")
+	}
+
+	stdout := c.MustRun("--no-hooks", "--config", "config.json", "create", "--name", "plain", "--json")
+	AssertContains(t, stdout, `"hook_skipped_reason": "no_hooks_flag"`)
+
+	c.MustRun("--no-hooks", "--config", "config.json", "create", "--name", "second")
+	c.MustRun("--no-hooks", "--config", "config.json", "remove", "plain", "--force")
+
+	wtPath := filepath.Join(c.Dir, "worktrees", "second")
+	gitCommitInDir(t, wtPath, "second.txt", "second", "Second work")
+	NewCLITesterAt(t, wtPath).MustRun("--no-hooks", "--config", filepath.Join(c.Dir, "config.json"), "merge")
+
+	for _, hook := range hookNames {
+		if c.FileExists(hook + "-ran") {
+			t.Errorf("%s hook ran despite --no-hooks", hook)
+		}
+	}
+
+	if c.FileExists("worktrees/plain") || c.FileExists("worktrees/second") {
+		t.Error("expected remove and merge to remove their worktrees")
+	}
+
+	// Rollback still happens for a failing --post-create-cmd
+	_, stderr, code := c.Run("--no-hooks", "--config", "config.json", "create", "--name", "rolled-back", "--post-create-cmd", "exit 1")
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d\nstderr: %s", code, stderr)
+	}
+
+	if c.FileExists("worktrees/rolled-back") {
+		t.Error("failed create was not rolled back under --no-hooks")
+	}
+
+	AssertContains(t, c.MustRun("--help"), "--no-hooks")
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors for ls --filter expressions.
+var (
+	errInvalidFilter       = errors.New("invalid filter")
+	errUnknownFilterField  = errors.New("unknown filter field")
+	errUnsupportedFilterOp = errors.New("unsupported filter operator")
+)
+
+// Filter operators, longest first so "<=" is matched before "<".
+var filterOps = []string{"!=", "<=", ">=", "!~", "=", "<", ">", "~"}
+
+// filterKind is the type of value a filter field compares.
+type filterKind int
+
+const (
+	filterString filterKind = iota
+	filterInt
+	filterBool
+	filterAge
+)
+
+// filterFields maps field names to their kind. created and age both compare
+// the worktree's age, so created<24h means "created in the last 24 hours".
+var filterFields = map[string]filterKind{
+	"name":        filterString,
+	"agent_id":    filterString,
+	"path":        filterString,
+	"branch":      filterString,
+	"base_branch": filterString,
+	"state":       filterString,
+	"id":          filterInt,
+	"is_current":  filterBool,
+	"locked":      filterBool,
+	"prunable":    filterBool,
+	"managed":     filterBool,
+	"created":     filterAge,
+	"age":         filterAge,
+}
+
+// labelFilterPrefix starts a filter field that compares a label's value,
+// e.g. label.ticket=JIRA-123.
+const labelFilterPrefix = "label."
+
+// listFilter is a parsed ls --filter predicate: <field><op><value>.
+type listFilter struct {
+	field string
+	op    string
+	kind  filterKind
+	value string
+
+	// Parsed value for non-string kinds
+	num int
+	dur time.Duration
+	b   bool
+}
+
+// parseListFilter parses expr into a listFilter, validating the field, the
+// operator for that field's kind, and the value.
+func parseListFilter(expr string) (listFilter, error) {
+	end := strings.IndexAny(expr, "=!<>~")
+	if end <= 0 {
+		return listFilter{}, fmt.Errorf("%w %q: expected <field><op><value>, e.g. id>5", errInvalidFilter, expr)
+	}
+
+	f := listFilter{field: expr[:end]}
+
+	kind, ok := filterFields[f.field]
+	if key, isLabel := strings.CutPrefix(f.field, labelFilterPrefix); isLabel && labelKeyPattern.MatchString(key) {
+		kind, ok = filterString, true
+	}
+
+	if !ok {
+		return listFilter{}, fmt.Errorf("%w %q in %q (valid: %s)", errUnknownFilterField, f.field, expr, validFilterFields())
+	}
+
+	f.kind = kind
+
+	for _, op := range filterOps {
+		if strings.HasPrefix(expr[end:], op) {
+			f.op = op
+
+			break
+		}
+	}
+
+	if f.op == "" {
+		return listFilter{}, fmt.Errorf("%w %q: expected one of %s", errInvalidFilter, expr, strings.Join(filterOps, " "))
+	}
+
+	f.value = expr[end+len(f.op):]
+
+	if !f.opAllowed() {
+		return listFilter{}, fmt.Errorf("%w %q for field %s", errUnsupportedFilterOp, f.op, f.field)
+	}
+
+	var err error
+
+	switch f.kind {
+	case filterString:
+	case filterInt:
+		f.num, err = strconv.Atoi(f.value)
+	case filterBool:
+		f.b, err = strconv.ParseBool(f.value)
+	case filterAge:
+		f.dur, err = parseFilterDuration(f.value)
+	}
+
+	if err != nil {
+		return listFilter{}, fmt.Errorf("%w %q: bad value for %s: %w", errInvalidFilter, expr, f.field, err)
+	}
+
+	return f, nil
+}
+
+// opAllowed reports whether the filter's operator applies to its field kind:
+// ~ and !~ (substring) only for strings, ordering only for ids and ages.
+func (f listFilter) opAllowed() bool {
+	switch f.op {
+	case "=", "!=":
+		return true
+	case "~", "!~":
+		return f.kind == filterString
+	default:
+		return f.kind == filterInt || f.kind == filterAge
+	}
+}
+
+// parseFilterDuration parses a Go duration, plus a "d" suffix for days.
+func parseFilterDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("parsing days: %w", err)
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("parsing duration: %w", err)
+	}
+
+	return d, nil
+}
+
+// match reports whether row satisfies the filter. now is used to compute
+// the age of the worktree.
+func (f listFilter) match(row *jsonWorktree, now time.Time) bool {
+	switch f.kind {
+	case filterInt:
+		return compareOrdered(row.ID, f.op, f.num)
+	case filterAge:
+		return compareOrdered(now.Sub(row.Created), f.op, f.dur)
+	case filterBool:
+		matches := f.boolValue(row) == f.b
+		if f.op == "!=" {
+			return !matches
+		}
+
+		return matches
+	default:
+		value := f.stringValue(row)
+
+		switch f.op {
+		case "~":
+			return strings.Contains(value, f.value)
+		case "!~":
+			return !strings.Contains(value, f.value)
+		case "!=":
+			return value != f.value
+		default:
+			return value == f.value
+		}
+	}
+}
+
+func (f listFilter) stringValue(row *jsonWorktree) string {
+	if key, ok := strings.CutPrefix(f.field, labelFilterPrefix); ok {
+		return row.Labels[key]
+	}
+
+	switch f.field {
+	case "agent_id":
+		return row.AgentID
+	case "path":
+		return row.Path
+	case "branch":
+		return row.Branch
+	case "base_branch":
+		return row.BaseBranch
+	case "state":
+		return row.State
+	default:
+		return row.Name
+	}
+}
+
+func (f listFilter) boolValue(row *jsonWorktree) bool {
+	switch f.field {
+	case "is_current":
+		return row.IsCurrent
+	case "locked":
+		return row.Locked
+	case "prunable":
+		return row.Prunable
+	default:
+		return row.Managed
+	}
+}
+
+func compareOrdered[T int | time.Duration](a T, op string, b T) bool {
+	switch op {
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	default:
+		return a == b
+	}
+}
+
+// matchListFilters reports whether row satisfies every filter (AND).
+func matchListFilters(filters []listFilter, row *jsonWorktree, now time.Time) bool {
+	for _, f := range filters {
+		if !f.match(row, now) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validFilterFields() string {
+	names := make([]string, 0, len(filterFields))
+	for name := range filterFields {
+		names = append(names, name)
+	}
+
+	names = append(names, labelFilterPrefix+"<key>")
+	slices.Sort(names)
+
+	return strings.Join(names, ", ")
+}
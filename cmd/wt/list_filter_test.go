@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_ListFilter_Predicates(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	row := jsonWorktree{
+		Name:       "swift-fox",
+		AgentID:    "agent-7",
+		ID:         7,
+		Path:       "/code/worktrees/swift-fox",
+		Branch:     "swift-fox",
+		BaseBranch: "develop",
+		Created:    now.Add(-3 * time.Hour),
+		Locked:     true,
+		Managed:    true,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"id=7", true},
+		{"id!=7", false},
+		{"id>5", true},
+		{"id>7", false},
+		{"id>=7", true},
+		{"id<7", false},
+		{"id<=7", true},
+		{"name=swift-fox", true},
+		{"name=swift", false},
+		{"name~fox", true},
+		{"name~bear", false},
+		{"name!~bear", true},
+		{"base_branch=develop", true},
+		{"base_branch!=develop", false},
+		{"agent_id=agent-7", true},
+		{"path~/worktrees/", true},
+		{"branch=swift-fox", true},
+		{"state=", true},
+		{"locked=true", true},
+		{"locked!=true", false},
+		{"is_current=false", true},
+		{"managed=1", true},
+		{"created<24h", true},
+		{"created<1h", false},
+		{"age>2h", true},
+		{"age>=1d", false},
+		{"age<90m", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := parseListFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("parseListFilter(%q): %v", tt.expr, err)
+			}
+
+			if got := f.match(&row, now); got != tt.want {
+				t.Errorf("match(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ListFilter_Rejects_Invalid_Expressions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr    string
+		wantErr error
+	}{
+		{"id", errInvalidFilter},
+		{"=5", errInvalidFilter},
+		{"name!foo", errInvalidFilter},
+		{"color=red", errUnknownFilterField},
+		{"name>foo", errUnsupportedFilterOp},
+		{"id~5", errUnsupportedFilterOp},
+		{"locked<true", errUnsupportedFilterOp},
+		{"id>five", errInvalidFilter},
+		{"locked=maybe", errInvalidFilter},
+		{"age<soon", errInvalidFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseListFilter(tt.expr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("parseListFilter(%q) error = %v, want %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ListFilter_Matches_All_Filters(t *testing.T) {
+	t.Parallel()
+
+	row := jsonWorktree{Name: "swift-fox", ID: 3}
+
+	var filters []listFilter
+
+	for _, expr := range []string{"id>1", "name~fox"} {
+		f, err := parseListFilter(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		filters = append(filters, f)
+	}
+
+	if !matchListFilters(filters, &row, time.Now()) {
+		t.Error("expected row to match both filters")
+	}
+
+	row.ID = 1
+	if matchListFilters(filters, &row, time.Now()) {
+		t.Error("expected row failing one filter not to match")
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Errors for ls --pick.
+var (
+	errPickRequiresTerminal = errors.New("--pick needs an interactive terminal on stdin; pass a name instead (e.g. wt switch <name>)")
+	errPickFormatConflict   = errors.New("cannot use --pick with --json, --jsonl or --format")
+	errPickNoWorktrees      = errors.New("no worktrees to pick from")
+	errPickNoSelection      = errors.New("no worktree selected")
+	errPickNoMatch          = errors.New("no worktree matches")
+	errPickAmbiguous        = errors.New("selection matches several worktrees")
+)
+
+// pickWorktree shows rows as a numbered menu on menu and reads one selection
+// line from stdin. It returns the selected row.
+func pickWorktree(stdin io.Reader, menu io.Writer, rows []jsonWorktree) (jsonWorktree, error) {
+	if len(rows) == 0 {
+		return jsonWorktree{}, errPickNoWorktrees
+	}
+
+	width := len(strconv.Itoa(len(rows)))
+
+	for i, row := range rows {
+		marker := " "
+		if row.IsCurrent {
+			marker = "*"
+		}
+
+		fprintf(menu, "%s %*d) %-15s %s\n", marker, width, i+1, row.Name, row.Path)
+	}
+
+	fprintf(menu, "Select worktree [1-%d, or part of a name]: ", len(rows))
+
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return jsonWorktree{}, fmt.Errorf("reading selection: %w", err)
+	}
+
+	return matchPick(rows, strings.TrimSpace(line))
+}
+
+// matchPick resolves a selection: a 1-based menu number, an exact name, or
+// a substring of exactly one name.
+func matchPick(rows []jsonWorktree, selection string) (jsonWorktree, error) {
+	if selection == "" {
+		return jsonWorktree{}, errPickNoSelection
+	}
+
+	if n, err := strconv.Atoi(selection); err == nil {
+		if n < 1 || n > len(rows) {
+			return jsonWorktree{}, fmt.Errorf("%w: %s (valid: 1-%d)", errPickNoMatch, selection, len(rows))
+		}
+
+		return rows[n-1], nil
+	}
+
+	var matches []jsonWorktree
+
+	for _, row := range rows {
+		if row.Name == selection {
+			return row, nil
+		}
+
+		if strings.Contains(row.Name, selection) {
+			matches = append(matches, row)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return jsonWorktree{}, fmt.Errorf("%w: %s", errPickNoMatch, selection)
+	case 1:
+		return matches[0], nil
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, row := range matches {
+		names = append(names, row.Name)
+	}
+
+	return jsonWorktree{}, fmt.Errorf("%w: %s (%s)", errPickAmbiguous, selection, strings.Join(names, ", "))
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_matchPick_Resolves_Number_Name_And_Unique_Substring(t *testing.T) {
+	t.Parallel()
+
+	rows := []jsonWorktree{{Name: "fix-login"}, {Name: "fix-logout"}, {Name: "docs"}}
+
+	for selection, want := range map[string]string{"3": "docs", "fix-login": "fix-login", "out": "fix-logout", "doc": "docs"} {
+		got, err := matchPick(rows, selection)
+		if err != nil || got.Name != want {
+			t.Errorf("matchPick(%q) = %q, %v; want %q", selection, got.Name, err, want)
+		}
+	}
+
+	errCases := map[string]error{
+		"":      errPickNoSelection,
+		"0":     errPickNoMatch,
+		"4":     errPickNoMatch,
+		"nope":  errPickNoMatch,
+		"fix-l": errPickAmbiguous,
+	}
+
+	for selection, wantErr := range errCases {
+		_, err := matchPick(rows, selection)
+		if !errors.Is(err, wantErr) {
+			t.Errorf("matchPick(%q) error = %v, want %v", selection, err, wantErr)
+		}
+	}
+}
@@ -5,10 +5,32 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
 )
 
-// ErrNameGenerationFailed is returned when a unique agent_id cannot be generated.
-var ErrNameGenerationFailed = errors.New("generating unique name after 10 attempts (too many worktrees? use --name to specify)")
+// Name errors.
+var (
+	ErrNameGenerationFailed = errors.New("generating unique name after 10 attempts (too many worktrees? use --name to specify)")
+	errInvalidName          = errors.New("invalid name (must not be empty, . or .., or contain / or \\)")
+	errInvalidSlug          = errors.New("name is not usable as a worktree and branch name after slugification")
+	errInvalidSlugSeparator = errors.New("invalid name_slug separator (valid: -, _, .)")
+	errUnknownNamingScheme  = errors.New("unknown naming scheme (valid: adjective-animal, uuid, numeric, or a template like agent-<n>)")
+)
+
+// Naming schemes for auto-generated names (the "naming" config key).
+const (
+	namingAdjectiveAnimal = "adjective-animal" // swift-fox (default)
+	namingUUID            = "uuid"             // 0b6f3c1e-...
+	namingNumeric         = "numeric"          // 7, the worktree's ID
+	namingCounter         = "<n>"              // Replaced by the ID in templates like agent-<n>
+)
+
+// maxSequentialNameAttempts bounds how far numeric and template names move
+// past the ID when names or branches are taken (e.g. kept by wt remove -b).
+const maxSequentialNameAttempts = 100
 
 // adjectives for agent_id generation (~50 words).
 var adjectives = []string{
@@ -38,31 +60,68 @@ var animals = []string{
 	"puma", "rook", "swan", "toad", "wolf",
 }
 
-// generateAgentID creates a unique adjective-animal identifier.
-// existing is the list of current agent_ids and names to avoid collisions.
-// Returns error after 10 failed attempts to find a unique ID.
-func generateAgentID(existing []string) (string, error) {
-	existingSet := make(map[string]bool, len(existing))
-	for _, name := range existing {
-		existingSet[name] = true
+// namingTemplateName matches what a template may render to: letters, digits,
+// ., _ and -, starting with a letter or digit.
+var namingTemplateName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// validateNamingScheme checks a naming config value. Empty means the default,
+// adjective-animal. A template must contain <n> once and otherwise only the
+// characters allowed in names.
+func validateNamingScheme(naming string) error {
+	switch naming {
+	case "", namingAdjectiveAnimal, namingUUID, namingNumeric:
+		return nil
 	}
 
-	for range 10 {
-		adjIdx, err := rand.Int(rand.Reader, big.NewInt(int64(len(adjectives))))
-		if err != nil {
-			return "", fmt.Errorf("generating random adjective index: %w", err)
-		}
+	if strings.Count(naming, namingCounter) != 1 {
+		return fmt.Errorf("%w: %q", errUnknownNamingScheme, naming)
+	}
+
+	rendered := strings.Replace(naming, namingCounter, "1", 1)
+	if !namingTemplateName.MatchString(rendered) || strings.HasSuffix(rendered, ".lock") {
+		return fmt.Errorf("%w: %q (templates may only use letters, digits, ., _ and -)", errUnknownNamingScheme, naming)
+	}
+
+	return nil
+}
+
+// generateName creates an auto-generated name following the naming scheme
+// (see validateNamingScheme). id is the ID the new worktree will get, used
+// by numeric and template schemes; taken reports names that are in use by
+// a worktree or branch and must be skipped.
+func generateName(naming string, id int, taken func(string) bool) (string, error) {
+	switch naming {
+	case "", namingAdjectiveAnimal:
+		return generateUnique(10, taken, randomAdjectiveAnimal)
+	case namingUUID:
+		return generateUnique(10, taken, randomUUID)
+	}
 
-		animalIdx, err := rand.Int(rand.Reader, big.NewInt(int64(len(animals))))
+	template := naming
+	if naming == namingNumeric {
+		template = namingCounter
+	}
+
+	n := id
+
+	return generateUnique(maxSequentialNameAttempts, taken, func() (string, error) {
+		candidate := strings.Replace(template, namingCounter, strconv.Itoa(n), 1)
+		n++
+
+		return candidate, nil
+	})
+}
+
+// generateUnique calls next up to attempts times until it returns a name
+// that is not taken.
+func generateUnique(attempts int, taken func(string) bool, next func() (string, error)) (string, error) {
+	for range attempts {
+		candidate, err := next()
 		if err != nil {
-			return "", fmt.Errorf("generating random animal index: %w", err)
+			return "", err
 		}
 
-		adj := adjectives[adjIdx.Int64()]
-		animal := animals[animalIdx.Int64()]
-		candidate := adj + "-" + animal
-
-		if !existingSet[candidate] {
+		if !taken(candidate) {
 			return candidate, nil
 		}
 	}
@@ -70,6 +129,35 @@ func generateAgentID(existing []string) (string, error) {
 	return "", ErrNameGenerationFailed
 }
 
+func randomAdjectiveAnimal() (string, error) {
+	adjIdx, err := rand.Int(rand.Reader, big.NewInt(int64(len(adjectives))))
+	if err != nil {
+		return "", fmt.Errorf("generating random adjective index: %w", err)
+	}
+
+	animalIdx, err := rand.Int(rand.Reader, big.NewInt(int64(len(animals))))
+	if err != nil {
+		return "", fmt.Errorf("generating random animal index: %w", err)
+	}
+
+	return adjectives[adjIdx.Int64()] + "-" + animals[animalIdx.Int64()], nil
+}
+
+// randomUUID returns a random (version 4) UUID.
+func randomUUID() (string, error) {
+	var b [16]byte
+
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return "", fmt.Errorf("generating random uuid: %w", err)
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
 // getExistingNames returns all agent_ids and names from existing worktrees.
 // Used for collision detection during agent_id generation.
 func getExistingNames(worktrees []WorktreeInfo) []string {
@@ -85,3 +173,67 @@ func getExistingNames(worktrees []WorktreeInfo) []string {
 
 	return names
 }
+
+// validateWorktreeName checks a name given by the user (create --name, wt
+// rename). It becomes a directory directly in the base directory, so it must
+// be a single path element.
+func validateWorktreeName(name string) error {
+	if strings.TrimSpace(name) == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%w: %q", errInvalidName, name)
+	}
+
+	return nil
+}
+
+// NameSlugConfig controls how --name is turned into a worktree directory and
+// branch name. Configured as "name_slug" in config.json; when absent, names
+// are used as given.
+type NameSlugConfig struct {
+	Lowercase bool   `json:"lowercase"` // Lowercase ASCII letters
+	Separator string `json:"separator"` // Replaces spaces and disallowed characters (default "-")
+}
+
+// slugifyName maps name to [A-Za-z0-9._-]. Whitespace and ASCII punctuation
+// become the separator (collapsed and trimmed at the ends); other characters
+// such as non-ASCII letters are dropped.
+func slugifyName(name string, opts NameSlugConfig) (string, error) {
+	sep := opts.Separator
+	if sep == "" {
+		sep = "-"
+	}
+
+	if sep != "-" && sep != "_" && sep != "." {
+		return "", fmt.Errorf("%w: %q", errInvalidSlugSeparator, sep)
+	}
+
+	var builder strings.Builder
+
+	pendingSep := false
+
+	for _, r := range name {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if pendingSep && builder.Len() > 0 {
+				builder.WriteString(sep)
+			}
+
+			pendingSep = false
+
+			if opts.Lowercase {
+				r = unicode.ToLower(r)
+			}
+
+			builder.WriteRune(r)
+		case unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r):
+			pendingSep = true
+		}
+	}
+
+	slug := builder.String()
+
+	if slug == "" || strings.HasSuffix(slug, ".lock") {
+		return "", fmt.Errorf("%w: %q", errInvalidSlug, name)
+	}
+
+	return slug, nil
+}
@@ -2,15 +2,16 @@ package main
 
 import (
 	"errors"
+	"regexp"
 	"slices"
 	"strings"
 	"testing"
 )
 
-func Test_generateAgentID_Returns_Adjective_Animal_Format(t *testing.T) {
+func Test_generateName_Defaults_To_Adjective_Animal_Format(t *testing.T) {
 	t.Parallel()
 
-	agentID, err := generateAgentID(nil)
+	agentID, err := generateName("", 1, func(string) bool { return false })
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -35,14 +36,14 @@ func Test_generateAgentID_Returns_Adjective_Animal_Format(t *testing.T) {
 	}
 }
 
-func Test_generateAgentID_Avoids_Existing_Names(t *testing.T) {
+func Test_generateName_Adjective_Animal_Avoids_Existing_Names(t *testing.T) {
 	t.Parallel()
 
 	// Generate several IDs and ensure no duplicates
 	existing := []string{}
 
 	for range 20 {
-		agentID, err := generateAgentID(existing)
+		agentID, err := generateName("", 1, func(name string) bool { return slices.Contains(existing, name) })
 		if err != nil {
 			t.Fatalf("failed to generate agent_id: %v", err)
 		}
@@ -58,7 +59,7 @@ func Test_generateAgentID_Avoids_Existing_Names(t *testing.T) {
 	}
 }
 
-func Test_generateAgentID_Returns_Error_After_Exhausting_Retries(t *testing.T) {
+func Test_generateName_Adjective_Animal_Returns_Error_After_Exhausting_Retries(t *testing.T) {
 	t.Parallel()
 
 	// Create a list with all possible combinations
@@ -71,7 +72,7 @@ func Test_generateAgentID_Returns_Error_After_Exhausting_Retries(t *testing.T) {
 	}
 
 	// Try to generate when all are taken
-	_, err := generateAgentID(allCombinations)
+	_, err := generateName("", 1, func(name string) bool { return slices.Contains(allCombinations, name) })
 	if err == nil {
 		t.Fatal("expected error when all combinations exist, got nil")
 	}
@@ -81,14 +82,14 @@ func Test_generateAgentID_Returns_Error_After_Exhausting_Retries(t *testing.T) {
 	}
 }
 
-func Test_generateAgentID_Avoids_Collisions_With_Custom_Names(t *testing.T) {
+func Test_generateName_Adjective_Animal_Avoids_Collisions_With_Custom_Names(t *testing.T) {
 	t.Parallel()
 
 	// Existing includes both agent_ids and custom names
 	existing := []string{"swift-fox", "my-custom-name", "brave-owl"}
 
 	for range 50 {
-		agentID, err := generateAgentID(existing)
+		agentID, err := generateName("", 1, func(name string) bool { return slices.Contains(existing, name) })
 		if err != nil {
 			t.Fatalf("failed to generate agent_id: %v", err)
 		}
@@ -99,6 +100,108 @@ func Test_generateAgentID_Avoids_Collisions_With_Custom_Names(t *testing.T) {
 	}
 }
 
+func Test_generateName_Follows_Naming_Scheme(t *testing.T) {
+	t.Parallel()
+
+	none := func(string) bool { return false }
+
+	tests := []struct {
+		naming string
+		want   string
+	}{
+		{"numeric", "7"},
+		{"agent-<n>", "agent-7"},
+		{"<n>.task", "7.task"},
+	}
+
+	for _, tt := range tests {
+		got, err := generateName(tt.naming, 7, none)
+		if err != nil {
+			t.Errorf("generateName(%q) error: %v", tt.naming, err)
+
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("generateName(%q, 7) = %q, want %q", tt.naming, got, tt.want)
+		}
+	}
+
+	uuid, err := generateName("uuid", 7, none)
+	if err != nil {
+		t.Fatalf("generateName(uuid) error: %v", err)
+	}
+
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uuid) {
+		t.Errorf("expected a v4 UUID, got %q", uuid)
+	}
+
+	agentID, err := generateName("", 7, none)
+	if err != nil {
+		t.Fatalf("generateName(\"\") error: %v", err)
+	}
+
+	if len(strings.Split(agentID, "-")) != 2 {
+		t.Errorf("expected adjective-animal by default, got %q", agentID)
+	}
+}
+
+func Test_generateName_Skips_Taken_Names(t *testing.T) {
+	t.Parallel()
+
+	taken := func(name string) bool { return name == "agent-3" || name == "agent-4" }
+
+	got, err := generateName("agent-<n>", 3, taken)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if got != "agent-5" {
+		t.Errorf("expected agent-5, got %q", got)
+	}
+
+	_, err = generateName("numeric", 1, func(string) bool { return true })
+	if !errors.Is(err, ErrNameGenerationFailed) {
+		t.Errorf("expected ErrNameGenerationFailed, got: %v", err)
+	}
+}
+
+func Test_validateNamingScheme_Rejects_Unknown_Schemes(t *testing.T) {
+	t.Parallel()
+
+	for _, naming := range []string{"", "adjective-animal", "uuid", "numeric", "agent-<n>", "task_<n>", "<n>.task"} {
+		err := validateNamingScheme(naming)
+		if err != nil {
+			t.Errorf("validateNamingScheme(%q) unexpected error: %v", naming, err)
+		}
+	}
+
+	for _, naming := range []string{"random", "agent", "<n>-<n>", "agent <n>", "feat/<n>", "-<n>", "<n>.lock"} {
+		err := validateNamingScheme(naming)
+		if !errors.Is(err, errUnknownNamingScheme) {
+			t.Errorf("validateNamingScheme(%q) expected errUnknownNamingScheme, got %v", naming, err)
+		}
+	}
+}
+
+func Test_validateWorktreeName_Rejects_Non_Path_Elements(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"swift-fox", "fix.login", "a_b", "..x", "x.."} {
+		err := validateWorktreeName(name)
+		if err != nil {
+			t.Errorf("validateWorktreeName(%q) unexpected error: %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"", "  ", ".", "..", "../x", "a/b", "/abs", `a\b`} {
+		err := validateWorktreeName(name)
+		if !errors.Is(err, errInvalidName) {
+			t.Errorf("validateWorktreeName(%q) expected errInvalidName, got %v", name, err)
+		}
+	}
+}
+
 func Test_getExistingNames_Returns_Both_AgentID_And_Name(t *testing.T) {
 	t.Parallel()
 
@@ -178,14 +281,14 @@ func Test_WordLists_Have_Sufficient_Entries(t *testing.T) {
 	}
 }
 
-func Test_generateAgentID_Produces_Different_Results(t *testing.T) {
+func Test_generateName_Adjective_Animal_Produces_Different_Results(t *testing.T) {
 	t.Parallel()
 
 	// Generate multiple IDs and check we get some variety
 	results := make(map[string]bool)
 
 	for range 50 {
-		agentID, err := generateAgentID(nil)
+		agentID, err := generateName("", 1, func(string) bool { return false })
 		if err != nil {
 			t.Fatalf("failed to generate agent_id: %v", err)
 		}
@@ -198,3 +301,68 @@ func Test_generateAgentID_Produces_Different_Results(t *testing.T) {
 		t.Errorf("expected at least 10 unique agent_ids from 50 attempts, got %d", len(results))
 	}
 }
+
+func Test_slugifyName_Maps_Spaces_And_Special_Characters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts NameSlugConfig
+		want string
+	}{
+		{"Fix Login Bug", NameSlugConfig{Lowercase: true}, "fix-login-bug"},
+		{"Fix Login Bug", NameSlugConfig{}, "Fix-Login-Bug"},
+		{"  add: OAuth / SSO!  ", NameSlugConfig{Lowercase: true}, "add-oauth-sso"},
+		{"café crème", NameSlugConfig{Lowercase: true}, "caf-crme"},
+		{"日本語 docs", NameSlugConfig{}, "docs"},
+		{"feature--x__y", NameSlugConfig{Separator: "_"}, "feature_x_y"},
+		{"v1.2 release", NameSlugConfig{Separator: "."}, "v1.2.release"},
+	}
+
+	for _, tt := range tests {
+		got, err := slugifyName(tt.name, tt.opts)
+		if err != nil {
+			t.Errorf("slugifyName(%q) error: %v", tt.name, err)
+
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("slugifyName(%q, %+v) = %q, want %q", tt.name, tt.opts, got, tt.want)
+		}
+	}
+}
+
+func Test_slugifyName_Rejects_Unusable_Results(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"", "!!!", "日本語", "config lock"} {
+		_, err := slugifyName(name, NameSlugConfig{Separator: "."})
+		if !errors.Is(err, errInvalidSlug) {
+			t.Errorf("slugifyName(%q) expected errInvalidSlug, got %v", name, err)
+		}
+	}
+
+	_, err := slugifyName("ok", NameSlugConfig{Separator: "/"})
+	if !errors.Is(err, errInvalidSlugSeparator) {
+		t.Errorf("expected errInvalidSlugSeparator, got %v", err)
+	}
+}
+
+func Test_allocateAmong_Skips_IDs_Used_As_Parent_ID(t *testing.T) {
+	t.Parallel()
+
+	// The parent (id 5) is gone, but a child still names it
+	existing := []WorktreeInfo{
+		{Name: "child", AgentID: "child", ID: 3, ParentID: 5},
+	}
+
+	_, _, id, err := allocateAmong(existing, "new", "", func(string) bool { return false })
+	if err != nil {
+		t.Fatalf("allocateAmong: %v", err)
+	}
+
+	if id != 6 {
+		t.Errorf("expected id 6, past the dangling parent_id 5, got %d", id)
+	}
+}
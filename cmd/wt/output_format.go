@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// Output formats accepted by --format.
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+// Errors for --format.
+var (
+	errInvalidFormat    = errors.New("invalid format (valid: text, json, yaml)")
+	errFormatConflict   = errors.New("cannot use --json with --format")
+	errYAMLInvalidInput = errors.New("converting to YAML")
+)
+
+// errPorcelainConflict is returned when --porcelain is combined with another
+// output mode.
+var errPorcelainConflict = errors.New("cannot use --porcelain with")
+
+// addFormatFlag registers --format on flags.
+func addFormatFlag(flags *flag.FlagSet) {
+	flags.String("format", formatText, "Output format: text, json or yaml (--json is short for --format json)")
+}
+
+// addPorcelainFlag registers --porcelain on flags.
+func addPorcelainFlag(flags *flag.FlagSet) {
+	flags.Bool("porcelain", false, "Print stable key<TAB>value lines instead of text; everything else goes to stderr")
+}
+
+// printPorcelain writes --porcelain output: one "key\tvalue" line per pair
+// in kv. Each record starts with a status line, so several records (e.g.
+// a recursive remove) can be split on it.
+func printPorcelain(w io.Writer, kv ...string) {
+	for i := 0; i+1 < len(kv); i += 2 {
+		fprintf(w, "%s\t%s\n", kv[i], kv[i+1])
+	}
+}
+
+// outputFormat returns the format selected by --format, with --json as an
+// alias for --format json.
+func outputFormat(flags *flag.FlagSet) (string, error) {
+	format, _ := flags.GetString("format")
+	jsonOutput, _ := flags.GetBool("json")
+
+	switch format {
+	case formatText, formatJSON, formatYAML:
+	default:
+		return "", fmt.Errorf("%w: %q", errInvalidFormat, format)
+	}
+
+	if jsonOutput {
+		if flags.Changed("format") && format != formatJSON {
+			return "", fmt.Errorf("%w %s", errFormatConflict, format)
+		}
+
+		return formatJSON, nil
+	}
+
+	return format, nil
+}
+
+// jsonRequested reports whether flags asked for JSON output, with --json,
+// --jsonl or --format json. Commands without these flags never do.
+func jsonRequested(flags *flag.FlagSet) bool {
+	jsonOutput, _ := flags.GetBool("json")
+	jsonlOutput, _ := flags.GetBool("jsonl")
+	format, _ := flags.GetString("format")
+
+	return jsonOutput || jsonlOutput || format == formatJSON
+}
+
+// jsonError is the object a failed command prints to stderr under --json.
+type jsonError struct {
+	Error string `json:"error"`
+	Code  int    `json:"code"`
+}
+
+// printJSONError writes err to w as a one-line jsonError with exit code 1,
+// so tools reading --json output can parse failures too.
+func printJSONError(w io.Writer, err error) {
+	data, marshalErr := json.Marshal(jsonError{Error: err.Error(), Code: 1})
+	if marshalErr != nil {
+		fprintError(w, err)
+
+		return
+	}
+
+	fprintln(w, string(data))
+}
+
+// encodeYAML writes v as a YAML document with the same fields, in the same
+// order, as its JSON encoding. Strings that YAML would read as another type
+// (numbers, booleans, timestamps, ...) are double-quoted, so values survive
+// a round trip unchanged.
+func encodeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	node, err := decodeYAMLNode(dec)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errYAMLInvalidInput, err)
+	}
+
+	var buf strings.Builder
+
+	writeYAMLNode(&buf, node, 0)
+
+	_, err = io.WriteString(w, buf.String())
+	if err != nil {
+		return fmt.Errorf("writing YAML: %w", err)
+	}
+
+	return nil
+}
+
+// yamlNode is a JSON value with object key order preserved.
+type yamlNode struct {
+	scalar string // Rendered scalar, when not a collection
+	isMap  bool
+	isList bool
+	keys   []string
+	values []yamlNode
+}
+
+func decodeYAMLNode(dec *json.Decoder) (yamlNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return yamlNode{}, err
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		node := yamlNode{isMap: tok == '{', isList: tok == '['}
+
+		for dec.More() {
+			if node.isMap {
+				key, keyErr := dec.Token()
+				if keyErr != nil {
+					return yamlNode{}, keyErr
+				}
+
+				node.keys = append(node.keys, fmt.Sprint(key))
+			}
+
+			value, valueErr := decodeYAMLNode(dec)
+			if valueErr != nil {
+				return yamlNode{}, valueErr
+			}
+
+			node.values = append(node.values, value)
+		}
+
+		// Closing delimiter
+		_, err = dec.Token()
+		if err != nil {
+			return yamlNode{}, err
+		}
+
+		return node, nil
+	case string:
+		return yamlNode{scalar: yamlString(tok)}, nil
+	case nil:
+		return yamlNode{scalar: "null"}, nil
+	default:
+		// bool and json.Number print as YAML scalars as is
+		return yamlNode{scalar: fmt.Sprint(tok)}, nil
+	}
+}
+
+// writeYAMLNode writes node in block style at the given indentation.
+func writeYAMLNode(buf *strings.Builder, node yamlNode, indent int) {
+	pad := strings.Repeat("  ", indent)
+
+	switch {
+	case node.isMap && len(node.values) == 0:
+		buf.WriteString(pad + "{}\n")
+	case node.isList && len(node.values) == 0:
+		buf.WriteString(pad + "[]\n")
+	case node.isMap:
+		for i, key := range node.keys {
+			writeYAMLEntry(buf, pad+yamlString(key)+":", node.values[i], indent)
+		}
+	case node.isList:
+		for _, value := range node.values {
+			if value.isMap && len(value.values) > 0 {
+				// First key goes on the dash line, the rest align under it
+				var item strings.Builder
+
+				writeYAMLNode(&item, value, indent+1)
+
+				buf.WriteString(pad + "- " + strings.TrimPrefix(item.String(), pad+"  "))
+
+				continue
+			}
+
+			writeYAMLEntry(buf, pad+"-", value, indent)
+		}
+	default:
+		buf.WriteString(pad + node.scalar + "\n")
+	}
+}
+
+// writeYAMLEntry writes prefix ("key:" or "-") followed by value, inline for
+// scalars and empty collections, on the following lines otherwise.
+func writeYAMLEntry(buf *strings.Builder, prefix string, value yamlNode, indent int) {
+	switch {
+	case value.isMap && len(value.values) == 0:
+		buf.WriteString(prefix + " {}\n")
+	case value.isList && len(value.values) == 0:
+		buf.WriteString(prefix + " []\n")
+	case value.isMap || value.isList:
+		buf.WriteString(prefix + "\n")
+		writeYAMLNode(buf, value, indent+1)
+	default:
+		buf.WriteString(prefix + " " + value.scalar + "\n")
+	}
+}
+
+// yamlPlainString matches strings that are safe to write unquoted: they
+// start with a letter, / or _, do not end in a space and contain no
+// characters YAML treats specially.
+var yamlPlainString = regexp.MustCompile(`^[A-Za-z/_]([A-Za-z0-9_./@+ -]*[A-Za-z0-9_./@+-])?$`)
+
+// yamlReserved are plain words YAML reads as booleans or null.
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true,
+}
+
+// yamlString renders s as a YAML scalar, double-quoted unless it is plain.
+func yamlString(s string) string {
+	if yamlPlainString.MatchString(s) && !yamlReserved[strings.ToLower(s)] {
+		return s
+	}
+
+	// A JSON string is a valid YAML double-quoted scalar
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
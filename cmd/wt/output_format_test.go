@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func Test_EncodeYAML_Renders_JSON_Fields_In_Order(t *testing.T) {
+	t.Parallel()
+
+	rows := []jsonWorktree{
+		{
+			Name:       "swift-fox",
+			AgentID:    "swift-fox",
+			ID:         3,
+			Path:       "/code/worktrees/swift-fox",
+			Branch:     "feature/login",
+			BaseBranch: "main",
+			Created:    time.Date(2025, 1, 4, 10, 30, 0, 0, time.UTC),
+			Managed:    true,
+			Debug:      &jsonListDebug{BaseDir: "/code/worktrees", Source: listSourceBaseScan, GitKnown: true},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	err := encodeYAML(&buf, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `- name: swift-fox
+  agent_id: swift-fox
+  id: 3
+  path: /code/worktrees/swift-fox
+  branch: feature/login
+  base_branch: main
+  created: "2025-01-04T10:30:00Z"
+  is_current: false
+  locked: false
+  prunable: false
+  managed: true
+  debug:
+    base_dir: /code/worktrees
+    source: base_scan
+    git_known: true
+`
+	if buf.String() != want {
+		t.Errorf("encodeYAML() =\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func Test_EncodeYAML_Empty_List(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	err := encodeYAML(&buf, []jsonWorktree{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "[]\n" {
+		t.Errorf("encodeYAML(empty) = %q, want %q", buf.String(), "[]\n")
+	}
+}
+
+func Test_YAMLString_Quotes_Ambiguous_Scalars(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"swift-fox", "swift-fox"},
+		{"feature/login", "feature/login"},
+		{"", `""`},
+		{"42", `"42"`},
+		{"true", `"true"`},
+		{"No", `"No"`},
+		{"null", `"null"`},
+		{"~", `"~"`},
+		{".inf", `".inf"`},
+		{"not a worktree", "not a worktree"},
+		{"trailing ", `"trailing "`},
+		{"a: b", `"a: b"`},
+		{"#tag", `"#tag"`},
+		{"-dash", `"-dash"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"line\nbreak", `"line\nbreak"`},
+	}
+
+	for _, tt := range tests {
+		if got := yamlString(tt.in); got != tt.want {
+			t.Errorf("yamlString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/calvinalkan/agent-task/pkg/fs"
+)
+
+// errPluginFailed is returned when a plugin executable cannot be started.
+var errPluginFailed = errors.New("running plugin")
+
+// pluginPrefix is prepended to an unknown command name to find its plugin
+// executable, like git does for git-<name>.
+const pluginPrefix = "wt-"
+
+// findPlugin looks for an executable wt-<name> in the directories of the
+// PATH in env. Names containing a path separator never match, so a command
+// name cannot point outside PATH.
+func findPlugin(fsys fs.FS, env map[string]string, name string) (string, bool) {
+	if name == "" || strings.ContainsRune(name, filepath.Separator) {
+		return "", false
+	}
+
+	for _, dir := range filepath.SplitList(env["PATH"]) {
+		if dir == "" {
+			continue
+		}
+
+		path := filepath.Join(dir, pluginPrefix+name)
+
+		info, err := fsys.Stat(path)
+		if err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+			continue
+		}
+
+		return path, true
+	}
+
+	return "", false
+}
+
+// runPlugin runs pluginPath with args, connected to wt's stdin, stdout and
+// stderr, and returns its exit code. On top of the inherited environment the
+// plugin gets WT_CWD (the effective working directory, also its working
+// directory), WT_BASE (the configured base) and, inside a repository,
+// WT_REPO_ROOT (the main repository root).
+func runPlugin(
+	ctx context.Context,
+	stdin io.Reader,
+	stdout, stderr io.Writer,
+	cfg Config,
+	git *Git,
+	env map[string]string,
+	pluginPath string,
+	args []string,
+) int {
+	pluginEnv := map[string]string{
+		"WT_CWD":  cfg.EffectiveCwd,
+		"WT_BASE": cfg.Base,
+	}
+
+	// Plugins may also work outside a repository, so this is best-effort
+	if mainRepoRoot, err := git.MainRepoRoot(ctx, cfg.EffectiveCwd); err == nil {
+		pluginEnv["WT_REPO_ROOT"] = mainRepoRoot
+	}
+
+	cmd := newChildCmd(ctx, cfg.EffectiveCwd, append([]string{pluginPath}, args...), env, pluginEnv)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+		return exitErr.ExitCode()
+	}
+
+	fprintError(stderr, fmt.Errorf("%w %s: %w", errPluginFailed, pluginPath, err))
+
+	return 1
+}
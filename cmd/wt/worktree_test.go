@@ -2,6 +2,7 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -534,3 +535,282 @@ func Test_readWorktreeInfo_Returns_ErrNotWtWorktree_When_Missing(t *testing.T) {
 		t.Errorf("expected ErrNotWtWorktree, got: %v", err)
 	}
 }
+
+func Test_readWorktreeInfo_Tolerates_BOM_And_Surrounding_Whitespace(t *testing.T) {
+	t.Parallel()
+
+	contents := map[string]string{
+		"bom":        "\ufeff" + `{"name":"bom-wt","id":4,"created":"2025-01-07T12:00:00Z"}`,
+		"whitespace": "\n\t  " + `{"name":"bom-wt","id":4,"created":"2025-01-07T12:00:00Z"}` + "\r\n\n  \n",
+		"both":       "\ufeff  " + `{"name":"bom-wt","id":4,"created":"2025-01-07T12:00:00Z"}` + "\n\n",
+	}
+
+	for name, content := range contents {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+
+			err := os.MkdirAll(filepath.Join(dir, ".wt"), 0o750)
+			if err != nil {
+				t.Fatalf("failed to create .wt directory: %v", err)
+			}
+
+			writeTestFile(t, filepath.Join(dir, ".wt", "worktree.json"), content)
+
+			info, err := readWorktreeInfo(fs.NewReal(), dir)
+			if err != nil {
+				t.Fatalf("readWorktreeInfo: %v", err)
+			}
+
+			if info.Name != "bom-wt" || info.ID != 4 {
+				t.Errorf("unexpected info: %+v", info)
+			}
+		})
+	}
+}
+
+func Test_readWorktreeInfo_Malformed_Content_Returns_Descriptive_Error(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	infoPath := filepath.Join(dir, ".wt", "worktree.json")
+
+	err := os.MkdirAll(filepath.Dir(infoPath), 0o750)
+	if err != nil {
+		t.Fatalf("failed to create .wt directory: %v", err)
+	}
+
+	writeTestFile(t, infoPath, `{"name":"broken","id":4}`+"\ngarbage from a hook\n")
+
+	_, err = readWorktreeInfo(fs.NewReal(), dir)
+	if !errors.Is(err, ErrInvalidWorktreeInfo) {
+		t.Fatalf("expected ErrInvalidWorktreeInfo, got: %v", err)
+	}
+
+	if !strings.Contains(err.Error(), infoPath) {
+		t.Errorf("error should name the file: %v", err)
+	}
+}
+
+func Test_findWorktreesWithPaths_Keeps_Directory_Order_And_Skips_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	fsys := fs.NewReal()
+
+	for i := range 20 {
+		name := fmt.Sprintf("wt-%02d", i)
+
+		if i%5 == 0 {
+			// Unreadable metadata is skipped, not an error
+			err := os.MkdirAll(filepath.Join(dir, name, ".wt"), 0o750)
+			if err != nil {
+				t.Fatalf("creating .wt: %v", err)
+			}
+
+			writeTestFile(t, filepath.Join(dir, name, ".wt", "worktree.json"), "{not json")
+
+			continue
+		}
+
+		err := writeWorktreeInfo(fsys, filepath.Join(dir, name), &WorktreeInfo{Name: name, ID: i})
+		if err != nil {
+			t.Fatalf("writing metadata: %v", err)
+		}
+	}
+
+	worktrees, err := findWorktreesWithPaths(fsys, dir)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if len(worktrees) != 16 {
+		t.Fatalf("expected 16 worktrees, got %d", len(worktrees))
+	}
+
+	for i := 1; i < len(worktrees); i++ {
+		if worktrees[i-1].Name >= worktrees[i].Name {
+			t.Errorf("expected directory order, got %s before %s", worktrees[i-1].Name, worktrees[i].Name)
+		}
+
+		if worktrees[i].Path != filepath.Join(dir, worktrees[i].Name) {
+			t.Errorf("path %s does not match name %s", worktrees[i].Path, worktrees[i].Name)
+		}
+	}
+}
+
+// slowReadFS adds latency to every file read, like a network filesystem.
+type slowReadFS struct {
+	fs.FS
+
+	delay time.Duration
+}
+
+func (s slowReadFS) ReadFile(path string) ([]byte, error) {
+	time.Sleep(s.delay)
+
+	return s.FS.ReadFile(path)
+}
+
+// Benchmark_findWorktreesWithPaths compares the concurrent scan with reading
+// the same 100 worktrees one after another (walkWorktrees, as ls --jsonl
+// does), with 1ms of latency per read.
+func Benchmark_findWorktreesWithPaths(b *testing.B) {
+	dir := b.TempDir()
+	realFS := fs.NewReal()
+
+	for i := range 100 {
+		name := fmt.Sprintf("wt-%03d", i)
+
+		err := writeWorktreeInfo(realFS, filepath.Join(dir, name), &WorktreeInfo{Name: name, ID: i + 1})
+		if err != nil {
+			b.Fatalf("writing metadata: %v", err)
+		}
+	}
+
+	fsys := slowReadFS{FS: realFS, delay: time.Millisecond}
+
+	b.Run("serial", func(b *testing.B) {
+		for b.Loop() {
+			count := 0
+
+			err := walkWorktrees(fsys, dir, func(WorktreeWithPath) error {
+				count++
+
+				return nil
+			})
+			if err != nil || count != 100 {
+				b.Fatalf("expected 100 worktrees, got %d (%v)", count, err)
+			}
+		}
+	})
+
+	b.Run("concurrent", func(b *testing.B) {
+		for b.Loop() {
+			worktrees, err := findWorktreesWithPaths(fsys, dir)
+			if err != nil || len(worktrees) != 100 {
+				b.Fatalf("expected 100 worktrees, got %d (%v)", len(worktrees), err)
+			}
+		}
+	})
+}
+
+func Test_readWorktreeInfo_Upgrades_Metadata_Without_Schema_Version(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	fsys := fs.NewReal()
+
+	err := os.MkdirAll(filepath.Join(dir, ".wt"), 0o750)
+	if err != nil {
+		t.Fatalf("failed to create .wt directory: %v", err)
+	}
+
+	writeTestFile(t, filepath.Join(dir, ".wt", "worktree.json"), `{"name": "legacy", "agent_id": "old-cat", "id": 3}`)
+
+	info, err := readWorktreeInfo(fsys, dir)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if info.SchemaVersion != worktreeSchemaVersion {
+		t.Errorf("expected schema_version %d, got %d", worktreeSchemaVersion, info.SchemaVersion)
+	}
+
+	if warning := schemaWarning(&info, dir); warning != "" {
+		t.Errorf("expected no warning for legacy metadata, got %q", warning)
+	}
+
+	stored, err := storedSchemaVersion(fsys, dir)
+	if err != nil {
+		t.Fatalf("storedSchemaVersion: %v", err)
+	}
+
+	if stored != 0 {
+		t.Errorf("expected stored schema_version 0, got %d", stored)
+	}
+}
+
+func Test_readWorktreeInfo_Reads_Newer_Schema_Version_Best_Effort(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	fsys := fs.NewReal()
+
+	err := os.MkdirAll(filepath.Join(dir, ".wt"), 0o750)
+	if err != nil {
+		t.Fatalf("failed to create .wt directory: %v", err)
+	}
+
+	writeTestFile(t, filepath.Join(dir, ".wt", "worktree.json"), `{"schema_version": 99, "name": "future", "id": 4, "future_field": true}`)
+
+	info, err := readWorktreeInfo(fsys, dir)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if info.SchemaVersion != 99 || info.Name != "future" {
+		t.Errorf("expected schema_version 99 and name future, got %d and %q", info.SchemaVersion, info.Name)
+	}
+
+	AssertContains(t, schemaWarning(&info, dir), "schema_version 99, newer than the supported 1")
+}
+
+func Test_writeWorktreeInfo_Writes_Current_Schema_Version(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	fsys := fs.NewReal()
+
+	info := WorktreeInfo{Name: "fresh", ID: 1}
+
+	err := writeWorktreeInfo(fsys, dir, &info)
+	if err != nil {
+		t.Fatalf("writeWorktreeInfo: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".wt", "worktree.json"))
+	if err != nil {
+		t.Fatalf("reading worktree.json: %v", err)
+	}
+
+	AssertContains(t, string(data), `"schema_version": 1`)
+}
+
+func Test_writeWorktreeInfo_Refuses_To_Overwrite_Newer_Schema_Version(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	fsys := fs.NewReal()
+
+	err := os.MkdirAll(filepath.Join(dir, ".wt"), 0o750)
+	if err != nil {
+		t.Fatalf("failed to create .wt directory: %v", err)
+	}
+
+	future := `{"schema_version": 99, "name": "future", "id": 4, "future_field": true}`
+	writeTestFile(t, filepath.Join(dir, ".wt", "worktree.json"), future)
+
+	info, err := readWorktreeInfo(fsys, dir)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// Neither the metadata as read nor a fresh one replaces it
+	for _, write := range []*WorktreeInfo{&info, {Name: "fresh", ID: 1}} {
+		err = writeWorktreeInfo(fsys, dir, write)
+		if !errors.Is(err, ErrNewerWorktreeSchema) {
+			t.Errorf("expected ErrNewerWorktreeSchema, got %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".wt", "worktree.json"))
+	if err != nil {
+		t.Fatalf("reading worktree.json: %v", err)
+	}
+
+	if string(data) != future {
+		t.Errorf("metadata should be unchanged, got %s", data)
+	}
+}